@@ -8,6 +8,7 @@ import (
 	"os/signal"
 	"syscall"
 
+	database "todos/internal/adapter/database/sqlite"
 	"todos/internal/adapter/http"
 	"todos/internal/adapter/telemetry"
 	"todos/pkg/config"
@@ -39,6 +40,25 @@ func main() {
 
 	telemetryContainer.AppMetrics.StartSystemMetrics(ctx)
 
+	db, err := database.NewDB()
+
+	if err != nil {
+		log.Fatal("Failed to initialize database:", err)
+	}
+
+	defer db.Close()
+
+	container := http.NewContainer(db, logger)
+
+	// Reminders are claimed in the database, so the scheduler can stop at any
+	// point and pick up where it left off on the next boot
+	schedulerCtx, stopScheduler := context.WithCancel(ctx)
+	defer stopScheduler()
+
+	go container.ReminderScheduler.Run(schedulerCtx)
+	go container.AttachmentPurger.Run(schedulerCtx)
+	go container.TrashPurger.Run(schedulerCtx)
+
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 
@@ -50,9 +70,10 @@ func main() {
 			config.EnforceHTTPS = true
 		}
 
-		http.StartServerWithConfig(telemetryContainer.AppMetrics, logger, config)
+		http.StartServerWithContainer(container, telemetryContainer.AppMetrics, logger, config)
 	}()
 
 	<-c
 	logger.Logger.Info("Shutting down gracefully...")
+	stopScheduler()
 }
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_todos_user_status;
+DROP INDEX IF EXISTS idx_todos_user_updated_at;
+DROP INDEX IF EXISTS idx_todos_user_created_at;
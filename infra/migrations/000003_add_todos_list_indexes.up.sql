@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_todos_user_created_at ON todos (user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_todos_user_updated_at ON todos (user_id, updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_todos_user_status ON todos (user_id, status, id);
@@ -0,0 +1,27 @@
+-- FTS5 is compiled into the sqlite driver with the sqlite_fts5 build tag
+CREATE VIRTUAL TABLE IF NOT EXISTS todos_fts USING fts5(
+  title,
+  description,
+  content='todos',
+  content_rowid='id',
+  tokenize='unicode61',
+  prefix='2 3'
+);
+
+-- ORDER BY rank scores with bm25, title hits count double
+INSERT INTO todos_fts (todos_fts, rank) VALUES ('rank', 'bm25(2.0, 1.0)');
+
+CREATE TRIGGER IF NOT EXISTS todos_fts_after_insert AFTER INSERT ON todos BEGIN
+  INSERT INTO todos_fts (rowid, title, description) VALUES (new.id, new.title, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS todos_fts_after_update AFTER UPDATE OF title, description ON todos BEGIN
+  INSERT INTO todos_fts (todos_fts, rowid, title, description) VALUES ('delete', old.id, old.title, old.description);
+  INSERT INTO todos_fts (rowid, title, description) VALUES (new.id, new.title, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS todos_fts_after_delete AFTER DELETE ON todos BEGIN
+  INSERT INTO todos_fts (todos_fts, rowid, title, description) VALUES ('delete', old.id, old.title, old.description);
+END;
+
+INSERT INTO todos_fts (todos_fts) VALUES ('rebuild');
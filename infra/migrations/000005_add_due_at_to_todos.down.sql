@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_todos_user_due_at;
+
+ALTER TABLE todos DROP COLUMN all_day;
+ALTER TABLE todos DROP COLUMN due_at;
@@ -0,0 +1,4 @@
+ALTER TABLE todos ADD COLUMN due_at timestamp NULL;
+ALTER TABLE todos ADD COLUMN all_day boolean NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_todos_user_due_at ON todos (user_id, due_at) WHERE deleted_at IS NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_reminders_pending;
+DROP INDEX IF EXISTS idx_reminders_todo_id;
+DROP INDEX IF EXISTS idx_reminders_uuid_unique;
+DROP TABLE IF EXISTS reminders;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS reminders (
+  id integer primary key autoincrement,
+  uuid text not null,
+  todo_id integer not null,
+  user_id integer not null,
+  kind text not null,
+  offset_minutes integer not null default 0,
+  time_of_day integer not null default 0,
+  attempts integer not null default 0,
+  last_error text,
+  claim_token text,
+  claimed_at timestamp,
+  sent_at timestamp,
+  created_at timestamp not null default current_timestamp,
+  updated_at timestamp not null default current_timestamp,
+  deleted_at timestamp,
+
+  FOREIGN KEY (todo_id) REFERENCES todos (id),
+  FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reminders_uuid_unique ON reminders (uuid);
+CREATE INDEX IF NOT EXISTS idx_reminders_todo_id ON reminders (todo_id);
+CREATE INDEX IF NOT EXISTS idx_reminders_pending ON reminders (todo_id, claimed_at) WHERE sent_at IS NULL AND deleted_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_todo_items_todo_position;
+DROP INDEX IF EXISTS idx_todo_items_uuid_unique;
+DROP TABLE IF EXISTS todo_items;
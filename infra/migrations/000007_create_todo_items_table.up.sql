@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS todo_items (
+  id integer primary key autoincrement,
+  uuid text not null,
+  todo_id integer not null,
+  title text not null,
+  done boolean not null default false,
+  position integer not null default 0,
+  created_at timestamp not null default current_timestamp,
+  updated_at timestamp not null default current_timestamp,
+  deleted_at timestamp,
+
+  FOREIGN KEY (todo_id) REFERENCES todos (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_todo_items_uuid_unique ON todo_items (uuid);
+CREATE INDEX IF NOT EXISTS idx_todo_items_todo_position ON todo_items (todo_id, position, id) WHERE deleted_at IS NULL;
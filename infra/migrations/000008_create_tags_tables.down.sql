@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_todo_tags_tag_id;
+DROP TABLE IF EXISTS todo_tags;
+
+DROP INDEX IF EXISTS idx_tags_user_name_unique;
+DROP INDEX IF EXISTS idx_tags_uuid_unique;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS tags (
+  id integer primary key autoincrement,
+  uuid text not null,
+  user_id integer not null,
+  name text not null,
+  created_at timestamp not null default current_timestamp,
+  updated_at timestamp not null default current_timestamp,
+
+  FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_uuid_unique ON tags (uuid);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_name_unique ON tags (user_id, name COLLATE NOCASE);
+
+CREATE TABLE IF NOT EXISTS todo_tags (
+  todo_id integer not null,
+  tag_id integer not null,
+  created_at timestamp not null default current_timestamp,
+
+  PRIMARY KEY (todo_id, tag_id),
+  FOREIGN KEY (todo_id) REFERENCES todos (id),
+  FOREIGN KEY (tag_id) REFERENCES tags (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_tags_tag_id ON todo_tags (tag_id, todo_id);
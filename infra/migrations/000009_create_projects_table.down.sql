@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_todos_project_position;
+
+ALTER TABLE todos DROP COLUMN position;
+ALTER TABLE todos DROP COLUMN project_id;
+
+DROP INDEX IF EXISTS idx_projects_user_position;
+DROP INDEX IF EXISTS idx_projects_uuid_unique;
+DROP TABLE IF EXISTS projects;
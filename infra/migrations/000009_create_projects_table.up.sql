@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS projects (
+  id integer primary key autoincrement,
+  uuid text not null,
+  user_id integer not null,
+  name text not null,
+  description text,
+  position integer not null default 0,
+  archived_at timestamp,
+  created_at timestamp not null default current_timestamp,
+  updated_at timestamp not null default current_timestamp,
+  deleted_at timestamp,
+
+  FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_projects_uuid_unique ON projects (uuid);
+CREATE INDEX IF NOT EXISTS idx_projects_user_position ON projects (user_id, position, id) WHERE deleted_at IS NULL;
+
+ALTER TABLE todos ADD COLUMN project_id integer NULL REFERENCES projects (id);
+ALTER TABLE todos ADD COLUMN position integer NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_todos_project_position ON todos (project_id, position, id) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_project_members_user_id;
+DROP INDEX IF EXISTS idx_project_members_project_user_unique;
+DROP TABLE IF EXISTS project_members;
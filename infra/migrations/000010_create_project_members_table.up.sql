@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS project_members (
+  id integer primary key autoincrement,
+  project_id integer not null,
+  user_id integer not null,
+  role text not null check (role in ('viewer', 'editor', 'owner')),
+  created_at timestamp not null default current_timestamp,
+  updated_at timestamp not null default current_timestamp,
+
+  FOREIGN KEY (project_id) REFERENCES projects (id),
+  FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_project_members_project_user_unique ON project_members (project_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_project_members_user_id ON project_members (user_id, project_id);
+
+-- Whoever created a project owns it
+INSERT INTO project_members (project_id, user_id, role, created_at, updated_at)
+SELECT id, user_id, 'owner', created_at, updated_at FROM projects;
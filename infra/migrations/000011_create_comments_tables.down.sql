@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_comment_revisions_comment_id;
+DROP TABLE IF EXISTS comment_revisions;
+
+DROP INDEX IF EXISTS idx_comments_todo_id;
+DROP INDEX IF EXISTS idx_comments_uuid_unique;
+DROP TABLE IF EXISTS comments;
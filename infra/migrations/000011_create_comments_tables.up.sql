@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS comments (
+  id integer primary key autoincrement,
+  uuid text not null,
+  todo_id integer not null,
+  user_id integer not null,
+  parent_id integer null,
+  body text not null,
+  edited_at timestamp,
+  created_at timestamp not null default current_timestamp,
+  updated_at timestamp not null default current_timestamp,
+  deleted_at timestamp,
+
+  FOREIGN KEY (todo_id) REFERENCES todos (id),
+  FOREIGN KEY (user_id) REFERENCES users (id),
+  FOREIGN KEY (parent_id) REFERENCES comments (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_comments_uuid_unique ON comments (uuid);
+CREATE INDEX IF NOT EXISTS idx_comments_todo_id ON comments (todo_id, id) WHERE deleted_at IS NULL;
+
+-- Every edit keeps the body it replaced
+CREATE TABLE IF NOT EXISTS comment_revisions (
+  id integer primary key autoincrement,
+  comment_id integer not null,
+  body text not null,
+  created_at timestamp not null default current_timestamp,
+
+  FOREIGN KEY (comment_id) REFERENCES comments (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_revisions_comment_id ON comment_revisions (comment_id, id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_attachments_deleted_at;
+DROP INDEX IF EXISTS idx_attachments_todo_id;
+DROP INDEX IF EXISTS idx_attachments_uuid_unique;
+DROP TABLE IF EXISTS attachments;
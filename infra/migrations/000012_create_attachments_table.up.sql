@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS attachments (
+  id integer primary key autoincrement,
+  uuid text not null,
+  todo_id integer not null,
+  user_id integer not null,
+  filename text not null,
+  content_type text not null,
+  size integer not null,
+  storage_key text not null,
+  created_at timestamp not null default current_timestamp,
+  updated_at timestamp not null default current_timestamp,
+  deleted_at timestamp,
+
+  FOREIGN KEY (todo_id) REFERENCES todos (id),
+  FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_attachments_uuid_unique ON attachments (uuid);
+CREATE INDEX IF NOT EXISTS idx_attachments_todo_id ON attachments (todo_id, id) WHERE deleted_at IS NULL;
+
+-- The purge looks up removed attachments whose blobs are still on disk
+CREATE INDEX IF NOT EXISTS idx_attachments_deleted_at ON attachments (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE todos DROP COLUMN next_occurrence_id;
+ALTER TABLE todos DROP COLUMN rrule_exdates;
+ALTER TABLE todos DROP COLUMN rrule_start;
+ALTER TABLE todos DROP COLUMN rrule;
@@ -0,0 +1,7 @@
+ALTER TABLE todos ADD COLUMN rrule text;
+ALTER TABLE todos ADD COLUMN rrule_start timestamp;
+ALTER TABLE todos ADD COLUMN rrule_exdates text;
+
+-- Completing an occurrence links it to the one it spawned, so completing it
+-- again after reopening does not spawn a second copy
+ALTER TABLE todos ADD COLUMN next_occurrence_id integer REFERENCES todos (id);
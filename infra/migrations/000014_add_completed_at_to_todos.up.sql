@@ -0,0 +1,10 @@
+-- Status is the source of truth for the lifecycle and completed mirrors it.
+-- Rows written before that disagree sometimes; either flag saying the todo is
+-- done settles it as completed.
+ALTER TABLE todos ADD COLUMN completed_at timestamp NULL;
+
+UPDATE todos SET status = 3 WHERE completed = true AND status <> 3;
+UPDATE todos SET completed = (status = 3);
+
+-- The moment of completion was never recorded, the last update is the best guess
+UPDATE todos SET completed_at = updated_at WHERE status = 3;
@@ -0,0 +1,20 @@
+DROP INDEX IF EXISTS idx_todos_user_status;
+
+ALTER TABLE todos ADD COLUMN status_code integer NOT NULL DEFAULT 0;
+
+-- Custom statuses have no integer, they fall back on pending or completed
+UPDATE todos SET status_code = CASE
+  WHEN status = 'in_progress' THEN 1
+  WHEN status = 'in_review' THEN 2
+  WHEN completed = true THEN 3
+  ELSE 0
+END;
+
+ALTER TABLE todos DROP COLUMN status;
+ALTER TABLE todos RENAME COLUMN status_code TO status;
+
+CREATE INDEX IF NOT EXISTS idx_todos_user_status ON todos (user_id, status, id);
+
+DROP TABLE IF EXISTS workflow_transitions;
+DROP TABLE IF EXISTS workflow_statuses;
+DROP TABLE IF EXISTS workflows;
@@ -0,0 +1,64 @@
+-- A workflow lists the statuses todos move through. Projects may define their
+-- own, everything else follows the default one, the workflow without project.
+CREATE TABLE IF NOT EXISTS workflows (
+  id integer primary key autoincrement,
+  uuid text not null,
+  project_id integer null,
+  created_at timestamp not null default current_timestamp,
+  updated_at timestamp not null default current_timestamp,
+
+  FOREIGN KEY (project_id) REFERENCES projects (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workflows_uuid_unique ON workflows (uuid);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workflows_project_unique ON workflows (project_id);
+
+CREATE TABLE IF NOT EXISTS workflow_statuses (
+  id integer primary key autoincrement,
+  workflow_id integer not null,
+  key text not null,
+  name text not null,
+  position integer not null default 0,
+  terminal boolean not null default false,
+
+  FOREIGN KEY (workflow_id) REFERENCES workflows (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workflow_statuses_key_unique ON workflow_statuses (workflow_id, key);
+
+-- A workflow without transitions lets todos move freely between its statuses
+CREATE TABLE IF NOT EXISTS workflow_transitions (
+  id integer primary key autoincrement,
+  workflow_id integer not null,
+  from_key text not null,
+  to_key text not null,
+
+  FOREIGN KEY (workflow_id) REFERENCES workflows (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workflow_transitions_unique ON workflow_transitions (workflow_id, from_key, to_key);
+
+INSERT INTO workflows (uuid, project_id) VALUES ('00000000-0000-0000-0000-000000000001', NULL);
+
+INSERT INTO workflow_statuses (workflow_id, key, name, position, terminal)
+SELECT id, 'pending', 'Pending', 0, false FROM workflows WHERE project_id IS NULL
+UNION ALL SELECT id, 'in_progress', 'In progress', 1, false FROM workflows WHERE project_id IS NULL
+UNION ALL SELECT id, 'in_review', 'In review', 2, false FROM workflows WHERE project_id IS NULL
+UNION ALL SELECT id, 'completed', 'Completed', 3, true FROM workflows WHERE project_id IS NULL;
+
+-- Todos store the key of their status instead of the old integer enum
+DROP INDEX IF EXISTS idx_todos_user_status;
+
+ALTER TABLE todos ADD COLUMN status_key text NOT NULL DEFAULT 'pending';
+
+UPDATE todos SET status_key = CASE status
+  WHEN 1 THEN 'in_progress'
+  WHEN 2 THEN 'in_review'
+  WHEN 3 THEN 'completed'
+  ELSE 'pending'
+END;
+
+ALTER TABLE todos DROP COLUMN status;
+ALTER TABLE todos RENAME COLUMN status_key TO status;
+
+CREATE INDEX IF NOT EXISTS idx_todos_user_status ON todos (user_id, status, id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_todo_reviews_waiting;
+DROP INDEX IF EXISTS idx_todo_reviews_reviewer_unique;
+DROP INDEX IF EXISTS idx_todo_reviews_uuid_unique;
+DROP TABLE IF EXISTS todo_reviews;
+
+ALTER TABLE workflows DROP COLUMN review_rule;
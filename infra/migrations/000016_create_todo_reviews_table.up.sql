@@ -0,0 +1,23 @@
+-- The review rule decides when todos may leave in_review for a terminal status
+ALTER TABLE workflows ADD COLUMN review_rule text not null default 'all';
+
+CREATE TABLE IF NOT EXISTS todo_reviews (
+  id integer primary key autoincrement,
+  uuid text not null,
+  todo_id integer not null,
+  reviewer_id integer not null,
+  requested_by integer not null,
+  decision text not null default 'pending',
+  comment text null,
+  decided_at timestamp,
+  created_at timestamp not null default current_timestamp,
+  updated_at timestamp not null default current_timestamp,
+
+  FOREIGN KEY (todo_id) REFERENCES todos (id),
+  FOREIGN KEY (reviewer_id) REFERENCES users (id),
+  FOREIGN KEY (requested_by) REFERENCES users (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_todo_reviews_uuid_unique ON todo_reviews (uuid);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_todo_reviews_reviewer_unique ON todo_reviews (todo_id, reviewer_id);
+CREATE INDEX IF NOT EXISTS idx_todo_reviews_waiting ON todo_reviews (reviewer_id, id) WHERE decision = 'pending';
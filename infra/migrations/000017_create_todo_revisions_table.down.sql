@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_todo_revisions_number_unique;
+DROP INDEX IF EXISTS idx_todo_revisions_uuid_unique;
+DROP TABLE IF EXISTS todo_revisions;
@@ -0,0 +1,19 @@
+-- Every update of a todo keeps the fields it changed and the todo as it was
+-- before, so the change can be reverted. user_id is empty for updates made
+-- outside a user request.
+CREATE TABLE IF NOT EXISTS todo_revisions (
+  id integer primary key autoincrement,
+  uuid text not null,
+  todo_id integer not null,
+  number integer not null,
+  user_id integer null,
+  changes text not null,
+  snapshot text not null,
+  created_at timestamp not null default current_timestamp,
+
+  FOREIGN KEY (todo_id) REFERENCES todos (id),
+  FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_todo_revisions_uuid_unique ON todo_revisions (uuid);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_todo_revisions_number_unique ON todo_revisions (todo_id, number);
@@ -0,0 +1,3 @@
+-- The trash lists deleted todos newest first and the purge looks up the
+-- ones past their retention
+CREATE INDEX IF NOT EXISTS idx_todos_deleted_at ON todos (deleted_at, id) WHERE deleted_at IS NOT NULL;
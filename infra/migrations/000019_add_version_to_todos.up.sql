@@ -0,0 +1,3 @@
+-- Every write bumps the version, writers that read a todo before changing it
+-- only succeed while the row still has the version they read
+ALTER TABLE todos ADD COLUMN version integer NOT NULL DEFAULT 1;
@@ -0,0 +1,12 @@
+-- A todo is blocked by each of its blockers until they are completed
+CREATE TABLE IF NOT EXISTS todo_dependencies (
+  todo_id integer not null,
+  blocker_id integer not null,
+  created_at timestamp not null default current_timestamp,
+
+  PRIMARY KEY (todo_id, blocker_id),
+  FOREIGN KEY (todo_id) REFERENCES todos (id),
+  FOREIGN KEY (blocker_id) REFERENCES todos (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_dependencies_blocker ON todo_dependencies (blocker_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_time_entries_running;
+DROP INDEX IF EXISTS idx_time_entries_user_started;
+DROP INDEX IF EXISTS idx_time_entries_todo_id;
+DROP INDEX IF EXISTS idx_time_entries_uuid_unique;
+DROP TABLE IF EXISTS time_entries;
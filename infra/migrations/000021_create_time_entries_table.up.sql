@@ -0,0 +1,24 @@
+-- Time tracked on a todo. A running timer has no ended_at yet, seconds is
+-- filled in once the entry is stopped.
+CREATE TABLE IF NOT EXISTS time_entries (
+  id integer primary key autoincrement,
+  uuid text not null,
+  todo_id integer not null,
+  user_id integer not null,
+  note text not null default '',
+  started_at timestamp not null,
+  ended_at timestamp,
+  seconds integer not null default 0,
+  created_at timestamp not null default current_timestamp,
+  updated_at timestamp not null default current_timestamp,
+
+  FOREIGN KEY (todo_id) REFERENCES todos (id),
+  FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_uuid_unique ON time_entries (uuid);
+CREATE INDEX IF NOT EXISTS idx_time_entries_todo_id ON time_entries (todo_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_time_entries_user_started ON time_entries (user_id, started_at);
+
+-- A user runs one timer at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_running ON time_entries (user_id) WHERE ended_at IS NULL;
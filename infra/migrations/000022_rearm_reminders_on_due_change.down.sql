@@ -0,0 +1 @@
+DROP TRIGGER IF EXISTS reminders_rearm_after_due_change;
@@ -0,0 +1,9 @@
+-- Reminders fire relative to the todo's due date, so moving it re-arms the
+-- reminders that already went out or gave up for the old date
+CREATE TRIGGER IF NOT EXISTS reminders_rearm_after_due_change AFTER UPDATE OF due_at, all_day ON todos
+WHEN old.due_at IS NOT new.due_at OR old.all_day IS NOT new.all_day
+BEGIN
+  UPDATE reminders
+  SET sent_at = NULL, claim_token = NULL, claimed_at = NULL, attempts = 0, last_error = NULL, updated_at = CURRENT_TIMESTAMP
+  WHERE todo_id = new.id AND deleted_at IS NULL;
+END;
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS todos_version_after_blocker_change;
+DROP TRIGGER IF EXISTS todos_version_after_dependency_delete;
+DROP TRIGGER IF EXISTS todos_version_after_dependency_insert;
+DROP TRIGGER IF EXISTS todos_version_after_time_entry_delete;
+DROP TRIGGER IF EXISTS todos_version_after_time_entry_update;
+DROP TRIGGER IF EXISTS todos_version_after_time_entry_insert;
+DROP TRIGGER IF EXISTS todos_version_after_item_update;
+DROP TRIGGER IF EXISTS todos_version_after_item_insert;
@@ -0,0 +1,49 @@
+-- The version stands for the todo as GET returns it, so it also moves when
+-- the item counts, the tracked time or whether a blocker holds it back change
+
+CREATE TRIGGER IF NOT EXISTS todos_version_after_item_insert AFTER INSERT ON todo_items
+BEGIN
+  UPDATE todos SET version = version + 1 WHERE id = new.todo_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS todos_version_after_item_update AFTER UPDATE OF done, deleted_at ON todo_items
+WHEN old.done IS NOT new.done OR old.deleted_at IS NOT new.deleted_at
+BEGIN
+  UPDATE todos SET version = version + 1 WHERE id = new.todo_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS todos_version_after_time_entry_insert AFTER INSERT ON time_entries
+WHEN new.seconds <> 0
+BEGIN
+  UPDATE todos SET version = version + 1 WHERE id = new.todo_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS todos_version_after_time_entry_update AFTER UPDATE OF seconds ON time_entries
+WHEN old.seconds IS NOT new.seconds
+BEGIN
+  UPDATE todos SET version = version + 1 WHERE id = new.todo_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS todos_version_after_time_entry_delete AFTER DELETE ON time_entries
+WHEN old.seconds <> 0
+BEGIN
+  UPDATE todos SET version = version + 1 WHERE id = old.todo_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS todos_version_after_dependency_insert AFTER INSERT ON todo_dependencies
+BEGIN
+  UPDATE todos SET version = version + 1 WHERE id = new.todo_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS todos_version_after_dependency_delete AFTER DELETE ON todo_dependencies
+BEGIN
+  UPDATE todos SET version = version + 1 WHERE id = old.todo_id;
+END;
+
+-- Completing, deleting or restoring a blocker changes whether the todos it
+-- blocks are blocked
+CREATE TRIGGER IF NOT EXISTS todos_version_after_blocker_change AFTER UPDATE OF completed, deleted_at ON todos
+WHEN old.completed IS NOT new.completed OR old.deleted_at IS NOT new.deleted_at
+BEGIN
+  UPDATE todos SET version = version + 1 WHERE id IN (SELECT todo_id FROM todo_dependencies WHERE blocker_id = new.id);
+END;
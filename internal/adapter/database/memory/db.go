@@ -2,33 +2,127 @@ package memory
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"todos/internal/core/port"
+
+	"github.com/patrickmn/go-cache"
 )
 
-type memoryRepository struct{}
+// DefaultMaxBytes bounds the values a memory cache holds at once
+const DefaultMaxBytes = 64 << 20
+
+// ErrCacheFull is returned for a value that does not fit in what is left of
+// the cache once expired values are dropped
+var ErrCacheFull = errors.New("memory cache is full")
+
+// memoryRepository keeps the cache in the memory of this instance, for when
+// there is no redis to share it. It holds at most maxBytes of values.
+type memoryRepository struct {
+	mu       sync.Mutex
+	cache    *cache.Cache
+	size     atomic.Int64
+	maxBytes int64
+}
 
 func NewMemoryRepository() port.CacheRepository {
-	return &memoryRepository{}
+	return NewMemoryRepositoryWithLimit(DefaultMaxBytes)
+}
+
+func NewMemoryRepositoryWithLimit(maxBytes int64) port.CacheRepository {
+	c := &memoryRepository{cache: cache.New(cache.NoExpiration, 10*time.Minute), maxBytes: maxBytes}
+
+	// Called for deletes and for expired values the janitor drops
+	c.cache.OnEvicted(func(key string, value interface{}) {
+		c.size.Add(-int64(len(value.([]byte))))
+	})
+
+	return c
 }
 
+// Set drops the previous value of the key even when the new one does not fit
 func (c *memoryRepository) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cache.Delete(key)
+
+	if !c.fits(value) {
+		return ErrCacheFull
+	}
+
+	c.cache.Set(key, value, ttl)
+	c.size.Add(int64(len(value)))
+
 	return nil
 }
 
+func (c *memoryRepository) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.cache.Get(key); ok {
+		return false, nil
+	}
+
+	// An expired value may still be there, replacing it would not evict it
+	c.cache.Delete(key)
+
+	if !c.fits(value) {
+		return false, ErrCacheFull
+	}
+
+	c.cache.Set(key, value, ttl)
+	c.size.Add(int64(len(value)))
+
+	return true, nil
+}
+
+// fits reports whether the value can be added, dropping expired values first
+// when the cache looks full
+func (c *memoryRepository) fits(value []byte) bool {
+	if c.size.Load()+int64(len(value)) <= c.maxBytes {
+		return true
+	}
+
+	c.cache.DeleteExpired()
+
+	return c.size.Load()+int64(len(value)) <= c.maxBytes
+}
+
 func (c *memoryRepository) Get(ctx context.Context, key string) ([]byte, error) {
+	if value, ok := c.cache.Get(key); ok {
+		return value.([]byte), nil
+	}
+
 	return nil, nil
 }
 
 func (c *memoryRepository) Delete(ctx context.Context, key string) error {
+	c.cache.Delete(key)
 	return nil
 }
 
 func (c *memoryRepository) DeleteByPrefix(ctx context.Context, prefix string) error {
+	for key := range c.cache.Items() {
+		if strings.HasPrefix(key, prefix) {
+			c.cache.Delete(key)
+		}
+	}
+
 	return nil
 }
 
 func (c *memoryRepository) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cache.Flush()
+	c.size.Store(0)
+
 	return nil
 }
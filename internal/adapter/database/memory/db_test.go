@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestMemoryRepository_BoundsHeldBytes(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := context.Background()
+	c := NewMemoryRepositoryWithLimit(10)
+
+	Expect(c.Set(ctx, "a", []byte("12345"), time.Hour)).To(Succeed())
+	Expect(c.Set(ctx, "b", []byte("12345"), 50*time.Millisecond)).To(Succeed())
+
+	// Replacing a value frees what it held
+	Expect(c.Set(ctx, "a", []byte("1234"), time.Hour)).To(Succeed())
+	Expect(c.Set(ctx, "c", []byte("12"), time.Hour)).To(MatchError(ErrCacheFull))
+
+	reserved, err := c.SetNX(ctx, "c", []byte("12"), time.Hour)
+	Expect(err).To(MatchError(ErrCacheFull))
+	Expect(reserved).To(BeFalse())
+
+	// Expired values make room
+	time.Sleep(100 * time.Millisecond)
+
+	reserved, err = c.SetNX(ctx, "c", []byte("12"), time.Hour)
+	Expect(err).To(BeNil())
+	Expect(reserved).To(BeTrue())
+
+	// A value that does not fit is not kept, nor the one it replaced
+	Expect(c.Set(ctx, "a", []byte("12345678901"), time.Hour)).To(MatchError(ErrCacheFull))
+	Expect(c.Get(ctx, "a")).To(BeNil())
+
+	Expect(c.Delete(ctx, "c")).To(Succeed())
+	Expect(c.Set(ctx, "d", []byte("1234567890"), time.Hour)).To(Succeed())
+}
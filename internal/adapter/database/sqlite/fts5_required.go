@@ -0,0 +1,8 @@
+//go:build !sqlite_fts5
+
+package sqlite
+
+// Todo search needs FTS5, which the sqlite driver only compiles in with the
+// sqlite_fts5 build tag. Without the tag the build stops here, naming it,
+// rather than the api dying when it migrates.
+var _ = build_with_tags_sqlite_fts5
@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"todos/internal/adapter/database/sqlite"
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	tel "todos/internal/core/telemetry"
+)
+
+type AttachmentRepository struct {
+	db        *sqlite.DB
+	scanner   *sqlite.Scanner
+	telemetry port.Telemetry
+}
+
+func NewAttachmentRepository(db *sqlite.DB, telemetry port.Telemetry) port.AttachmentRepository {
+	if telemetry == nil {
+		telemetry = tel.NewNoOpProbe()
+	}
+
+	return &AttachmentRepository{
+		db:        db,
+		scanner:   sqlite.NewScanner(),
+		telemetry: telemetry,
+	}
+}
+
+func (ar *AttachmentRepository) attachments() sq.SelectBuilder {
+	return ar.db.QueryBuilder.Select("attachments.*").
+		From("attachments").
+		Where("attachments.deleted_at IS NULL")
+}
+
+func (ar *AttachmentRepository) ListByTodo(ctx context.Context, todoId int) ([]domain.Attachment, error) {
+	query := ar.attachments().
+		Where(sq.Eq{"attachments.todo_id": todoId}).
+		OrderBy("attachments.id ASC")
+
+	return ar.list(ctx, "ListByTodo", query, map[string]interface{}{"todo.id": todoId})
+}
+
+func (ar *AttachmentRepository) GetByUUID(ctx context.Context, todoId int, uid string) (domain.Attachment, error) {
+	query := ar.attachments().
+		Where(sq.Eq{"attachments.uuid": uid, "attachments.todo_id": todoId})
+
+	return ar.get(ctx, "GetByUUID", query, map[string]interface{}{"todo.id": todoId, "attachment.uuid": uid})
+}
+
+func (ar *AttachmentRepository) Find(ctx context.Context, uid string) (domain.Attachment, error) {
+	query := ar.attachments().
+		Join("todos ON todos.id = attachments.todo_id").
+		Where(sq.Eq{"attachments.uuid": uid}).
+		Where("todos.deleted_at IS NULL")
+
+	return ar.get(ctx, "Find", query, map[string]interface{}{"attachment.uuid": uid})
+}
+
+func (ar *AttachmentRepository) Create(ctx context.Context, attachment domain.Attachment) (domain.Attachment, error) {
+	ctx, span := ar.telemetry.StartRepositorySpan(ctx, "Create", "attachment", map[string]interface{}{
+		"db.system":       "sqlite",
+		"db.table":        "attachments",
+		"db.operation":    "INSERT",
+		"attachment.uuid": attachment.UUID.String(),
+		"todo.id":         attachment.TodoId,
+		"user.id":         attachment.UserId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.Attachment, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		ar.telemetry.RecordRepositoryOperation(ctx, "Create", "attachment", time.Since(startTime), err)
+		return domain.Attachment{}, err
+	}
+
+	query, args, err := ar.db.QueryBuilder.Insert("attachments").
+		Columns("uuid", "todo_id", "user_id", "filename", "content_type", "size", "storage_key", "created_at", "updated_at").
+		Values(attachment.UUID.String(), attachment.TodoId, attachment.UserId, attachment.Filename, attachment.ContentType, attachment.Size, attachment.StorageKey, attachment.CreatedAt, attachment.UpdatedAt).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	if _, err := ar.db.ExecContext(ctx, query, args...); err != nil {
+		return fail(err)
+	}
+
+	saved, err := ar.GetByUUID(ctx, attachment.TodoId, attachment.UUID.String())
+
+	if err != nil {
+		return fail(err)
+	}
+
+	span.SetStatus("ok", "")
+	ar.telemetry.RecordRepositoryOperation(ctx, "Create", "attachment", time.Since(startTime), nil)
+
+	return saved, nil
+}
+
+// Delete only hides the attachment, its blob is removed by the purge
+func (ar *AttachmentRepository) Delete(ctx context.Context, attachment domain.Attachment) error {
+	query := ar.db.QueryBuilder.Update("attachments").
+		Set("deleted_at", time.Now()).
+		Where(sq.Eq{"id": attachment.ID})
+
+	return ar.exec(ctx, "Delete", query, map[string]interface{}{"attachment.uuid": attachment.UUID.String()})
+}
+
+func (ar *AttachmentRepository) ListPurgeable(ctx context.Context, deletedBefore time.Time, limit int) ([]domain.Attachment, error) {
+	query := ar.db.QueryBuilder.Select("attachments.*").
+		From("attachments").
+		Where(sq.LtOrEq{"attachments.deleted_at": deletedBefore}).
+		OrderBy("attachments.id ASC").
+		Limit(uint64(limit))
+
+	return ar.list(ctx, "ListPurgeable", query, map[string]interface{}{
+		"purge.deleted_before": deletedBefore,
+		"purge.limit":          limit,
+	})
+}
+
+func (ar *AttachmentRepository) ListStored(ctx context.Context, todoId int) ([]domain.Attachment, error) {
+	query := ar.db.QueryBuilder.Select("attachments.*").
+		From("attachments").
+		Where(sq.Eq{"attachments.todo_id": todoId}).
+		OrderBy("attachments.id ASC")
+
+	return ar.list(ctx, "ListStored", query, map[string]interface{}{"todo.id": todoId})
+}
+
+func (ar *AttachmentRepository) Purge(ctx context.Context, id int) error {
+	query := ar.db.QueryBuilder.Delete("attachments").Where(sq.Eq{"id": id})
+
+	return ar.exec(ctx, "Purge", query, map[string]interface{}{"attachment.id": id})
+}
+
+func (ar *AttachmentRepository) list(ctx context.Context, operation string, query sq.SelectBuilder, attrs map[string]interface{}) ([]domain.Attachment, error) {
+	ctx, span := ar.telemetry.StartRepositorySpan(ctx, operation, "attachment", attachmentSpan(attrs))
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) ([]domain.Attachment, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		ar.telemetry.RecordRepositoryOperation(ctx, operation, "attachment", time.Since(startTime), err)
+		return []domain.Attachment{}, err
+	}
+
+	statement, args, err := query.ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := ar.db.QueryContext(ctx, statement, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	attachments := []domain.Attachment{}
+
+	if err := ar.scanner.ScanRowsToSlice(rows, &attachments); err != nil {
+		return fail(err)
+	}
+
+	span.SetAttributes(map[string]interface{}{"db.rows_returned": len(attachments)})
+	span.SetStatus("ok", "")
+	ar.telemetry.RecordRepositoryOperation(ctx, operation, "attachment", time.Since(startTime), nil)
+
+	return attachments, nil
+}
+
+func (ar *AttachmentRepository) get(ctx context.Context, operation string, query sq.SelectBuilder, attrs map[string]interface{}) (domain.Attachment, error) {
+	ctx, span := ar.telemetry.StartRepositorySpan(ctx, operation, "attachment", attachmentSpan(attrs))
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.Attachment, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		ar.telemetry.RecordRepositoryOperation(ctx, operation, "attachment", time.Since(startTime), err)
+		return domain.Attachment{}, err
+	}
+
+	statement, args, err := query.Limit(1).ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := ar.db.QueryContext(ctx, statement, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	var attachment domain.Attachment
+
+	if err := ar.scanner.ScanRowToStruct(rows, &attachment); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fail(domain.ErrAttachmentNotFound)
+		}
+
+		return fail(fmt.Errorf("%w: %v", domain.ErrAttachmentNotFound, err))
+	}
+
+	span.SetStatus("ok", "")
+	ar.telemetry.RecordRepositoryOperation(ctx, operation, "attachment", time.Since(startTime), nil)
+
+	return attachment, nil
+}
+
+func (ar *AttachmentRepository) exec(ctx context.Context, operation string, query sq.Sqlizer, attrs map[string]interface{}) error {
+	ctx, span := ar.telemetry.StartRepositorySpan(ctx, operation, "attachment", attachmentSpan(attrs))
+	defer span.End()
+
+	startTime := time.Now()
+
+	err := execAll(ctx, ar.db, []sq.Sqlizer{query})
+
+	if err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		ar.telemetry.RecordRepositoryOperation(ctx, operation, "attachment", time.Since(startTime), err)
+		return err
+	}
+
+	span.SetStatus("ok", "")
+	ar.telemetry.RecordRepositoryOperation(ctx, operation, "attachment", time.Since(startTime), nil)
+
+	return nil
+}
+
+func attachmentSpan(attrs map[string]interface{}) map[string]interface{} {
+	attrs["db.system"] = "sqlite"
+	attrs["db.table"] = "attachments"
+
+	return attrs
+}
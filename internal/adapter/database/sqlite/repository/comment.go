@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"todos/internal/adapter/database/sqlite"
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	tel "todos/internal/core/telemetry"
+	"todos/internal/core/util"
+)
+
+// commentColumns selects a comment with its author, parent and edit count
+var commentColumns = []string{
+	"comments.*",
+	"users.uuid AS author_uuid",
+	"users.name AS author_name",
+	"(SELECT parent.uuid FROM comments AS parent WHERE parent.id = comments.parent_id) AS parent_uuid",
+	"(SELECT COUNT(*) FROM comment_revisions WHERE comment_revisions.comment_id = comments.id) AS edit_count",
+}
+
+type CommentRepository struct {
+	db        *sqlite.DB
+	scanner   *sqlite.Scanner
+	telemetry port.Telemetry
+}
+
+func NewCommentRepository(db *sqlite.DB, telemetry port.Telemetry) port.CommentRepository {
+	if telemetry == nil {
+		telemetry = tel.NewNoOpProbe()
+	}
+
+	return &CommentRepository{
+		db:        db,
+		scanner:   sqlite.NewScanner(),
+		telemetry: telemetry,
+	}
+}
+
+func (cr *CommentRepository) comments() sq.SelectBuilder {
+	return cr.db.QueryBuilder.Select(commentColumns...).
+		From("comments").
+		Join("users ON users.id = comments.user_id").
+		Where("comments.deleted_at IS NULL")
+}
+
+// ListByTodo pages through a todo's comments oldest first
+func (cr *CommentRepository) ListByTodo(ctx context.Context, todoId int, limit int, cursor string) ([]domain.Comment, bool, error) {
+	ctx, span := cr.telemetry.StartRepositorySpan(ctx, "ListByTodo", "comment", map[string]interface{}{
+		"db.system":         "sqlite",
+		"db.table":          "comments",
+		"todo.id":           todoId,
+		"pagination.limit":  limit,
+		"pagination.cursor": cursor,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) ([]domain.Comment, bool, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		cr.telemetry.RecordRepositoryOperation(ctx, "ListByTodo", "comment", time.Since(startTime), err)
+		return []domain.Comment{}, false, err
+	}
+
+	query := cr.comments().
+		Where(sq.Eq{"comments.todo_id": todoId}).
+		OrderBy("comments.id ASC").
+		Limit(uint64(limit + 1))
+
+	if cursor != "" {
+		data, err := util.DecodeSortCursor(cursor)
+
+		if err != nil {
+			return fail(fmt.Errorf("%w: %v", domain.ErrInvalidCursor, err))
+		}
+
+		if data.Sort != domain.CommentCursorSort {
+			return fail(fmt.Errorf("%w: cursor was not issued for comments", domain.ErrInvalidCursor))
+		}
+
+		query = query.Where(sq.Gt{"comments.id": data.ID})
+	}
+
+	statement, args, err := query.ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := cr.db.QueryContext(ctx, statement, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	comments := []domain.Comment{}
+
+	if err := cr.scanner.ScanRowsToSlice(rows, &comments); err != nil {
+		return fail(err)
+	}
+
+	hasNext := len(comments) > limit
+
+	if hasNext {
+		comments = comments[:limit]
+	}
+
+	span.SetAttributes(map[string]interface{}{
+		"db.rows_returned": len(comments),
+		"db.has_next":      hasNext,
+	})
+	span.SetStatus("ok", "")
+	cr.telemetry.RecordRepositoryOperation(ctx, "ListByTodo", "comment", time.Since(startTime), nil)
+
+	return comments, hasNext, nil
+}
+
+func (cr *CommentRepository) GetByUUID(ctx context.Context, todoId int, uid string) (domain.Comment, error) {
+	ctx, span := cr.telemetry.StartRepositorySpan(ctx, "GetByUUID", "comment", map[string]interface{}{
+		"db.system":    "sqlite",
+		"db.table":     "comments",
+		"todo.id":      todoId,
+		"comment.uuid": uid,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.Comment, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		cr.telemetry.RecordRepositoryOperation(ctx, "GetByUUID", "comment", time.Since(startTime), err)
+		return domain.Comment{}, err
+	}
+
+	query, args, err := cr.comments().
+		Where(sq.Eq{"comments.uuid": uid, "comments.todo_id": todoId}).
+		Limit(1).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := cr.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	var comment domain.Comment
+
+	if err := cr.scanner.ScanRowToStruct(rows, &comment); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fail(domain.ErrCommentNotFound)
+		}
+
+		return fail(fmt.Errorf("%w: %v", domain.ErrCommentNotFound, err))
+	}
+
+	span.SetStatus("ok", "")
+	cr.telemetry.RecordRepositoryOperation(ctx, "GetByUUID", "comment", time.Since(startTime), nil)
+
+	return comment, nil
+}
+
+func (cr *CommentRepository) Create(ctx context.Context, comment domain.Comment) (domain.Comment, error) {
+	ctx, span := cr.telemetry.StartRepositorySpan(ctx, "Create", "comment", map[string]interface{}{
+		"db.system":    "sqlite",
+		"db.table":     "comments",
+		"db.operation": "INSERT",
+		"comment.uuid": comment.UUID.String(),
+		"todo.id":      comment.TodoId,
+		"user.id":      comment.UserId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.Comment, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		cr.telemetry.RecordRepositoryOperation(ctx, "Create", "comment", time.Since(startTime), err)
+		return domain.Comment{}, err
+	}
+
+	query, args, err := cr.db.QueryBuilder.Insert("comments").
+		Columns("uuid", "todo_id", "user_id", "parent_id", "body", "created_at", "updated_at").
+		Values(comment.UUID.String(), comment.TodoId, comment.UserId, comment.ParentId, comment.Body, comment.CreatedAt, comment.UpdatedAt).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	if _, err := cr.db.ExecContext(ctx, query, args...); err != nil {
+		return fail(err)
+	}
+
+	saved, err := cr.GetByUUID(ctx, comment.TodoId, comment.UUID.String())
+
+	if err != nil {
+		return fail(err)
+	}
+
+	span.SetStatus("ok", "")
+	cr.telemetry.RecordRepositoryOperation(ctx, "Create", "comment", time.Since(startTime), nil)
+
+	return saved, nil
+}
+
+func (cr *CommentRepository) Update(ctx context.Context, comment domain.Comment, body string) (domain.Comment, error) {
+	err := cr.inTx(ctx, "Update", comment, func(tx *sqlite.Tx, now time.Time) error {
+		return execAll(ctx, tx, []sq.Sqlizer{
+			cr.db.QueryBuilder.Insert("comment_revisions").
+				Columns("comment_id", "body", "created_at").
+				Values(comment.ID, comment.Body, now),
+			cr.db.QueryBuilder.Update("comments").
+				Set("body", body).
+				Set("edited_at", now).
+				Set("updated_at", now).
+				Where(sq.Eq{"id": comment.ID}),
+		})
+	})
+
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	return cr.GetByUUID(ctx, comment.TodoId, comment.UUID.String())
+}
+
+func (cr *CommentRepository) Delete(ctx context.Context, comment domain.Comment) error {
+	return cr.inTx(ctx, "Delete", comment, func(tx *sqlite.Tx, now time.Time) error {
+		return execAll(ctx, tx, []sq.Sqlizer{
+			cr.db.QueryBuilder.Update("comments").
+				Set("deleted_at", now).
+				Where(sq.Eq{"id": comment.ID}),
+		})
+	})
+}
+
+// ListRevisions returns the replaced bodies of a comment, newest first
+func (cr *CommentRepository) ListRevisions(ctx context.Context, commentId int) ([]domain.CommentRevision, error) {
+	ctx, span := cr.telemetry.StartRepositorySpan(ctx, "ListRevisions", "comment", map[string]interface{}{
+		"db.system":  "sqlite",
+		"db.table":   "comment_revisions",
+		"comment.id": commentId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) ([]domain.CommentRevision, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		cr.telemetry.RecordRepositoryOperation(ctx, "ListRevisions", "comment", time.Since(startTime), err)
+		return []domain.CommentRevision{}, err
+	}
+
+	query, args, err := cr.db.QueryBuilder.Select("*").
+		From("comment_revisions").
+		Where(sq.Eq{"comment_id": commentId}).
+		OrderBy("id DESC").
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := cr.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	revisions := []domain.CommentRevision{}
+
+	if err := cr.scanner.ScanRowsToSlice(rows, &revisions); err != nil {
+		return fail(err)
+	}
+
+	span.SetAttributes(map[string]interface{}{"db.rows_returned": len(revisions)})
+	span.SetStatus("ok", "")
+	cr.telemetry.RecordRepositoryOperation(ctx, "ListRevisions", "comment", time.Since(startTime), nil)
+
+	return revisions, nil
+}
+
+// inTx runs fn in a transaction with the usual span and metrics around it
+func (cr *CommentRepository) inTx(ctx context.Context, operation string, comment domain.Comment, fn func(tx *sqlite.Tx, now time.Time) error) error {
+	ctx, span := cr.telemetry.StartRepositorySpan(ctx, operation, "comment", map[string]interface{}{
+		"db.system":    "sqlite",
+		"db.table":     "comments",
+		"db.operation": "UPDATE",
+		"comment.uuid": comment.UUID.String(),
+		"user.id":      comment.UserId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	err := func() error {
+		tx, err := cr.db.BeginTx(ctx, nil)
+
+		if err != nil {
+			return err
+		}
+
+		defer tx.Rollback()
+
+		if err := fn(tx, time.Now()); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	}()
+
+	if err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		cr.telemetry.RecordRepositoryOperation(ctx, operation, "comment", time.Since(startTime), err)
+		return err
+	}
+
+	span.SetStatus("ok", "")
+	cr.telemetry.RecordRepositoryOperation(ctx, operation, "comment", time.Since(startTime), nil)
+
+	return nil
+}
@@ -0,0 +1,393 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"todos/internal/adapter/database/sqlite"
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	tel "todos/internal/core/telemetry"
+)
+
+// projectColumns selects a project with the number of live todos in it
+var projectColumns = []string{
+	"projects.*",
+	"(SELECT COUNT(*) FROM todos WHERE todos.project_id = projects.id AND todos.deleted_at IS NULL) AS todo_count",
+}
+
+type ProjectRepository struct {
+	db        *sqlite.DB
+	scanner   *sqlite.Scanner
+	telemetry port.Telemetry
+}
+
+func NewProjectRepository(db *sqlite.DB, telemetry port.Telemetry) port.ProjectRepository {
+	if telemetry == nil {
+		telemetry = tel.NewNoOpProbe()
+	}
+
+	return &ProjectRepository{
+		db:        db,
+		scanner:   sqlite.NewScanner(),
+		telemetry: telemetry,
+	}
+}
+
+func (pr *ProjectRepository) ListByUser(ctx context.Context, userId int, archived bool) ([]domain.Project, error) {
+	ctx, span := pr.telemetry.StartRepositorySpan(ctx, "ListByUser", "project", map[string]interface{}{
+		"db.system":        "sqlite",
+		"db.table":         "projects",
+		"user.id":          userId,
+		"project.archived": archived,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) ([]domain.Project, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		pr.telemetry.RecordRepositoryOperation(ctx, "ListByUser", "project", time.Since(startTime), err)
+		return []domain.Project{}, err
+	}
+
+	query := pr.db.QueryBuilder.Select(projectColumns...).
+		Column("project_members.role").
+		From("projects").
+		Join("project_members ON project_members.project_id = projects.id").
+		Where(sq.Eq{"project_members.user_id": userId}).
+		Where("projects.deleted_at IS NULL").
+		OrderBy("projects.position ASC", "projects.id ASC")
+
+	if archived {
+		query = query.Where(sq.NotEq{"projects.archived_at": nil})
+	} else {
+		query = query.Where(sq.Eq{"projects.archived_at": nil})
+	}
+
+	statement, args, err := query.ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := pr.db.QueryContext(ctx, statement, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	projects := []domain.Project{}
+
+	if err := pr.scanner.ScanRowsToSlice(rows, &projects); err != nil {
+		return fail(err)
+	}
+
+	span.SetAttributes(map[string]interface{}{"db.rows_returned": len(projects)})
+	span.SetStatus("ok", "")
+	pr.telemetry.RecordRepositoryOperation(ctx, "ListByUser", "project", time.Since(startTime), nil)
+
+	return projects, nil
+}
+
+func (pr *ProjectRepository) GetByUUID(ctx context.Context, uid string) (domain.Project, error) {
+	ctx, span := pr.telemetry.StartRepositorySpan(ctx, "GetByUUID", "project", map[string]interface{}{
+		"db.system":    "sqlite",
+		"db.table":     "projects",
+		"project.uuid": uid,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.Project, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		pr.telemetry.RecordRepositoryOperation(ctx, "GetByUUID", "project", time.Since(startTime), err)
+		return domain.Project{}, err
+	}
+
+	query, args, err := pr.db.QueryBuilder.Select(projectColumns...).
+		From("projects").
+		Where(sq.Eq{"uuid": uid}).
+		Where("deleted_at IS NULL").
+		Limit(1).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := pr.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	var project domain.Project
+
+	if err := pr.scanner.ScanRowToStruct(rows, &project); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fail(domain.ErrProjectNotFound)
+		}
+
+		return fail(fmt.Errorf("%w: %v", domain.ErrProjectNotFound, err))
+	}
+
+	span.SetStatus("ok", "")
+	pr.telemetry.RecordRepositoryOperation(ctx, "GetByUUID", "project", time.Since(startTime), nil)
+
+	return project, nil
+}
+
+// Create appends the project after the user's other projects and makes the
+// user its owner
+func (pr *ProjectRepository) Create(ctx context.Context, project domain.Project) (domain.Project, error) {
+	ctx, span := pr.telemetry.StartRepositorySpan(ctx, "Create", "project", map[string]interface{}{
+		"db.system":    "sqlite",
+		"db.table":     "projects",
+		"db.operation": "INSERT",
+		"project.uuid": project.UUID.String(),
+		"user.id":      project.UserId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.Project, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		pr.telemetry.RecordRepositoryOperation(ctx, "Create", "project", time.Since(startTime), err)
+		return domain.Project{}, err
+	}
+
+	position := sq.Expr("(SELECT COALESCE(MAX(position) + 1, 0) FROM projects WHERE user_id = ? AND deleted_at IS NULL)", project.UserId)
+
+	query, args, err := pr.db.QueryBuilder.Insert("projects").
+		Columns("uuid", "user_id", "name", "description", "position", "created_at", "updated_at").
+		Values(project.UUID.String(), project.UserId, project.Name, project.Description, position, project.CreatedAt, project.UpdatedAt).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	err = func() error {
+		tx, err := pr.db.BeginTx(ctx, nil)
+
+		if err != nil {
+			return err
+		}
+
+		defer tx.Rollback()
+
+		result, err := tx.ExecContext(ctx, query, args...)
+
+		if err != nil {
+			return err
+		}
+
+		projectId, err := result.LastInsertId()
+
+		if err != nil {
+			return err
+		}
+
+		err = execAll(ctx, tx, []sq.Sqlizer{
+			pr.db.QueryBuilder.Insert("project_members").
+				Columns("project_id", "user_id", "role", "created_at", "updated_at").
+				Values(projectId, project.UserId, domain.ProjectOwner, project.CreatedAt, project.UpdatedAt),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	}()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	saved, err := pr.GetByUUID(ctx, project.UUID.String())
+
+	if err != nil {
+		return fail(err)
+	}
+
+	span.SetStatus("ok", "")
+	pr.telemetry.RecordRepositoryOperation(ctx, "Create", "project", time.Since(startTime), nil)
+
+	return saved, nil
+}
+
+func (pr *ProjectRepository) Update(ctx context.Context, project domain.Project) (domain.Project, error) {
+	project.UpdatedAt = time.Now()
+
+	return pr.update(ctx, "Update", project, project.ToMap())
+}
+
+func (pr *ProjectRepository) SetArchived(ctx context.Context, project domain.Project, archived bool) (domain.Project, error) {
+	now := time.Now()
+
+	var archivedAt *time.Time
+
+	if archived {
+		archivedAt = &now
+	}
+
+	return pr.update(ctx, "SetArchived", project, map[string]interface{}{
+		"archived_at": archivedAt,
+		"updated_at":  now,
+	})
+}
+
+func (pr *ProjectRepository) Delete(ctx context.Context, project domain.Project) error {
+	return pr.inTx(ctx, "Delete", "project", projectSpan(project), func(tx *sqlite.Tx, now time.Time) error {
+		return execAll(ctx, tx, []sq.Sqlizer{
+			pr.db.QueryBuilder.Update("todos").
+				Set("project_id", nil).
+				Set("position", 0).
+				Set("updated_at", now).
+				Set("version", sq.Expr("version + 1")).
+				Where(sq.Eq{"project_id": project.ID}),
+			pr.db.QueryBuilder.Update("projects").
+				Set("deleted_at", now).
+				Set("updated_at", now).
+				Where(sq.Eq{"id": project.ID}),
+		})
+	})
+}
+
+func (pr *ProjectRepository) Reorder(ctx context.Context, project domain.Project, todoUUIDs []string) error {
+	return pr.inTx(ctx, "Reorder", "project", projectSpan(project), func(tx *sqlite.Tx, now time.Time) error {
+		var count int
+
+		query, args, err := pr.db.QueryBuilder.Select("COUNT(*)").
+			From("todos").
+			Where(sq.Eq{"project_id": project.ID}).
+			Where("deleted_at IS NULL").
+			ToSql()
+
+		if err != nil {
+			return err
+		}
+
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+			return err
+		}
+
+		if count != len(todoUUIDs) {
+			return domain.ErrInvalidProjectOrder
+		}
+
+		seen := make(map[string]bool, len(todoUUIDs))
+
+		for position, uid := range todoUUIDs {
+			if seen[uid] {
+				return domain.ErrInvalidProjectOrder
+			}
+
+			seen[uid] = true
+
+			query, args, err := pr.db.QueryBuilder.Update("todos").
+				Set("position", position).
+				Set("updated_at", now).
+				Set("version", sq.Expr("version + 1")).
+				Where(sq.Eq{"uuid": uid, "project_id": project.ID}).
+				Where("deleted_at IS NULL").
+				ToSql()
+
+			if err != nil {
+				return err
+			}
+
+			result, err := tx.ExecContext(ctx, query, args...)
+
+			if err != nil {
+				return err
+			}
+
+			if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+				return domain.ErrInvalidProjectOrder
+			}
+		}
+
+		return nil
+	})
+}
+
+func (pr *ProjectRepository) update(ctx context.Context, operation string, project domain.Project, values map[string]interface{}) (domain.Project, error) {
+	err := pr.inTx(ctx, operation, "project", projectSpan(project), func(tx *sqlite.Tx, now time.Time) error {
+		return execAll(ctx, tx, []sq.Sqlizer{
+			pr.db.QueryBuilder.Update("projects").
+				SetMap(values).
+				Where(sq.Eq{"id": project.ID}).
+				Where("deleted_at IS NULL"),
+		})
+	})
+
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	return pr.GetByUUID(ctx, project.UUID.String())
+}
+
+// projectSpan describes a write on the project for inTx
+func projectSpan(project domain.Project) map[string]interface{} {
+	return map[string]interface{}{
+		"db.system":    "sqlite",
+		"db.table":     "projects",
+		"db.operation": "UPDATE",
+		"project.uuid": project.UUID.String(),
+		"user.id":      project.UserId,
+	}
+}
+
+// inTx runs fn in a transaction with the usual span and metrics around it
+func (pr *ProjectRepository) inTx(ctx context.Context, operation string, entity string, attrs map[string]interface{}, fn func(tx *sqlite.Tx, now time.Time) error) error {
+	ctx, span := pr.telemetry.StartRepositorySpan(ctx, operation, entity, attrs)
+	defer span.End()
+
+	startTime := time.Now()
+
+	err := func() error {
+		tx, err := pr.db.BeginTx(ctx, nil)
+
+		if err != nil {
+			return err
+		}
+
+		defer tx.Rollback()
+
+		if err := fn(tx, time.Now()); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	}()
+
+	if err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		pr.telemetry.RecordRepositoryOperation(ctx, operation, entity, time.Since(startTime), err)
+		return err
+	}
+
+	span.SetStatus("ok", "")
+	pr.telemetry.RecordRepositoryOperation(ctx, operation, entity, time.Since(startTime), nil)
+
+	return nil
+}
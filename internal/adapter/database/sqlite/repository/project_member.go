@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattn/go-sqlite3"
+
+	"todos/internal/adapter/database/sqlite"
+	"todos/internal/core/domain"
+)
+
+// memberColumns selects a membership with the member's user details
+var memberColumns = []string{
+	"project_members.*",
+	"users.uuid AS user_uuid",
+	"users.name",
+	"users.email",
+}
+
+func (pr *ProjectRepository) members() sq.SelectBuilder {
+	return pr.db.QueryBuilder.Select(memberColumns...).
+		From("project_members").
+		Join("users ON users.id = project_members.user_id")
+}
+
+func (pr *ProjectRepository) ListMembers(ctx context.Context, projectId int) ([]domain.ProjectMember, error) {
+	ctx, span := pr.telemetry.StartRepositorySpan(ctx, "ListMembers", "project_member", map[string]interface{}{
+		"db.system":  "sqlite",
+		"db.table":   "project_members",
+		"project.id": projectId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) ([]domain.ProjectMember, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		pr.telemetry.RecordRepositoryOperation(ctx, "ListMembers", "project_member", time.Since(startTime), err)
+		return []domain.ProjectMember{}, err
+	}
+
+	query, args, err := pr.members().
+		Where(sq.Eq{"project_members.project_id": projectId}).
+		OrderBy("project_members.id ASC").
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := pr.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	members := []domain.ProjectMember{}
+
+	if err := pr.scanner.ScanRowsToSlice(rows, &members); err != nil {
+		return fail(err)
+	}
+
+	span.SetAttributes(map[string]interface{}{"db.rows_returned": len(members)})
+	span.SetStatus("ok", "")
+	pr.telemetry.RecordRepositoryOperation(ctx, "ListMembers", "project_member", time.Since(startTime), nil)
+
+	return members, nil
+}
+
+func (pr *ProjectRepository) GetMember(ctx context.Context, projectId int, userId int) (domain.ProjectMember, error) {
+	ctx, span := pr.telemetry.StartRepositorySpan(ctx, "GetMember", "project_member", map[string]interface{}{
+		"db.system":  "sqlite",
+		"db.table":   "project_members",
+		"project.id": projectId,
+		"user.id":    userId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.ProjectMember, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		pr.telemetry.RecordRepositoryOperation(ctx, "GetMember", "project_member", time.Since(startTime), err)
+		return domain.ProjectMember{}, err
+	}
+
+	query, args, err := pr.members().
+		Where(sq.Eq{"project_members.project_id": projectId, "project_members.user_id": userId}).
+		Limit(1).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := pr.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	var member domain.ProjectMember
+
+	if err := pr.scanner.ScanRowToStruct(rows, &member); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fail(domain.ErrProjectMemberNotFound)
+		}
+
+		return fail(err)
+	}
+
+	span.SetStatus("ok", "")
+	pr.telemetry.RecordRepositoryOperation(ctx, "GetMember", "project_member", time.Since(startTime), nil)
+
+	return member, nil
+}
+
+func (pr *ProjectRepository) AddMember(ctx context.Context, member domain.ProjectMember) (domain.ProjectMember, error) {
+	err := pr.inTx(ctx, "AddMember", "project_member", memberSpan(member), func(tx *sqlite.Tx, now time.Time) error {
+		err := execAll(ctx, tx, []sq.Sqlizer{
+			pr.db.QueryBuilder.Insert("project_members").
+				Columns("project_id", "user_id", "role", "created_at", "updated_at").
+				Values(member.ProjectId, member.UserId, member.Role, now, now),
+		})
+
+		var sqliteErr sqlite3.Error
+
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return domain.ErrProjectMemberExists
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return domain.ProjectMember{}, err
+	}
+
+	return pr.GetMember(ctx, member.ProjectId, member.UserId)
+}
+
+func (pr *ProjectRepository) UpdateMemberRole(ctx context.Context, member domain.ProjectMember, role domain.ProjectRole) (domain.ProjectMember, error) {
+	err := pr.inTx(ctx, "UpdateMemberRole", "project_member", memberSpan(member), func(tx *sqlite.Tx, now time.Time) error {
+		if role != domain.ProjectOwner {
+			if err := pr.keepAnOwner(ctx, tx, member); err != nil {
+				return err
+			}
+		}
+
+		return execAll(ctx, tx, []sq.Sqlizer{
+			pr.db.QueryBuilder.Update("project_members").
+				Set("role", role).
+				Set("updated_at", now).
+				Where(sq.Eq{"id": member.ID}),
+		})
+	})
+
+	if err != nil {
+		return domain.ProjectMember{}, err
+	}
+
+	return pr.GetMember(ctx, member.ProjectId, member.UserId)
+}
+
+func (pr *ProjectRepository) RemoveMember(ctx context.Context, member domain.ProjectMember) error {
+	return pr.inTx(ctx, "RemoveMember", "project_member", memberSpan(member), func(tx *sqlite.Tx, now time.Time) error {
+		if err := pr.keepAnOwner(ctx, tx, member); err != nil {
+			return err
+		}
+
+		return execAll(ctx, tx, []sq.Sqlizer{
+			pr.db.QueryBuilder.Delete("project_members").Where(sq.Eq{"id": member.ID}),
+		})
+	})
+}
+
+// keepAnOwner fails when member is the only owner left on the project. It
+// runs inside the transaction that demotes or removes the member, so two
+// owners cannot both step down at once.
+func (pr *ProjectRepository) keepAnOwner(ctx context.Context, tx *sqlite.Tx, member domain.ProjectMember) error {
+	query, args, err := pr.db.QueryBuilder.Select("COUNT(*)").
+		From("project_members").
+		Where(sq.Eq{"project_id": member.ProjectId, "role": domain.ProjectOwner}).
+		Where(sq.NotEq{"id": member.ID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	var owners int
+
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&owners); err != nil {
+		return err
+	}
+
+	if owners == 0 && member.Role == domain.ProjectOwner {
+		return domain.ErrLastProjectOwner
+	}
+
+	return nil
+}
+
+func memberSpan(member domain.ProjectMember) map[string]interface{} {
+	return map[string]interface{}{
+		"db.system":    "sqlite",
+		"db.table":     "project_members",
+		"db.operation": "UPDATE",
+		"project.id":   member.ProjectId,
+		"user.id":      member.UserId,
+	}
+}
@@ -0,0 +1,168 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "todos/pkg/test"
+
+	"todos/internal/adapter/database/sqlite/repository"
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	coretelemetry "todos/internal/core/telemetry"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProjectRepositoryTestSuite struct {
+	suite.Suite
+	ProjectRepo port.ProjectRepository
+	TodoRepo    port.TodoRepository
+	User        domain.User
+}
+
+func (s *ProjectRepositoryTestSuite) SetupTest() {
+	db := InitTestDB()
+	telemetry := coretelemetry.NewNoOpProbe()
+
+	s.ProjectRepo = repository.NewProjectRepository(db, telemetry)
+	s.TodoRepo = repository.NewTodoRepository(db, telemetry)
+
+	s.User, _ = repository.NewUserRepository(db, telemetry).Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Test User",
+		Email: "test@example.com",
+	})
+}
+
+func TestProjectRepositoryTestSuite(t *testing.T) {
+	RegisterTestingT(t)
+	suite.Run(t, new(ProjectRepositoryTestSuite))
+}
+
+func (s *ProjectRepositoryTestSuite) createProject(name string) domain.Project {
+	now := time.Now()
+
+	project, err := s.ProjectRepo.Create(context.Background(), domain.Project{
+		UUID:      uuid.New(),
+		UserId:    s.User.ID,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+
+	Expect(err).To(BeNil())
+
+	return project
+}
+
+func (s *ProjectRepositoryTestSuite) createTodo(title string, projectId *int) domain.Todo {
+	now := time.Now()
+
+	todo, err := s.TodoRepo.Create(context.Background(), domain.Todo{
+		UUID:      uuid.New(),
+		Title:     title,
+		ProjectId: projectId,
+		UserId:    s.User.ID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+
+	Expect(err).To(BeNil())
+
+	return todo
+}
+
+func (s *ProjectRepositoryTestSuite) listTitles(filter domain.TodoFilter) []string {
+	filter.Sort = domain.TodoSort{Field: domain.TodoSortPosition}
+
+	todos, _, err := s.TodoRepo.GetAllWithCursor(context.Background(), s.User.ID, 10, "", filter)
+	Expect(err).To(BeNil())
+
+	titles := []string{}
+	for _, todo := range todos {
+		titles = append(titles, todo.Title)
+	}
+
+	return titles
+}
+
+func (s *ProjectRepositoryTestSuite) TestRepository_Create_AppendsPositions() {
+	first := s.createProject("Home")
+	second := s.createProject("Work")
+
+	Expect(first.Position).To(Equal(0))
+	Expect(second.Position).To(Equal(1))
+
+	a := s.createTodo("Paint fence", &first.ID)
+	b := s.createTodo("Fix sink", &first.ID)
+
+	Expect(a.Position).To(Equal(0))
+	Expect(b.Position).To(Equal(1))
+	Expect(*b.ProjectUUID).To(Equal(first.UUID))
+
+	project, err := s.ProjectRepo.GetByUUID(context.Background(), first.UUID.String())
+	Expect(err).To(BeNil())
+	Expect(project.TodoCount).To(Equal(2))
+}
+
+func (s *ProjectRepositoryTestSuite) TestRepository_Archive_HidesTodosFromDefaultList() {
+	project := s.createProject("Someday")
+	s.createTodo("Learn piano", &project.ID)
+	s.createTodo("Buy milk", nil)
+
+	_, err := s.ProjectRepo.SetArchived(context.Background(), project, true)
+	Expect(err).To(BeNil())
+
+	Expect(s.listTitles(domain.TodoFilter{})).To(ConsistOf("Buy milk"))
+	Expect(s.listTitles(domain.TodoFilter{IncludeArchived: true})).To(ConsistOf("Buy milk", "Learn piano"))
+	Expect(s.listTitles(domain.TodoFilter{Project: project.UUID.String()})).To(ConsistOf("Learn piano"))
+	Expect(s.listTitles(domain.TodoFilter{Project: domain.NoProject})).To(ConsistOf("Buy milk"))
+
+	archived, err := s.ProjectRepo.ListByUser(context.Background(), s.User.ID, true)
+	Expect(err).To(BeNil())
+	Expect(archived).To(HaveLen(1))
+
+	active, err := s.ProjectRepo.ListByUser(context.Background(), s.User.ID, false)
+	Expect(err).To(BeNil())
+	Expect(active).To(BeEmpty())
+}
+
+func (s *ProjectRepositoryTestSuite) TestRepository_Reorder() {
+	project := s.createProject("Trip")
+	a := s.createTodo("Book flights", &project.ID)
+	b := s.createTodo("Pack", &project.ID)
+	c := s.createTodo("Renew passport", &project.ID)
+
+	err := s.ProjectRepo.Reorder(context.Background(), project, []string{c.UUID.String(), a.UUID.String(), b.UUID.String()})
+	Expect(err).To(BeNil())
+
+	Expect(s.listTitles(domain.TodoFilter{Project: project.UUID.String()})).To(Equal([]string{"Renew passport", "Book flights", "Pack"}))
+
+	// Partial or duplicated orders leave the positions untouched
+	err = s.ProjectRepo.Reorder(context.Background(), project, []string{a.UUID.String(), b.UUID.String()})
+	Expect(err).To(MatchError(domain.ErrInvalidProjectOrder))
+
+	err = s.ProjectRepo.Reorder(context.Background(), project, []string{a.UUID.String(), a.UUID.String(), b.UUID.String()})
+	Expect(err).To(MatchError(domain.ErrInvalidProjectOrder))
+
+	Expect(s.listTitles(domain.TodoFilter{Project: project.UUID.String()})).To(Equal([]string{"Renew passport", "Book flights", "Pack"}))
+}
+
+func (s *ProjectRepositoryTestSuite) TestRepository_Delete_DetachesTodos() {
+	project := s.createProject("Old")
+	todo := s.createTodo("Keep me", &project.ID)
+
+	Expect(s.ProjectRepo.Delete(context.Background(), project)).To(Succeed())
+
+	_, err := s.ProjectRepo.GetByUUID(context.Background(), project.UUID.String())
+	Expect(err).To(MatchError(domain.ErrProjectNotFound))
+
+	saved, err := s.TodoRepo.GetByUUID(context.Background(), todo.UUID.String())
+	Expect(err).To(BeNil())
+	Expect(saved.ProjectId).To(BeNil())
+	Expect(saved.ProjectUUID).To(BeNil())
+}
@@ -0,0 +1,455 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+
+	"todos/internal/adapter/database/sqlite"
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	tel "todos/internal/core/telemetry"
+)
+
+// remindAtExpression is the moment a reminder fires, as a julian day, derived
+// from the todo's current due_at so editing the due date moves the reminder too.
+// A trigger clears sent_at and the claim when due_at changes, so a reminder
+// already delivered fires again for the new date.
+const remindAtExpression = `CASE reminders.kind
+	WHEN 'day_of' THEN julianday(date(todos.due_at)) + reminders.time_of_day / 1440.0
+	ELSE julianday(todos.due_at) - reminders.offset_minutes / 1440.0
+END`
+
+type ReminderRepository struct {
+	db        *sqlite.DB
+	scanner   *sqlite.Scanner
+	telemetry port.Telemetry
+}
+
+func NewReminderRepository(db *sqlite.DB, telemetry port.Telemetry) port.ReminderRepository {
+	if telemetry == nil {
+		telemetry = tel.NewNoOpProbe()
+	}
+
+	return &ReminderRepository{
+		db:        db,
+		scanner:   sqlite.NewScanner(),
+		telemetry: telemetry,
+	}
+}
+
+// claimedReminder is the row shape returned by ClaimDue
+type claimedReminder struct {
+	ID            int                 `db:"id"`
+	UUID          uuid.UUID           `db:"uuid"`
+	Kind          domain.ReminderKind `db:"kind"`
+	OffsetMinutes int                 `db:"offset_minutes"`
+	TimeOfDay     int                 `db:"time_of_day"`
+	Attempts      int                 `db:"attempts"`
+	UserId        int                 `db:"user_id"`
+	TodoUUID      uuid.UUID           `db:"todo_uuid"`
+	Title         string              `db:"title"`
+	DueAt         *time.Time          `db:"due_at"`
+	AllDay        bool                `db:"all_day"`
+}
+
+func (rr *ReminderRepository) Create(ctx context.Context, reminder domain.Reminder) (domain.Reminder, error) {
+	ctx, span := rr.telemetry.StartRepositorySpan(ctx, "Create", "reminder", map[string]interface{}{
+		"db.system":     "sqlite",
+		"db.table":      "reminders",
+		"db.operation":  "INSERT",
+		"reminder.uuid": reminder.UUID.String(),
+		"reminder.kind": string(reminder.Kind),
+		"todo.id":       reminder.TodoId,
+		"user.id":       reminder.UserId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.Reminder, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		rr.telemetry.RecordRepositoryOperation(ctx, "Create", "reminder", time.Since(startTime), err)
+		return domain.Reminder{}, err
+	}
+
+	query, args, err := rr.db.QueryBuilder.Insert("reminders").
+		Columns("uuid", "todo_id", "user_id", "kind", "offset_minutes", "time_of_day", "created_at", "updated_at").
+		Values(reminder.UUID.String(), reminder.TodoId, reminder.UserId, string(reminder.Kind), reminder.OffsetMinutes, reminder.TimeOfDay, reminder.CreatedAt, reminder.UpdatedAt).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rr.telemetry.RecordRepositoryQuery(ctx, "Create", "reminder", query, args)
+
+	if _, err := rr.db.ExecContext(ctx, query, args...); err != nil {
+		return fail(err)
+	}
+
+	saved, err := rr.GetByUUID(ctx, reminder.UUID.String())
+
+	if err != nil {
+		return fail(err)
+	}
+
+	span.SetStatus("ok", "")
+	rr.telemetry.RecordRepositoryOperation(ctx, "Create", "reminder", time.Since(startTime), nil)
+
+	return saved, nil
+}
+
+func (rr *ReminderRepository) GetByUUID(ctx context.Context, uid string) (domain.Reminder, error) {
+	ctx, span := rr.telemetry.StartRepositorySpan(ctx, "GetByUUID", "reminder", map[string]interface{}{
+		"db.system":     "sqlite",
+		"db.table":      "reminders",
+		"reminder.uuid": uid,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.Reminder, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		rr.telemetry.RecordRepositoryOperation(ctx, "GetByUUID", "reminder", time.Since(startTime), err)
+		return domain.Reminder{}, err
+	}
+
+	query, args, err := rr.db.QueryBuilder.Select("*").
+		From("reminders").
+		Where(sq.Eq{"uuid": uid}).
+		Where("deleted_at IS NULL").
+		Limit(1).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := rr.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	var reminder domain.Reminder
+
+	if err := rr.scanner.ScanRowToStruct(rows, &reminder); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fail(domain.ErrReminderNotFound)
+		}
+
+		return fail(fmt.Errorf("%w: %v", domain.ErrReminderNotFound, err))
+	}
+
+	span.SetStatus("ok", "")
+	rr.telemetry.RecordRepositoryOperation(ctx, "GetByUUID", "reminder", time.Since(startTime), nil)
+
+	return reminder, nil
+}
+
+func (rr *ReminderRepository) ListByTodo(ctx context.Context, todoId int, userId int) ([]domain.Reminder, error) {
+	ctx, span := rr.telemetry.StartRepositorySpan(ctx, "ListByTodo", "reminder", map[string]interface{}{
+		"db.system": "sqlite",
+		"db.table":  "reminders",
+		"todo.id":   todoId,
+		"user.id":   userId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) ([]domain.Reminder, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		rr.telemetry.RecordRepositoryOperation(ctx, "ListByTodo", "reminder", time.Since(startTime), err)
+		return []domain.Reminder{}, err
+	}
+
+	query, args, err := rr.db.QueryBuilder.Select("*").
+		From("reminders").
+		Where(sq.Eq{"todo_id": todoId, "user_id": userId}).
+		Where("deleted_at IS NULL").
+		OrderBy("id ASC").
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := rr.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	reminders := []domain.Reminder{}
+
+	if err := rr.scanner.ScanRowsToSlice(rows, &reminders); err != nil {
+		return fail(err)
+	}
+
+	span.SetAttributes(map[string]interface{}{"db.rows_returned": len(reminders)})
+	span.SetStatus("ok", "")
+	rr.telemetry.RecordRepositoryOperation(ctx, "ListByTodo", "reminder", time.Since(startTime), nil)
+
+	return reminders, nil
+}
+
+func (rr *ReminderRepository) DeleteByUUID(ctx context.Context, uid string) error {
+	ctx, span := rr.telemetry.StartRepositorySpan(ctx, "DeleteByUUID", "reminder", map[string]interface{}{
+		"db.system":     "sqlite",
+		"db.table":      "reminders",
+		"db.operation":  "UPDATE",
+		"reminder.uuid": uid,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) error {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		rr.telemetry.RecordRepositoryOperation(ctx, "DeleteByUUID", "reminder", time.Since(startTime), err)
+		return err
+	}
+
+	now := time.Now()
+
+	query, args, err := rr.db.QueryBuilder.Update("reminders").
+		Set("deleted_at", now).
+		Set("updated_at", now).
+		Where(sq.Eq{"uuid": uid}).
+		Where("deleted_at IS NULL").
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	result, err := rr.db.ExecContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fail(domain.ErrReminderNotFound)
+	}
+
+	span.SetStatus("ok", "")
+	rr.telemetry.RecordRepositoryOperation(ctx, "DeleteByUUID", "reminder", time.Since(startTime), nil)
+
+	return nil
+}
+
+// ClaimDue marks due reminders with a fresh claim token in a single UPDATE, so
+// two schedulers (or one restarted mid-tick) never pick the same row while the
+// claim is fresh. Claims older than staleBefore are treated as abandoned.
+func (rr *ReminderRepository) ClaimDue(ctx context.Context, now time.Time, staleBefore time.Time, limit int) ([]domain.Notification, error) {
+	ctx, span := rr.telemetry.StartRepositorySpan(ctx, "ClaimDue", "reminder", map[string]interface{}{
+		"db.system":    "sqlite",
+		"db.table":     "reminders",
+		"db.operation": "UPDATE",
+		"claim.limit":  limit,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) ([]domain.Notification, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		rr.telemetry.RecordRepositoryOperation(ctx, "ClaimDue", "reminder", time.Since(startTime), err)
+		return []domain.Notification{}, err
+	}
+
+	token := uuid.New().String()
+
+	due, dueArgs, err := rr.db.QueryBuilder.Select("reminders.id").
+		From("reminders").
+		Join("todos ON todos.id = reminders.todo_id").
+		Where("reminders.sent_at IS NULL").
+		Where("reminders.deleted_at IS NULL").
+		Where(sq.Lt{"reminders.attempts": domain.MaxReminderAttempts}).
+		Where(sq.Or{
+			sq.Eq{"reminders.claimed_at": nil},
+			sq.Lt{"reminders.claimed_at": staleBefore.UTC()},
+		}).
+		Where("todos.deleted_at IS NULL").
+		Where(sq.Eq{"todos.completed": false}).
+		Where("todos.due_at IS NOT NULL").
+		Where(remindAtExpression+" <= julianday(?)", now.UTC()).
+		OrderBy("reminders.id ASC").
+		Limit(uint64(limit)).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	claim, claimArgs, err := rr.db.QueryBuilder.Update("reminders").
+		Set("claim_token", token).
+		Set("claimed_at", now.UTC()).
+		Set("updated_at", now.UTC()).
+		Where("id IN ("+due+")", dueArgs...).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rr.telemetry.RecordRepositoryQuery(ctx, "ClaimDue", "reminder", claim, claimArgs)
+
+	result, err := rr.db.ExecContext(ctx, claim, claimArgs...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		span.SetStatus("ok", "")
+		rr.telemetry.RecordRepositoryOperation(ctx, "ClaimDue", "reminder", time.Since(startTime), nil)
+		return []domain.Notification{}, nil
+	}
+
+	query, args, err := rr.db.QueryBuilder.Select(
+		"reminders.id", "reminders.uuid", "reminders.kind", "reminders.offset_minutes",
+		"reminders.time_of_day", "reminders.attempts", "reminders.user_id",
+		"todos.uuid AS todo_uuid", "todos.title", "todos.due_at", "todos.all_day",
+	).
+		From("reminders").
+		Join("todos ON todos.id = reminders.todo_id").
+		Where(sq.Eq{"reminders.claim_token": token}).
+		OrderBy("reminders.id ASC").
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := rr.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	var claimed []claimedReminder
+
+	if err := rr.scanner.ScanRowsToSlice(rows, &claimed); err != nil {
+		return fail(err)
+	}
+
+	notifications := make([]domain.Notification, 0, len(claimed))
+
+	for _, row := range claimed {
+		reminder := domain.Reminder{Kind: row.Kind, OffsetMinutes: row.OffsetMinutes, TimeOfDay: row.TimeOfDay}
+		remindAt, err := reminder.RemindAt(domain.Todo{DueAt: row.DueAt})
+
+		// The due date went away since the claim, the reminder waits unclaimed
+		// for a new one
+		if err != nil {
+			if err := rr.releaseClaim(ctx, row.ID, now); err != nil {
+				return fail(err)
+			}
+
+			continue
+		}
+
+		notifications = append(notifications, domain.Notification{
+			ReminderId:   row.ID,
+			ReminderUUID: row.UUID,
+			Kind:         row.Kind,
+			TodoUUID:     row.TodoUUID,
+			UserId:       row.UserId,
+			Title:        row.Title,
+			DueAt:        row.DueAt,
+			AllDay:       row.AllDay,
+			RemindAt:     remindAt,
+			Attempts:     row.Attempts,
+		})
+	}
+
+	span.SetAttributes(map[string]interface{}{"db.rows_claimed": len(notifications)})
+	span.SetStatus("ok", "")
+	rr.telemetry.RecordRepositoryOperation(ctx, "ClaimDue", "reminder", time.Since(startTime), nil)
+
+	return notifications, nil
+}
+
+// releaseClaim hands a claimed reminder back without counting an attempt
+func (rr *ReminderRepository) releaseClaim(ctx context.Context, id int, now time.Time) error {
+	return rr.update(ctx, "ReleaseClaim", id, map[string]interface{}{
+		"claim_token": nil,
+		"claimed_at":  nil,
+		"updated_at":  now.UTC(),
+	})
+}
+
+// MarkSent records the delivery, after which the reminder is never claimed again
+func (rr *ReminderRepository) MarkSent(ctx context.Context, id int, sentAt time.Time) error {
+	return rr.update(ctx, "MarkSent", id, map[string]interface{}{
+		"sent_at":     sentAt.UTC(),
+		"claim_token": nil,
+		"last_error":  nil,
+		"updated_at":  sentAt.UTC(),
+	})
+}
+
+// MarkFailed counts a failed delivery. The claim is kept so the reminder is
+// retried only once the claim goes stale, which doubles as a retry backoff.
+func (rr *ReminderRepository) MarkFailed(ctx context.Context, id int, reason string) error {
+	return rr.update(ctx, "MarkFailed", id, map[string]interface{}{
+		"attempts":   sq.Expr("attempts + 1"),
+		"last_error": reason,
+		"updated_at": time.Now().UTC(),
+	})
+}
+
+func (rr *ReminderRepository) update(ctx context.Context, operation string, id int, values map[string]interface{}) error {
+	ctx, span := rr.telemetry.StartRepositorySpan(ctx, operation, "reminder", map[string]interface{}{
+		"db.system":    "sqlite",
+		"db.table":     "reminders",
+		"db.operation": "UPDATE",
+		"reminder.id":  id,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	query, args, err := rr.db.QueryBuilder.Update("reminders").
+		SetMap(values).
+		Where(sq.Eq{"id": id}).
+		Where("sent_at IS NULL").
+		ToSql()
+
+	if err == nil {
+		_, err = rr.db.ExecContext(ctx, query, args...)
+	}
+
+	if err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		rr.telemetry.RecordRepositoryOperation(ctx, operation, "reminder", time.Since(startTime), err)
+		return err
+	}
+
+	span.SetStatus("ok", "")
+	rr.telemetry.RecordRepositoryOperation(ctx, operation, "reminder", time.Since(startTime), nil)
+
+	return nil
+}
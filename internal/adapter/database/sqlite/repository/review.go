@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"todos/internal/adapter/database/sqlite"
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	tel "todos/internal/core/telemetry"
+	"todos/internal/core/util"
+)
+
+// reviewColumns selects a review with its reviewer and todo
+var reviewColumns = []string{
+	"todo_reviews.*",
+	"users.uuid AS reviewer_uuid",
+	"users.name AS reviewer_name",
+	"todos.uuid AS todo_uuid",
+	"todos.title AS todo_title",
+}
+
+type ReviewRepository struct {
+	db        *sqlite.DB
+	scanner   *sqlite.Scanner
+	telemetry port.Telemetry
+}
+
+func NewReviewRepository(db *sqlite.DB, telemetry port.Telemetry) port.ReviewRepository {
+	if telemetry == nil {
+		telemetry = tel.NewNoOpProbe()
+	}
+
+	return &ReviewRepository{
+		db:        db,
+		scanner:   sqlite.NewScanner(),
+		telemetry: telemetry,
+	}
+}
+
+func (rr *ReviewRepository) reviews() sq.SelectBuilder {
+	return rr.db.QueryBuilder.Select(reviewColumns...).
+		From("todo_reviews").
+		Join("users ON users.id = todo_reviews.reviewer_id").
+		Join("todos ON todos.id = todo_reviews.todo_id")
+}
+
+func (rr *ReviewRepository) ListByTodo(ctx context.Context, todoId int) ([]domain.Review, error) {
+	ctx, span := rr.telemetry.StartRepositorySpan(ctx, "ListByTodo", "review", map[string]interface{}{
+		"db.system": "sqlite",
+		"db.table":  "todo_reviews",
+		"todo.id":   todoId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) ([]domain.Review, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		rr.telemetry.RecordRepositoryOperation(ctx, "ListByTodo", "review", time.Since(startTime), err)
+		return []domain.Review{}, err
+	}
+
+	reviews := []domain.Review{}
+
+	err := rr.query(ctx, rr.reviews().Where(sq.Eq{"todo_reviews.todo_id": todoId}).OrderBy("todo_reviews.id ASC"), func(rows *sql.Rows) error {
+		return rr.scanner.ScanRowsToSlice(rows, &reviews)
+	})
+
+	if err != nil {
+		return fail(err)
+	}
+
+	span.SetAttributes(map[string]interface{}{"db.rows_returned": len(reviews)})
+	span.SetStatus("ok", "")
+	rr.telemetry.RecordRepositoryOperation(ctx, "ListByTodo", "review", time.Since(startTime), nil)
+
+	return reviews, nil
+}
+
+func (rr *ReviewRepository) ListWaiting(ctx context.Context, reviewerId int, limit int, cursor string) ([]domain.Review, bool, error) {
+	ctx, span := rr.telemetry.StartRepositorySpan(ctx, "ListWaiting", "review", map[string]interface{}{
+		"db.system":         "sqlite",
+		"db.table":          "todo_reviews",
+		"user.id":           reviewerId,
+		"pagination.limit":  limit,
+		"pagination.cursor": cursor,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) ([]domain.Review, bool, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		rr.telemetry.RecordRepositoryOperation(ctx, "ListWaiting", "review", time.Since(startTime), err)
+		return []domain.Review{}, false, err
+	}
+
+	query := rr.reviews().
+		Where(sq.Eq{"todo_reviews.reviewer_id": reviewerId, "todo_reviews.decision": domain.ReviewPending}).
+		Where("todos.deleted_at IS NULL").
+		Where(sq.Eq{"todos.status": domain.TodoStatusInReview}).
+		Where(visibleTo(reviewerId)).
+		OrderBy("todo_reviews.id ASC").
+		Limit(uint64(limit + 1))
+
+	if cursor != "" {
+		data, err := util.DecodeSortCursor(cursor)
+
+		if err != nil {
+			return fail(fmt.Errorf("%w: %v", domain.ErrInvalidCursor, err))
+		}
+
+		if data.Sort != domain.ReviewCursorSort {
+			return fail(fmt.Errorf("%w: cursor was not issued for reviews", domain.ErrInvalidCursor))
+		}
+
+		query = query.Where(sq.Gt{"todo_reviews.id": data.ID})
+	}
+
+	reviews := []domain.Review{}
+
+	err := rr.query(ctx, query, func(rows *sql.Rows) error {
+		return rr.scanner.ScanRowsToSlice(rows, &reviews)
+	})
+
+	if err != nil {
+		return fail(err)
+	}
+
+	hasNext := len(reviews) > limit
+
+	if hasNext {
+		reviews = reviews[:limit]
+	}
+
+	span.SetAttributes(map[string]interface{}{
+		"db.rows_returned": len(reviews),
+		"db.has_next":      hasNext,
+	})
+	span.SetStatus("ok", "")
+	rr.telemetry.RecordRepositoryOperation(ctx, "ListWaiting", "review", time.Since(startTime), nil)
+
+	return reviews, hasNext, nil
+}
+
+func (rr *ReviewRepository) GetByReviewer(ctx context.Context, todoId int, reviewerId int) (domain.Review, error) {
+	ctx, span := rr.telemetry.StartRepositorySpan(ctx, "GetByReviewer", "review", map[string]interface{}{
+		"db.system": "sqlite",
+		"db.table":  "todo_reviews",
+		"todo.id":   todoId,
+		"user.id":   reviewerId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.Review, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		rr.telemetry.RecordRepositoryOperation(ctx, "GetByReviewer", "review", time.Since(startTime), err)
+		return domain.Review{}, err
+	}
+
+	var review domain.Review
+
+	err := rr.query(ctx, rr.reviews().Where(sq.Eq{"todo_reviews.todo_id": todoId, "todo_reviews.reviewer_id": reviewerId}).Limit(1), func(rows *sql.Rows) error {
+		return rr.scanner.ScanRowToStruct(rows, &review)
+	})
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return fail(domain.ErrReviewNotFound)
+	}
+
+	if err != nil {
+		return fail(err)
+	}
+
+	span.SetStatus("ok", "")
+	rr.telemetry.RecordRepositoryOperation(ctx, "GetByReviewer", "review", time.Since(startTime), nil)
+
+	return review, nil
+}
+
+func (rr *ReviewRepository) Request(ctx context.Context, reviews []domain.Review) ([]domain.Review, error) {
+	if len(reviews) == 0 {
+		return []domain.Review{}, nil
+	}
+
+	err := rr.inTx(ctx, "Request", reviews[0], func(tx *sqlite.Tx, now time.Time) error {
+		statements := make([]sq.Sqlizer, 0, len(reviews))
+
+		for _, review := range reviews {
+			statements = append(statements, rr.db.QueryBuilder.Insert("todo_reviews").
+				Columns("uuid", "todo_id", "reviewer_id", "requested_by", "decision", "created_at", "updated_at").
+				Values(review.UUID.String(), review.TodoId, review.ReviewerId, review.RequestedBy, domain.ReviewPending, now, now).
+				Suffix("ON CONFLICT (todo_id, reviewer_id) DO UPDATE SET "+
+					"requested_by = excluded.requested_by, decision = excluded.decision, "+
+					"comment = NULL, decided_at = NULL, updated_at = excluded.updated_at"))
+		}
+
+		return execAll(ctx, tx, statements)
+	})
+
+	if err != nil {
+		return []domain.Review{}, err
+	}
+
+	return rr.ListByTodo(ctx, reviews[0].TodoId)
+}
+
+func (rr *ReviewRepository) Decide(ctx context.Context, review domain.Review) (domain.Review, error) {
+	err := rr.inTx(ctx, "Decide", review, func(tx *sqlite.Tx, now time.Time) error {
+		return execAll(ctx, tx, []sq.Sqlizer{
+			rr.db.QueryBuilder.Update("todo_reviews").
+				Set("decision", review.Decision).
+				Set("comment", review.Comment).
+				Set("decided_at", review.DecidedAt).
+				Set("updated_at", now).
+				Where(sq.Eq{"id": review.ID}),
+		})
+	})
+
+	if err != nil {
+		return domain.Review{}, err
+	}
+
+	return rr.GetByReviewer(ctx, review.TodoId, review.ReviewerId)
+}
+
+func (rr *ReviewRepository) query(ctx context.Context, query sq.SelectBuilder, scan func(rows *sql.Rows) error) error {
+	statement, args, err := query.ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := rr.db.QueryContext(ctx, statement, args...)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	return scan(rows)
+}
+
+// inTx runs fn in a transaction with the usual span and metrics around it
+func (rr *ReviewRepository) inTx(ctx context.Context, operation string, review domain.Review, fn func(tx *sqlite.Tx, now time.Time) error) error {
+	ctx, span := rr.telemetry.StartRepositorySpan(ctx, operation, "review", map[string]interface{}{
+		"db.system": "sqlite",
+		"db.table":  "todo_reviews",
+		"todo.id":   review.TodoId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	err := func() error {
+		tx, err := rr.db.BeginTx(ctx, nil)
+
+		if err != nil {
+			return err
+		}
+
+		defer tx.Rollback()
+
+		if err := fn(tx, time.Now()); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	}()
+
+	if err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		rr.telemetry.RecordRepositoryOperation(ctx, operation, "review", time.Since(startTime), err)
+		return err
+	}
+
+	span.SetStatus("ok", "")
+	rr.telemetry.RecordRepositoryOperation(ctx, operation, "review", time.Since(startTime), nil)
+
+	return nil
+}
@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattn/go-sqlite3"
+
+	"todos/internal/adapter/database/sqlite"
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	tel "todos/internal/core/telemetry"
+)
+
+// tagColumns selects a tag with the number of live todos carrying it
+var tagColumns = []string{
+	"tags.*",
+	"(SELECT COUNT(*) FROM todo_tags JOIN todos ON todos.id = todo_tags.todo_id WHERE todo_tags.tag_id = tags.id AND todos.deleted_at IS NULL) AS todo_count",
+}
+
+type TagRepository struct {
+	db        *sqlite.DB
+	scanner   *sqlite.Scanner
+	telemetry port.Telemetry
+}
+
+func NewTagRepository(db *sqlite.DB, telemetry port.Telemetry) port.TagRepository {
+	if telemetry == nil {
+		telemetry = tel.NewNoOpProbe()
+	}
+
+	return &TagRepository{
+		db:        db,
+		scanner:   sqlite.NewScanner(),
+		telemetry: telemetry,
+	}
+}
+
+func (tr *TagRepository) ListByUser(ctx context.Context, userId int) ([]domain.Tag, error) {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "ListByUser", "tag", map[string]interface{}{
+		"db.system": "sqlite",
+		"db.table":  "tags",
+		"user.id":   userId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) ([]domain.Tag, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "ListByUser", "tag", time.Since(startTime), err)
+		return []domain.Tag{}, err
+	}
+
+	query, args, err := tr.db.QueryBuilder.Select(tagColumns...).
+		From("tags").
+		Where(sq.Eq{"user_id": userId}).
+		OrderBy("lower(name) ASC").
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := tr.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	tags := []domain.Tag{}
+
+	if err := tr.scanner.ScanRowsToSlice(rows, &tags); err != nil {
+		return fail(err)
+	}
+
+	span.SetAttributes(map[string]interface{}{"db.rows_returned": len(tags)})
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, "ListByUser", "tag", time.Since(startTime), nil)
+
+	return tags, nil
+}
+
+func (tr *TagRepository) GetByUUID(ctx context.Context, uid string) (domain.Tag, error) {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "GetByUUID", "tag", map[string]interface{}{
+		"db.system": "sqlite",
+		"db.table":  "tags",
+		"tag.uuid":  uid,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.Tag, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "GetByUUID", "tag", time.Since(startTime), err)
+		return domain.Tag{}, err
+	}
+
+	query, args, err := tr.db.QueryBuilder.Select(tagColumns...).
+		From("tags").
+		Where(sq.Eq{"uuid": uid}).
+		Limit(1).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := tr.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	var tag domain.Tag
+
+	if err := tr.scanner.ScanRowToStruct(rows, &tag); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fail(domain.ErrTagNotFound)
+		}
+
+		return fail(fmt.Errorf("%w: %v", domain.ErrTagNotFound, err))
+	}
+
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, "GetByUUID", "tag", time.Since(startTime), nil)
+
+	return tag, nil
+}
+
+func (tr *TagRepository) Create(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "Create", "tag", map[string]interface{}{
+		"db.system":    "sqlite",
+		"db.table":     "tags",
+		"db.operation": "INSERT",
+		"tag.uuid":     tag.UUID.String(),
+		"user.id":      tag.UserId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.Tag, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "Create", "tag", time.Since(startTime), err)
+		return domain.Tag{}, err
+	}
+
+	query, args, err := tr.db.QueryBuilder.Insert("tags").
+		Columns("uuid", "user_id", "name", "created_at", "updated_at").
+		Values(tag.UUID.String(), tag.UserId, tag.Name, tag.CreatedAt, tag.UpdatedAt).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	if _, err := tr.db.ExecContext(ctx, query, args...); err != nil {
+		return fail(uniqueTagError(err))
+	}
+
+	saved, err := tr.GetByUUID(ctx, tag.UUID.String())
+
+	if err != nil {
+		return fail(err)
+	}
+
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, "Create", "tag", time.Since(startTime), nil)
+
+	return saved, nil
+}
+
+func (tr *TagRepository) Rename(ctx context.Context, tag domain.Tag, name string) (domain.Tag, error) {
+	err := tr.inTx(ctx, "Rename", tag, func(tx *sqlite.Tx, now time.Time) error {
+		if err := touchTaggedTodos(ctx, tx, tr.db.QueryBuilder, now, tag.ID); err != nil {
+			return err
+		}
+
+		query, args, err := tr.db.QueryBuilder.Update("tags").
+			Set("name", name).
+			Set("updated_at", now).
+			Where(sq.Eq{"id": tag.ID}).
+			ToSql()
+
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, query, args...)
+
+		return uniqueTagError(err)
+	})
+
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	return tr.GetByUUID(ctx, tag.UUID.String())
+}
+
+// Merge moves every todo from source onto target and removes source
+func (tr *TagRepository) Merge(ctx context.Context, source domain.Tag, target domain.Tag) (domain.Tag, error) {
+	err := tr.inTx(ctx, "Merge", source, func(tx *sqlite.Tx, now time.Time) error {
+		if err := touchTaggedTodos(ctx, tx, tr.db.QueryBuilder, now, source.ID); err != nil {
+			return err
+		}
+
+		statements := []sq.Sqlizer{
+			// Todos already carrying both tags keep their single target row
+			tr.db.QueryBuilder.Insert("todo_tags").
+				Options("OR IGNORE").
+				Columns("todo_id", "tag_id", "created_at").
+				Select(tr.db.QueryBuilder.Select().
+					Column("todo_id").
+					Column("?", target.ID).
+					Column("?", now).
+					From("todo_tags").
+					Where(sq.Eq{"tag_id": source.ID})),
+			tr.db.QueryBuilder.Delete("todo_tags").Where(sq.Eq{"tag_id": source.ID}),
+			tr.db.QueryBuilder.Delete("tags").Where(sq.Eq{"id": source.ID}),
+			tr.db.QueryBuilder.Update("tags").Set("updated_at", now).Where(sq.Eq{"id": target.ID}),
+		}
+
+		return execAll(ctx, tx, statements)
+	})
+
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	return tr.GetByUUID(ctx, target.UUID.String())
+}
+
+func (tr *TagRepository) Delete(ctx context.Context, tag domain.Tag) error {
+	return tr.inTx(ctx, "Delete", tag, func(tx *sqlite.Tx, now time.Time) error {
+		if err := touchTaggedTodos(ctx, tx, tr.db.QueryBuilder, now, tag.ID); err != nil {
+			return err
+		}
+
+		return execAll(ctx, tx, []sq.Sqlizer{
+			tr.db.QueryBuilder.Delete("todo_tags").Where(sq.Eq{"tag_id": tag.ID}),
+			tr.db.QueryBuilder.Delete("tags").Where(sq.Eq{"id": tag.ID}),
+		})
+	})
+}
+
+// inTx runs fn in a transaction with the usual span and metrics around it
+func (tr *TagRepository) inTx(ctx context.Context, operation string, tag domain.Tag, fn func(tx *sqlite.Tx, now time.Time) error) error {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, operation, "tag", map[string]interface{}{
+		"db.system":    "sqlite",
+		"db.table":     "tags",
+		"db.operation": "UPDATE",
+		"tag.uuid":     tag.UUID.String(),
+		"user.id":      tag.UserId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	err := func() error {
+		tx, err := tr.db.BeginTx(ctx, nil)
+
+		if err != nil {
+			return err
+		}
+
+		defer tx.Rollback()
+
+		if err := fn(tx, time.Now()); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	}()
+
+	if err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, operation, "tag", time.Since(startTime), err)
+		return err
+	}
+
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, operation, "tag", time.Since(startTime), nil)
+
+	return nil
+}
+
+// touchTaggedTodos bumps updated_at and the version of every todo carrying
+// the tag
+func touchTaggedTodos(ctx context.Context, db execer, builder *sq.StatementBuilderType, now time.Time, tagId int) error {
+	return execAll(ctx, db, []sq.Sqlizer{
+		builder.Update("todos").
+			Set("updated_at", now).
+			Set("version", sq.Expr("version + 1")).
+			Where(sq.Expr("id IN (SELECT todo_id FROM todo_tags WHERE tag_id = ?)", tagId)),
+	})
+}
+
+func execAll(ctx context.Context, db execer, statements []sq.Sqlizer) error {
+	for _, statement := range statements {
+		query, args, err := statement.ToSql()
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := db.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// uniqueTagError reports a clash on (user_id, name) as domain.ErrTagExists
+func uniqueTagError(err error) error {
+	var sqliteErr sqlite3.Error
+
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		return domain.ErrTagExists
+	}
+
+	return err
+}
@@ -0,0 +1,124 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "todos/pkg/test"
+
+	"todos/internal/adapter/database/sqlite/repository"
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	coretelemetry "todos/internal/core/telemetry"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/suite"
+)
+
+type TagRepositoryTestSuite struct {
+	suite.Suite
+	TagRepo  port.TagRepository
+	TodoRepo port.TodoRepository
+	User     domain.User
+}
+
+func (s *TagRepositoryTestSuite) SetupTest() {
+	db := InitTestDB()
+	telemetry := coretelemetry.NewNoOpProbe()
+
+	s.TagRepo = repository.NewTagRepository(db, telemetry)
+	s.TodoRepo = repository.NewTodoRepository(db, telemetry)
+
+	s.User, _ = repository.NewUserRepository(db, telemetry).Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Test User",
+		Email: "test@example.com",
+	})
+}
+
+func TestTagRepositoryTestSuite(t *testing.T) {
+	RegisterTestingT(t)
+	suite.Run(t, new(TagRepositoryTestSuite))
+}
+
+func (s *TagRepositoryTestSuite) createTodo(title string, tags ...string) domain.Todo {
+	created := time.Now().Add(-time.Hour)
+
+	todo, err := s.TodoRepo.Create(context.Background(), domain.Todo{
+		UUID:      uuid.New(),
+		Title:     title,
+		Tags:      tags,
+		UserId:    s.User.ID,
+		CreatedAt: created,
+		UpdatedAt: created,
+	})
+
+	Expect(err).To(BeNil())
+
+	return todo
+}
+
+func (s *TagRepositoryTestSuite) tagNamed(name string) domain.Tag {
+	tags, err := s.TagRepo.ListByUser(context.Background(), s.User.ID)
+	Expect(err).To(BeNil())
+
+	for _, tag := range tags {
+		if tag.Name == name {
+			return tag
+		}
+	}
+
+	s.T().Fatalf("tag %s not found", name)
+	return domain.Tag{}
+}
+
+func (s *TagRepositoryTestSuite) TestRepository_Rename_TouchesTodos() {
+	todo := s.createTodo("Report", "job")
+
+	renamed, err := s.TagRepo.Rename(context.Background(), s.tagNamed("job"), "work")
+
+	Expect(err).To(BeNil())
+	Expect(renamed.Name).To(Equal("work"))
+
+	reloaded, _ := s.TodoRepo.GetByUUID(context.Background(), todo.UUID.String())
+
+	Expect(reloaded.Tags).To(Equal([]string{"work"}))
+	Expect(reloaded.UpdatedAt.After(todo.UpdatedAt)).To(BeTrue())
+}
+
+func (s *TagRepositoryTestSuite) TestRepository_Rename_ConflictKeepsTags() {
+	s.createTodo("Report", "job", "work")
+
+	_, err := s.TagRepo.Rename(context.Background(), s.tagNamed("job"), "Work")
+
+	Expect(err).To(MatchError(domain.ErrTagExists))
+	Expect(s.tagNamed("job").TodoCount).To(Equal(1))
+}
+
+func (s *TagRepositoryTestSuite) TestRepository_Merge_DeduplicatesTodos() {
+	both := s.createTodo("Both", "job", "work")
+	s.createTodo("Only job", "job")
+
+	merged, err := s.TagRepo.Merge(context.Background(), s.tagNamed("job"), s.tagNamed("work"))
+
+	Expect(err).To(BeNil())
+	Expect(merged.TodoCount).To(Equal(2))
+
+	tags, _ := s.TagRepo.ListByUser(context.Background(), s.User.ID)
+	Expect(tags).To(HaveLen(1))
+
+	reloaded, _ := s.TodoRepo.GetByUUID(context.Background(), both.UUID.String())
+	Expect(reloaded.Tags).To(Equal([]string{"work"}))
+}
+
+func (s *TagRepositoryTestSuite) TestRepository_Delete_UntagsTodos() {
+	todo := s.createTodo("Report", "job")
+
+	err := s.TagRepo.Delete(context.Background(), s.tagNamed("job"))
+	Expect(err).To(BeNil())
+
+	reloaded, _ := s.TodoRepo.GetByUUID(context.Background(), todo.UUID.String())
+	Expect(reloaded.Tags).To(BeEmpty())
+}
@@ -0,0 +1,378 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattn/go-sqlite3"
+
+	"todos/internal/adapter/database/sqlite"
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	tel "todos/internal/core/telemetry"
+)
+
+// timeEntryColumns selects an entry with its todo, project and owner
+var timeEntryColumns = []string{
+	"time_entries.*",
+	"todos.uuid AS todo_uuid",
+	"todos.title AS todo_title",
+	"projects.uuid AS project_uuid",
+	"projects.name AS project_name",
+	"users.uuid AS user_uuid",
+}
+
+type TimeEntryRepository struct {
+	db        *sqlite.DB
+	scanner   *sqlite.Scanner
+	telemetry port.Telemetry
+}
+
+func NewTimeEntryRepository(db *sqlite.DB, telemetry port.Telemetry) port.TimeEntryRepository {
+	if telemetry == nil {
+		telemetry = tel.NewNoOpProbe()
+	}
+
+	return &TimeEntryRepository{
+		db:        db,
+		scanner:   sqlite.NewScanner(),
+		telemetry: telemetry,
+	}
+}
+
+func (er *TimeEntryRepository) entries() sq.SelectBuilder {
+	return er.db.QueryBuilder.Select(timeEntryColumns...).
+		From("time_entries").
+		Join("todos ON todos.id = time_entries.todo_id").
+		LeftJoin("projects ON projects.id = todos.project_id").
+		Join("users ON users.id = time_entries.user_id")
+}
+
+func (er *TimeEntryRepository) GetByUUID(ctx context.Context, uid string) (domain.TimeEntry, error) {
+	return er.get(ctx, "GetByUUID", sq.Eq{"time_entries.uuid": uid}, domain.ErrTimeEntryNotFound)
+}
+
+func (er *TimeEntryRepository) GetRunning(ctx context.Context, userId int) (domain.TimeEntry, error) {
+	return er.get(ctx, "GetRunning", sq.And{
+		sq.Eq{"time_entries.user_id": userId},
+		sq.Expr("time_entries.ended_at IS NULL"),
+	}, domain.ErrNoTimerRunning)
+}
+
+// get loads the one entry matching where, notFound when there is none
+func (er *TimeEntryRepository) get(ctx context.Context, operation string, where sq.Sqlizer, notFound error) (domain.TimeEntry, error) {
+	ctx, span := er.telemetry.StartRepositorySpan(ctx, operation, "time_entry", map[string]interface{}{
+		"db.system": "sqlite",
+		"db.table":  "time_entries",
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.TimeEntry, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		er.telemetry.RecordRepositoryOperation(ctx, operation, "time_entry", time.Since(startTime), err)
+		return domain.TimeEntry{}, err
+	}
+
+	query, args, err := er.entries().
+		Where(where).
+		Limit(1).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := er.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	var entry domain.TimeEntry
+
+	if err := er.scanner.ScanRowToStruct(rows, &entry); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fail(notFound)
+		}
+
+		return fail(fmt.Errorf("%w: %v", notFound, err))
+	}
+
+	span.SetStatus("ok", "")
+	er.telemetry.RecordRepositoryOperation(ctx, operation, "time_entry", time.Since(startTime), nil)
+
+	return entry, nil
+}
+
+// ListByTodo returns the entries of every user on a todo, latest first
+func (er *TimeEntryRepository) ListByTodo(ctx context.Context, todoId int) ([]domain.TimeEntry, error) {
+	ctx, span := er.telemetry.StartRepositorySpan(ctx, "ListByTodo", "time_entry", map[string]interface{}{
+		"db.system": "sqlite",
+		"db.table":  "time_entries",
+		"todo.id":   todoId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	entries, err := er.list(ctx, er.entries().
+		Where(sq.Eq{"time_entries.todo_id": todoId}).
+		OrderBy("time_entries.started_at DESC", "time_entries.id DESC"))
+
+	if err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		er.telemetry.RecordRepositoryOperation(ctx, "ListByTodo", "time_entry", time.Since(startTime), err)
+		return []domain.TimeEntry{}, err
+	}
+
+	span.SetAttributes(map[string]interface{}{"db.rows_returned": len(entries)})
+	span.SetStatus("ok", "")
+	er.telemetry.RecordRepositoryOperation(ctx, "ListByTodo", "time_entry", time.Since(startTime), nil)
+
+	return entries, nil
+}
+
+func (er *TimeEntryRepository) ListForReport(ctx context.Context, userId int, from time.Time, to time.Time) ([]domain.TimeEntry, error) {
+	ctx, span := er.telemetry.StartRepositorySpan(ctx, "ListForReport", "time_entry", map[string]interface{}{
+		"db.system": "sqlite",
+		"db.table":  "time_entries",
+		"user.id":   userId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) ([]domain.TimeEntry, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		er.telemetry.RecordRepositoryOperation(ctx, "ListForReport", "time_entry", time.Since(startTime), err)
+		return []domain.TimeEntry{}, err
+	}
+
+	entries, err := er.list(ctx, er.entries().
+		Where(sq.Eq{"time_entries.user_id": userId}).
+		Where("todos.deleted_at IS NULL").
+		Where(sq.Lt{"time_entries.started_at": to.UTC()}).
+		Where(sq.Or{sq.Expr("time_entries.ended_at IS NULL"), sq.Gt{"time_entries.ended_at": from.UTC()}}).
+		OrderBy("time_entries.started_at ASC", "time_entries.id ASC"))
+
+	if err != nil {
+		return fail(err)
+	}
+
+	if err := er.loadTags(ctx, entries); err != nil {
+		return fail(err)
+	}
+
+	span.SetAttributes(map[string]interface{}{"db.rows_returned": len(entries)})
+	span.SetStatus("ok", "")
+	er.telemetry.RecordRepositoryOperation(ctx, "ListForReport", "time_entry", time.Since(startTime), nil)
+
+	return entries, nil
+}
+
+func (er *TimeEntryRepository) list(ctx context.Context, query sq.SelectBuilder) ([]domain.TimeEntry, error) {
+	statement, args, err := query.ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := er.db.QueryContext(ctx, statement, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	entries := []domain.TimeEntry{}
+
+	if err := er.scanner.ScanRowsToSlice(rows, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, rows.Err()
+}
+
+// loadTags fills in the tags of each entry's todo
+func (er *TimeEntryRepository) loadTags(ctx context.Context, entries []domain.TimeEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(entries))
+
+	for _, entry := range entries {
+		ids = append(ids, entry.TodoId)
+	}
+
+	query, args, err := er.db.QueryBuilder.Select("todo_tags.todo_id", "tags.name").
+		From("todo_tags").
+		Join("tags ON tags.id = todo_tags.tag_id").
+		Where(sq.Eq{"todo_tags.todo_id": ids}).
+		OrderBy("lower(tags.name) ASC").
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := er.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	tags := map[int][]string{}
+
+	for rows.Next() {
+		var todoId int
+		var name string
+
+		if err := rows.Scan(&todoId, &name); err != nil {
+			return err
+		}
+
+		tags[todoId] = append(tags[todoId], name)
+	}
+
+	for i := range entries {
+		entries[i].Tags = tags[entries[i].TodoId]
+	}
+
+	return rows.Err()
+}
+
+func (er *TimeEntryRepository) Create(ctx context.Context, entry domain.TimeEntry) (domain.TimeEntry, error) {
+	ctx, span := er.telemetry.StartRepositorySpan(ctx, "Create", "time_entry", map[string]interface{}{
+		"db.system":       "sqlite",
+		"db.table":        "time_entries",
+		"db.operation":    "INSERT",
+		"time_entry.uuid": entry.UUID.String(),
+		"todo.id":         entry.TodoId,
+		"user.id":         entry.UserId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.TimeEntry, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		er.telemetry.RecordRepositoryOperation(ctx, "Create", "time_entry", time.Since(startTime), err)
+		return domain.TimeEntry{}, err
+	}
+
+	query, args, err := er.db.QueryBuilder.Insert("time_entries").
+		Columns("uuid", "todo_id", "user_id", "note", "started_at", "ended_at", "seconds", "created_at", "updated_at").
+		Values(entry.UUID.String(), entry.TodoId, entry.UserId, entry.Note, entry.StartedAt, entry.EndedAt, entry.Seconds, entry.CreatedAt, entry.UpdatedAt).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	if _, err := er.db.ExecContext(ctx, query, args...); err != nil {
+		var sqliteErr sqlite3.Error
+
+		// Only the running timer index can clash, uuids are fresh
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique && entry.IsRunning() {
+			return fail(domain.ErrTimerRunning)
+		}
+
+		return fail(err)
+	}
+
+	saved, err := er.GetByUUID(ctx, entry.UUID.String())
+
+	if err != nil {
+		return fail(err)
+	}
+
+	span.SetStatus("ok", "")
+	er.telemetry.RecordRepositoryOperation(ctx, "Create", "time_entry", time.Since(startTime), nil)
+
+	return saved, nil
+}
+
+func (er *TimeEntryRepository) Update(ctx context.Context, entry domain.TimeEntry) (domain.TimeEntry, error) {
+	err := er.exec(ctx, "Update", entry, er.db.QueryBuilder.Update("time_entries").
+		Set("note", entry.Note).
+		Set("started_at", entry.StartedAt).
+		Set("ended_at", entry.EndedAt).
+		Set("seconds", entry.Seconds).
+		Set("updated_at", time.Now().UTC()).
+		Where(sq.Eq{"id": entry.ID}))
+
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+
+	return er.GetByUUID(ctx, entry.UUID.String())
+}
+
+func (er *TimeEntryRepository) Delete(ctx context.Context, entry domain.TimeEntry) error {
+	return er.exec(ctx, "Delete", entry, er.db.QueryBuilder.Delete("time_entries").Where(sq.Eq{"id": entry.ID}))
+}
+
+// exec runs a write on one entry, ErrTimeEntryNotFound when it is gone
+func (er *TimeEntryRepository) exec(ctx context.Context, operation string, entry domain.TimeEntry, statement sq.Sqlizer) error {
+	ctx, span := er.telemetry.StartRepositorySpan(ctx, operation, "time_entry", map[string]interface{}{
+		"db.system":       "sqlite",
+		"db.table":        "time_entries",
+		"time_entry.uuid": entry.UUID.String(),
+		"user.id":         entry.UserId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	err := func() error {
+		query, args, err := statement.ToSql()
+
+		if err != nil {
+			return err
+		}
+
+		er.telemetry.RecordRepositoryQuery(ctx, operation, "time_entry", query, args)
+
+		result, err := er.db.ExecContext(ctx, query, args...)
+
+		if err != nil {
+			return err
+		}
+
+		if affected, err := result.RowsAffected(); err != nil {
+			return err
+		} else if affected == 0 {
+			return domain.ErrTimeEntryNotFound
+		}
+
+		return nil
+	}()
+
+	if err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		er.telemetry.RecordRepositoryOperation(ctx, operation, "time_entry", time.Since(startTime), err)
+		return err
+	}
+
+	span.SetStatus("ok", "")
+	er.telemetry.RecordRepositoryOperation(ctx, operation, "time_entry", time.Since(startTime), nil)
+
+	return nil
+}
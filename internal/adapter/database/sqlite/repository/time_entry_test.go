@@ -0,0 +1,144 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "todos/pkg/test"
+
+	"todos/internal/adapter/database/sqlite/repository"
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	coretelemetry "todos/internal/core/telemetry"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/suite"
+)
+
+type TimeEntryRepositoryTestSuite struct {
+	suite.Suite
+	TimeRepo port.TimeEntryRepository
+	TodoRepo port.TodoRepository
+	User     domain.User
+	Todo     domain.Todo
+}
+
+func (s *TimeEntryRepositoryTestSuite) SetupTest() {
+	db := InitTestDB()
+	telemetry := coretelemetry.NewNoOpProbe()
+
+	s.TimeRepo = repository.NewTimeEntryRepository(db, telemetry)
+	s.TodoRepo = repository.NewTodoRepository(db, telemetry)
+
+	s.User, _ = repository.NewUserRepository(db, telemetry).Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Test User",
+		Email: "test@example.com",
+	})
+
+	s.Todo, _ = s.TodoRepo.Create(context.Background(), domain.Todo{
+		UUID:      uuid.New(),
+		Title:     "Billable",
+		UserId:    s.User.ID,
+		Tags:      []string{"client"},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+}
+
+func TestTimeEntryRepositoryTestSuite(t *testing.T) {
+	RegisterTestingT(t)
+	suite.Run(t, new(TimeEntryRepositoryTestSuite))
+}
+
+func (s *TimeEntryRepositoryTestSuite) createEntry(start time.Time, end *time.Time) (domain.TimeEntry, error) {
+	entry := domain.TimeEntry{
+		UUID:      uuid.New(),
+		TodoId:    s.Todo.ID,
+		UserId:    s.User.ID,
+		StartedAt: start,
+		EndedAt:   end,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := entry.Validate(time.Now()); err != nil {
+		return domain.TimeEntry{}, err
+	}
+
+	return s.TimeRepo.Create(context.Background(), entry)
+}
+
+func (s *TimeEntryRepositoryTestSuite) TestRepository_Timer() {
+	ctx := context.Background()
+	start := time.Now().UTC().Add(-time.Hour)
+
+	_, err := s.TimeRepo.GetRunning(ctx, s.User.ID)
+	Expect(err).To(MatchError(domain.ErrNoTimerRunning))
+
+	running, err := s.createEntry(start, nil)
+
+	Expect(err).To(BeNil())
+	Expect(running.TodoUUID).To(Equal(s.Todo.UUID))
+	Expect(running.TodoTitle).To(Equal("Billable"))
+	Expect(running.UserUUID).To(Equal(s.User.UUID))
+	Expect(running.IsRunning()).To(BeTrue())
+
+	// The index lets a user run one timer only
+	_, err = s.createEntry(start, nil)
+	Expect(err).To(MatchError(domain.ErrTimerRunning))
+
+	found, err := s.TimeRepo.GetRunning(ctx, s.User.ID)
+
+	Expect(err).To(BeNil())
+	Expect(found.UUID).To(Equal(running.UUID))
+
+	Expect(found.Stop(start.Add(30 * time.Minute))).To(Succeed())
+
+	stopped, err := s.TimeRepo.Update(ctx, found)
+
+	Expect(err).To(BeNil())
+	Expect(stopped.Seconds).To(Equal(1800))
+	Expect(stopped.EndedAt).ToNot(BeNil())
+
+	todo, _ := s.TodoRepo.GetByUUID(ctx, s.Todo.UUID.String())
+	Expect(todo.Tracked).To(Equal(1800))
+
+	Expect(s.TimeRepo.Delete(ctx, stopped)).To(Succeed())
+	Expect(s.TimeRepo.Delete(ctx, stopped)).To(MatchError(domain.ErrTimeEntryNotFound))
+}
+
+func (s *TimeEntryRepositoryTestSuite) TestRepository_ListForReport() {
+	ctx := context.Background()
+	day := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -3)
+
+	inside := day.Add(time.Hour)
+	across := day.Add(-time.Hour)
+	before := day.Add(-3 * time.Hour)
+
+	for _, start := range []time.Time{inside, across, before} {
+		end := start.Add(90 * time.Minute)
+		_, err := s.createEntry(start, &end)
+		Expect(err).To(BeNil())
+	}
+
+	entries, err := s.TimeRepo.ListForReport(ctx, s.User.ID, day, day.AddDate(0, 0, 1))
+
+	Expect(err).To(BeNil())
+	Expect(entries).To(HaveLen(2))
+	Expect(entries[0].StartedAt.Equal(across)).To(BeTrue())
+	Expect(entries[1].StartedAt.Equal(inside)).To(BeTrue())
+	Expect(entries[0].Tags).To(Equal([]string{"client"}))
+
+	all, _ := s.TimeRepo.ListByTodo(ctx, s.Todo.ID)
+	Expect(all).To(HaveLen(3))
+	Expect(all[0].StartedAt.Equal(inside)).To(BeTrue())
+
+	// Entries of todos in the trash stay out of reports
+	Expect(s.TodoRepo.DeleteByUUID(ctx, s.Todo.UUID.String(), 0)).To(Succeed())
+
+	entries, _ = s.TimeRepo.ListForReport(ctx, s.User.ID, day, day.AddDate(0, 0, 1))
+	Expect(entries).To(BeEmpty())
+}
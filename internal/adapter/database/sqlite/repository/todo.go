@@ -2,8 +2,12 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
@@ -15,6 +19,17 @@ import (
 	"todos/internal/core/util"
 )
 
+// todoColumns selects a todo together with its checklist progress, whether
+// an open blocker holds it back and the time tracked on it
+var todoColumns = []string{
+	"todos.*",
+	"(SELECT COUNT(*) FROM todo_items WHERE todo_items.todo_id = todos.id AND todo_items.deleted_at IS NULL) AS items_total",
+	"(SELECT COUNT(*) FROM todo_items WHERE todo_items.todo_id = todos.id AND todo_items.deleted_at IS NULL AND todo_items.done = true) AS items_done",
+	"EXISTS (SELECT 1 FROM todo_dependencies JOIN todos AS blockers ON blockers.id = todo_dependencies.blocker_id WHERE todo_dependencies.todo_id = todos.id AND blockers.completed = false AND blockers.deleted_at IS NULL) AS blocked",
+	"(SELECT COALESCE(SUM(time_entries.seconds), 0) FROM time_entries WHERE time_entries.todo_id = todos.id) AS tracked_seconds",
+	"(SELECT projects.uuid FROM projects WHERE projects.id = todos.project_id) AS project_uuid",
+}
+
 type TodoRepository struct {
 	db        *sqlite.DB
 	scanner   *sqlite.Scanner
@@ -33,13 +48,18 @@ func NewTodoRepository(db *sqlite.DB, telemetry port.Telemetry) port.TodoReposit
 	}
 }
 
-func (tr *TodoRepository) GetAllWithCursor(ctx context.Context, userId int, limit int, cursor string) ([]domain.Todo, bool, error) {
+func (tr *TodoRepository) GetAllWithCursor(ctx context.Context, userId int, limit int, cursor string, filter domain.TodoFilter) ([]domain.Todo, bool, error) {
+	if filter.Sort.IsZero() {
+		filter.Sort = domain.DefaultTodoSort()
+	}
+
 	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "GetAllWithCursor", "todo", map[string]interface{}{
 		"db.system":         "sqlite",
 		"db.table":          "todos",
 		"user.id":           userId,
 		"pagination.limit":  limit,
 		"pagination.cursor": cursor,
+		"pagination.sort":   filter.Sort.String(),
 	})
 	defer span.End()
 
@@ -56,23 +76,20 @@ func (tr *TodoRepository) GetAllWithCursor(ctx context.Context, userId int, limi
 
 	actualLimit := limit + 1
 
-	query := tr.db.QueryBuilder.Select("*").
+	column := string(filter.Sort.Field)
+	direction := strings.ToUpper(filter.Sort.Direction())
+
+	query := tr.db.QueryBuilder.Select(todoColumns...).
 		From("todos").
-		Where(sq.Eq{"user_id": userId}).
+		Where(visibleTo(userId)).
 		Where("deleted_at IS NULL").
-		OrderBy("created_at DESC, id DESC").
+		OrderBy(fmt.Sprintf("%s %s, id %s", column, direction, direction)).
 		Limit(uint64(actualLimit))
 
-	if cursor != "" {
-		datetimeStr, id, err := util.DecodeCursor(cursor)
-		if err != nil {
-			span.SetStatus("error", err.Error())
-			span.RecordError(err)
-			tr.telemetry.RecordRepositoryOperation(ctx, "GetAllWithCursor", "todo", time.Since(startTime), err)
-			return []domain.Todo{}, false, err
-		}
+	query = applyTodoFilter(query, filter)
 
-		datetime, err := time.Parse(time.RFC3339, datetimeStr)
+	if cursor != "" {
+		after, err := cursorCondition(cursor, filter.Sort)
 		if err != nil {
 			span.SetStatus("error", err.Error())
 			span.RecordError(err)
@@ -80,13 +97,7 @@ func (tr *TodoRepository) GetAllWithCursor(ctx context.Context, userId int, limi
 			return []domain.Todo{}, false, err
 		}
 
-		query = query.Where(sq.Or{
-			sq.Lt{"created_at": datetime},
-			sq.And{
-				sq.Eq{"created_at": datetime},
-				sq.Lt{"id": id},
-			},
-		})
+		query = query.Where(after)
 	}
 
 	sql, args, err := query.ToSql()
@@ -126,6 +137,13 @@ func (tr *TodoRepository) GetAllWithCursor(ctx context.Context, userId int, limi
 		todos = todos[:limit]
 	}
 
+	if err := tr.loadTags(ctx, todos); err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "GetAllWithCursor", "todo", time.Since(startTime), err)
+		return []domain.Todo{}, false, err
+	}
+
 	// Update span with operation results
 	span.SetAttributes(map[string]interface{}{
 		"db.rows_returned": len(todos),
@@ -140,6 +158,141 @@ func (tr *TodoRepository) GetAllWithCursor(ctx context.Context, userId int, limi
 	return todos, hasNext, nil
 }
 
+func applyTodoFilter(query sq.SelectBuilder, filter domain.TodoFilter) sq.SelectBuilder {
+	if len(filter.Statuses) > 0 {
+		query = query.Where(sq.Eq{"status": filter.Statuses})
+	}
+
+	if filter.Completed != nil {
+		query = query.Where(sq.Eq{"completed": *filter.Completed})
+	}
+
+	if filter.CreatedAfter != nil {
+		query = query.Where(sq.Gt{"created_at": *filter.CreatedAfter})
+	}
+
+	if filter.CreatedBefore != nil {
+		query = query.Where(sq.Lt{"created_at": *filter.CreatedBefore})
+	}
+
+	if filter.UpdatedSince != nil {
+		query = query.Where(sq.GtOrEq{"updated_at": *filter.UpdatedSince})
+	}
+
+	if filter.Owner != 0 {
+		query = query.Where(sq.Eq{"todos.user_id": filter.Owner})
+	}
+
+	if filter.Due != "" {
+		query = applyDueFilter(query, filter.Due, filter.Now)
+	}
+
+	switch {
+	case filter.Project == domain.NoProject:
+		query = query.Where(sq.Eq{"project_id": nil})
+	case filter.Project != "":
+		query = query.Where("project_id = (SELECT id FROM projects WHERE uuid = ?)", filter.Project)
+	case !filter.IncludeArchived:
+		query = query.Where("(project_id IS NULL OR project_id NOT IN (SELECT id FROM projects WHERE archived_at IS NOT NULL))")
+	}
+
+	if len(filter.AnyTags) > 0 {
+		query = query.Where(taggedWith(filter.AnyTags, false))
+	}
+
+	if len(filter.AllTags) > 0 {
+		query = query.Where(taggedWith(filter.AllTags, true))
+	}
+
+	return query
+}
+
+// applyDueFilter keeps the todos whose due date falls in the requested window.
+// All-day todos are stored at midnight UTC and only become overdue the next day.
+func applyDueFilter(query sq.SelectBuilder, due domain.DueFilter, now time.Time) sq.SelectBuilder {
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	today := domain.StartOfDay(now)
+
+	switch due {
+	case domain.DueOverdue:
+		return query.
+			Where(sq.Eq{"completed": false}).
+			Where(sq.Or{
+				sq.And{sq.Eq{"all_day": false}, sq.Lt{"due_at": now.UTC()}},
+				sq.And{sq.Eq{"all_day": true}, sq.Lt{"due_at": today}},
+			})
+	case domain.DueToday:
+		return query.
+			Where(sq.GtOrEq{"due_at": today}).
+			Where(sq.Lt{"due_at": today.AddDate(0, 0, 1)})
+	case domain.DueThisWeek:
+		return query.
+			Where(sq.GtOrEq{"due_at": today}).
+			Where(sq.Lt{"due_at": domain.StartOfNextWeek(now)})
+	case domain.DueNone:
+		return query.Where(sq.Eq{"due_at": nil})
+	}
+
+	return query
+}
+
+// cursorCondition builds the keyset predicate that continues after the row
+// encoded in the cursor, using the same sort key and direction
+func cursorCondition(cursor string, sort domain.TodoSort) (sq.Sqlizer, error) {
+	data, err := util.DecodeSortCursor(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidCursor, err)
+	}
+
+	// Cursors issued before sorting existed only carry created_at
+	if data.Sort == "" {
+		data.Sort = domain.DefaultTodoSort().String()
+		data.Value = data.Datetime
+	}
+
+	if data.Sort != sort.String() {
+		return nil, fmt.Errorf("%w: cursor was issued for sort %s", domain.ErrInvalidCursor, data.Sort)
+	}
+
+	var value interface{}
+
+	switch sort.Field {
+	case domain.TodoSortCreatedAt, domain.TodoSortUpdatedAt:
+		value, err = time.Parse(time.RFC3339Nano, data.Value)
+	case domain.TodoSortPosition:
+		value, err = strconv.Atoi(data.Value)
+	default:
+		value = data.Value
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidCursor, err)
+	}
+
+	column := string(sort.Field)
+
+	if sort.Desc {
+		return sq.Or{
+			sq.Lt{column: value},
+			sq.And{
+				sq.Eq{column: value},
+				sq.Lt{"id": data.ID},
+			},
+		}, nil
+	}
+
+	return sq.Or{
+		sq.Gt{column: value},
+		sq.And{
+			sq.Eq{column: value},
+			sq.Gt{"id": data.ID},
+		},
+	}, nil
+}
+
 func (tr *TodoRepository) GetByUUID(ctx context.Context, uid string) (domain.Todo, error) {
 	startTime := time.Now()
 
@@ -151,13 +304,12 @@ func (tr *TodoRepository) GetByUUID(ctx context.Context, uid string) (domain.Tod
 	})
 	defer span.End()
 
-	query := tr.db.QueryBuilder.Select("*").
+	query, args, err := tr.db.QueryBuilder.Select(todoColumns...).
 		From("todos").
 		Where(sq.Eq{"uuid": uid}).
 		Where("deleted_at IS NULL").
-		Limit(1)
-
-	sql, args, err := query.ToSql()
+		Limit(1).
+		ToSql()
 
 	if err != nil {
 		span.SetStatus("error", err.Error())
@@ -166,7 +318,7 @@ func (tr *TodoRepository) GetByUUID(ctx context.Context, uid string) (domain.Tod
 		return domain.Todo{}, err
 	}
 
-	rows, err := tr.db.QueryContext(ctx, sql, args...)
+	rows, err := tr.db.QueryContext(ctx, query, args...)
 
 	if err != nil {
 		span.SetStatus("error", err.Error())
@@ -180,16 +332,35 @@ func (tr *TodoRepository) GetByUUID(ctx context.Context, uid string) (domain.Tod
 	var todo domain.Todo
 	err = tr.scanner.ScanRowToStruct(rows, &todo)
 
-	span.SetStatus("ok", "")
-	tr.telemetry.RecordRepositoryOperation(ctx, "GetByUUID", "todo", time.Since(startTime), nil)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Error("Error getting todo by uuid", "error", err)
+		}
+
+		err = notFoundError(err)
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "GetByUUID", "todo", time.Since(startTime), err)
+		return domain.Todo{}, err
+	}
 
-	todo.Status, _ = todo.StatusToEnum(todo.StatusOrFallback())
+	// Release the connection before loading the tags
+	rows.Close()
 
-	if err != nil {
-		slog.Error("Error getting todo by uuid", "error", err)
+	todos := []domain.Todo{todo}
+
+	if err := tr.loadTags(ctx, todos); err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "GetByUUID", "todo", time.Since(startTime), err)
 		return domain.Todo{}, err
 	}
 
+	todo = todos[0]
+
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, "GetByUUID", "todo", time.Since(startTime), nil)
+
 	return todo, nil
 }
 
@@ -217,10 +388,7 @@ func (tr *TodoRepository) Create(ctx context.Context, todo domain.Todo) (domain.
 
 	uuid := todo.UUID.String()
 
-	query, args, err := tr.db.QueryBuilder.Insert("todos").
-		Columns("uuid", "title", "description", "status", "completed", "user_id", "created_at", "updated_at").
-		Values(uuid, todo.Title, todo.Description, todo.Status, todo.Completed, todo.UserId, todo.CreatedAt, todo.UpdatedAt).
-		ToSql()
+	query, args, err := tr.insertTodo(todo).ToSql()
 
 	if err != nil {
 		span.SetStatus("error", err.Error())
@@ -233,8 +401,8 @@ func (tr *TodoRepository) Create(ctx context.Context, todo domain.Todo) (domain.
 	// Record the insert query
 	tr.telemetry.RecordRepositoryQuery(ctx, "Create", "todo", query, args)
 
-	// Execute insert
-	result, err := tr.db.ExecContext(ctx, query, args...)
+	// Execute insert, together with the tags when there are any
+	result, err := tr.execWithTags(ctx, query, args, todo.UserId, 0, todo.Tags)
 	if err != nil {
 		span.SetStatus("error", err.Error())
 		span.RecordError(err)
@@ -302,9 +470,19 @@ func (tr *TodoRepository) UpdateByUUID(ctx context.Context, todo domain.Todo) (d
 		span.SetStatus("error", err.Error())
 		span.RecordError(err)
 		tr.telemetry.RecordRepositoryOperation(ctx, "UpdateByUUID", "todo", time.Since(startTime), err)
-		return domain.Todo{}, fmt.Errorf("todo with uuid %s not found", todo.UUID)
+		return domain.Todo{}, err
+	}
+
+	if todo.Version != 0 && todo.Version != oldTodo.Version {
+		err := fmt.Errorf("%w: todo %s is at version %d, not %d", domain.ErrVersionConflict, todo.UUID, oldTodo.Version, todo.Version)
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "UpdateByUUID", "todo", time.Since(startTime), err)
+		return domain.Todo{}, err
 	}
 
+	before := oldTodo.Snapshot()
+
 	// Track what fields are being updated
 	changes := make(map[string]interface{})
 
@@ -319,14 +497,70 @@ func (tr *TodoRepository) UpdateByUUID(ctx context.Context, todo domain.Todo) (d
 		changes["description"] = todo.Description
 	}
 
-	if (todo.Completed == false || todo.Completed == true) && todo.Completed != oldTodo.Completed {
-		oldTodo.Completed = todo.Completed
-		changes["completed"] = todo.Completed
+	if todo.ClearDescription && oldTodo.Description != "" {
+		oldTodo.Description = ""
+		changes["description"] = ""
 	}
 
-	if todo.Status != 0 && todo.Status != oldTodo.Status {
+	// The service settles the lifecycle against the workflow, status,
+	// completed and completed_at only ever move together
+	if todo.Status != "" && todo.Status != oldTodo.Status {
 		oldTodo.Status = todo.Status
-		changes["status"] = todo.Status
+		oldTodo.Completed = todo.Completed
+		oldTodo.CompletedAt = todo.CompletedAt
+		changes["status"] = todo.Status.String()
+	}
+
+	if todo.DueAt != nil && (oldTodo.DueAt == nil || !todo.DueAt.Equal(*oldTodo.DueAt) || todo.AllDay != oldTodo.AllDay) {
+		oldTodo.DueAt = todo.DueAt
+		oldTodo.AllDay = todo.AllDay
+		changes["due_at"] = todo.DueAt.Format(time.RFC3339)
+		changes["all_day"] = todo.AllDay
+	}
+
+	if todo.ClearDue && oldTodo.DueAt != nil {
+		oldTodo.DueAt = nil
+		oldTodo.AllDay = false
+		changes["due_at"] = ""
+	}
+
+	if todo.Tags != nil {
+		changes["tags"] = strings.Join(todo.Tags, ",")
+	}
+
+	// An empty rule stops the repetition and forgets its skipped occurrences
+	if todo.RRule != nil {
+		oldTodo.RRule, oldTodo.RRuleStart, oldTodo.RRuleExdates = nil, nil, nil
+
+		if *todo.RRule != "" {
+			oldTodo.RRule = todo.RRule
+			oldTodo.RRuleStart = todo.RRuleStart
+		}
+
+		changes["rrule"] = *todo.RRule
+	}
+
+	if todo.RRuleExdates != nil {
+		oldTodo.RRuleExdates = todo.RRuleExdates
+		changes["rrule_exdates"] = *todo.RRuleExdates
+	}
+
+	// Moving into another project appends the todo to it
+	var moved bool
+
+	if todo.ProjectId != nil {
+		switch {
+		case *todo.ProjectId == 0:
+			oldTodo.ProjectId = nil
+			oldTodo.ProjectUUID = nil
+			oldTodo.Position = 0
+		case oldTodo.ProjectId == nil || *oldTodo.ProjectId != *todo.ProjectId:
+			moved = true
+			oldTodo.ProjectId = todo.ProjectId
+			oldTodo.ProjectUUID = todo.ProjectUUID
+		}
+
+		changes["project_id"] = *todo.ProjectId
 	}
 
 	oldTodo.UpdatedAt = time.Now()
@@ -340,9 +574,18 @@ func (tr *TodoRepository) UpdateByUUID(ctx context.Context, todo domain.Todo) (d
 	}
 	span.SetAttributes(updateAttrs)
 
+	values := oldTodo.ToMap()
+	values["version"] = sq.Expr("version + 1")
+
+	if moved {
+		values["position"] = appendTodoPosition(*oldTodo.ProjectId)
+	}
+
+	// The row is written back merged with what was read above, so it only
+	// goes through while nobody else wrote it in between
 	query, rowArgs, err := tr.db.QueryBuilder.Update("todos").
-		SetMap(oldTodo.ToMap()).
-		Where(sq.Eq{"uuid": todo.UUID}).
+		SetMap(values).
+		Where(sq.Eq{"uuid": todo.UUID, "version": oldTodo.Version}).
 		Where("deleted_at IS NULL").
 		ToSql()
 
@@ -356,8 +599,28 @@ func (tr *TodoRepository) UpdateByUUID(ctx context.Context, todo domain.Todo) (d
 	// Record the update query
 	tr.telemetry.RecordRepositoryQuery(ctx, "UpdateByUUID", "todo", query, rowArgs)
 
-	// Execute update
-	result, err := tr.db.ExecContext(ctx, query, rowArgs...)
+	if todo.Tags != nil {
+		oldTodo.Tags = todo.Tags
+	}
+
+	var revisions []sq.Sqlizer
+
+	if diff := before.Diff(oldTodo.Snapshot()); len(diff) > 0 {
+		revision, err := tr.insertRevision(oldTodo.ID, todo.EditorId, diff, before, oldTodo.UpdatedAt)
+
+		if err != nil {
+			span.SetStatus("error", err.Error())
+			span.RecordError(err)
+			tr.telemetry.RecordRepositoryOperation(ctx, "UpdateByUUID", "todo", time.Since(startTime), err)
+			return domain.Todo{}, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	// Execute update, replacing the tags and recording the revision in the
+	// same transaction
+	result, err := tr.execWithTags(ctx, query, rowArgs, oldTodo.UserId, oldTodo.ID, todo.Tags, revisions...)
 	if err != nil {
 		span.SetStatus("error", err.Error())
 		span.RecordError(err)
@@ -373,7 +636,7 @@ func (tr *TodoRepository) UpdateByUUID(ctx context.Context, todo domain.Todo) (d
 	}
 
 	if rowsAffected == 0 {
-		err := fmt.Errorf("no todo updated with uuid %s", todo.UUID)
+		err := fmt.Errorf("%w: todo %s changed while updating it", domain.ErrVersionConflict, todo.UUID)
 		span.SetStatus("error", err.Error())
 		span.RecordError(err)
 		tr.telemetry.RecordRepositoryOperation(ctx, "UpdateByUUID", "todo", time.Since(startTime), err)
@@ -404,17 +667,20 @@ func (tr *TodoRepository) UpdateByUUID(ctx context.Context, todo domain.Todo) (d
 	return updatedTodo, nil
 }
 
-func (tr *TodoRepository) DeleteByUUID(ctx context.Context, uuid string) error {
+// DeleteByUUID soft deletes the todo. A non-zero version must match the row's
+// for it to go through, a todo already in the trash is not found.
+func (tr *TodoRepository) DeleteByUUID(ctx context.Context, uuid string, version int) error {
 	startTime := time.Now()
 
 	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "DeleteByUUID", "todo", map[string]interface{}{
-		"db.system": "sqlite",
-		"db.table":  "todos",
-		"todo.uuid": uuid,
+		"db.system":    "sqlite",
+		"db.table":     "todos",
+		"todo.uuid":    uuid,
+		"todo.version": version,
 	})
 	defer span.End()
 
-	stmt, err := tr.db.PrepareContext(ctx, "UPDATE todos SET deleted_at = ? WHERE uuid = ?")
+	stmt, err := tr.db.PrepareContext(ctx, "UPDATE todos SET deleted_at = ?, version = version + 1 WHERE uuid = ? AND deleted_at IS NULL AND (? = 0 OR version = ?)")
 
 	if err != nil {
 		span.SetStatus("error", err.Error())
@@ -426,7 +692,7 @@ func (tr *TodoRepository) DeleteByUUID(ctx context.Context, uuid string) error {
 	defer stmt.Close()
 
 	now := time.Now()
-	result, err := stmt.ExecContext(ctx, now, uuid)
+	result, err := stmt.ExecContext(ctx, now, uuid, version, version)
 
 	if err != nil {
 		span.SetStatus("error", err.Error())
@@ -438,10 +704,19 @@ func (tr *TodoRepository) DeleteByUUID(ctx context.Context, uuid string) error {
 	rowsAffected, _ := result.RowsAffected()
 
 	if rowsAffected == 0 {
-		span.SetStatus("error", fmt.Errorf("todos with uuid %s not found", uuid).Error())
-		span.RecordError(fmt.Errorf("todos with uuid %s not found", uuid))
-		tr.telemetry.RecordRepositoryOperation(ctx, "DeleteByUUID", "todo", time.Since(startTime), fmt.Errorf("todos with uuid %s not found", uuid))
-		return fmt.Errorf("todos with uuid %s not found", uuid)
+		err := fmt.Errorf("%w: todos with uuid %s not found", domain.ErrTodoNotFound, uuid)
+
+		// A todo still there missed only on its version
+		if version != 0 {
+			if _, getErr := tr.GetByUUID(ctx, uuid); getErr == nil {
+				err = fmt.Errorf("%w: todo %s is no longer at version %d", domain.ErrVersionConflict, uuid, version)
+			}
+		}
+
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "DeleteByUUID", "todo", time.Since(startTime), err)
+		return err
 	}
 
 	span.SetStatus("ok", "")
@@ -449,3 +724,53 @@ func (tr *TodoRepository) DeleteByUUID(ctx context.Context, uuid string) error {
 
 	return nil
 }
+
+// todoInsertColumns are the columns todoInsertValues fills in
+var todoInsertColumns = []string{"uuid", "title", "description", "status", "completed", "completed_at", "due_at", "all_day", "project_id", "position", "user_id", "rrule", "rrule_start", "rrule_exdates", "created_at", "updated_at"}
+
+// insertTodo builds the insert for a new todo
+func (tr *TodoRepository) insertTodo(todo domain.Todo) sq.InsertBuilder {
+	return tr.db.QueryBuilder.Insert("todos").
+		Columns(todoInsertColumns...).
+		Values(todoInsertValues(todo)...)
+}
+
+// todoInsertValues is the row inserted for a new todo. Todos in a project go
+// to the bottom of it.
+func todoInsertValues(todo domain.Todo) []interface{} {
+	var position interface{} = todo.Position
+
+	if todo.ProjectId != nil && *todo.ProjectId == 0 {
+		todo.ProjectId = nil
+	}
+
+	if todo.ProjectId != nil {
+		position = appendTodoPosition(*todo.ProjectId)
+	}
+
+	if todo.Status == "" {
+		todo.Status = domain.TodoStatusPending
+	}
+
+	return []interface{}{todo.UUID.String(), todo.Title, todo.Description, todo.Status, todo.Completed, todo.CompletedAt, todo.DueAt, todo.AllDay, todo.ProjectId, position, todo.UserId, todo.RRule, todo.RRuleStart, todo.RRuleExdates, todo.CreatedAt, todo.UpdatedAt}
+}
+
+// notFoundError translates missing rows and scan failures into domain.ErrTodoNotFound
+func notFoundError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrTodoNotFound
+	}
+
+	return fmt.Errorf("%w: %v", domain.ErrTodoNotFound, err)
+}
+
+// appendTodoPosition places a todo after the last live todo of the project
+func appendTodoPosition(projectId int) sq.Sqlizer {
+	return sq.Expr("(SELECT COALESCE(MAX(position) + 1, 0) FROM todos WHERE project_id = ? AND deleted_at IS NULL)", projectId)
+}
+
+// visibleTo matches the user's personal todos and the todos of every project
+// they are a member of, like MembershipPolicy
+func visibleTo(userId int) sq.Sqlizer {
+	return sq.Expr("((todos.project_id IS NULL AND todos.user_id = ?) OR todos.project_id IN (SELECT project_id FROM project_members WHERE user_id = ?))", userId, userId)
+}
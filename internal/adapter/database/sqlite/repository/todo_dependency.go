@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+
+	"todos/internal/core/domain"
+)
+
+// dependencyReaches finds whether the second todo is among the blockers of
+// the first, directly or through blockers of blockers
+const dependencyReaches = `
+WITH RECURSIVE reachable(id) AS (
+  SELECT ?
+  UNION
+  SELECT todo_dependencies.blocker_id FROM todo_dependencies JOIN reachable ON todo_dependencies.todo_id = reachable.id
+)
+SELECT EXISTS (SELECT 1 FROM reachable WHERE id = ?)`
+
+// AddBlocker makes blocker block the todo. The check for a cycle runs in the
+// same transaction as the insert, so two requests cannot close one between
+// them. Deleted todos keep their dependencies and count for the check, they
+// may come back from the trash.
+func (tr *TodoRepository) AddBlocker(ctx context.Context, todoId int, blockerId int) error {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "AddBlocker", "todo", map[string]interface{}{
+		"db.system":       "sqlite",
+		"db.table":        "todo_dependencies",
+		"todo.id":         todoId,
+		"todo.blocker_id": blockerId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	err := func() error {
+		if todoId == blockerId {
+			return domain.ErrDependencySelf
+		}
+
+		tx, err := tr.db.BeginTx(ctx, nil)
+
+		if err != nil {
+			return err
+		}
+
+		defer tx.Rollback()
+
+		var exists bool
+
+		err = tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM todo_dependencies WHERE todo_id = ? AND blocker_id = ?)", todoId, blockerId).Scan(&exists)
+
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return domain.ErrDependencyExists
+		}
+
+		var cycle bool
+
+		tr.telemetry.RecordRepositoryQuery(ctx, "AddBlocker", "todo", dependencyReaches, []interface{}{blockerId, todoId})
+
+		if err := tx.QueryRowContext(ctx, dependencyReaches, blockerId, todoId).Scan(&cycle); err != nil {
+			return err
+		}
+
+		if cycle {
+			return domain.ErrDependencyCycle
+		}
+
+		query, args, err := tr.db.QueryBuilder.Insert("todo_dependencies").
+			Columns("todo_id", "blocker_id", "created_at").
+			Values(todoId, blockerId, time.Now().UTC()).
+			ToSql()
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	}()
+
+	if err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "AddBlocker", "todo", time.Since(startTime), err)
+		return err
+	}
+
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, "AddBlocker", "todo", time.Since(startTime), nil)
+
+	return nil
+}
+
+func (tr *TodoRepository) RemoveBlocker(ctx context.Context, todoId int, blockerId int) error {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "RemoveBlocker", "todo", map[string]interface{}{
+		"db.system":       "sqlite",
+		"db.table":        "todo_dependencies",
+		"todo.id":         todoId,
+		"todo.blocker_id": blockerId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) error {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "RemoveBlocker", "todo", time.Since(startTime), err)
+		return err
+	}
+
+	query, args, err := tr.db.QueryBuilder.Delete("todo_dependencies").
+		Where(sq.Eq{"todo_id": todoId, "blocker_id": blockerId}).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	tr.telemetry.RecordRepositoryQuery(ctx, "RemoveBlocker", "todo", query, args)
+
+	result, err := tr.db.ExecContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	if affected, err := result.RowsAffected(); err != nil {
+		return fail(err)
+	} else if affected == 0 {
+		return fail(domain.ErrDependencyNotFound)
+	}
+
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, "RemoveBlocker", "todo", time.Since(startTime), nil)
+
+	return nil
+}
+
+// ListBlockers returns the todos blocking a todo that are not in the trash
+// and the user can see, open ones first
+func (tr *TodoRepository) ListBlockers(ctx context.Context, userId int, todoId int) ([]domain.Todo, error) {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "ListBlockers", "todo", map[string]interface{}{
+		"db.system": "sqlite",
+		"db.table":  "todo_dependencies",
+		"user.id":   userId,
+		"todo.id":   todoId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	query := tr.db.QueryBuilder.Select(todoColumns...).
+		From("todos").
+		Where("todos.id IN (SELECT blocker_id FROM todo_dependencies WHERE todo_id = ?)", todoId).
+		Where("todos.deleted_at IS NULL").
+		Where(visibleTo(userId)).
+		OrderBy("todos.completed ASC", "todos.id ASC")
+
+	todos, err := tr.queryTodos(ctx, query)
+
+	if err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "ListBlockers", "todo", time.Since(startTime), err)
+		return []domain.Todo{}, err
+	}
+
+	span.SetAttributes(map[string]interface{}{
+		"db.rows_returned": len(todos),
+	})
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, "ListBlockers", "todo", time.Since(startTime), nil)
+
+	return todos, nil
+}
+
+// GetDependencyGraph loads the todos of a project and the dependencies they
+// take part in. Todos outside the project are only included when the user
+// can see them, dependencies on the others are left out.
+func (tr *TodoRepository) GetDependencyGraph(ctx context.Context, userId int, projectId int) (domain.DependencyGraph, error) {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "GetDependencyGraph", "todo", map[string]interface{}{
+		"db.system":  "sqlite",
+		"db.table":   "todo_dependencies",
+		"user.id":    userId,
+		"project.id": projectId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.DependencyGraph, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "GetDependencyGraph", "todo", time.Since(startTime), err)
+		return domain.DependencyGraph{}, err
+	}
+
+	related := sq.Expr(`todos.id IN (
+		SELECT todo_dependencies.blocker_id FROM todo_dependencies JOIN todos AS dependents ON dependents.id = todo_dependencies.todo_id WHERE dependents.project_id = ?
+		UNION
+		SELECT todo_dependencies.todo_id FROM todo_dependencies JOIN todos AS blockers ON blockers.id = todo_dependencies.blocker_id WHERE blockers.project_id = ?
+	)`, projectId, projectId)
+
+	query := tr.db.QueryBuilder.Select(todoColumns...).
+		From("todos").
+		Where("todos.deleted_at IS NULL").
+		Where(sq.Or{sq.Eq{"todos.project_id": projectId}, sq.And{related, visibleTo(userId)}}).
+		OrderByClause("todos.project_id IS ? DESC", projectId).
+		OrderBy("todos.position ASC", "todos.id ASC")
+
+	todos, err := tr.queryTodos(ctx, query)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	graph := domain.DependencyGraph{Todos: todos, Dependencies: []domain.TodoDependency{}}
+
+	if len(todos) == 0 {
+		return graph, nil
+	}
+
+	ids := make([]int, 0, len(todos))
+	uuids := make(map[int]uuid.UUID, len(todos))
+
+	for _, todo := range todos {
+		ids = append(ids, todo.ID)
+		uuids[todo.ID] = todo.UUID
+	}
+
+	edges, args, err := tr.db.QueryBuilder.Select("todo_id", "blocker_id").
+		From("todo_dependencies").
+		Where(sq.Eq{"todo_id": ids, "blocker_id": ids}).
+		OrderBy("todo_id", "blocker_id").
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	tr.telemetry.RecordRepositoryQuery(ctx, "GetDependencyGraph", "todo", edges, args)
+
+	rows, err := tr.db.QueryContext(ctx, edges, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var todoId, blockerId int
+
+		if err := rows.Scan(&todoId, &blockerId); err != nil {
+			return fail(err)
+		}
+
+		graph.Dependencies = append(graph.Dependencies, domain.TodoDependency{
+			TodoUUID:    uuids[todoId],
+			BlockerUUID: uuids[blockerId],
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return fail(err)
+	}
+
+	span.SetAttributes(map[string]interface{}{
+		"db.rows_returned": len(todos),
+		"graph.edges":      len(graph.Dependencies),
+	})
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, "GetDependencyGraph", "todo", time.Since(startTime), nil)
+
+	return graph, nil
+}
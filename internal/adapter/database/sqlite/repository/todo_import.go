@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"todos/internal/core/domain"
+)
+
+// createManyBatchSize is how many todos go into one INSERT, well below the
+// limit SQLite puts on the parameters of a statement
+const createManyBatchSize = 200
+
+// CreateMany inserts the todos and their tags in one transaction, a batch of
+// rows per statement. Either all of them are created or none.
+func (tr *TodoRepository) CreateMany(ctx context.Context, todos []domain.Todo) error {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "CreateMany", "todo", map[string]interface{}{
+		"db.system":    "sqlite",
+		"db.table":     "todos",
+		"db.operation": "INSERT",
+		"todo.count":   len(todos),
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	err := func() error {
+		tx, err := tr.db.BeginTx(ctx, nil)
+
+		if err != nil {
+			return err
+		}
+
+		defer tx.Rollback()
+
+		for start := 0; start < len(todos); start += createManyBatchSize {
+			batch := todos[start:min(start+createManyBatchSize, len(todos))]
+
+			insert := tr.db.QueryBuilder.Insert("todos").
+				Columns(todoInsertColumns...).
+				Suffix("RETURNING id, uuid")
+
+			for _, todo := range batch {
+				insert = insert.Values(todoInsertValues(todo)...)
+			}
+
+			query, args, err := insert.ToSql()
+
+			if err != nil {
+				return err
+			}
+
+			tr.telemetry.RecordRepositoryQuery(ctx, "CreateMany", "todo", query, args)
+
+			// RETURNING lists the rows in no particular order
+			ids, err := func() (map[string]int, error) {
+				rows, err := tx.QueryContext(ctx, query, args...)
+
+				if err != nil {
+					return nil, err
+				}
+
+				defer rows.Close()
+
+				ids := make(map[string]int, len(batch))
+
+				for rows.Next() {
+					var id int
+					var uid string
+
+					if err := rows.Scan(&id, &uid); err != nil {
+						return nil, err
+					}
+
+					ids[uid] = id
+				}
+
+				return ids, rows.Err()
+			}()
+
+			if err != nil {
+				return err
+			}
+
+			for _, todo := range batch {
+				if len(todo.Tags) == 0 {
+					continue
+				}
+
+				id, ok := ids[todo.UUID.String()]
+
+				if !ok {
+					return fmt.Errorf("todo %s was not inserted", todo.UUID)
+				}
+
+				if err := replaceTodoTags(ctx, tx, tr.db.QueryBuilder, todo.UserId, id, todo.Tags); err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.Commit()
+	}()
+
+	if err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "CreateMany", "todo", time.Since(startTime), err)
+		return err
+	}
+
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, "CreateMany", "todo", time.Since(startTime), nil)
+
+	return nil
+}
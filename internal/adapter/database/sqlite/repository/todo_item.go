@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"todos/internal/adapter/database/sqlite"
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	tel "todos/internal/core/telemetry"
+)
+
+type TodoItemRepository struct {
+	db        *sqlite.DB
+	scanner   *sqlite.Scanner
+	telemetry port.Telemetry
+}
+
+func NewTodoItemRepository(db *sqlite.DB, telemetry port.Telemetry) port.TodoItemRepository {
+	if telemetry == nil {
+		telemetry = tel.NewNoOpProbe()
+	}
+
+	return &TodoItemRepository{
+		db:        db,
+		scanner:   sqlite.NewScanner(),
+		telemetry: telemetry,
+	}
+}
+
+func (ir *TodoItemRepository) ListByTodo(ctx context.Context, todoId int) ([]domain.TodoItem, error) {
+	ctx, span := ir.telemetry.StartRepositorySpan(ctx, "ListByTodo", "todo_item", map[string]interface{}{
+		"db.system": "sqlite",
+		"db.table":  "todo_items",
+		"todo.id":   todoId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) ([]domain.TodoItem, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		ir.telemetry.RecordRepositoryOperation(ctx, "ListByTodo", "todo_item", time.Since(startTime), err)
+		return []domain.TodoItem{}, err
+	}
+
+	query, args, err := ir.db.QueryBuilder.Select("*").
+		From("todo_items").
+		Where(sq.Eq{"todo_id": todoId}).
+		Where("deleted_at IS NULL").
+		OrderBy("position ASC", "id ASC").
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := ir.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	items := []domain.TodoItem{}
+
+	if err := ir.scanner.ScanRowsToSlice(rows, &items); err != nil {
+		return fail(err)
+	}
+
+	span.SetAttributes(map[string]interface{}{"db.rows_returned": len(items)})
+	span.SetStatus("ok", "")
+	ir.telemetry.RecordRepositoryOperation(ctx, "ListByTodo", "todo_item", time.Since(startTime), nil)
+
+	return items, nil
+}
+
+func (ir *TodoItemRepository) GetByUUID(ctx context.Context, todoId int, uid string) (domain.TodoItem, error) {
+	ctx, span := ir.telemetry.StartRepositorySpan(ctx, "GetByUUID", "todo_item", map[string]interface{}{
+		"db.system": "sqlite",
+		"db.table":  "todo_items",
+		"todo.id":   todoId,
+		"item.uuid": uid,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.TodoItem, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		ir.telemetry.RecordRepositoryOperation(ctx, "GetByUUID", "todo_item", time.Since(startTime), err)
+		return domain.TodoItem{}, err
+	}
+
+	query, args, err := ir.db.QueryBuilder.Select("*").
+		From("todo_items").
+		Where(sq.Eq{"todo_id": todoId, "uuid": uid}).
+		Where("deleted_at IS NULL").
+		Limit(1).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := ir.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	var item domain.TodoItem
+
+	if err := ir.scanner.ScanRowToStruct(rows, &item); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fail(domain.ErrTodoItemNotFound)
+		}
+
+		return fail(fmt.Errorf("%w: %v", domain.ErrTodoItemNotFound, err))
+	}
+
+	span.SetStatus("ok", "")
+	ir.telemetry.RecordRepositoryOperation(ctx, "GetByUUID", "todo_item", time.Since(startTime), nil)
+
+	return item, nil
+}
+
+func (ir *TodoItemRepository) Create(ctx context.Context, item domain.TodoItem) (domain.TodoItem, error) {
+	ctx, span := ir.telemetry.StartRepositorySpan(ctx, "Create", "todo_item", map[string]interface{}{
+		"db.system":    "sqlite",
+		"db.table":     "todo_items",
+		"db.operation": "INSERT",
+		"todo.id":      item.TodoId,
+		"item.uuid":    item.UUID.String(),
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.TodoItem, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		ir.telemetry.RecordRepositoryOperation(ctx, "Create", "todo_item", time.Since(startTime), err)
+		return domain.TodoItem{}, err
+	}
+
+	var position interface{} = item.Position
+
+	if item.Position == domain.TodoItemAppend {
+		position = sq.Expr("(SELECT COALESCE(MAX(position) + 1, 0) FROM todo_items WHERE todo_id = ? AND deleted_at IS NULL)", item.TodoId)
+	}
+
+	query, args, err := ir.db.QueryBuilder.Insert("todo_items").
+		Columns("uuid", "todo_id", "title", "done", "position", "created_at", "updated_at").
+		Values(item.UUID.String(), item.TodoId, item.Title, item.Done, position, item.CreatedAt, item.UpdatedAt).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	ir.telemetry.RecordRepositoryQuery(ctx, "Create", "todo_item", query, args)
+
+	if _, err := ir.db.ExecContext(ctx, query, args...); err != nil {
+		return fail(err)
+	}
+
+	saved, err := ir.GetByUUID(ctx, item.TodoId, item.UUID.String())
+
+	if err != nil {
+		return fail(err)
+	}
+
+	span.SetStatus("ok", "")
+	ir.telemetry.RecordRepositoryOperation(ctx, "Create", "todo_item", time.Since(startTime), nil)
+
+	return saved, nil
+}
+
+func (ir *TodoItemRepository) UpdateByUUID(ctx context.Context, item domain.TodoItem) (domain.TodoItem, error) {
+	ctx, span := ir.telemetry.StartRepositorySpan(ctx, "UpdateByUUID", "todo_item", map[string]interface{}{
+		"db.system":    "sqlite",
+		"db.table":     "todo_items",
+		"db.operation": "UPDATE",
+		"todo.id":      item.TodoId,
+		"item.uuid":    item.UUID.String(),
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.TodoItem, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		ir.telemetry.RecordRepositoryOperation(ctx, "UpdateByUUID", "todo_item", time.Since(startTime), err)
+		return domain.TodoItem{}, err
+	}
+
+	query, args, err := ir.db.QueryBuilder.Update("todo_items").
+		SetMap(item.ToMap()).
+		Where(sq.Eq{"todo_id": item.TodoId, "uuid": item.UUID.String()}).
+		Where("deleted_at IS NULL").
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	ir.telemetry.RecordRepositoryQuery(ctx, "UpdateByUUID", "todo_item", query, args)
+
+	result, err := ir.db.ExecContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fail(domain.ErrTodoItemNotFound)
+	}
+
+	updated, err := ir.GetByUUID(ctx, item.TodoId, item.UUID.String())
+
+	if err != nil {
+		return fail(err)
+	}
+
+	span.SetStatus("ok", "")
+	ir.telemetry.RecordRepositoryOperation(ctx, "UpdateByUUID", "todo_item", time.Since(startTime), nil)
+
+	return updated, nil
+}
+
+// DeleteByUUID soft deletes the item, mirroring TodoRepository.DeleteByUUID
+func (ir *TodoItemRepository) DeleteByUUID(ctx context.Context, todoId int, uid string) error {
+	ctx, span := ir.telemetry.StartRepositorySpan(ctx, "DeleteByUUID", "todo_item", map[string]interface{}{
+		"db.system":    "sqlite",
+		"db.table":     "todo_items",
+		"db.operation": "UPDATE",
+		"todo.id":      todoId,
+		"item.uuid":    uid,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) error {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		ir.telemetry.RecordRepositoryOperation(ctx, "DeleteByUUID", "todo_item", time.Since(startTime), err)
+		return err
+	}
+
+	query, args, err := ir.db.QueryBuilder.Update("todo_items").
+		Set("deleted_at", time.Now()).
+		Where(sq.Eq{"todo_id": todoId, "uuid": uid}).
+		Where("deleted_at IS NULL").
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	result, err := ir.db.ExecContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fail(fmt.Errorf("%w: item with uuid %s not found", domain.ErrTodoItemNotFound, uid))
+	}
+
+	span.SetStatus("ok", "")
+	ir.telemetry.RecordRepositoryOperation(ctx, "DeleteByUUID", "todo_item", time.Since(startTime), nil)
+
+	return nil
+}
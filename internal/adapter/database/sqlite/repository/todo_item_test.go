@@ -0,0 +1,117 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "todos/pkg/test"
+
+	"todos/internal/adapter/database/sqlite/repository"
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	coretelemetry "todos/internal/core/telemetry"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/suite"
+)
+
+type TodoItemRepositoryTestSuite struct {
+	suite.Suite
+	ItemRepo port.TodoItemRepository
+	TodoRepo port.TodoRepository
+	Todo     domain.Todo
+}
+
+func (s *TodoItemRepositoryTestSuite) SetupTest() {
+	db := InitTestDB()
+	telemetry := coretelemetry.NewNoOpProbe()
+
+	s.ItemRepo = repository.NewTodoItemRepository(db, telemetry)
+	s.TodoRepo = repository.NewTodoRepository(db, telemetry)
+
+	user, _ := repository.NewUserRepository(db, telemetry).Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Test User",
+		Email: "test@example.com",
+	})
+
+	s.Todo, _ = s.TodoRepo.Create(context.Background(), domain.Todo{
+		UUID:      uuid.New(),
+		Title:     "Checklist",
+		UserId:    user.ID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+}
+
+func TestTodoItemRepositoryTestSuite(t *testing.T) {
+	RegisterTestingT(t)
+	suite.Run(t, new(TodoItemRepositoryTestSuite))
+}
+
+func (s *TodoItemRepositoryTestSuite) createItem(title string, done bool) domain.TodoItem {
+	item, err := s.ItemRepo.Create(context.Background(), domain.TodoItem{
+		UUID:      uuid.New(),
+		TodoId:    s.Todo.ID,
+		Title:     title,
+		Done:      done,
+		Position:  domain.TodoItemAppend,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+
+	Expect(err).To(BeNil())
+
+	return item
+}
+
+func (s *TodoItemRepositoryTestSuite) TestRepository_Create_AppendsPosition() {
+	first := s.createItem("First", false)
+	second := s.createItem("Second", false)
+
+	Expect(first.Position).To(Equal(0))
+	Expect(second.Position).To(Equal(1))
+
+	first.Position = 5
+	_, err := s.ItemRepo.UpdateByUUID(context.Background(), first)
+	Expect(err).To(BeNil())
+
+	items, err := s.ItemRepo.ListByTodo(context.Background(), s.Todo.ID)
+
+	Expect(err).To(BeNil())
+	Expect(items[0].Title).To(Equal("Second"))
+	Expect(items[1].Title).To(Equal("First"))
+}
+
+func (s *TodoItemRepositoryTestSuite) TestRepository_TodoProgressIgnoresDeletedItems() {
+	s.createItem("Done", true)
+	open := s.createItem("Open", false)
+
+	todo, err := s.TodoRepo.GetByUUID(context.Background(), s.Todo.UUID.String())
+
+	Expect(err).To(BeNil())
+	Expect(todo.ItemsTotal).To(Equal(2))
+	Expect(todo.ItemsDone).To(Equal(1))
+	Expect(todo.IsChecklistDone()).To(BeFalse())
+
+	err = s.ItemRepo.DeleteByUUID(context.Background(), s.Todo.ID, open.UUID.String())
+	Expect(err).To(BeNil())
+
+	todo, _ = s.TodoRepo.GetByUUID(context.Background(), s.Todo.UUID.String())
+
+	Expect(todo.ItemsTotal).To(Equal(1))
+	Expect(todo.IsChecklistDone()).To(BeTrue())
+
+	_, err = s.ItemRepo.GetByUUID(context.Background(), s.Todo.ID, open.UUID.String())
+	Expect(err).To(MatchError(domain.ErrTodoItemNotFound))
+}
+
+func (s *TodoItemRepositoryTestSuite) TestRepository_GetByUUID_ScopedToTodo() {
+	item := s.createItem("Scoped", false)
+
+	_, err := s.ItemRepo.GetByUUID(context.Background(), s.Todo.ID+1, item.UUID.String())
+
+	Expect(err).To(MatchError(domain.ErrTodoItemNotFound))
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"todos/internal/adapter/database/sqlite"
+	"todos/internal/core/domain"
+)
+
+// CreateNextOccurrence inserts the todo following a completed occurrence and
+// links the two in one transaction. The link is only set while empty, so two
+// racing completions spawn a single occurrence.
+func (tr *TodoRepository) CreateNextOccurrence(ctx context.Context, previous domain.Todo, next domain.Todo) (domain.Todo, error) {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "CreateNextOccurrence", "todo", map[string]interface{}{
+		"db.system":     "sqlite",
+		"db.table":      "todos",
+		"db.operation":  "INSERT",
+		"todo.uuid":     next.UUID.String(),
+		"todo.previous": previous.UUID.String(),
+		"user.id":       next.UserId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.Todo, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "CreateNextOccurrence", "todo", time.Since(startTime), err)
+		return domain.Todo{}, err
+	}
+
+	err := func() error {
+		tx, err := tr.db.BeginTx(ctx, nil)
+
+		if err != nil {
+			return err
+		}
+
+		defer tx.Rollback()
+
+		id, err := insertReturningId(ctx, tx, tr.insertTodo(next))
+
+		if err != nil {
+			return err
+		}
+
+		if next.Tags != nil {
+			if err := replaceTodoTags(ctx, tx, tr.db.QueryBuilder, next.UserId, id, next.Tags); err != nil {
+				return err
+			}
+		}
+
+		query, args, err := tr.db.QueryBuilder.Update("todos").
+			Set("next_occurrence_id", id).
+			Where(sq.Eq{"id": previous.ID}).
+			Where("next_occurrence_id IS NULL").
+			ToSql()
+
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, query, args...)
+
+		if err != nil {
+			return err
+		}
+
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			if err == nil {
+				err = domain.ErrOccurrenceExists
+			}
+
+			return err
+		}
+
+		return tx.Commit()
+	}()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	saved, err := tr.GetByUUID(ctx, next.UUID.String())
+
+	if err != nil {
+		return fail(err)
+	}
+
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, "CreateNextOccurrence", "todo", time.Since(startTime), nil)
+
+	return saved, nil
+}
+
+func insertReturningId(ctx context.Context, tx *sqlite.Tx, insert sq.InsertBuilder) (int, error) {
+	query, args, err := insert.ToSql()
+
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(ctx, query, args...)
+
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+
+	return int(id), err
+}
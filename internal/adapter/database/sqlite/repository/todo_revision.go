@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/util"
+)
+
+// todoRevisionRow holds a revision as stored, changes and snapshot as JSON
+type todoRevisionRow struct {
+	ID        int
+	UUID      uuid.UUID
+	TodoId    int
+	Number    int
+	UserId    *int
+	UserUUID  *uuid.UUID `db:"user_uuid"`
+	UserName  *string    `db:"user_name"`
+	Changes   string
+	Snapshot  string
+	CreatedAt time.Time
+}
+
+func (row todoRevisionRow) revision() (domain.TodoRevision, error) {
+	revision := domain.TodoRevision{
+		ID:        row.ID,
+		UUID:      row.UUID,
+		TodoId:    row.TodoId,
+		Number:    row.Number,
+		UserId:    row.UserId,
+		UserUUID:  row.UserUUID,
+		UserName:  row.UserName,
+		CreatedAt: row.CreatedAt,
+	}
+
+	if err := json.Unmarshal([]byte(row.Changes), &revision.Changes); err != nil {
+		return domain.TodoRevision{}, fmt.Errorf("revision %d changes: %w", row.Number, err)
+	}
+
+	if err := json.Unmarshal([]byte(row.Snapshot), &revision.Snapshot); err != nil {
+		return domain.TodoRevision{}, fmt.Errorf("revision %d snapshot: %w", row.Number, err)
+	}
+
+	return revision, nil
+}
+
+func (tr *TodoRepository) revisions(todoId int) sq.SelectBuilder {
+	return tr.db.QueryBuilder.Select("todo_revisions.*", "users.uuid AS user_uuid", "users.name AS user_name").
+		From("todo_revisions").
+		LeftJoin("users ON users.id = todo_revisions.user_id").
+		Where(sq.Eq{"todo_revisions.todo_id": todoId})
+}
+
+// ListRevisions pages through the revisions of a todo, newest first
+func (tr *TodoRepository) ListRevisions(ctx context.Context, todoId int, limit int, cursor string) ([]domain.TodoRevision, bool, error) {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "ListRevisions", "todo", map[string]interface{}{
+		"db.system":         "sqlite",
+		"db.table":          "todo_revisions",
+		"todo.id":           todoId,
+		"pagination.limit":  limit,
+		"pagination.cursor": cursor,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) ([]domain.TodoRevision, bool, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "ListRevisions", "todo", time.Since(startTime), err)
+		return []domain.TodoRevision{}, false, err
+	}
+
+	query := tr.revisions(todoId).
+		OrderBy("todo_revisions.number DESC").
+		Limit(uint64(limit + 1))
+
+	if cursor != "" {
+		data, err := util.DecodeSortCursor(cursor)
+
+		if err != nil {
+			return fail(fmt.Errorf("%w: %v", domain.ErrInvalidCursor, err))
+		}
+
+		if data.Sort != domain.RevisionCursorSort {
+			return fail(fmt.Errorf("%w: cursor was not issued for revisions", domain.ErrInvalidCursor))
+		}
+
+		query = query.Where(sq.Lt{"todo_revisions.number": data.ID})
+	}
+
+	revisions, err := tr.queryRevisions(ctx, query)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	hasNext := len(revisions) > limit
+
+	if hasNext {
+		revisions = revisions[:limit]
+	}
+
+	span.SetAttributes(map[string]interface{}{
+		"db.rows_returned": len(revisions),
+		"db.has_next":      hasNext,
+	})
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, "ListRevisions", "todo", time.Since(startTime), nil)
+
+	return revisions, hasNext, nil
+}
+
+func (tr *TodoRepository) GetRevision(ctx context.Context, todoId int, number int) (domain.TodoRevision, error) {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "GetRevision", "todo", map[string]interface{}{
+		"db.system":       "sqlite",
+		"db.table":        "todo_revisions",
+		"todo.id":         todoId,
+		"revision.number": number,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.TodoRevision, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "GetRevision", "todo", time.Since(startTime), err)
+		return domain.TodoRevision{}, err
+	}
+
+	revisions, err := tr.queryRevisions(ctx, tr.revisions(todoId).Where(sq.Eq{"todo_revisions.number": number}).Limit(1))
+
+	if err != nil {
+		return fail(err)
+	}
+
+	if len(revisions) == 0 {
+		return fail(domain.ErrRevisionNotFound)
+	}
+
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, "GetRevision", "todo", time.Since(startTime), nil)
+
+	return revisions[0], nil
+}
+
+func (tr *TodoRepository) queryRevisions(ctx context.Context, query sq.SelectBuilder) ([]domain.TodoRevision, error) {
+	statement, args, err := query.ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tr.db.QueryContext(ctx, statement, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	stored := []todoRevisionRow{}
+
+	if err := tr.scanner.ScanRowsToSlice(rows, &stored); err != nil {
+		return nil, err
+	}
+
+	revisions := make([]domain.TodoRevision, 0, len(stored))
+
+	for _, row := range stored {
+		revision, err := row.revision()
+
+		if err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
+}
+
+// insertRevision builds the statement recording an update, numbered after
+// the todo's last revision. It runs in the update's transaction, which keeps
+// the numbers gapless.
+func (tr *TodoRepository) insertRevision(todoId int, editorId int, changes map[string]domain.FieldChange, snapshot domain.TodoSnapshot, now time.Time) (sq.Sqlizer, error) {
+	changesJSON, err := json.Marshal(changes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotJSON, err := json.Marshal(snapshot)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var userId interface{}
+
+	if editorId != 0 {
+		userId = editorId
+	}
+
+	return tr.db.QueryBuilder.Insert("todo_revisions").
+		Columns("uuid", "todo_id", "number", "user_id", "changes", "snapshot", "created_at").
+		Values(
+			uuid.New().String(),
+			todoId,
+			sq.Expr("(SELECT COALESCE(MAX(number), 0) + 1 FROM todo_revisions WHERE todo_id = ?)", todoId),
+			userId,
+			string(changesJSON),
+			string(snapshotJSON),
+			now,
+		), nil
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/util"
+)
+
+// FTS5 marks matches with these control characters rather than <mark> tags,
+// so the text around them can be HTML-escaped before the tags go in
+const (
+	highlightOpen  = "\x02"
+	highlightClose = "\x03"
+)
+
+var highlightMarkup = strings.NewReplacer(highlightOpen, "<mark>", highlightClose, "</mark>")
+
+type todoSearchHit struct {
+	id                   int
+	rank                 float64
+	titleHighlight       string
+	descriptionHighlight string
+}
+
+// Search pages on the bm25 rank of the last hit. That score depends on every
+// row in the index, so writes between two page requests can shift ranks and
+// make a page skip or repeat a todo; the cursor is a best-effort position.
+func (tr *TodoRepository) Search(ctx context.Context, userId int, text string, limit int, cursor string) ([]domain.TodoSearchResult, bool, error) {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "Search", "todo", map[string]interface{}{
+		"db.system":         "sqlite",
+		"db.table":          "todos_fts",
+		"user.id":           userId,
+		"pagination.limit":  limit,
+		"pagination.cursor": cursor,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) ([]domain.TodoSearchResult, bool, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "Search", "todo", time.Since(startTime), err)
+		return []domain.TodoSearchResult{}, false, err
+	}
+
+	terms := domain.SearchTerms(text)
+
+	if len(terms) == 0 {
+		return fail(domain.ErrEmptySearchQuery)
+	}
+
+	// Every word matches as a prefix, so "gro" finds "groceries"
+	for i, term := range terms {
+		terms[i] = term + "*"
+	}
+
+	// rank is bm25 with the weights set up by the migration, lower is better
+	builder := tr.db.QueryBuilder.Select(
+		"todos.id",
+		"todos_fts.rank",
+		"highlight(todos_fts, 0, char(2), char(3))",
+		"snippet(todos_fts, 1, char(2), char(3), '…', 16)",
+	).
+		From("todos_fts").
+		Join("todos ON todos.id = todos_fts.rowid").
+		Where("todos_fts MATCH ?", strings.Join(terms, " ")).
+		Where(visibleTo(userId)).
+		Where("todos.deleted_at IS NULL")
+
+	if cursor != "" {
+		rank, id, err := decodeSearchCursor(cursor)
+
+		if err != nil {
+			return fail(err)
+		}
+
+		builder = builder.Where("(todos_fts.rank > ? OR (todos_fts.rank = ? AND todos.id < ?))", rank, rank, id)
+	}
+
+	query, args, err := builder.
+		OrderBy("todos_fts.rank", "todos.id DESC").
+		Limit(uint64(limit + 1)).
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	tr.telemetry.RecordRepositoryQuery(ctx, "Search", "todo", query, args)
+
+	hits, err := tr.searchHits(ctx, query, args)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	hasNext := len(hits) > limit
+	if hasNext {
+		hits = hits[:limit]
+	}
+
+	todos, err := tr.getByIDs(ctx, hits)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	results := make([]domain.TodoSearchResult, 0, len(hits))
+
+	for _, hit := range hits {
+		todo, ok := todos[hit.id]
+
+		if !ok {
+			continue
+		}
+
+		results = append(results, domain.TodoSearchResult{
+			Todo:                 todo,
+			Rank:                 hit.rank,
+			TitleHighlight:       hit.titleHighlight,
+			DescriptionHighlight: hit.descriptionHighlight,
+		})
+	}
+
+	span.SetAttributes(map[string]interface{}{
+		"db.rows_returned": len(results),
+		"db.has_next":      hasNext,
+	})
+
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, "Search", "todo", time.Since(startTime), nil)
+
+	return results, hasNext, nil
+}
+
+func (tr *TodoRepository) searchHits(ctx context.Context, query string, args []interface{}) ([]todoSearchHit, error) {
+	rows, err := tr.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	hits := []todoSearchHit{}
+
+	for rows.Next() {
+		var hit todoSearchHit
+		var title, description *string
+
+		if err := rows.Scan(&hit.id, &hit.rank, &title, &description); err != nil {
+			return nil, err
+		}
+
+		if title != nil {
+			hit.titleHighlight = markHighlight(*title)
+		}
+
+		if description != nil {
+			hit.descriptionHighlight = markHighlight(*description)
+		}
+
+		// Results carry a score where higher is better
+		hit.rank = -hit.rank
+		hits = append(hits, hit)
+	}
+
+	return hits, rows.Err()
+}
+
+func (tr *TodoRepository) getByIDs(ctx context.Context, hits []todoSearchHit) (map[int]domain.Todo, error) {
+	result := make(map[int]domain.Todo, len(hits))
+
+	if len(hits) == 0 {
+		return result, nil
+	}
+
+	ids := make([]int, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.id)
+	}
+
+	query, args, err := tr.db.QueryBuilder.Select(todoColumns...).
+		From("todos").
+		Where(sq.Eq{"id": ids}).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tr.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var todos []domain.Todo
+
+	if err := tr.scanner.ScanRowsToSlice(rows, &todos); err != nil {
+		return nil, err
+	}
+
+	if err := tr.loadTags(ctx, todos); err != nil {
+		return nil, err
+	}
+
+	for _, todo := range todos {
+		result[todo.ID] = todo
+	}
+
+	return result, nil
+}
+
+// markHighlight escapes the stored text so clients can render the <mark> tags
+// it adds around each match without rendering anything a user typed
+func markHighlight(text string) string {
+	return highlightMarkup.Replace(html.EscapeString(text))
+}
+
+// decodeSearchCursor reads the bm25 rank and id of the last hit a page ended
+// on. Ranks move as the index changes, see Search.
+func decodeSearchCursor(cursor string) (float64, int, error) {
+	data, err := util.DecodeSortCursor(cursor)
+
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %v", domain.ErrInvalidCursor, err)
+	}
+
+	if data.Sort != domain.TodoSearchSort {
+		return 0, 0, fmt.Errorf("%w: cursor was issued for sort %s", domain.ErrInvalidCursor, data.Sort)
+	}
+
+	score, err := strconv.ParseFloat(data.Value, 64)
+
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %v", domain.ErrInvalidCursor, err)
+	}
+
+	return -score, data.ID, nil
+}
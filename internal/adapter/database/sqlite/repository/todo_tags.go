@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+
+	"todos/internal/core/domain"
+)
+
+// execer is satisfied by both *sqlite.DB and *sqlite.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// taggedWith matches todos carrying any (or, with all set, every) of the tags
+func taggedWith(names []string, all bool) sq.Sqlizer {
+	seen := make(map[string]bool, len(names))
+	lowered := make([]string, 0, len(names))
+
+	for _, name := range names {
+		name = strings.ToLower(name)
+
+		if !seen[name] {
+			seen[name] = true
+			lowered = append(lowered, name)
+		}
+	}
+
+	tagged := sq.Select("todo_tags.todo_id").
+		From("todo_tags").
+		Join("tags ON tags.id = todo_tags.tag_id").
+		Where(sq.Eq{"lower(tags.name)": lowered})
+
+	if all {
+		tagged = tagged.
+			GroupBy("todo_tags.todo_id").
+			Having("COUNT(DISTINCT lower(tags.name)) = ?", len(lowered))
+	}
+
+	sql, args, err := tagged.ToSql()
+
+	if err != nil {
+		return sq.Expr("0")
+	}
+
+	return sq.Expr("todos.id IN ("+sql+")", args...)
+}
+
+// loadTags fills Tags on every todo with a single query
+func (tr *TodoRepository) loadTags(ctx context.Context, todos []domain.Todo) error {
+	if len(todos) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(todos))
+	for _, todo := range todos {
+		ids = append(ids, todo.ID)
+	}
+
+	query, args, err := tr.db.QueryBuilder.Select("todo_tags.todo_id", "tags.name").
+		From("todo_tags").
+		Join("tags ON tags.id = todo_tags.tag_id").
+		Where(sq.Eq{"todo_tags.todo_id": ids}).
+		OrderBy("lower(tags.name) ASC").
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := tr.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	tags := make(map[int][]string, len(todos))
+
+	for rows.Next() {
+		var todoId int
+		var name string
+
+		if err := rows.Scan(&todoId, &name); err != nil {
+			return err
+		}
+
+		tags[todoId] = append(tags[todoId], name)
+	}
+
+	for i := range todos {
+		todos[i].Tags = tags[todos[i].ID]
+
+		if todos[i].Tags == nil {
+			todos[i].Tags = []string{}
+		}
+	}
+
+	return rows.Err()
+}
+
+// execWithTags runs a todo write and, when tags is not nil, replaces the todo's
+// tags in the same transaction. todoId 0 means the write is the todo insert.
+// The extra statements run last in the same transaction.
+func (tr *TodoRepository) execWithTags(ctx context.Context, query string, args []interface{}, userId int, todoId int, tags []string, extra ...sq.Sqlizer) (sql.Result, error) {
+	if tags == nil && len(extra) == 0 {
+		return tr.db.ExecContext(ctx, query, args...)
+	}
+
+	tx, err := tr.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return result, err
+	}
+
+	if todoId == 0 {
+		id, err := result.LastInsertId()
+
+		if err != nil {
+			return nil, err
+		}
+
+		todoId = int(id)
+	}
+
+	if tags != nil {
+		if err := replaceTodoTags(ctx, tx, tr.db.QueryBuilder, userId, todoId, tags); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := execAll(ctx, tx, extra); err != nil {
+		return nil, err
+	}
+
+	return result, tx.Commit()
+}
+
+// replaceTodoTags points the todo at exactly the named tags, creating the
+// missing ones for the user
+func replaceTodoTags(ctx context.Context, db execer, builder *sq.StatementBuilderType, userId int, todoId int, names []string) error {
+	now := time.Now()
+
+	query, args, err := builder.Delete("todo_tags").Where(sq.Eq{"todo_id": todoId}).ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		query, args, err := builder.Insert("tags").
+			Options("OR IGNORE").
+			Columns("uuid", "user_id", "name", "created_at", "updated_at").
+			Values(uuid.New().String(), userId, name, now, now).
+			ToSql()
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := db.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		query, args, err = builder.Insert("todo_tags").
+			Options("OR IGNORE").
+			Columns("todo_id", "tag_id", "created_at").
+			Select(builder.Select().
+				Column("?", todoId).
+				Column("id").
+				Column("?", now).
+				From("tags").
+				Where(sq.Eq{"user_id": userId}).
+				Where("name = ? COLLATE NOCASE", name)).
+			ToSql()
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := db.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package repository_test
 
 import (
 	"context"
+	"strconv"
 	"testing"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"todos/internal/core/domain"
 	"todos/internal/core/port"
 	coretelemetry "todos/internal/core/telemetry"
+	"todos/internal/core/util"
 
 	"github.com/google/uuid"
 	. "github.com/onsi/gomega"
@@ -38,7 +40,7 @@ func TestTodoRepositoryTestSuite(t *testing.T) {
 }
 
 func (s *TodoRepositoryTestSuite) TestRepository_GetAllTodos_Empty() {
-	users, _, err := s.TodoRepo.GetAllWithCursor(context.Background(), 0, 10, "")
+	users, _, err := s.TodoRepo.GetAllWithCursor(context.Background(), 0, 10, "", domain.TodoFilter{})
 
 	Expect(err).To(BeNil())
 	Expect(users).To(BeEmpty())
@@ -51,13 +53,11 @@ func (s *TodoRepositoryTestSuite) TestRepository_CreateTodo_Success() {
 		Email: "test@example.com",
 	})
 
-	status := int(domain.TodoStatusPending)
-
 	todo, err := s.TodoRepo.Create(context.Background(), domain.Todo{
 		UUID:        uuid.New(),
 		Title:       "My User",
 		Description: "Some description",
-		Status:      status,
+		Status:      domain.TodoStatusPending,
 		Completed:   false,
 		UserId:      user.ID,
 		CreatedAt:   time.Now(),
@@ -88,10 +88,361 @@ func (s *TodoRepositoryTestSuite) TestRepository_DeleteByUUID_Success() {
 
 	savedTodo, _ := s.TodoRepo.Create(context.Background(), todo)
 
-	err := s.TodoRepo.DeleteByUUID(context.Background(), savedTodo.UUID.String())
+	err := s.TodoRepo.DeleteByUUID(context.Background(), savedTodo.UUID.String(), 0)
 	assert.NoError(s.T(), err)
 
 	_, err = s.TodoRepo.GetByUUID(context.Background(), savedTodo.UUID.String())
 
-	Expect(err.Error()).To(ContainSubstring("no rows"))
+	Expect(err).To(MatchError(domain.ErrTodoNotFound))
+
+	// Deleting it again leaves the trashed todo as it was
+	trashed, _ := s.TodoRepo.GetTrashedByUUID(context.Background(), savedTodo.UUID.String())
+
+	err = s.TodoRepo.DeleteByUUID(context.Background(), savedTodo.UUID.String(), 0)
+	Expect(err).To(MatchError(domain.ErrTodoNotFound))
+
+	again, _ := s.TodoRepo.GetTrashedByUUID(context.Background(), savedTodo.UUID.String())
+
+	Expect(again.Version).To(Equal(trashed.Version))
+	Expect(again.DeletedAt).To(Equal(trashed.DeletedAt))
+}
+
+func (s *TodoRepositoryTestSuite) TestRepository_Version() {
+	ctx := context.Background()
+
+	user, _ := s.UserRepo.Create(ctx, domain.User{
+		UUID:  uuid.New(),
+		Name:  "Test User",
+		Email: "test@example.com",
+	})
+
+	todo, _ := s.TodoRepo.Create(ctx, domain.Todo{
+		UUID:      uuid.New(),
+		Title:     "Test Todo",
+		UserId:    user.ID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+
+	Expect(todo.Version).To(Equal(1))
+
+	updated, err := s.TodoRepo.UpdateByUUID(ctx, domain.Todo{UUID: todo.UUID, Title: "Renamed", Version: 1})
+
+	Expect(err).To(BeNil())
+	Expect(updated.Version).To(Equal(2))
+
+	// A writer still holding version 1 is turned away
+	_, err = s.TodoRepo.UpdateByUUID(ctx, domain.Todo{UUID: todo.UUID, Title: "Stale", Version: 1})
+	Expect(err).To(MatchError(domain.ErrVersionConflict))
+
+	err = s.TodoRepo.DeleteByUUID(ctx, todo.UUID.String(), 1)
+	Expect(err).To(MatchError(domain.ErrVersionConflict))
+
+	current, _ := s.TodoRepo.GetByUUID(ctx, todo.UUID.String())
+	Expect(current.Title).To(Equal("Renamed"))
+
+	Expect(s.TodoRepo.DeleteByUUID(ctx, todo.UUID.String(), 2)).To(Succeed())
+	Expect(s.TodoRepo.DeleteByUUID(ctx, uuid.NewString(), 2)).To(MatchError(domain.ErrTodoNotFound))
+}
+
+func (s *TodoRepositoryTestSuite) TestRepository_CreateMany() {
+	ctx := context.Background()
+
+	user, _ := s.UserRepo.Create(ctx, domain.User{
+		UUID:  uuid.New(),
+		Name:  "Test User",
+		Email: "test@example.com",
+	})
+
+	// More todos than go into one statement
+	todos := make([]domain.Todo, 450)
+
+	for i := range todos {
+		todos[i] = domain.Todo{
+			UUID:      uuid.New(),
+			Title:     "Imported Todo",
+			Status:    domain.TodoStatusPending,
+			UserId:    user.ID,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+	}
+
+	todos[0].Tags = []string{"imported", "work"}
+	todos[449].Tags = []string{"imported"}
+
+	Expect(s.TodoRepo.CreateMany(ctx, todos)).To(Succeed())
+
+	first, err := s.TodoRepo.GetByUUID(ctx, todos[0].UUID.String())
+
+	Expect(err).To(BeNil())
+	Expect(first.Tags).To(Equal([]string{"imported", "work"}))
+
+	last, _ := s.TodoRepo.GetByUUID(ctx, todos[449].UUID.String())
+	Expect(last.Tags).To(Equal([]string{"imported"}))
+
+	// A failing row keeps the whole import out
+	again := []domain.Todo{
+		{UUID: uuid.New(), Title: "Fresh Todo", UserId: user.ID, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		todos[1],
+	}
+
+	Expect(s.TodoRepo.CreateMany(ctx, again)).ToNot(Succeed())
+
+	_, err = s.TodoRepo.GetByUUID(ctx, again[0].UUID.String())
+	Expect(err).To(MatchError(domain.ErrTodoNotFound))
+}
+
+func (s *TodoRepositoryTestSuite) TestRepository_Dependencies() {
+	ctx := context.Background()
+
+	user, _ := s.UserRepo.Create(ctx, domain.User{
+		UUID:  uuid.New(),
+		Name:  "Test User",
+		Email: "test@example.com",
+	})
+
+	create := func(title string) domain.Todo {
+		todo, _ := s.TodoRepo.Create(ctx, domain.Todo{
+			UUID:      uuid.New(),
+			Title:     title,
+			Status:    domain.TodoStatusPending,
+			UserId:    user.ID,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+
+		return todo
+	}
+
+	first, second, third := create("First"), create("Second"), create("Third")
+
+	Expect(s.TodoRepo.AddBlocker(ctx, first.ID, second.ID)).To(Succeed())
+	Expect(s.TodoRepo.AddBlocker(ctx, second.ID, third.ID)).To(Succeed())
+
+	Expect(s.TodoRepo.AddBlocker(ctx, first.ID, first.ID)).To(MatchError(domain.ErrDependencySelf))
+	Expect(s.TodoRepo.AddBlocker(ctx, first.ID, second.ID)).To(MatchError(domain.ErrDependencyExists))
+
+	// Third waits on second through first
+	Expect(s.TodoRepo.AddBlocker(ctx, third.ID, first.ID)).To(MatchError(domain.ErrDependencyCycle))
+
+	blocked, _ := s.TodoRepo.GetByUUID(ctx, first.UUID.String())
+	Expect(blocked.Blocked).To(BeTrue())
+
+	blockers, err := s.TodoRepo.ListBlockers(ctx, user.ID, first.ID)
+
+	Expect(err).To(BeNil())
+	Expect(blockers).To(HaveLen(1))
+	Expect(blockers[0].UUID).To(Equal(second.UUID))
+	Expect(blockers[0].Blocked).To(BeTrue())
+
+	_, err = s.TodoRepo.UpdateByUUID(ctx, domain.Todo{UUID: second.UUID, Status: domain.TodoStatusCompleted, Completed: true})
+	Expect(err).To(BeNil())
+
+	unblocked, _ := s.TodoRepo.GetByUUID(ctx, first.UUID.String())
+	Expect(unblocked.Blocked).To(BeFalse())
+	Expect(unblocked.Version).To(Equal(blocked.Version + 1))
+
+	Expect(s.TodoRepo.RemoveBlocker(ctx, second.ID, third.ID)).To(Succeed())
+	Expect(s.TodoRepo.RemoveBlocker(ctx, second.ID, third.ID)).To(MatchError(domain.ErrDependencyNotFound))
+
+	// Purging a todo drops the dependencies it took part in
+	Expect(s.TodoRepo.DeleteByUUID(ctx, second.UUID.String(), 0)).To(Succeed())
+	Expect(s.TodoRepo.Purge(ctx, second)).To(Succeed())
+
+	blockers, _ = s.TodoRepo.ListBlockers(ctx, user.ID, first.ID)
+	Expect(blockers).To(BeEmpty())
+	Expect(s.TodoRepo.RemoveBlocker(ctx, first.ID, second.ID)).To(MatchError(domain.ErrDependencyNotFound))
+}
+
+func (s *TodoRepositoryTestSuite) TestRepository_GetAllWithCursor_Filters() {
+	user, _ := s.UserRepo.Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Test User",
+		Email: "test@example.com",
+	})
+
+	now := time.Now()
+
+	for i, status := range []domain.TodoStatus{domain.TodoStatusPending, domain.TodoStatusInProgress, domain.TodoStatusCompleted} {
+		s.TodoRepo.Create(context.Background(), domain.Todo{
+			UUID:      uuid.New(),
+			Title:     status.String(),
+			Status:    status,
+			Completed: status == domain.TodoStatusCompleted,
+			UserId:    user.ID,
+			CreatedAt: now.Add(time.Duration(i) * time.Hour),
+			UpdatedAt: now.Add(time.Duration(i) * time.Hour),
+		})
+	}
+
+	todos, _, err := s.TodoRepo.GetAllWithCursor(context.Background(), user.ID, 10, "", domain.TodoFilter{
+		Statuses: []domain.TodoStatus{domain.TodoStatusPending, domain.TodoStatusInProgress},
+	})
+
+	Expect(err).To(BeNil())
+	Expect(todos).To(HaveLen(2))
+
+	completed := true
+	todos, _, err = s.TodoRepo.GetAllWithCursor(context.Background(), user.ID, 10, "", domain.TodoFilter{
+		Completed: &completed,
+	})
+
+	Expect(err).To(BeNil())
+	Expect(todos).To(HaveLen(1))
+	Expect(todos[0].Title).To(Equal("completed"))
+
+	after := now.Add(30 * time.Minute)
+	todos, _, err = s.TodoRepo.GetAllWithCursor(context.Background(), user.ID, 10, "", domain.TodoFilter{
+		CreatedAfter: &after,
+		Sort:         domain.TodoSort{Field: domain.TodoSortTitle, Desc: false},
+	})
+
+	Expect(err).To(BeNil())
+	Expect(todos).To(HaveLen(2))
+	Expect(todos[0].Title).To(Equal("completed"))
+	Expect(todos[1].Title).To(Equal("in_progress"))
+}
+
+func (s *TodoRepositoryTestSuite) TestRepository_Search() {
+	user, _ := s.UserRepo.Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Test User",
+		Email: "test@example.com",
+	})
+
+	other, _ := s.UserRepo.Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Other User",
+		Email: "other@example.com",
+	})
+
+	create := func(userId int, title string, description string) domain.Todo {
+		todo, _ := s.TodoRepo.Create(context.Background(), domain.Todo{
+			UUID:        uuid.New(),
+			Title:       title,
+			Description: description,
+			UserId:      userId,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		})
+
+		return todo
+	}
+
+	inDescription := create(user.ID, "Call mom", "ask about the groceries list")
+	inTitle := create(user.ID, "Buy groceries", "milk and eggs")
+	create(user.ID, "Pay rent", "before friday")
+	create(other.ID, "Groceries for other user", "")
+	deleted := create(user.ID, "Groceries to delete", "")
+	s.TodoRepo.DeleteByUUID(context.Background(), deleted.UUID.String(), 0)
+
+	results, hasNext, err := s.TodoRepo.Search(context.Background(), user.ID, "gro", 10, "")
+
+	Expect(err).To(BeNil())
+	Expect(hasNext).To(BeFalse())
+	Expect(results).To(HaveLen(2))
+	Expect(results[0].Todo.ID).To(Equal(inTitle.ID))
+	Expect(results[1].Todo.ID).To(Equal(inDescription.ID))
+	Expect(results[0].Rank).To(BeNumerically(">", results[1].Rank))
+	Expect(results[0].TitleHighlight).To(Equal("Buy <mark>groceries</mark>"))
+	Expect(results[1].DescriptionHighlight).To(ContainSubstring("<mark>groceries</mark>"))
+
+	// Pages continue after the rank and id of the last hit
+	page, hasNext, err := s.TodoRepo.Search(context.Background(), user.ID, "gro", 1, "")
+
+	Expect(err).To(BeNil())
+	Expect(hasNext).To(BeTrue())
+	Expect(page[0].Todo.ID).To(Equal(inTitle.ID))
+
+	cursor := util.EncodeSortCursor(domain.TodoSearchSort, strconv.FormatFloat(page[0].Rank, 'g', -1, 64), page[0].Todo.ID)
+	page, hasNext, err = s.TodoRepo.Search(context.Background(), user.ID, "gro", 1, cursor)
+
+	Expect(err).To(BeNil())
+	Expect(hasNext).To(BeFalse())
+	Expect(page).To(HaveLen(1))
+	Expect(page[0].Todo.ID).To(Equal(inDescription.ID))
+
+	_, _, err = s.TodoRepo.Search(context.Background(), user.ID, "gro", 1, "garbage")
+	Expect(err).To(MatchError(domain.ErrInvalidCursor))
+
+	// Updates are reindexed by the migration triggers
+	s.TodoRepo.UpdateByUUID(context.Background(), domain.Todo{UUID: inTitle.UUID, Title: "Buy bread"})
+
+	results, _, err = s.TodoRepo.Search(context.Background(), user.ID, "groceries", 10, "")
+
+	Expect(err).To(BeNil())
+	Expect(results).To(HaveLen(1))
+	Expect(results[0].Todo.ID).To(Equal(inDescription.ID))
+
+	// Stored markup comes back escaped, only the match markers are tags
+	create(user.ID, "<img src=x onerror=alert(1)> chores", "<b>chores</b> & more")
+
+	results, _, err = s.TodoRepo.Search(context.Background(), user.ID, "chores", 10, "")
+
+	Expect(err).To(BeNil())
+	Expect(results).To(HaveLen(1))
+	Expect(results[0].TitleHighlight).To(Equal("&lt;img src=x onerror=alert(1)&gt; <mark>chores</mark>"))
+	Expect(results[0].DescriptionHighlight).To(Equal("&lt;b&gt;<mark>chores</mark>&lt;/b&gt; &amp; more"))
+
+	_, _, err = s.TodoRepo.Search(context.Background(), user.ID, " ?! ", 10, "")
+	Expect(err).To(MatchError(domain.ErrEmptySearchQuery))
+}
+
+func (s *TodoRepositoryTestSuite) TestRepository_GetAllWithCursor_DueFilters() {
+	user, _ := s.UserRepo.Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Test User",
+		Email: "test@example.com",
+	})
+
+	// Wednesday afternoon
+	now := time.Date(2026, 3, 11, 15, 0, 0, 0, time.UTC)
+
+	at := func(t time.Time) *time.Time { return &t }
+
+	todos := map[string]domain.Todo{
+		"overdue":       {DueAt: at(now.Add(-20 * time.Hour))},
+		"today_all_day": {DueAt: at(domain.StartOfDay(now)), AllDay: true},
+		"tonight":       {DueAt: at(now.Add(5 * time.Hour))},
+		"saturday":      {DueAt: at(now.AddDate(0, 0, 3))},
+		"next_week":     {DueAt: at(now.AddDate(0, 0, 7))},
+		"done_late":     {DueAt: at(now.AddDate(0, 0, -1)), Completed: true},
+		"someday":       {},
+	}
+
+	for title, todo := range todos {
+		todo.UUID = uuid.New()
+		todo.Title = title
+		todo.UserId = user.ID
+		todo.CreatedAt = now
+		todo.UpdatedAt = now
+
+		_, err := s.TodoRepo.Create(context.Background(), todo)
+		Expect(err).To(BeNil())
+	}
+
+	titles := func(due domain.DueFilter) []string {
+		rows, _, err := s.TodoRepo.GetAllWithCursor(context.Background(), user.ID, 20, "", domain.TodoFilter{
+			Due: due,
+			Now: now,
+		})
+		Expect(err).To(BeNil())
+
+		result := []string{}
+		for _, row := range rows {
+			result = append(result, row.Title)
+		}
+
+		return result
+	}
+
+	Expect(titles(domain.DueOverdue)).To(ConsistOf("overdue"))
+	Expect(titles(domain.DueToday)).To(ConsistOf("today_all_day", "tonight"))
+	Expect(titles(domain.DueThisWeek)).To(ConsistOf("today_all_day", "tonight", "saturday"))
+	Expect(titles(domain.DueNone)).To(ConsistOf("someday"))
+
+	saved, _, _ := s.TodoRepo.GetAllWithCursor(context.Background(), user.ID, 1, "", domain.TodoFilter{Due: domain.DueOverdue, Now: now})
+	Expect(saved[0].DueAt).ToNot(BeNil())
+	Expect(saved[0].DueAt.Equal(now.Add(-20 * time.Hour))).To(BeTrue())
 }
@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/util"
+)
+
+// trashableBy matches the deleted todos a user could have deleted: their
+// personal ones and those in projects they edit
+func trashableBy(userId int) sq.Sqlizer {
+	return sq.Expr("((todos.project_id IS NULL AND todos.user_id = ?) OR todos.project_id IN (SELECT project_id FROM project_members WHERE user_id = ? AND role IN (?, ?)))",
+		userId, userId, domain.ProjectEditor, domain.ProjectOwner)
+}
+
+func (tr *TodoRepository) trashed() sq.SelectBuilder {
+	return tr.db.QueryBuilder.Select(todoColumns...).
+		From("todos").
+		Where("todos.deleted_at IS NOT NULL")
+}
+
+// ListTrash pages through the user's deleted todos, most recently deleted first
+func (tr *TodoRepository) ListTrash(ctx context.Context, userId int, limit int, cursor string) ([]domain.Todo, bool, error) {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "ListTrash", "todo", map[string]interface{}{
+		"db.system":         "sqlite",
+		"db.table":          "todos",
+		"user.id":           userId,
+		"pagination.limit":  limit,
+		"pagination.cursor": cursor,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) ([]domain.Todo, bool, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "ListTrash", "todo", time.Since(startTime), err)
+		return []domain.Todo{}, false, err
+	}
+
+	query := tr.trashed().
+		Where(trashableBy(userId)).
+		OrderBy("todos.deleted_at DESC", "todos.id DESC").
+		Limit(uint64(limit + 1))
+
+	if cursor != "" {
+		data, err := util.DecodeSortCursor(cursor)
+
+		if err != nil {
+			return fail(fmt.Errorf("%w: %v", domain.ErrInvalidCursor, err))
+		}
+
+		if data.Sort != domain.TrashCursorSort {
+			return fail(fmt.Errorf("%w: cursor was not issued for the trash", domain.ErrInvalidCursor))
+		}
+
+		deletedAt, err := time.Parse(time.RFC3339Nano, data.Value)
+
+		if err != nil {
+			return fail(fmt.Errorf("%w: %v", domain.ErrInvalidCursor, err))
+		}
+
+		query = query.Where(sq.Or{
+			sq.Lt{"todos.deleted_at": deletedAt},
+			sq.And{
+				sq.Eq{"todos.deleted_at": deletedAt},
+				sq.Lt{"todos.id": data.ID},
+			},
+		})
+	}
+
+	todos, err := tr.queryTodos(ctx, query)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	hasNext := len(todos) > limit
+
+	if hasNext {
+		todos = todos[:limit]
+	}
+
+	span.SetAttributes(map[string]interface{}{
+		"db.rows_returned": len(todos),
+		"db.has_next":      hasNext,
+	})
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, "ListTrash", "todo", time.Since(startTime), nil)
+
+	return todos, hasNext, nil
+}
+
+// GetTrashedByUUID loads a deleted todo, ErrTodoNotFound when it is not in the trash
+func (tr *TodoRepository) GetTrashedByUUID(ctx context.Context, uid string) (domain.Todo, error) {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "GetTrashedByUUID", "todo", map[string]interface{}{
+		"db.system": "sqlite",
+		"db.table":  "todos",
+		"todo.uuid": uid,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	todos, err := tr.queryTodos(ctx, tr.trashed().Where(sq.Eq{"todos.uuid": uid}).Limit(1))
+
+	if err == nil && len(todos) == 0 {
+		err = notFoundError(sql.ErrNoRows)
+	}
+
+	if err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "GetTrashedByUUID", "todo", time.Since(startTime), err)
+		return domain.Todo{}, err
+	}
+
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, "GetTrashedByUUID", "todo", time.Since(startTime), nil)
+
+	return todos[0], nil
+}
+
+// ListPurgeable returns todos deleted before the given time, oldest first
+func (tr *TodoRepository) ListPurgeable(ctx context.Context, deletedBefore time.Time, limit int) ([]domain.Todo, error) {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, "ListPurgeable", "todo", map[string]interface{}{
+		"db.system":            "sqlite",
+		"db.table":             "todos",
+		"purge.deleted_before": deletedBefore,
+		"purge.limit":          limit,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	todos, err := tr.queryTodos(ctx, tr.trashed().
+		Where(sq.LtOrEq{"todos.deleted_at": deletedBefore}).
+		OrderBy("todos.deleted_at ASC", "todos.id ASC").
+		Limit(uint64(limit)))
+
+	if err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, "ListPurgeable", "todo", time.Since(startTime), err)
+		return []domain.Todo{}, err
+	}
+
+	span.SetAttributes(map[string]interface{}{"db.rows_returned": len(todos)})
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, "ListPurgeable", "todo", time.Since(startTime), nil)
+
+	return todos, nil
+}
+
+func (tr *TodoRepository) Restore(ctx context.Context, todo domain.Todo) (domain.Todo, error) {
+	query := tr.db.QueryBuilder.Update("todos").
+		Set("deleted_at", nil).
+		Set("updated_at", time.Now()).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": todo.ID}).
+		Where("deleted_at IS NOT NULL")
+
+	if err := tr.execTrash(ctx, "Restore", todo, []sq.Sqlizer{query}); err != nil {
+		return domain.Todo{}, err
+	}
+
+	return tr.GetByUUID(ctx, todo.UUID.String())
+}
+
+// Purge removes a deleted todo for good along with everything hanging off
+// it. Attachment blobs are not touched, the caller removes them first.
+func (tr *TodoRepository) Purge(ctx context.Context, todo domain.Todo) error {
+	byTodo := sq.Eq{"todo_id": todo.ID}
+
+	statements := []sq.Sqlizer{
+		tr.db.QueryBuilder.Delete("comment_revisions").Where("comment_id IN (SELECT id FROM comments WHERE todo_id = ?)", todo.ID),
+		tr.db.QueryBuilder.Delete("comments").Where(byTodo),
+		tr.db.QueryBuilder.Delete("todo_items").Where(byTodo),
+		tr.db.QueryBuilder.Delete("attachments").Where(byTodo),
+		tr.db.QueryBuilder.Delete("reminders").Where(byTodo),
+		tr.db.QueryBuilder.Delete("todo_tags").Where(byTodo),
+		tr.db.QueryBuilder.Delete("todo_reviews").Where(byTodo),
+		tr.db.QueryBuilder.Delete("todo_revisions").Where(byTodo),
+		tr.db.QueryBuilder.Delete("time_entries").Where(byTodo),
+		tr.db.QueryBuilder.Delete("todo_dependencies").Where(sq.Or{byTodo, sq.Eq{"blocker_id": todo.ID}}),
+		// Earlier occurrences of a recurring todo keep pointing at the next one
+		tr.db.QueryBuilder.Update("todos").Set("next_occurrence_id", nil).Where(sq.Eq{"next_occurrence_id": todo.ID}),
+		tr.db.QueryBuilder.Delete("todos").Where(sq.Eq{"id": todo.ID}).Where("deleted_at IS NOT NULL"),
+	}
+
+	return tr.execTrash(ctx, "Purge", todo, statements)
+}
+
+// queryTodos runs a todo select and loads the tags of the rows it returns
+func (tr *TodoRepository) queryTodos(ctx context.Context, query sq.SelectBuilder) ([]domain.Todo, error) {
+	statement, args, err := query.ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tr.db.QueryContext(ctx, statement, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	todos := []domain.Todo{}
+	err = tr.scanner.ScanRowsToSlice(rows, &todos)
+
+	// Release the connection before loading the tags
+	rows.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tr.loadTags(ctx, todos); err != nil {
+		return nil, err
+	}
+
+	return todos, nil
+}
+
+// execTrash runs statements in a transaction, the last one must touch the
+// todo or it was no longer in the trash
+func (tr *TodoRepository) execTrash(ctx context.Context, operation string, todo domain.Todo, statements []sq.Sqlizer) error {
+	ctx, span := tr.telemetry.StartRepositorySpan(ctx, operation, "todo", map[string]interface{}{
+		"db.system": "sqlite",
+		"db.table":  "todos",
+		"todo.uuid": todo.UUID.String(),
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	err := func() error {
+		tx, err := tr.db.BeginTx(ctx, nil)
+
+		if err != nil {
+			return err
+		}
+
+		defer tx.Rollback()
+
+		last := len(statements) - 1
+
+		if err := execAll(ctx, tx, statements[:last]); err != nil {
+			return err
+		}
+
+		query, args, err := statements[last].ToSql()
+
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, query, args...)
+
+		if err != nil {
+			return err
+		}
+
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			if err == nil {
+				err = fmt.Errorf("%w: todo %s is not in the trash", domain.ErrTodoNotFound, todo.UUID)
+			}
+
+			return err
+		}
+
+		return tx.Commit()
+	}()
+
+	if err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		tr.telemetry.RecordRepositoryOperation(ctx, operation, "todo", time.Since(startTime), err)
+		return err
+	}
+
+	span.SetStatus("ok", "")
+	tr.telemetry.RecordRepositoryOperation(ctx, operation, "todo", time.Since(startTime), nil)
+
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 
@@ -71,7 +72,7 @@ func (ur *UserRepository) GetByEmail(ctx context.Context, email string) (domain.
 		Where(sq.Eq{"email": email}).
 		Limit(1)
 
-	sql, args, err := query.ToSql()
+	statement, args, err := query.ToSql()
 
 	if err != nil {
 		return domain.User{}, err
@@ -79,7 +80,7 @@ func (ur *UserRepository) GetByEmail(ctx context.Context, email string) (domain.
 
 	var data domain.User
 
-	rows, err := ur.db.QueryContext(ctx, sql, args...)
+	rows, err := ur.db.QueryContext(ctx, statement, args...)
 
 	if err != nil {
 		return domain.User{}, err
@@ -91,6 +92,10 @@ func (ur *UserRepository) GetByEmail(ctx context.Context, email string) (domain.
 
 	slog.Info("data", "data", data)
 
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+
 	if err != nil {
 		slog.Error("Error getting user by email", "error", err)
 		return domain.User{}, err
@@ -99,7 +104,7 @@ func (ur *UserRepository) GetByEmail(ctx context.Context, email string) (domain.
 	return data, nil
 }
 
-func (ur *UserRepository) getByUUIDTx(ctx context.Context, tx *sql.Tx, uid string) (domain.User, error) {
+func (ur *UserRepository) getByUUIDTx(ctx context.Context, tx *sqlite.Tx, uid string) (domain.User, error) {
 	query := ur.db.QueryBuilder.Select("id", "uuid", "name", "email").
 		From("users").
 		Where(sq.Eq{"uuid": uid}).
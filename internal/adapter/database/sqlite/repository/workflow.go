@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"todos/internal/adapter/database/sqlite"
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	tel "todos/internal/core/telemetry"
+)
+
+type WorkflowRepository struct {
+	db        *sqlite.DB
+	scanner   *sqlite.Scanner
+	telemetry port.Telemetry
+}
+
+func NewWorkflowRepository(db *sqlite.DB, telemetry port.Telemetry) port.WorkflowRepository {
+	if telemetry == nil {
+		telemetry = tel.NewNoOpProbe()
+	}
+
+	return &WorkflowRepository{
+		db:        db,
+		scanner:   sqlite.NewScanner(),
+		telemetry: telemetry,
+	}
+}
+
+func (wr *WorkflowRepository) GetDefault(ctx context.Context) (domain.Workflow, error) {
+	return wr.get(ctx, "GetDefault", sq.Eq{"project_id": nil})
+}
+
+func (wr *WorkflowRepository) GetByProject(ctx context.Context, projectId int) (domain.Workflow, error) {
+	return wr.get(ctx, "GetByProject", sq.Eq{"project_id": projectId})
+}
+
+func (wr *WorkflowRepository) Save(ctx context.Context, workflow domain.Workflow) (domain.Workflow, error) {
+	err := wr.inTx(ctx, "Save", workflow, func(tx *sqlite.Tx, now time.Time) error {
+		if workflow.ID == 0 {
+			id, err := insertReturningId(ctx, tx, wr.db.QueryBuilder.Insert("workflows").
+				Columns("uuid", "project_id", "review_rule", "created_at", "updated_at").
+				Values(workflow.UUID.String(), workflow.ProjectId, workflow.ReviewRule, now, now))
+
+			if err != nil {
+				return err
+			}
+
+			workflow.ID = id
+		}
+
+		statements := []sq.Sqlizer{
+			wr.db.QueryBuilder.Update("workflows").
+				Set("review_rule", workflow.ReviewRule).
+				Set("updated_at", now).
+				Where(sq.Eq{"id": workflow.ID}),
+			wr.db.QueryBuilder.Delete("workflow_transitions").Where(sq.Eq{"workflow_id": workflow.ID}),
+			wr.db.QueryBuilder.Delete("workflow_statuses").Where(sq.Eq{"workflow_id": workflow.ID}),
+		}
+
+		for position, status := range workflow.Statuses {
+			statements = append(statements, wr.db.QueryBuilder.Insert("workflow_statuses").
+				Columns("workflow_id", "key", "name", "position", "terminal").
+				Values(workflow.ID, status.Key, status.Name, position, status.Terminal))
+		}
+
+		for _, transition := range workflow.Transitions {
+			statements = append(statements, wr.db.QueryBuilder.Insert("workflow_transitions").
+				Columns("workflow_id", "from_key", "to_key").
+				Values(workflow.ID, transition.From, transition.To))
+		}
+
+		return execAll(ctx, tx, statements)
+	})
+
+	if err != nil {
+		return domain.Workflow{}, err
+	}
+
+	return wr.GetByProject(ctx, *workflow.ProjectId)
+}
+
+func (wr *WorkflowRepository) Delete(ctx context.Context, workflow domain.Workflow) error {
+	return wr.inTx(ctx, "Delete", workflow, func(tx *sqlite.Tx, now time.Time) error {
+		return execAll(ctx, tx, []sq.Sqlizer{
+			wr.db.QueryBuilder.Delete("workflow_transitions").Where(sq.Eq{"workflow_id": workflow.ID}),
+			wr.db.QueryBuilder.Delete("workflow_statuses").Where(sq.Eq{"workflow_id": workflow.ID}),
+			wr.db.QueryBuilder.Delete("workflows").Where(sq.Eq{"id": workflow.ID}),
+		})
+	})
+}
+
+func (wr *WorkflowRepository) StatusesInUse(ctx context.Context, projectId int) ([]domain.TodoStatus, error) {
+	ctx, span := wr.telemetry.StartRepositorySpan(ctx, "StatusesInUse", "workflow", map[string]interface{}{
+		"db.system":  "sqlite",
+		"db.table":   "todos",
+		"project.id": projectId,
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) ([]domain.TodoStatus, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		wr.telemetry.RecordRepositoryOperation(ctx, "StatusesInUse", "workflow", time.Since(startTime), err)
+		return []domain.TodoStatus{}, err
+	}
+
+	query, args, err := wr.db.QueryBuilder.Select("DISTINCT status").
+		From("todos").
+		Where(sq.Eq{"project_id": projectId}).
+		Where("deleted_at IS NULL").
+		OrderBy("status").
+		ToSql()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	rows, err := wr.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer rows.Close()
+
+	statuses := []domain.TodoStatus{}
+
+	for rows.Next() {
+		var status string
+
+		if err := rows.Scan(&status); err != nil {
+			return fail(err)
+		}
+
+		statuses = append(statuses, domain.TodoStatus(status))
+	}
+
+	if err := rows.Err(); err != nil {
+		return fail(err)
+	}
+
+	span.SetStatus("ok", "")
+	wr.telemetry.RecordRepositoryOperation(ctx, "StatusesInUse", "workflow", time.Since(startTime), nil)
+
+	return statuses, nil
+}
+
+// get loads a workflow with its statuses in order and its transitions
+func (wr *WorkflowRepository) get(ctx context.Context, operation string, where sq.Sqlizer) (domain.Workflow, error) {
+	ctx, span := wr.telemetry.StartRepositorySpan(ctx, operation, "workflow", map[string]interface{}{
+		"db.system": "sqlite",
+		"db.table":  "workflows",
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	fail := func(err error) (domain.Workflow, error) {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		wr.telemetry.RecordRepositoryOperation(ctx, operation, "workflow", time.Since(startTime), err)
+		return domain.Workflow{}, err
+	}
+
+	var workflow domain.Workflow
+
+	err := wr.query(ctx, wr.db.QueryBuilder.Select("*").From("workflows").Where(where).OrderBy("id").Limit(1), func(rows *sql.Rows) error {
+		return wr.scanner.ScanRowToStruct(rows, &workflow)
+	})
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return fail(domain.ErrWorkflowNotFound)
+	}
+
+	if err != nil {
+		return fail(err)
+	}
+
+	workflow.Statuses = []domain.WorkflowStatus{}
+	workflow.Transitions = []domain.WorkflowTransition{}
+
+	err = wr.query(ctx, wr.db.QueryBuilder.Select("*").From("workflow_statuses").Where(sq.Eq{"workflow_id": workflow.ID}).OrderBy("position", "id"), func(rows *sql.Rows) error {
+		return wr.scanner.ScanRowsToSlice(rows, &workflow.Statuses)
+	})
+
+	if err != nil {
+		return fail(err)
+	}
+
+	err = wr.query(ctx, wr.db.QueryBuilder.Select("*").From("workflow_transitions").Where(sq.Eq{"workflow_id": workflow.ID}).OrderBy("id"), func(rows *sql.Rows) error {
+		return wr.scanner.ScanRowsToSlice(rows, &workflow.Transitions)
+	})
+
+	if err != nil {
+		return fail(err)
+	}
+
+	span.SetAttributes(map[string]interface{}{
+		"workflow.uuid":     workflow.UUID.String(),
+		"workflow.statuses": len(workflow.Statuses),
+	})
+	span.SetStatus("ok", "")
+	wr.telemetry.RecordRepositoryOperation(ctx, operation, "workflow", time.Since(startTime), nil)
+
+	return workflow, nil
+}
+
+func (wr *WorkflowRepository) query(ctx context.Context, query sq.SelectBuilder, scan func(rows *sql.Rows) error) error {
+	statement, args, err := query.ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := wr.db.QueryContext(ctx, statement, args...)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	return scan(rows)
+}
+
+// inTx runs fn in a transaction with the usual span and metrics around it
+func (wr *WorkflowRepository) inTx(ctx context.Context, operation string, workflow domain.Workflow, fn func(tx *sqlite.Tx, now time.Time) error) error {
+	ctx, span := wr.telemetry.StartRepositorySpan(ctx, operation, "workflow", map[string]interface{}{
+		"db.system":     "sqlite",
+		"db.table":      "workflows",
+		"workflow.uuid": workflow.UUID.String(),
+	})
+	defer span.End()
+
+	startTime := time.Now()
+
+	err := func() error {
+		tx, err := wr.db.BeginTx(ctx, nil)
+
+		if err != nil {
+			return err
+		}
+
+		defer tx.Rollback()
+
+		if err := fn(tx, time.Now()); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	}()
+
+	if err != nil {
+		span.SetStatus("error", err.Error())
+		span.RecordError(err)
+		wr.telemetry.RecordRepositoryOperation(ctx, operation, "workflow", time.Since(startTime), err)
+		return err
+	}
+
+	span.SetStatus("ok", "")
+	wr.telemetry.RecordRepositoryOperation(ctx, operation, "workflow", time.Since(startTime), nil)
+
+	return nil
+}
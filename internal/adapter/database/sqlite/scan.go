@@ -229,6 +229,18 @@ func (s *Scanner) setFieldValue(field reflect.Value, val interface{}, structFiel
 		return nil
 	}
 
+	// Nullable columns such as deleted_at map to pointer fields
+	if fieldType.Kind() == reflect.Ptr {
+		elem := reflect.New(fieldType.Elem())
+
+		if err := s.setFieldValue(elem.Elem(), val, structField); err != nil {
+			return err
+		}
+
+		field.Set(elem)
+		return nil
+	}
+
 	valValue := reflect.ValueOf(val)
 
 	if valValue.IsValid() && valValue.Type().AssignableTo(fieldType) {
@@ -251,8 +263,12 @@ func (s *Scanner) setFieldValue(field reflect.Value, val interface{}, structFiel
 			field.SetInt(int64(v))
 		}
 	case reflect.Bool:
-		if b, ok := val.(bool); ok {
-			field.SetBool(b)
+		switch v := val.(type) {
+		case bool:
+			field.SetBool(v)
+		// Computed columns such as EXISTS come back as integers
+		case int64:
+			field.SetBool(v != 0)
 		}
 	case reflect.Float64, reflect.Float32:
 		if f, ok := val.(float64); ok {
@@ -0,0 +1,136 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type txKey struct{}
+
+// txState is the transaction WithinTx carries in the context
+type txState struct {
+	tx         *sql.Tx
+	savepoints int
+}
+
+// Tx is a transaction begun through DB. Begun inside WithinTx it is a
+// savepoint of the surrounding transaction: committing releases it and
+// rolling back only undoes what was done since it began.
+type Tx struct {
+	*sql.Tx
+	savepoint string
+	done      bool
+}
+
+func (tx *Tx) Commit() error {
+	if tx.savepoint == "" {
+		return tx.Tx.Commit()
+	}
+
+	if tx.done {
+		return sql.ErrTxDone
+	}
+
+	tx.done = true
+
+	_, err := tx.Tx.Exec("RELEASE SAVEPOINT " + tx.savepoint)
+
+	return err
+}
+
+func (tx *Tx) Rollback() error {
+	if tx.savepoint == "" {
+		return tx.Tx.Rollback()
+	}
+
+	if tx.done {
+		return sql.ErrTxDone
+	}
+
+	tx.done = true
+
+	if _, err := tx.Tx.Exec("ROLLBACK TO SAVEPOINT " + tx.savepoint); err != nil {
+		return err
+	}
+
+	_, err := tx.Tx.Exec("RELEASE SAVEPOINT " + tx.savepoint)
+
+	return err
+}
+
+// WithinTx runs fn in a transaction carried by the context fn receives.
+// Every statement issued through DB with that context joins it, so what fn
+// does is committed or rolled back as a whole. Nested calls use savepoints.
+func (db *DB) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, ok := ctx.Value(txKey{}).(*txState); !ok {
+		ctx = context.WithValue(ctx, txKey{}, &txState{tx: tx.Tx})
+	}
+
+	if err := fn(ctx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.savepoints++
+		savepoint := fmt.Sprintf("sp_%d", state.savepoints)
+
+		if _, err := state.tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+			return nil, err
+		}
+
+		return &Tx{Tx: state.tx, savepoint: savepoint}, nil
+	}
+
+	tx, err := db.DB.BeginTx(ctx, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tx{Tx: tx}, nil
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx.ExecContext(ctx, query, args...)
+	}
+
+	return db.DB.ExecContext(ctx, query, args...)
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx.QueryContext(ctx, query, args...)
+	}
+
+	return db.DB.QueryContext(ctx, query, args...)
+}
+
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx.QueryRowContext(ctx, query, args...)
+	}
+
+	return db.DB.QueryRowContext(ctx, query, args...)
+}
+
+func (db *DB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx.PrepareContext(ctx, query)
+	}
+
+	return db.DB.PrepareContext(ctx, query)
+}
@@ -21,11 +21,30 @@ func StartServerWithConfig(metrics *telemetry.AppMetrics, logger *config.LokiLog
 	db, _ := database.NewDB()
 	defer db.Close()
 
-	container := NewContainer(db, logger)
+	StartServerWithContainer(NewContainer(db, logger), metrics, logger, config)
+}
 
+// StartServerWithContainer serves an already wired container, letting the
+// caller share it with background workers such as the reminder scheduler
+func StartServerWithContainer(container *Container, metrics *telemetry.AppMetrics, logger *config.LokiLogger, config *config.AppConfig) {
 	router := routes.SetupRouterWithConfig(routes.HandlersConfig{
-		AuthHandler: container.AuthHandler,
-		TodoHandler: container.TodoHandler,
+		AuthHandler:     container.AuthHandler,
+		TodoHandler:     container.TodoHandler,
+		ReminderHandler: container.ReminderHandler,
+		TodoItemHandler: container.TodoItemHandler,
+		TagHandler:      container.TagHandler,
+		ProjectHandler:  container.ProjectHandler,
+		CommentHandler:  container.CommentHandler,
+		ReviewHandler:   container.ReviewHandler,
+		TrashHandler:    container.TrashHandler,
+		BatchHandler:    container.BatchHandler,
+		TransferHandler: container.TransferHandler,
+		TimeHandler:     container.TimeHandler,
+
+		AttachmentHandler: container.AttachmentHandler,
+
+		IdempotencyCache: container.Cache,
+		IdempotencyTTL:   container.IdempotencyTTL,
 	}, metrics, logger, config)
 
 	port := os.Getenv("PORT")
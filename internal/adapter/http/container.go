@@ -1,29 +1,80 @@
 package http
 
 import (
+	"context"
 	"log/slog"
+	"os"
+	"strconv"
+	"time"
 
+	"todos/internal/adapter/database/memory"
+	"todos/internal/adapter/database/redis"
 	database "todos/internal/adapter/database/sqlite"
 	repository "todos/internal/adapter/database/sqlite/repository"
 
 	"todos/internal/adapter/http/handler"
+	"todos/internal/adapter/http/middleware"
+	"todos/internal/adapter/notification"
+	"todos/internal/adapter/storage"
+	"todos/internal/core/policy"
 	"todos/internal/core/port"
 	"todos/internal/core/service"
 	"todos/internal/core/telemetry"
+	"todos/internal/core/util"
 	"todos/pkg/config"
 )
 
 type Container struct {
-	UserRepo port.UserRepository
-	TodoRepo port.TodoRepository
+	UserRepo     port.UserRepository
+	TodoRepo     port.TodoRepository
+	ReminderRepo port.ReminderRepository
+	TodoItemRepo port.TodoItemRepository
+	TagRepo      port.TagRepository
+	ProjectRepo  port.ProjectRepository
+	CommentRepo  port.CommentRepository
+	ReviewRepo   port.ReviewRepository
+	TimeRepo     port.TimeEntryRepository
 
-	UserUseCase port.UserService
-	TodoUseCase port.TodoService
-	AuthUseCase port.AuthService
+	AttachmentRepo port.AttachmentRepository
 
-	UserHandler *handler.UserHandler
-	TodoHandler *handler.TodoHandler
-	AuthHandler *handler.AuthHandler
+	Cache          port.CacheRepository
+	IdempotencyTTL time.Duration
+
+	UserUseCase     port.UserService
+	TodoUseCase     port.TodoService
+	AuthUseCase     port.AuthService
+	ReminderUseCase port.ReminderService
+	TodoItemUseCase port.TodoItemService
+	TagUseCase      port.TagService
+	ProjectUseCase  port.ProjectService
+	CommentUseCase  port.CommentService
+	ReviewUseCase   port.ReviewService
+	TrashUseCase    port.TrashService
+	BatchUseCase    port.TodoBatchService
+	TransferUseCase port.TodoTransferService
+	TimeUseCase     port.TimeEntryService
+
+	AttachmentUseCase port.AttachmentService
+
+	UserHandler     *handler.UserHandler
+	TodoHandler     *handler.TodoHandler
+	AuthHandler     *handler.AuthHandler
+	ReminderHandler *handler.ReminderHandler
+	TodoItemHandler *handler.TodoItemHandler
+	TagHandler      *handler.TagHandler
+	ProjectHandler  *handler.ProjectHandler
+	CommentHandler  *handler.CommentHandler
+	ReviewHandler   *handler.ReviewHandler
+	TrashHandler    *handler.TrashHandler
+	BatchHandler    *handler.TodoBatchHandler
+	TransferHandler *handler.TodoTransferHandler
+	TimeHandler     *handler.TimeEntryHandler
+
+	AttachmentHandler *handler.AttachmentHandler
+
+	ReminderScheduler *service.ReminderScheduler
+	AttachmentPurger  *service.AttachmentPurger
+	TrashPurger       *service.TrashPurger
 }
 
 func NewContainer(db *database.DB, logger *config.LokiLogger) *Container {
@@ -35,15 +86,105 @@ func NewContainer(db *database.DB, logger *config.LokiLogger) *Container {
 	// Inject probe into repositories
 	userRepo := repository.NewUserRepository(db, probe)
 	todoRepo := repository.NewTodoRepository(db, probe)
+	reminderRepo := repository.NewReminderRepository(db, probe)
+	itemRepo := repository.NewTodoItemRepository(db, probe)
+	tagRepo := repository.NewTagRepository(db, probe)
+	projectRepo := repository.NewProjectRepository(db, probe)
+	commentRepo := repository.NewCommentRepository(db, probe)
+	attachmentRepo := repository.NewAttachmentRepository(db, probe)
+	workflowRepo := repository.NewWorkflowRepository(db, probe)
+	reviewRepo := repository.NewReviewRepository(db, probe)
+	timeRepo := repository.NewTimeEntryRepository(db, probe)
+
+	// Authorization policy shared by every todo use case
+	todoPolicy := policy.NewMembershipPolicy(projectRepo)
 
 	// Services get probe for business-level telemetry
 	authSvc := service.NewAuthService(userRepo)
 	userSvc := service.NewUserService(userRepo)
-	todoSvc := service.NewTodoService(todoRepo, probe)
+	todoSvc := service.NewTodoService(todoRepo, projectRepo, workflowRepo, reviewRepo, db, probe, todoPolicy)
+	reminderSvc := service.NewReminderService(reminderRepo, todoSvc, probe)
+	itemSvc := service.NewTodoItemService(itemRepo, todoSvc, db, probe)
+	tagSvc := service.NewTagService(tagRepo, probe)
+	projectSvc := service.NewProjectService(projectRepo, userRepo, workflowRepo, probe)
+	commentSvc := service.NewCommentService(commentRepo, todoSvc, probe)
+	reviewSvc := service.NewReviewService(reviewRepo, todoSvc, userRepo, probe)
+	batchSvc := service.NewTodoBatchService(todoSvc, db, probe)
+
+	// Reminders go to a webhook when one is configured, otherwise to the log
+	clock := util.SystemClock{}
+	delivery := notification.NewLogDelivery(slog.Default())
+
+	if url := os.Getenv("REMINDER_WEBHOOK_URL"); url != "" {
+		delivery = notification.NewWebhookDelivery(url, os.Getenv("REMINDER_WEBHOOK_SECRET"), nil, clock)
+	}
+
+	reminderScheduler := service.NewReminderScheduler(reminderRepo, delivery, clock, probe)
+	transferSvc := service.NewTodoTransferService(todoRepo, workflowRepo, clock, probe)
+	timeSvc := service.NewTimeEntryService(timeRepo, todoSvc, clock, probe)
+
+	// Attachment contents live on disk. Download links are signed with a key
+	// of their own, without one no links are handed out rather than links
+	// anyone could forge.
+	blobPath := os.Getenv("ATTACHMENTS_PATH")
+
+	if blobPath == "" {
+		blobPath = "./storage/attachments"
+	}
+
+	signingKey := os.Getenv("ATTACHMENT_SIGNING_KEY")
+
+	if signingKey == "" {
+		slog.Warn("ATTACHMENT_SIGNING_KEY is not set, attachment downloads are turned off")
+	}
+
+	blobs := storage.NewFilesystemStore(blobPath)
+	attachmentSvc := service.NewAttachmentService(attachmentRepo, blobs, todoSvc, clock, signingKey, probe)
+	attachmentPurger := service.NewAttachmentPurger(attachmentRepo, blobs, clock, probe)
+
+	// Deleted todos stay in the trash for TRASH_RETENTION_DAYS, 30 by default,
+	// then go for good with their comments, items and attachments
+	trashSvc := service.NewTrashService(todoRepo, attachmentRepo, blobs, todoPolicy, clock, probe)
+
+	if days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS")); err == nil && days > 0 {
+		trashSvc.Retention = time.Duration(days) * 24 * time.Hour
+	}
+
+	trashPurger := service.NewTrashPurger(trashSvc)
+
+	// Responses kept for idempotency keys are shared through redis when
+	// REDIS_ADDR is set, otherwise each instance keeps its own. They can be
+	// replayed for IDEMPOTENCY_TTL_HOURS, 24 by default.
+	cache := memory.NewMemoryRepository()
+
+	if os.Getenv("REDIS_ADDR") != "" {
+		if shared, err := redis.New(context.Background()); err != nil {
+			slog.Error("Error connecting to redis, caching in memory", "error", err)
+		} else {
+			cache = shared
+		}
+	}
+
+	idempotencyTTL := middleware.DefaultIdempotencyTTL
+
+	if hours, err := strconv.Atoi(os.Getenv("IDEMPOTENCY_TTL_HOURS")); err == nil && hours > 0 {
+		idempotencyTTL = time.Duration(hours) * time.Hour
+	}
 
 	authHandler := handler.NewAuthHandler(authSvc)
 	userHandler := handler.NewUserHandler(userSvc)
 	todoHandler := handler.NewTodoHandler(todoSvc, logger)
+	reminderHandler := handler.NewReminderHandler(reminderSvc)
+	itemHandler := handler.NewTodoItemHandler(itemSvc)
+	tagHandler := handler.NewTagHandler(tagSvc)
+	projectHandler := handler.NewProjectHandler(projectSvc)
+	commentHandler := handler.NewCommentHandler(commentSvc)
+	reviewHandler := handler.NewReviewHandler(reviewSvc)
+	trashHandler := handler.NewTrashHandler(trashSvc)
+	batchHandler := handler.NewTodoBatchHandler(batchSvc)
+	transferHandler := handler.NewTodoTransferHandler(transferSvc)
+	timeHandler := handler.NewTimeEntryHandler(timeSvc)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentSvc)
 
 	return &Container{
 		AuthHandler: authHandler,
@@ -53,5 +194,52 @@ func NewContainer(db *database.DB, logger *config.LokiLogger) *Container {
 
 		UserRepo:    userRepo,
 		UserHandler: userHandler,
+
+		ReminderRepo:      reminderRepo,
+		ReminderUseCase:   reminderSvc,
+		ReminderHandler:   reminderHandler,
+		ReminderScheduler: reminderScheduler,
+
+		TodoItemRepo:    itemRepo,
+		TodoItemUseCase: itemSvc,
+		TodoItemHandler: itemHandler,
+
+		TagRepo:    tagRepo,
+		TagUseCase: tagSvc,
+		TagHandler: tagHandler,
+
+		ProjectRepo:    projectRepo,
+		ProjectUseCase: projectSvc,
+		ProjectHandler: projectHandler,
+
+		CommentRepo:    commentRepo,
+		CommentUseCase: commentSvc,
+		CommentHandler: commentHandler,
+
+		ReviewRepo:    reviewRepo,
+		ReviewUseCase: reviewSvc,
+		ReviewHandler: reviewHandler,
+
+		TrashUseCase: trashSvc,
+		TrashHandler: trashHandler,
+		TrashPurger:  trashPurger,
+
+		BatchUseCase: batchSvc,
+		BatchHandler: batchHandler,
+
+		TransferUseCase: transferSvc,
+		TransferHandler: transferHandler,
+
+		TimeRepo:    timeRepo,
+		TimeUseCase: timeSvc,
+		TimeHandler: timeHandler,
+
+		AttachmentRepo:    attachmentRepo,
+		AttachmentUseCase: attachmentSvc,
+		AttachmentHandler: attachmentHandler,
+		AttachmentPurger:  attachmentPurger,
+
+		Cache:          cache,
+		IdempotencyTTL: idempotencyTTL,
 	}
 }
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	. "todos/internal/adapter/http/helper"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+	"todos/internal/core/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead leaves room for the multipart boundaries and headers
+// around the file when capping the request body
+const multipartOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	svc port.AttachmentService
+}
+
+func NewAttachmentHandler(attachmentUseCase port.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		svc: attachmentUseCase,
+	}
+}
+
+func (h *AttachmentHandler) GetAttachments(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	attachments, err := h.svc.List(c.Request.Context(), userId, c.Param("uuid"))
+
+	if err != nil {
+		sendAttachmentError(c, err, "Error listing attachments")
+		return
+	}
+
+	data := make([]response.AttachmentResponse, 0, len(attachments))
+
+	for _, attachment := range attachments {
+		data = append(data, response.NewAttachmentResponse(attachment, h.svc.Sign(attachment)))
+	}
+
+	SendSuccess(c, http.StatusOK, data)
+}
+
+// GetAttachment returns the metadata with a freshly signed download link
+func (h *AttachmentHandler) GetAttachment(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	attachment, err := h.svc.GetByUUID(c.Request.Context(), userId, c.Param("uuid"), c.Param("attachment_uuid"))
+
+	if err != nil {
+		sendAttachmentError(c, err, "Error getting attachment")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewAttachmentResponse(attachment, h.svc.Sign(attachment)))
+}
+
+// UploadAttachment takes a multipart form with the file under "file". The
+// content type is sniffed from the first bytes, whatever the client claims.
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, domain.MaxAttachmentSize+multipartOverhead)
+
+	file, header, err := c.Request.FormFile("file")
+
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+
+		if errors.As(err, &tooLarge) {
+			sendAttachmentError(c, domain.ErrAttachmentTooLarge, "")
+			return
+		}
+
+		SendBadRequestError(c, "file", "file is required")
+		return
+	}
+
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		sendAttachmentError(c, err, "Error reading attachment")
+		return
+	}
+
+	head = head[:n]
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+
+	attachment, err := h.svc.Upload(c.Request.Context(), userId, c.Param("uuid"), domain.Attachment{
+		Filename:    attachmentFilename(header.Filename),
+		ContentType: contentType,
+		Size:        header.Size,
+	}, io.MultiReader(bytes.NewReader(head), file))
+
+	if err != nil {
+		sendAttachmentError(c, err, "Error uploading attachment")
+		return
+	}
+
+	SendSuccess(c, http.StatusCreated, response.NewAttachmentResponse(attachment, h.svc.Sign(attachment)))
+}
+
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	if err := h.svc.Delete(c.Request.Context(), userId, c.Param("uuid"), c.Param("attachment_uuid")); err != nil {
+		sendAttachmentError(c, err, "Error deleting attachment")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Attachment deleted successfully",
+	})
+}
+
+// DownloadAttachment serves a signed link, it sits outside the JWT group
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+
+	if err != nil {
+		sendAttachmentError(c, domain.ErrInvalidDownloadLink, "")
+		return
+	}
+
+	attachment, body, err := h.svc.Download(c.Request.Context(), c.Param("uuid"), expires, c.Query("signature"))
+
+	if err != nil {
+		sendAttachmentError(c, err, "Error downloading attachment")
+		return
+	}
+
+	defer body.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, body, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, no-store",
+	})
+}
+
+// attachmentFilename keeps only the base name a client sent
+func attachmentFilename(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+
+	return name
+}
+
+func sendAttachmentError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden):
+		sendTodoAccessError(c, err)
+	case errors.Is(err, domain.ErrAttachmentNotFound) || errors.Is(err, domain.ErrBlobNotFound):
+		SendNotFoundError(c, "Attachment not found")
+	case errors.Is(err, domain.ErrAttachmentTooLarge):
+		SendError(c, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", []response.ValidationError{{Field: "file", Message: err.Error()}})
+	case errors.Is(err, domain.ErrAttachmentType):
+		SendError(c, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE", []response.ValidationError{{Field: "file", Message: err.Error()}})
+	case errors.Is(err, domain.ErrAttachmentEmpty):
+		SendBadRequestError(c, "file", err.Error())
+	case errors.Is(err, domain.ErrInvalidDownloadLink):
+		SendForbiddenError(c, err.Error())
+	default:
+		slog.Error(message, "error", err, "uuid", c.Param("uuid"))
+		SendInternalError(c, message)
+	}
+}
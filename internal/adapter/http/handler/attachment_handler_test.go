@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"todos/internal/adapter/http/helper"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+	"todos/internal/core/service"
+	"todos/internal/core/util"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func (s *TodoHandlerSuite) uploadFile(path string, filename string, content []byte, userId int) *httptest.ResponseRecorder {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, _ := writer.CreateFormFile("file", filename)
+	part.Write(content)
+	writer.Close()
+
+	req, _ := http.NewRequest("POST", path, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	jwtToken, _ := helper.CreateJwtTokenForUser(userId)
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+	rr := httptest.NewRecorder()
+	s.Router.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func (s *TodoHandlerSuite) TestAttachmentUploadAndSignedDownload() {
+	user := CreateUserMock(s)
+	other := CreateOtherUserMock(s)
+	todo := CreateTodo(s, user.ID)
+
+	path := "/todos/" + todo.UUID.String() + "/attachments"
+
+	decode := func(body io.Reader) response.AttachmentResponse {
+		data := struct {
+			Data response.AttachmentResponse `json:"data"`
+		}{}
+		raw, _ := io.ReadAll(body)
+		json.Unmarshal(raw, &data)
+
+		return data.Data
+	}
+
+	content := append(pngHeader, []byte("rest of the image")...)
+
+	// The client's content type is ignored in favour of the sniffed one
+	rr := s.uploadFile(path, "../../screenshot.png", content, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	attachment := decode(rr.Body)
+	Expect(attachment.Filename).To(Equal("screenshot.png"))
+	Expect(attachment.ContentType).To(Equal("image/png"))
+	Expect(attachment.Size).To(Equal(int64(len(content))))
+	Expect(attachment.DownloadURL).To(HavePrefix("/attachments/" + attachment.UUID.String() + "/download?"))
+
+	// Signed links work without a token
+	req, _ := http.NewRequest("GET", attachment.DownloadURL, nil)
+	download := httptest.NewRecorder()
+	s.Router.ServeHTTP(download, req)
+
+	Expect(download.Code).To(Equal(http.StatusOK))
+	Expect(download.Body.Bytes()).To(Equal(content))
+	Expect(download.Header().Get("Content-Type")).To(Equal("image/png"))
+	Expect(download.Header().Get("Content-Disposition")).To(Equal(`attachment; filename=screenshot.png`))
+
+	// Tampering with the expiry breaks the signature
+	req, _ = http.NewRequest("GET", strings.Replace(attachment.DownloadURL, "expires=", "expires=9", 1), nil)
+	download = httptest.NewRecorder()
+	s.Router.ServeHTTP(download, req)
+
+	Expect(download.Code).To(Equal(http.StatusForbidden))
+
+	rr = s.uploadFile(path, "page.html", []byte("<html><body>hi</body></html>"), user.ID)
+	Expect(rr.Code).To(Equal(http.StatusUnsupportedMediaType))
+
+	rr = s.uploadFile(path, "big.txt", bytes.Repeat([]byte("a"), int(domain.MaxAttachmentSize)+1), user.ID)
+	Expect(rr.Code).To(Equal(http.StatusRequestEntityTooLarge))
+
+	rr = s.uploadFile(path, "notes.txt", []byte("plain notes"), other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("GET", path, "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+	Expect(rr.Body.String()).To(ContainSubstring(attachment.UUID.String()))
+
+	// Deleted attachments can no longer be downloaded, even with a valid link
+	rr = s.serveRequest("DELETE", path+"/"+attachment.UUID.String(), "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	req, _ = http.NewRequest("GET", attachment.DownloadURL, nil)
+	download = httptest.NewRecorder()
+	s.Router.ServeHTTP(download, req)
+
+	Expect(download.Code).To(Equal(http.StatusNotFound))
+}
+
+func (s *TodoHandlerSuite) TestAttachmentLinksNeedASigningKey() {
+	unkeyed := service.NewAttachmentService(nil, nil, nil, util.SystemClock{}, "", nil)
+	attachment := domain.Attachment{UUID: uuid.New()}
+
+	Expect(unkeyed.Sign(attachment)).To(Equal(domain.AttachmentLink{}))
+
+	// A link signed with an empty key would be forgeable, so none is honoured
+	expires := time.Now().Add(time.Hour).Unix()
+	forged := hmac.New(sha256.New, nil)
+	forged.Write([]byte(attachment.UUID.String() + "." + strconv.FormatInt(expires, 10)))
+
+	_, _, err := unkeyed.Download(context.Background(), attachment.UUID.String(), expires, hex.EncodeToString(forged.Sum(nil)))
+	Expect(err).To(MatchError(domain.ErrInvalidDownloadLink))
+
+	res, _ := json.Marshal(response.NewAttachmentResponse(attachment, unkeyed.Sign(attachment)))
+	Expect(string(res)).NotTo(ContainSubstring("download_url"))
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	. "todos/internal/adapter/http/helper"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/request"
+	"todos/internal/core/model/response"
+	"todos/internal/core/port"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CommentHandler struct {
+	svc port.CommentService
+}
+
+func NewCommentHandler(commentUseCase port.CommentService) *CommentHandler {
+	return &CommentHandler{
+		svc: commentUseCase,
+	}
+}
+
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	if limit <= 0 {
+		limit = 20
+	}
+
+	data, err := h.svc.List(c.Request.Context(), userId, c.Param("uuid"), limit, c.Query("cursor"))
+
+	if err != nil {
+		sendCommentError(c, err, "Error listing comments")
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	params, ok := bindRequest[request.CommentRequest](c)
+
+	if !ok {
+		return
+	}
+
+	comment := domain.Comment{Body: params.Body}
+
+	if params.ParentUUID != nil {
+		parent := uuid.MustParse(*params.ParentUUID)
+		comment.ParentUUID = &parent
+	}
+
+	comment, err := h.svc.Create(c.Request.Context(), userId, c.Param("uuid"), comment)
+
+	if err != nil {
+		sendCommentError(c, err, "Error creating comment")
+		return
+	}
+
+	SendSuccess(c, http.StatusCreated, response.NewCommentResponse(comment))
+}
+
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	params, ok := bindRequest[request.CommentRequest](c)
+
+	if !ok {
+		return
+	}
+
+	comment, err := h.svc.Update(c.Request.Context(), userId, c.Param("uuid"), c.Param("comment_uuid"), params.Body)
+
+	if err != nil {
+		sendCommentError(c, err, "Error updating comment")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewCommentResponse(comment))
+}
+
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	if err := h.svc.Delete(c.Request.Context(), userId, c.Param("uuid"), c.Param("comment_uuid")); err != nil {
+		sendCommentError(c, err, "Error deleting comment")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Comment deleted successfully",
+	})
+}
+
+// GetCommentHistory lists the bodies a comment had before its edits
+func (h *CommentHandler) GetCommentHistory(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	revisions, err := h.svc.History(c.Request.Context(), userId, c.Param("uuid"), c.Param("comment_uuid"))
+
+	if err != nil {
+		sendCommentError(c, err, "Error getting comment history")
+		return
+	}
+
+	data := make([]response.CommentRevisionResponse, 0, len(revisions))
+
+	for _, revision := range revisions {
+		data = append(data, response.NewCommentRevisionResponse(revision))
+	}
+
+	SendSuccess(c, http.StatusOK, data)
+}
+
+func sendCommentError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden):
+		sendTodoAccessError(c, err)
+	case errors.Is(err, domain.ErrCommentNotFound):
+		SendNotFoundError(c, "Comment not found")
+	case errors.Is(err, domain.ErrInvalidCommentParent):
+		SendBadRequestError(c, "parent_uuid", err.Error())
+	case errors.Is(err, domain.ErrInvalidCursor):
+		SendBadRequestError(c, "cursor", err.Error())
+	default:
+		slog.Error(message, "error", err, "uuid", c.Param("uuid"))
+		SendInternalError(c, message)
+	}
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	. "github.com/onsi/gomega"
+
+	"todos/internal/core/model/response"
+)
+
+func (s *TodoHandlerSuite) TestCommentThreadWithHistory() {
+	user := CreateUserMock(s)
+	other := CreateOtherUserMock(s)
+	todo := CreateTodo(s, user.ID)
+
+	path := "/todos/" + todo.UUID.String() + "/comments"
+
+	decode := func(body io.Reader) response.CommentResponse {
+		data := struct {
+			Data response.CommentResponse `json:"data"`
+		}{}
+		raw, _ := io.ReadAll(body)
+		json.Unmarshal(raw, &data)
+
+		return data.Data
+	}
+
+	rr := s.serveRequest("POST", path, `{"body": "Can we ship this today?"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	question := decode(rr.Body)
+	Expect(question.Author.UUID).To(Equal(user.UUID))
+	Expect(question.ParentUUID).To(BeNil())
+
+	rr = s.serveRequest("POST", path, fmt.Sprintf(`{"body": "Yes, after review", "parent_uuid": "%s"}`, question.UUID), user.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	answer := decode(rr.Body)
+	Expect(*answer.ParentUUID).To(Equal(question.UUID))
+
+	rr = s.serveRequest("POST", path, `{"body": ""}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	// Comments follow the todo's visibility
+	rr = s.serveRequest("GET", path, "", other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("POST", path, `{"body": "Hello?"}`, other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	// Edits keep the previous body
+	commentPath := path + "/" + question.UUID.String()
+
+	rr = s.serveRequest("PUT", commentPath, `{"body": "Can we ship this tomorrow?"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	edited := decode(rr.Body)
+	Expect(edited.Body).To(Equal("Can we ship this tomorrow?"))
+	Expect(edited.Edited).To(BeTrue())
+	Expect(edited.EditCount).To(Equal(1))
+
+	rr = s.serveRequest("GET", commentPath+"/history", "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	history := struct {
+		Data []response.CommentRevisionResponse `json:"data"`
+	}{}
+	raw, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(raw, &history)
+
+	Expect(history.Data).To(HaveLen(1))
+	Expect(history.Data[0].Body).To(Equal("Can we ship this today?"))
+
+	// Pages come oldest first and chain through next_cursor
+	rr = s.serveRequest("GET", path+"?limit=1", "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	page := response.CursorResponse{}
+	raw, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(raw, &page)
+
+	var comments []response.CommentResponse
+	json.Unmarshal(page.Data, &comments)
+
+	Expect(comments).To(HaveLen(1))
+	Expect(comments[0].UUID).To(Equal(question.UUID))
+	Expect(page.Pagination.HasNext).To(BeTrue())
+
+	rr = s.serveRequest("GET", path+"?limit=1&cursor="+url.QueryEscape(page.Pagination.NextCursor), "", user.ID)
+	raw, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(raw, &page)
+	json.Unmarshal(page.Data, &comments)
+
+	Expect(comments).To(HaveLen(1))
+	Expect(comments[0].UUID).To(Equal(answer.UUID))
+	Expect(page.Pagination.HasNext).To(BeFalse())
+
+	rr = s.serveRequest("DELETE", path+"/"+answer.UUID.String(), "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("GET", path+"/"+answer.UUID.String()+"/history", "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+}
@@ -0,0 +1,355 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	. "todos/internal/adapter/http/helper"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/request"
+	"todos/internal/core/model/response"
+	"todos/internal/core/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProjectHandler struct {
+	svc port.ProjectService
+}
+
+func NewProjectHandler(projectUseCase port.ProjectService) *ProjectHandler {
+	return &ProjectHandler{
+		svc: projectUseCase,
+	}
+}
+
+// GetProjects lists the active projects, or the archived ones with ?archived=true
+func (p *ProjectHandler) GetProjects(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	var archived bool
+
+	if value := c.Query("archived"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+
+		if err != nil {
+			SendBadRequestError(c, "archived", "invalid archived: "+value)
+			return
+		}
+
+		archived = parsed
+	}
+
+	projects, err := p.svc.List(c.Request.Context(), userId, archived)
+
+	if err != nil {
+		sendProjectError(c, err, "Error listing projects")
+		return
+	}
+
+	data := make([]response.ProjectResponse, 0, len(projects))
+
+	for _, project := range projects {
+		data = append(data, response.NewProjectResponse(project))
+	}
+
+	SendSuccess(c, http.StatusOK, data)
+}
+
+func (p *ProjectHandler) GetProject(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	project, err := p.svc.GetByUUID(c.Request.Context(), userId, c.Param("uuid"))
+
+	if err != nil {
+		sendProjectError(c, err, "Error getting project")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewProjectResponse(project))
+}
+
+func (p *ProjectHandler) CreateProject(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	params, ok := bindRequest[request.ProjectRequest](c)
+
+	if !ok {
+		return
+	}
+
+	if params.Name == nil {
+		SendBadRequestError(c, "name", "name is required")
+		return
+	}
+
+	project := domain.Project{
+		UserId: userId,
+		Name:   *params.Name,
+	}
+
+	if params.Description != nil {
+		project.Description = *params.Description
+	}
+
+	project, err := p.svc.Create(c.Request.Context(), project)
+
+	if err != nil {
+		sendProjectError(c, err, "Error creating project")
+		return
+	}
+
+	SendSuccess(c, http.StatusCreated, response.NewProjectResponse(project))
+}
+
+func (p *ProjectHandler) UpdateProject(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	params, ok := bindRequest[request.ProjectRequest](c)
+
+	if !ok {
+		return
+	}
+
+	project, err := p.svc.Update(c.Request.Context(), userId, c.Param("uuid"), domain.ProjectChanges{
+		Name:        params.Name,
+		Description: params.Description,
+		Position:    params.Position,
+	})
+
+	if err != nil {
+		sendProjectError(c, err, "Error updating project")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewProjectResponse(project))
+}
+
+func (p *ProjectHandler) ArchiveProject(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	project, err := p.svc.Archive(c.Request.Context(), userId, c.Param("uuid"))
+
+	if err != nil {
+		sendProjectError(c, err, "Error archiving project")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewProjectResponse(project))
+}
+
+func (p *ProjectHandler) UnarchiveProject(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	project, err := p.svc.Unarchive(c.Request.Context(), userId, c.Param("uuid"))
+
+	if err != nil {
+		sendProjectError(c, err, "Error unarchiving project")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewProjectResponse(project))
+}
+
+// ReorderProject sets the order of the todos inside a project
+func (p *ProjectHandler) ReorderProject(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	params, ok := bindRequest[request.ProjectOrderRequest](c)
+
+	if !ok {
+		return
+	}
+
+	if err := p.svc.Reorder(c.Request.Context(), userId, c.Param("uuid"), params.Todos); err != nil {
+		sendProjectError(c, err, "Error reordering project")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project reordered successfully",
+	})
+}
+
+func (p *ProjectHandler) DeleteProject(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	if err := p.svc.Delete(c.Request.Context(), userId, c.Param("uuid")); err != nil {
+		sendProjectError(c, err, "Error deleting project")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project deleted successfully",
+	})
+}
+
+func (p *ProjectHandler) GetMembers(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	members, err := p.svc.ListMembers(c.Request.Context(), userId, c.Param("uuid"))
+
+	if err != nil {
+		sendProjectError(c, err, "Error listing project members")
+		return
+	}
+
+	data := make([]response.ProjectMemberResponse, 0, len(members))
+
+	for _, member := range members {
+		data = append(data, response.NewProjectMemberResponse(member))
+	}
+
+	SendSuccess(c, http.StatusOK, data)
+}
+
+// InviteMember shares the project with an existing user, looked up by email
+func (p *ProjectHandler) InviteMember(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	params, ok := bindRequest[request.ProjectMemberRequest](c)
+
+	if !ok {
+		return
+	}
+
+	member, err := p.svc.InviteMember(c.Request.Context(), userId, c.Param("uuid"), params.Email, domain.ProjectRole(params.Role))
+
+	if err != nil {
+		sendProjectError(c, err, "Error inviting project member")
+		return
+	}
+
+	SendSuccess(c, http.StatusCreated, response.NewProjectMemberResponse(member))
+}
+
+func (p *ProjectHandler) UpdateMember(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	params, ok := bindRequest[request.ProjectRoleRequest](c)
+
+	if !ok {
+		return
+	}
+
+	member, err := p.svc.UpdateMemberRole(c.Request.Context(), userId, c.Param("uuid"), c.Param("user_uuid"), domain.ProjectRole(params.Role))
+
+	if err != nil {
+		sendProjectError(c, err, "Error updating project member")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewProjectMemberResponse(member))
+}
+
+func (p *ProjectHandler) RemoveMember(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	if err := p.svc.RemoveMember(c.Request.Context(), userId, c.Param("uuid"), c.Param("user_uuid")); err != nil {
+		sendProjectError(c, err, "Error removing project member")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project member removed successfully",
+	})
+}
+
+func (p *ProjectHandler) GetWorkflow(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	workflow, err := p.svc.GetWorkflow(c.Request.Context(), userId, c.Param("uuid"))
+
+	if err != nil {
+		sendProjectError(c, err, "Error getting project workflow")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewWorkflowResponse(workflow))
+}
+
+// UpdateWorkflow replaces the statuses and transitions todos of the project
+// follow
+func (p *ProjectHandler) UpdateWorkflow(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	params, ok := bindRequest[request.WorkflowRequest](c)
+
+	if !ok {
+		return
+	}
+
+	var workflow domain.Workflow
+
+	for _, status := range params.Statuses {
+		workflow.Statuses = append(workflow.Statuses, domain.WorkflowStatus{
+			Key:      domain.TodoStatus(status.Key),
+			Name:     status.Name,
+			Terminal: status.Terminal,
+		})
+	}
+
+	for _, transition := range params.Transitions {
+		workflow.Transitions = append(workflow.Transitions, domain.WorkflowTransition{
+			From: domain.TodoStatus(transition.From),
+			To:   domain.TodoStatus(transition.To),
+		})
+	}
+
+	if params.ReviewRule != nil {
+		workflow.ReviewRule = domain.ReviewRule(*params.ReviewRule)
+	}
+
+	workflow, err := p.svc.DefineWorkflow(c.Request.Context(), userId, c.Param("uuid"), workflow)
+
+	if err != nil {
+		sendProjectError(c, err, "Error updating project workflow")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewWorkflowResponse(workflow))
+}
+
+// ResetWorkflow puts the project back on the default workflow
+func (p *ProjectHandler) ResetWorkflow(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	workflow, err := p.svc.ResetWorkflow(c.Request.Context(), userId, c.Param("uuid"))
+
+	if err != nil {
+		sendProjectError(c, err, "Error resetting project workflow")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewWorkflowResponse(workflow))
+}
+
+func sendProjectError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrProjectNotFound):
+		SendNotFoundError(c, "Project not found")
+	case errors.Is(err, domain.ErrForbidden):
+		SendForbiddenError(c, "You are not allowed to perform this action")
+	case errors.Is(err, domain.ErrInvalidProjectOrder):
+		SendBadRequestError(c, "todos", err.Error())
+	case errors.Is(err, domain.ErrInvalidProjectRole):
+		SendBadRequestError(c, "role", err.Error())
+	case errors.Is(err, domain.ErrUserNotFound):
+		SendNotFoundError(c, "No user with this email")
+	case errors.Is(err, domain.ErrProjectMemberNotFound):
+		SendNotFoundError(c, "Project member not found")
+	case errors.Is(err, domain.ErrProjectMemberExists):
+		SendConflictError(c, "email", err.Error())
+	case errors.Is(err, domain.ErrLastProjectOwner):
+		SendConflictError(c, "role", err.Error())
+	case errors.Is(err, domain.ErrInvalidWorkflow):
+		SendBadRequestError(c, "workflow", err.Error())
+	case errors.Is(err, domain.ErrStatusInUse):
+		SendConflictError(c, "statuses", err.Error())
+	default:
+		slog.Error(message, "error", err)
+		SendInternalError(c, message)
+	}
+}
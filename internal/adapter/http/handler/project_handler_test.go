@@ -0,0 +1,361 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/gomega"
+
+	"todos/internal/core/model/response"
+)
+
+func (s *TodoHandlerSuite) TestProjectsGroupAndArchiveTodos() {
+	user := CreateUserMock(s)
+	other := CreateOtherUserMock(s)
+
+	rr := s.serveRequest("POST", "/projects", `{"name": "Garden"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	project := struct {
+		Data response.ProjectResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &project)
+
+	projectUUID := project.Data.UUID.String()
+
+	rr = s.serveRequest("POST", "/todos", fmt.Sprintf(`{"title": "Plant tulips", "project_uuid": "%s"}`, projectUUID), user.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	created := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &created)
+
+	Expect(created.Data.ProjectUUID.String()).To(Equal(projectUUID))
+
+	rr = s.serveRequest("POST", "/todos", `{"title": "Pay rent"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	// Other users' projects cannot be used
+	rr = s.serveRequest("POST", "/todos", fmt.Sprintf(`{"title": "Sneak in", "project_uuid": "%s"}`, projectUUID), other.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("GET", "/projects/"+projectUUID, "", other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	listTitles := func(query string) []string {
+		rr := s.serveRequest("GET", "/todos?"+query, "", user.ID)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+
+		data := response.CursorResponse{}
+		body, _ := io.ReadAll(rr.Body)
+		json.Unmarshal(body, &data)
+
+		var todos []response.TodoResponse
+		json.Unmarshal(data.Data, &todos)
+
+		titles := []string{}
+		for _, todo := range todos {
+			titles = append(titles, todo.Title)
+		}
+
+		return titles
+	}
+
+	Expect(listTitles("project=" + projectUUID)).To(ConsistOf("Plant tulips"))
+	Expect(listTitles("project=none")).To(ConsistOf("Pay rent"))
+
+	rr = s.serveRequest("POST", "/projects/"+projectUUID+"/archive", "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	Expect(listTitles("")).To(ConsistOf("Pay rent"))
+	Expect(listTitles("include_archived=true")).To(ConsistOf("Pay rent", "Plant tulips"))
+
+	rr = s.serveRequest("GET", "/projects?archived=true", "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	archived := struct {
+		Data []response.ProjectResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &archived)
+
+	Expect(archived.Data).To(HaveLen(1))
+	Expect(archived.Data[0].Archived).To(BeTrue())
+	Expect(archived.Data[0].TodoCount).To(Equal(1))
+
+	rr = s.serveRequest("POST", "/todos", fmt.Sprintf(`{"title": "Rake leaves", "project_uuid": "%s"}`, projectUUID), user.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+
+	rr = s.serveRequest("POST", "/projects/"+projectUUID+"/unarchive", "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	// An empty project_uuid moves the todo out of the project
+	rr = s.serveRequest("PUT", "/todo/"+created.Data.UUID.String(), `{"title": "Plant tulips", "project_uuid": ""}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	Expect(listTitles("project=" + projectUUID)).To(BeEmpty())
+
+	rr = s.serveRequest("DELETE", "/projects/"+projectUUID, "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("GET", "/projects/"+projectUUID, "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+}
+
+func (s *TodoHandlerSuite) TestProjectTodoOrder() {
+	user := CreateUserMock(s)
+
+	rr := s.serveRequest("POST", "/projects", `{"name": "Move"}`, user.ID)
+
+	project := struct {
+		Data response.ProjectResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &project)
+
+	projectUUID := project.Data.UUID.String()
+
+	uuids := []string{}
+	for _, title := range []string{"Pack boxes", "Hire van", "Clean flat"} {
+		rr := s.serveRequest("POST", "/todos", fmt.Sprintf(`{"title": "%s", "project_uuid": "%s"}`, title, projectUUID), user.ID)
+		Expect(rr.Code).To(Equal(http.StatusCreated))
+
+		created := struct {
+			Data response.TodoResponse `json:"data"`
+		}{}
+		body, _ := io.ReadAll(rr.Body)
+		json.Unmarshal(body, &created)
+
+		uuids = append(uuids, created.Data.UUID.String())
+	}
+
+	order := fmt.Sprintf(`{"todos": ["%s", "%s", "%s"]}`, uuids[1], uuids[2], uuids[0])
+	rr = s.serveRequest("PUT", "/projects/"+projectUUID+"/order", order, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("GET", "/todos?project="+projectUUID+"&sort=position", "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	data := response.CursorResponse{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &data)
+
+	var todos []response.TodoResponse
+	json.Unmarshal(data.Data, &todos)
+
+	titles := []string{}
+	for _, todo := range todos {
+		titles = append(titles, todo.Title)
+	}
+
+	Expect(titles).To(Equal([]string{"Hire van", "Clean flat", "Pack boxes"}))
+
+	rr = s.serveRequest("PUT", "/projects/"+projectUUID+"/order", fmt.Sprintf(`{"todos": ["%s"]}`, uuids[0]), user.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+}
+
+func (s *TodoHandlerSuite) TestProjectSharingRoles() {
+	owner := CreateUserMock(s)
+	member := CreateOtherUserMock(s)
+
+	rr := s.serveRequest("POST", "/projects", `{"name": "Team"}`, owner.ID)
+
+	project := struct {
+		Data response.ProjectResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &project)
+
+	Expect(project.Data.Role).To(Equal("owner"))
+
+	projectUUID := project.Data.UUID.String()
+	membersPath := "/projects/" + projectUUID + "/members"
+
+	rr = s.serveRequest("POST", "/todos", fmt.Sprintf(`{"title": "Ship release", "project_uuid": "%s"}`, projectUUID), owner.ID)
+
+	created := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &created)
+
+	todoPath := "/todo/" + created.Data.UUID.String()
+
+	// Outsiders cannot see the todo at all
+	rr = s.serveRequest("GET", "/todos/"+created.Data.UUID.String(), "", member.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("POST", membersPath, `{"email": "nobody@example.com", "role": "viewer"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("POST", membersPath, `{"email": "user100@example.com", "role": "admin"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("POST", membersPath, `{"email": "user100@example.com", "role": "viewer"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	rr = s.serveRequest("POST", membersPath, `{"email": "user100@example.com", "role": "editor"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+
+	// Viewers read the shared todo and the project list, but cannot change anything
+	rr = s.serveRequest("GET", "/todos/"+created.Data.UUID.String(), "", member.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("GET", "/todos?project="+projectUUID, "", member.ID)
+	data := response.CursorResponse{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &data)
+	Expect(data.Size).To(Equal(1))
+
+	rr = s.serveRequest("PUT", todoPath, `{"title": "Ship it now"}`, member.ID)
+	Expect(rr.Code).To(Equal(http.StatusForbidden))
+
+	rr = s.serveRequest("POST", "/todos", fmt.Sprintf(`{"title": "Sneak in", "project_uuid": "%s"}`, projectUUID), member.ID)
+	Expect(rr.Code).To(Equal(http.StatusForbidden))
+
+	rr = s.serveRequest("POST", membersPath, `{"email": "user99@example.com", "role": "viewer"}`, member.ID)
+	Expect(rr.Code).To(Equal(http.StatusForbidden))
+
+	// Editors can update the shared todo
+	rr = s.serveRequest("PUT", membersPath+"/"+member.UUID.String(), `{"role": "editor"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("PUT", todoPath, `{"title": "Ship it now"}`, member.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("GET", membersPath, "", member.ID)
+	members := struct {
+		Data []response.ProjectMemberResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &members)
+
+	roles := map[string]string{}
+	for _, m := range members.Data {
+		roles[m.Email] = m.Role
+	}
+
+	Expect(roles).To(Equal(map[string]string{"user99@example.com": "owner", "user100@example.com": "editor"}))
+
+	// The last owner can neither step down nor leave
+	rr = s.serveRequest("PUT", membersPath+"/"+owner.UUID.String(), `{"role": "viewer"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+
+	rr = s.serveRequest("DELETE", membersPath+"/"+owner.UUID.String(), "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+
+	// Members can leave on their own, which hides the project again
+	rr = s.serveRequest("DELETE", membersPath+"/"+member.UUID.String(), "", member.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("GET", "/projects/"+projectUUID, "", member.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("GET", "/todos/"+created.Data.UUID.String(), "", member.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+}
+
+func (s *TodoHandlerSuite) TestProjectWorkflow() {
+	user := CreateUserMock(s)
+
+	rr := s.serveRequest("POST", "/projects", `{"name": "Support"}`, user.ID)
+
+	project := struct {
+		Data response.ProjectResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &project)
+
+	workflowPath := "/projects/" + project.Data.UUID.String() + "/workflow"
+
+	readWorkflow := func(rr *httptest.ResponseRecorder) response.WorkflowResponse {
+		data := struct {
+			Data response.WorkflowResponse `json:"data"`
+		}{}
+		body, _ := io.ReadAll(rr.Body)
+		json.Unmarshal(body, &data)
+		return data.Data
+	}
+
+	// Projects start on the default workflow
+	rr = s.serveRequest("GET", workflowPath, "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	workflow := readWorkflow(rr)
+	Expect(workflow.Default).To(BeTrue())
+	Expect(workflow.Statuses).To(HaveLen(4))
+
+	rr = s.serveRequest("PUT", workflowPath, `{"statuses": [{"key": "done", "name": "Done", "terminal": true}]}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("PUT", workflowPath, `{
+		"statuses": [
+			{"key": "pending", "name": "New"},
+			{"key": "triaged", "name": "Triaged"},
+			{"key": "resolved", "name": "Resolved", "terminal": true},
+			{"key": "wont_fix", "name": "Won't fix", "terminal": true}
+		],
+		"transitions": [
+			{"from": "pending", "to": "triaged"},
+			{"from": "pending", "to": "wont_fix"},
+			{"from": "triaged", "to": "resolved"}
+		]
+	}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	workflow = readWorkflow(rr)
+	Expect(workflow.Default).To(BeFalse())
+	Expect(workflow.Transitions).To(HaveLen(3))
+
+	rr = s.serveRequest("POST", "/todos", fmt.Sprintf(`{"title": "Login fails", "project_uuid": "%s"}`, project.Data.UUID), user.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	created := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &created)
+
+	Expect(created.Data.Status).To(Equal("pending"))
+
+	todoPath := "/todo/" + created.Data.UUID.String()
+
+	rr = s.serveRequest("PUT", todoPath, `{"title": "Login fails", "status": "in_review"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	// Skipping triage is not allowed
+	rr = s.serveRequest("PUT", todoPath, `{"title": "Login fails", "status": "resolved"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+
+	rr = s.serveRequest("PUT", todoPath, `{"title": "Login fails", "status": "triaged"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	// completed moves to the first terminal status the workflow allows
+	rr = s.serveRequest("PUT", todoPath, `{"title": "Login fails", "completed": true}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	updated := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &updated)
+
+	Expect(updated.Data.Status).To(Equal("resolved"))
+	Expect(updated.Data.Completed).To(BeTrue())
+
+	// Statuses holding todos cannot be dropped
+	rr = s.serveRequest("PUT", workflowPath, `{"statuses": [{"key": "pending", "name": "New"}, {"key": "closed", "name": "Closed", "terminal": true}]}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+
+	rr = s.serveRequest("DELETE", workflowPath, "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+
+	rr = s.serveRequest("PUT", todoPath, `{"title": "Login fails", "status": "pending"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	. "todos/internal/adapter/http/helper"
+	. "todos/internal/adapter/http/validation"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/request"
+	"todos/internal/core/model/response"
+	"todos/internal/core/port"
+	"todos/internal/core/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReminderHandler struct {
+	svc port.ReminderService
+}
+
+func NewReminderHandler(reminderUseCase port.ReminderService) *ReminderHandler {
+	return &ReminderHandler{
+		svc: reminderUseCase,
+	}
+}
+
+func (r *ReminderHandler) GetReminders(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+	ctx := c.Request.Context()
+
+	reminders, todo, err := r.svc.ListByTodo(ctx, userId, c.Param("uuid"))
+
+	if err != nil {
+		if errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden) {
+			sendTodoAccessError(c, err)
+			return
+		}
+
+		slog.Error("Error listing reminders", "error", err, "uuid", c.Param("uuid"))
+		SendInternalError(c, "Error listing reminders")
+		return
+	}
+
+	data := make([]response.ReminderResponse, 0, len(reminders))
+
+	for _, reminder := range reminders {
+		data = append(data, response.NewReminderResponse(reminder, todo))
+	}
+
+	SendSuccess(c, http.StatusOK, data)
+}
+
+func (r *ReminderHandler) CreateReminder(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+	ctx := c.Request.Context()
+
+	params, err := util.ParamsToMap[request.ReminderRequest](c)
+
+	if err != nil {
+		SendBadRequestError(c, "request", "Invalid request parameters")
+		return
+	}
+
+	if err := Validator.Struct(params); err != nil {
+		SendValidationError(c, err)
+		return
+	}
+
+	reminder := domain.Reminder{
+		Kind:          domain.ReminderKind(params.Kind),
+		OffsetMinutes: params.OffsetMinutes,
+	}
+
+	if reminder.Kind == domain.ReminderDayOf {
+		reminder.TimeOfDay, err = domain.ParseTimeOfDay(params.TimeOfDay)
+
+		if err != nil {
+			SendBadRequestError(c, "time_of_day", err.Error())
+			return
+		}
+	}
+
+	reminder, todo, err := r.svc.Create(ctx, userId, c.Param("uuid"), reminder)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden):
+			sendTodoAccessError(c, err)
+		case errors.Is(err, domain.ErrTodoHasNoDueDate):
+			SendBadRequestError(c, "due_at", "Todo needs a due date before it can have reminders")
+		case errors.Is(err, domain.ErrInvalidReminder):
+			SendBadRequestError(c, "reminder", err.Error())
+		default:
+			slog.Error("Error creating reminder", "error", err, "uuid", c.Param("uuid"))
+			SendInternalError(c, "Error creating reminder")
+		}
+
+		return
+	}
+
+	SendSuccess(c, http.StatusCreated, response.NewReminderResponse(reminder, todo))
+}
+
+func (r *ReminderHandler) DeleteReminder(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+	ctx := c.Request.Context()
+
+	err := r.svc.DeleteByUUID(ctx, userId, c.Param("uuid"), c.Param("reminder_uuid"))
+
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden):
+			sendTodoAccessError(c, err)
+		case errors.Is(err, domain.ErrReminderNotFound):
+			SendNotFoundError(c, "Reminder not found")
+		default:
+			SendInternalError(c, "Error deleting reminder")
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reminder deleted successfully",
+	})
+}
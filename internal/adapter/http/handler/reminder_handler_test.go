@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+
+	factory "todos/pkg/test/factory"
+)
+
+func (s *TodoHandlerSuite) TestCreateAndListReminders() {
+	user := CreateUserMock(s)
+	dueAt := time.Date(2030, 1, 15, 18, 30, 0, 0, time.UTC)
+
+	todo, _ := s.TodoRepo.Create(ctx, factory.NewTodo[domain.Todo](map[string]any{
+		"Title":  "Dentist",
+		"DueAt":  &dueAt,
+		"UserId": user.ID,
+	}))
+
+	path := "/todos/" + todo.UUID.String() + "/reminders"
+
+	rr := s.serveRequest("POST", path, `{"kind": "before_due", "offset_minutes": 30}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	created := struct {
+		Data response.ReminderResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &created)
+
+	Expect(created.Data.RemindAt.Equal(dueAt.Add(-30 * time.Minute))).To(BeTrue())
+
+	rr = s.serveRequest("POST", path, `{"kind": "day_of", "time_of_day": "08:15"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	rr = s.serveRequest("GET", path, "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	listed := struct {
+		Data []response.ReminderResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &listed)
+
+	Expect(listed.Data).To(HaveLen(2))
+	Expect(listed.Data[1].TimeOfDay).To(Equal("08:15"))
+
+	rr = s.serveRequest("DELETE", path+"/"+created.Data.UUID.String(), "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("DELETE", path+"/"+created.Data.UUID.String(), "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+}
+
+func (s *TodoHandlerSuite) TestCreateReminderValidation() {
+	user := CreateUserMock(s)
+	other := CreateOtherUserMock(s)
+	undated, _ := s.TodoRepo.Create(ctx, factory.NewTodo[domain.Todo](map[string]any{
+		"Title":  "Someday",
+		"DueAt":  (*time.Time)(nil),
+		"UserId": user.ID,
+	}))
+
+	path := "/todos/" + undated.UUID.String() + "/reminders"
+
+	rr := s.serveRequest("POST", path, `{"kind": "before_due", "offset_minutes": 30}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("POST", path, `{"kind": "whenever"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("POST", path, `{"kind": "day_of", "time_of_day": "25:00"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("GET", path, "", other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	. "todos/internal/adapter/http/helper"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/request"
+	"todos/internal/core/model/response"
+	"todos/internal/core/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	svc port.ReviewService
+}
+
+func NewReviewHandler(reviewUseCase port.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		svc: reviewUseCase,
+	}
+}
+
+func (h *ReviewHandler) GetReviews(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	reviews, err := h.svc.List(c.Request.Context(), userId, c.Param("uuid"))
+
+	if err != nil {
+		sendReviewError(c, err, "Error listing reviews")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, newReviewResponses(reviews))
+}
+
+// RequestReview asks users, by email, to approve or reject the todo
+func (h *ReviewHandler) RequestReview(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	params, ok := bindRequest[request.ReviewRequest](c)
+
+	if !ok {
+		return
+	}
+
+	reviews, err := h.svc.Request(c.Request.Context(), userId, c.Param("uuid"), params.Reviewers)
+
+	if err != nil {
+		sendReviewError(c, err, "Error requesting review")
+		return
+	}
+
+	SendSuccess(c, http.StatusCreated, newReviewResponses(reviews))
+}
+
+func (h *ReviewHandler) ApproveTodo(c *gin.Context) {
+	h.decide(c, domain.ReviewApproved)
+}
+
+// RejectTodo needs a comment telling what to change
+func (h *ReviewHandler) RejectTodo(c *gin.Context) {
+	h.decide(c, domain.ReviewRejected)
+}
+
+// GetWaitingReviews lists the reviews the caller has yet to decide
+func (h *ReviewHandler) GetWaitingReviews(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	if limit <= 0 {
+		limit = 20
+	}
+
+	data, err := h.svc.Waiting(c.Request.Context(), userId, limit, c.Query("cursor"))
+
+	if err != nil {
+		sendReviewError(c, err, "Error listing waiting reviews")
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *ReviewHandler) decide(c *gin.Context, decision domain.ReviewDecision) {
+	userId := c.GetInt("x-user-id")
+
+	var params request.ReviewDecisionRequest
+
+	if c.Request.ContentLength != 0 {
+		var ok bool
+
+		if params, ok = bindRequest[request.ReviewDecisionRequest](c); !ok {
+			return
+		}
+	}
+
+	review, err := h.svc.Decide(c.Request.Context(), userId, c.Param("uuid"), decision, params.Comment)
+
+	if err != nil {
+		sendReviewError(c, err, "Error deciding review")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewReviewResponse(review))
+}
+
+func newReviewResponses(reviews []domain.Review) []response.ReviewResponse {
+	data := make([]response.ReviewResponse, 0, len(reviews))
+
+	for _, review := range reviews {
+		data = append(data, response.NewReviewResponse(review))
+	}
+
+	return data
+}
+
+func sendReviewError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden):
+		sendTodoAccessError(c, err)
+	case errors.Is(err, domain.ErrReviewNotFound):
+		SendNotFoundError(c, "You were not asked to review this todo")
+	case errors.Is(err, domain.ErrSelfReview) || errors.Is(err, domain.ErrReviewerNotAllowed):
+		SendBadRequestError(c, "reviewers", err.Error())
+	case errors.Is(err, domain.ErrReviewCommentRequired) || errors.Is(err, domain.ErrInvalidReviewDecision):
+		SendBadRequestError(c, "comment", err.Error())
+	case errors.Is(err, domain.ErrTodoNotInReview):
+		SendConflictError(c, "status", err.Error())
+	case errors.Is(err, domain.ErrInvalidCursor):
+		SendBadRequestError(c, "cursor", err.Error())
+	default:
+		slog.Error(message, "error", err, "uuid", c.Param("uuid"))
+		SendInternalError(c, message)
+	}
+}
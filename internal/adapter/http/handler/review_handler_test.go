@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	. "github.com/onsi/gomega"
+
+	"todos/internal/core/model/response"
+)
+
+func (s *TodoHandlerSuite) TestReviewWorkflow() {
+	owner := CreateUserMock(s)
+	reviewer := CreateOtherUserMock(s)
+
+	rr := s.serveRequest("POST", "/projects", `{"name": "Docs"}`, owner.ID)
+
+	project := struct {
+		Data response.ProjectResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &project)
+
+	rr = s.serveRequest("POST", "/todos", fmt.Sprintf(`{"title": "Write guide", "status": "in_review", "project_uuid": "%s"}`, project.Data.UUID), owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	created := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &created)
+
+	reviewsPath := "/todos/" + created.Data.UUID.String() + "/reviews"
+	todoPath := "/todo/" + created.Data.UUID.String()
+
+	waiting := func() []response.ReviewResponse {
+		rr := s.serveRequest("GET", "/reviews/waiting", "", reviewer.ID)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+
+		data := response.CursorResponse{}
+		body, _ := io.ReadAll(rr.Body)
+		json.Unmarshal(body, &data)
+
+		var reviews []response.ReviewResponse
+		json.Unmarshal(data.Data, &reviews)
+
+		return reviews
+	}
+
+	// Reviewers must be able to see the todo, and cannot be the requester
+	rr = s.serveRequest("POST", reviewsPath, `{"reviewers": ["user100@example.com"]}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	Expect(rr.Body.String()).To(ContainSubstring("reviewer cannot see the todo: user100@example.com"))
+
+	// An unknown email cannot be told apart from a user without access
+	rr = s.serveRequest("POST", reviewsPath, `{"reviewers": ["nobody@example.com"]}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	Expect(rr.Body.String()).To(ContainSubstring("reviewer cannot see the todo: nobody@example.com"))
+
+	rr = s.serveRequest("POST", reviewsPath, `{"reviewers": ["user99@example.com"]}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("POST", "/projects/"+project.Data.UUID.String()+"/members", `{"email": "user100@example.com", "role": "viewer"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	rr = s.serveRequest("POST", reviewsPath, `{"reviewers": ["user100@example.com"]}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	queue := waiting()
+	Expect(queue).To(HaveLen(1))
+	Expect(queue[0].Todo.Title).To(Equal("Write guide"))
+	Expect(queue[0].Decision).To(Equal("pending"))
+
+	rr = s.serveRequest("PUT", todoPath, `{"title": "Write guide", "completed": true}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+
+	// Stepping out of review first does not get around the rule
+	rr = s.serveRequest("PATCH", "/todos/"+created.Data.UUID.String(), `{"status": "in_progress"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("PUT", todoPath, `{"title": "Write guide", "completed": true}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+
+	// Nor does the queue hold todos that cannot be decided on
+	Expect(waiting()).To(BeEmpty())
+
+	rr = s.serveRequest("PATCH", "/todos/"+created.Data.UUID.String(), `{"status": "in_review"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	// Only the requested reviewers decide, and rejecting needs a comment
+	rr = s.serveRequest("POST", reviewsPath+"/approve", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("POST", reviewsPath+"/reject", "", reviewer.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("POST", reviewsPath+"/reject", `{"comment": "Add an example"}`, reviewer.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+	Expect(waiting()).To(BeEmpty())
+
+	rr = s.serveRequest("PUT", todoPath, `{"title": "Write guide", "completed": true}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+
+	// Asking again starts a new round
+	rr = s.serveRequest("POST", reviewsPath, `{"reviewers": ["user100@example.com"]}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+	Expect(waiting()).To(HaveLen(1))
+
+	// A reviewer taken off the project no longer sees the request
+	rr = s.serveRequest("DELETE", "/projects/"+project.Data.UUID.String()+"/members/"+reviewer.UUID.String(), "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+	Expect(waiting()).To(BeEmpty())
+
+	rr = s.serveRequest("POST", "/projects/"+project.Data.UUID.String()+"/members", `{"email": "user100@example.com", "role": "viewer"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+	Expect(waiting()).To(HaveLen(1))
+
+	rr = s.serveRequest("POST", reviewsPath+"/approve", `{"comment": "Looks good"}`, reviewer.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("GET", reviewsPath, "", owner.ID)
+	reviews := struct {
+		Data []response.ReviewResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &reviews)
+
+	Expect(reviews.Data).To(HaveLen(1))
+	Expect(reviews.Data[0].Decision).To(Equal("approved"))
+	Expect(*reviews.Data[0].Comment).To(Equal("Looks good"))
+
+	rr = s.serveRequest("PUT", todoPath, `{"title": "Write guide", "completed": true}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	// Once the todo left in_review the reviews are closed
+	rr = s.serveRequest("POST", reviewsPath+"/reject", `{"comment": "Too late"}`, reviewer.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	. "todos/internal/adapter/http/helper"
+	. "todos/internal/adapter/http/validation"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/request"
+	"todos/internal/core/model/response"
+	"todos/internal/core/port"
+	"todos/internal/core/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	svc port.TagService
+}
+
+func NewTagHandler(tagUseCase port.TagService) *TagHandler {
+	return &TagHandler{
+		svc: tagUseCase,
+	}
+}
+
+func (t *TagHandler) GetTags(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	tags, err := t.svc.List(c.Request.Context(), userId)
+
+	if err != nil {
+		sendTagError(c, err, "Error listing tags")
+		return
+	}
+
+	data := make([]response.TagResponse, 0, len(tags))
+
+	for _, tag := range tags {
+		data = append(data, response.NewTagResponse(tag))
+	}
+
+	SendSuccess(c, http.StatusOK, data)
+}
+
+func (t *TagHandler) CreateTag(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	params, ok := bindRequest[request.TagRequest](c)
+
+	if !ok {
+		return
+	}
+
+	tag, err := t.svc.Create(c.Request.Context(), userId, params.Name)
+
+	if err != nil {
+		sendTagError(c, err, "Error creating tag")
+		return
+	}
+
+	SendSuccess(c, http.StatusCreated, response.NewTagResponse(tag))
+}
+
+func (t *TagHandler) RenameTag(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	params, ok := bindRequest[request.TagRequest](c)
+
+	if !ok {
+		return
+	}
+
+	tag, err := t.svc.Rename(c.Request.Context(), userId, c.Param("uuid"), params.Name)
+
+	if err != nil {
+		sendTagError(c, err, "Error renaming tag")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewTagResponse(tag))
+}
+
+func (t *TagHandler) MergeTag(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	params, ok := bindRequest[request.TagMergeRequest](c)
+
+	if !ok {
+		return
+	}
+
+	tag, err := t.svc.Merge(c.Request.Context(), userId, c.Param("uuid"), params.Into)
+
+	if err != nil {
+		sendTagError(c, err, "Error merging tags")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewTagResponse(tag))
+}
+
+func (t *TagHandler) DeleteTag(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	if err := t.svc.Delete(c.Request.Context(), userId, c.Param("uuid")); err != nil {
+		sendTagError(c, err, "Error deleting tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Tag deleted successfully",
+	})
+}
+
+func bindRequest[T any](c *gin.Context) (T, bool) {
+	params, err := util.ParamsToMap[T](c)
+
+	if err != nil {
+		SendBadRequestError(c, "request", "Invalid request parameters")
+		return params, false
+	}
+
+	if err := Validator.Struct(params); err != nil {
+		SendValidationError(c, err)
+		return params, false
+	}
+
+	return params, true
+}
+
+func sendTagError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrTagNotFound):
+		SendNotFoundError(c, "Tag not found")
+	case errors.Is(err, domain.ErrTagExists):
+		SendConflictError(c, "name", "A tag with this name already exists, merge the tags instead")
+	case errors.Is(err, domain.ErrInvalidTagName):
+		SendBadRequestError(c, "name", err.Error())
+	case errors.Is(err, domain.ErrTagMergeSelf):
+		SendBadRequestError(c, "into", err.Error())
+	default:
+		slog.Error(message, "error", err)
+		SendInternalError(c, message)
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	. "github.com/onsi/gomega"
+
+	"todos/internal/core/model/response"
+)
+
+func (s *TodoHandlerSuite) TestTagsOnTodosAndFilters() {
+	user := CreateUserMock(s)
+
+	rr := s.serveRequest("POST", "/todos", `{"title": "Write report", "tags": ["#work", "urgent", "Work"]}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	created := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &created)
+
+	Expect(created.Data.Tags).To(Equal([]string{"urgent", "work"}))
+
+	rr = s.serveRequest("POST", "/todos", `{"title": "Mow the lawn", "tags": ["home"]}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	listTitles := func(query string) []string {
+		rr := s.serveRequest("GET", "/todos?"+query, "", user.ID)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+
+		data := response.CursorResponse{}
+		body, _ := io.ReadAll(rr.Body)
+		json.Unmarshal(body, &data)
+
+		var todos []response.TodoResponse
+		json.Unmarshal(data.Data, &todos)
+
+		titles := []string{}
+		for _, todo := range todos {
+			titles = append(titles, todo.Title)
+		}
+
+		return titles
+	}
+
+	Expect(listTitles("tag=WORK")).To(ConsistOf("Write report"))
+	Expect(listTitles("any_tag=home,urgent")).To(ConsistOf("Write report", "Mow the lawn"))
+	Expect(listTitles("all_tags=work,urgent")).To(ConsistOf("Write report"))
+	Expect(listTitles("all_tags=work,home")).To(BeEmpty())
+
+	rr = s.serveRequest("PUT", "/todo/"+created.Data.UUID.String(), `{"title": "Write report", "tags": []}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	Expect(listTitles("tag=work")).To(BeEmpty())
+
+	rr = s.serveRequest("GET", "/todos?tag=bad%20tag", "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+}
+
+func (s *TodoHandlerSuite) TestTagRenameMergeAndDelete() {
+	user := CreateUserMock(s)
+	other := CreateOtherUserMock(s)
+
+	s.serveRequest("POST", "/todos", `{"title": "First todo", "tags": ["job"]}`, user.ID)
+	s.serveRequest("POST", "/todos", `{"title": "Second todo", "tags": ["job", "work"]}`, user.ID)
+
+	tags := func() map[string]response.TagResponse {
+		rr := s.serveRequest("GET", "/tags", "", user.ID)
+
+		data := struct {
+			Data []response.TagResponse `json:"data"`
+		}{}
+		body, _ := io.ReadAll(rr.Body)
+		json.Unmarshal(body, &data)
+
+		byName := map[string]response.TagResponse{}
+		for _, tag := range data.Data {
+			byName[tag.Name] = tag
+		}
+
+		return byName
+	}
+
+	current := tags()
+	Expect(current["job"].TodoCount).To(Equal(2))
+
+	rr := s.serveRequest("PUT", "/tags/"+current["job"].UUID.String(), `{"name": "WORK"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+
+	rr = s.serveRequest("PUT", "/tags/"+current["job"].UUID.String(), `{"name": "gig"}`, other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("POST", "/tags/"+current["job"].UUID.String()+"/merge", `{"into": "`+current["work"].UUID.String()+`"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	current = tags()
+	Expect(current).To(HaveLen(1))
+	Expect(current["work"].TodoCount).To(Equal(2))
+
+	rr = s.serveRequest("DELETE", "/tags/"+current["work"].UUID.String(), "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+	Expect(tags()).To(BeEmpty())
+}
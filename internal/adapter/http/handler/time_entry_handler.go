@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	. "todos/internal/adapter/http/helper"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/request"
+	"todos/internal/core/model/response"
+	"todos/internal/core/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultReportRange is how far back a report reaches without ?from=
+const defaultReportRange = 30 * 24 * time.Hour
+
+type TimeEntryHandler struct {
+	svc port.TimeEntryService
+}
+
+func NewTimeEntryHandler(timeEntryUseCase port.TimeEntryService) *TimeEntryHandler {
+	return &TimeEntryHandler{
+		svc: timeEntryUseCase,
+	}
+}
+
+func (h *TimeEntryHandler) GetTimeEntries(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	entries, err := h.svc.List(c.Request.Context(), userId, c.Param("uuid"))
+
+	if err != nil {
+		sendTimeEntryError(c, err, "Error listing time entries")
+		return
+	}
+
+	data := make([]response.TimeEntryResponse, 0, len(entries))
+
+	for _, entry := range entries {
+		data = append(data, response.NewTimeEntryResponse(entry))
+	}
+
+	SendSuccess(c, http.StatusOK, data)
+}
+
+// CreateTimeEntry records time spent on the todo by hand
+func (h *TimeEntryHandler) CreateTimeEntry(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	params, ok := bindRequest[request.TimeEntryRequest](c)
+
+	if !ok {
+		return
+	}
+
+	if params.StartedAt == nil {
+		SendBadRequestError(c, "started_at", "Started at is required")
+		return
+	}
+
+	if params.EndedAt == nil {
+		SendBadRequestError(c, "ended_at", "Ended at is required, start a timer instead")
+		return
+	}
+
+	entry := domain.TimeEntry{StartedAt: *params.StartedAt, EndedAt: params.EndedAt}
+
+	if params.Note != nil {
+		entry.Note = *params.Note
+	}
+
+	saved, err := h.svc.Create(c.Request.Context(), userId, c.Param("uuid"), entry)
+
+	if err != nil {
+		sendTimeEntryError(c, err, "Error creating time entry")
+		return
+	}
+
+	SendSuccess(c, http.StatusCreated, response.NewTimeEntryResponse(saved))
+}
+
+func (h *TimeEntryHandler) UpdateTimeEntry(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	params, ok := bindRequest[request.TimeEntryRequest](c)
+
+	if !ok {
+		return
+	}
+
+	change := domain.TimeEntryChange{StartedAt: params.StartedAt, EndedAt: params.EndedAt, Note: params.Note}
+
+	entry, err := h.svc.Update(c.Request.Context(), userId, c.Param("uuid"), change)
+
+	if err != nil {
+		sendTimeEntryError(c, err, "Error updating time entry")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewTimeEntryResponse(entry))
+}
+
+func (h *TimeEntryHandler) DeleteTimeEntry(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	if err := h.svc.Delete(c.Request.Context(), userId, c.Param("uuid")); err != nil {
+		sendTimeEntryError(c, err, "Error deleting time entry")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Time entry deleted successfully",
+	})
+}
+
+// StartTimer starts tracking time on the todo, the body with a note is
+// optional
+func (h *TimeEntryHandler) StartTimer(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	var params request.TimerStartRequest
+
+	if c.Request.ContentLength != 0 {
+		var ok bool
+
+		if params, ok = bindRequest[request.TimerStartRequest](c); !ok {
+			return
+		}
+	}
+
+	entry, err := h.svc.Start(c.Request.Context(), userId, c.Param("uuid"), params.Note)
+
+	if err != nil {
+		sendTimeEntryError(c, err, "Error starting timer")
+		return
+	}
+
+	SendSuccess(c, http.StatusCreated, response.NewTimeEntryResponse(entry))
+}
+
+func (h *TimeEntryHandler) StopTimer(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	entry, err := h.svc.Stop(c.Request.Context(), userId)
+
+	if err != nil {
+		sendTimeEntryError(c, err, "Error stopping timer")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewTimeEntryResponse(entry))
+}
+
+func (h *TimeEntryHandler) GetTimer(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	entry, err := h.svc.Running(c.Request.Context(), userId)
+
+	if err != nil {
+		sendTimeEntryError(c, err, "Error getting timer")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewTimeEntryResponse(entry))
+}
+
+// GetTimeReport sums the user's tracked time per todo, tag, project or day.
+// ?from= and ?to= take RFC3339 times or dates in ?tz=, a date for ?to= is
+// included whole. ?format=csv answers with a spreadsheet.
+func (h *TimeEntryHandler) GetTimeReport(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	filter, field, err := parseTimeReportFilter(c, time.Now())
+
+	if err != nil {
+		SendBadRequestError(c, field, err.Error())
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+
+	if format != "json" && format != "csv" {
+		SendBadRequestError(c, "format", fmt.Sprintf("invalid format: %s", format))
+		return
+	}
+
+	report, err := h.svc.Report(c.Request.Context(), userId, filter)
+
+	if err != nil {
+		sendTimeEntryError(c, err, "Error building time report")
+		return
+	}
+
+	if format == "json" {
+		SendSuccess(c, http.StatusOK, response.NewTimeReportResponse(report))
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "time-by-"+string(report.Group)+".csv"))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{string(report.Group), "label", "seconds", "hours", "entries"})
+
+	for _, row := range report.Rows {
+		writer.Write([]string{
+			row.Key,
+			row.Label,
+			strconv.Itoa(row.Seconds),
+			strconv.FormatFloat(float64(row.Seconds)/3600, 'f', 2, 64),
+			strconv.Itoa(row.Entries),
+		})
+	}
+
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		slog.Error("Error writing time report", "error", err, "user_id", userId)
+	}
+}
+
+// parseTimeReportFilter reads the report parameters from the query string,
+// returning the offending parameter name alongside any parsing error
+func parseTimeReportFilter(c *gin.Context, now time.Time) (domain.TimeReportFilter, string, error) {
+	var filter domain.TimeReportFilter
+
+	group, err := domain.ParseTimeReportGroup(c.Query("group"))
+
+	if err != nil {
+		return filter, "group", err
+	}
+
+	filter.Group = group
+	filter.Location = time.UTC
+
+	if value := c.Query("tz"); value != "" {
+		location, err := time.LoadLocation(value)
+
+		if err != nil {
+			return filter, "tz", fmt.Errorf("invalid tz: %s", value)
+		}
+
+		filter.Location = location
+	}
+
+	filter.To = now
+
+	if value := c.Query("to"); value != "" {
+		to, err := parseReportTime(value, filter.Location)
+
+		if err != nil {
+			return filter, "to", err
+		}
+
+		// A date covers the whole day
+		if _, err := time.Parse(time.DateOnly, value); err == nil {
+			to = to.AddDate(0, 0, 1)
+		}
+
+		filter.To = to
+	}
+
+	filter.From = filter.To.Add(-defaultReportRange)
+
+	if value := c.Query("from"); value != "" {
+		from, err := parseReportTime(value, filter.Location)
+
+		if err != nil {
+			return filter, "from", err
+		}
+
+		filter.From = from
+	}
+
+	return filter, "", nil
+}
+
+func parseReportTime(value string, location *time.Location) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+
+	if parsed, err := time.ParseInLocation(time.DateOnly, value, location); err == nil {
+		return parsed, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q: expected RFC3339 datetime or YYYY-MM-DD date", value)
+}
+
+func sendTimeEntryError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden):
+		sendTodoAccessError(c, err)
+	case errors.Is(err, domain.ErrTimeEntryNotFound):
+		SendNotFoundError(c, "Time entry not found")
+	case errors.Is(err, domain.ErrNoTimerRunning):
+		SendNotFoundError(c, "No timer is running")
+	case errors.Is(err, domain.ErrTimerRunning):
+		SendConflictError(c, "timer", err.Error())
+	case errors.Is(err, domain.ErrInvalidTimeEntry):
+		SendBadRequestError(c, "time_entry", err.Error())
+	case errors.Is(err, domain.ErrInvalidTimeReport):
+		SendBadRequestError(c, "report", err.Error())
+	default:
+		slog.Error(message, "error", err, "uuid", c.Param("uuid"))
+		SendInternalError(c, message)
+	}
+}
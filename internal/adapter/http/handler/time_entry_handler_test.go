@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"todos/internal/core/model/response"
+)
+
+func (s *TodoHandlerSuite) TestTimeTracking() {
+	owner := CreateUserMock(s)
+	other := CreateOtherUserMock(s)
+
+	rr := s.serveRequest("POST", "/todos", `{"title": "Audit", "tags": ["client"]}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	todo := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &todo)
+
+	todoUUID := todo.Data.UUID.String()
+
+	rr = s.serveRequest("POST", "/timer/stop", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("POST", "/todos/"+todoUUID+"/timer/start", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	// One timer at a time
+	rr = s.serveRequest("POST", "/todos/"+todoUUID+"/timer/start", `{"note": "again"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+
+	rr = s.serveRequest("POST", "/todos/"+todoUUID+"/timer/start", "", other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("GET", "/timer", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	timer := struct {
+		Data response.TimeEntryResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &timer)
+
+	Expect(timer.Data.Running).To(BeTrue())
+	Expect(timer.Data.TodoUUID.String()).To(Equal(todoUUID))
+
+	rr = s.serveRequest("POST", "/timer/stop", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("GET", "/timer", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	day := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	entryBody := func(start time.Time, minutes int) string {
+		return fmt.Sprintf(`{"started_at": "%s", "ended_at": "%s", "note": "review"}`,
+			start.Format(time.RFC3339), start.Add(time.Duration(minutes)*time.Minute).Format(time.RFC3339))
+	}
+
+	rr = s.serveRequest("POST", "/todos/"+todoUUID+"/time-entries", entryBody(day.Add(9*time.Hour), 90), owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	manual := struct {
+		Data response.TimeEntryResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &manual)
+
+	Expect(manual.Data.Seconds).To(Equal(5400))
+	Expect(manual.Data.Note).To(Equal("review"))
+
+	rr = s.serveRequest("POST", "/todos/"+todoUUID+"/time-entries", entryBody(day.Add(9*time.Hour), -30), owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("POST", "/todos/"+todoUUID+"/time-entries", `{"started_at": "2024-01-01T10:00:00Z"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	// Only the owner edits an entry
+	entryPath := "/time-entries/" + manual.Data.UUID.String()
+	edit := fmt.Sprintf(`{"ended_at": "%s"}`, day.Add(11*time.Hour).Format(time.RFC3339))
+
+	rr = s.serveRequest("PUT", entryPath, edit, other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("PUT", entryPath, edit, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("GET", "/todos/"+todoUUID, "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &todo)
+
+	Expect(todo.Data.Tracked).To(BeNumerically(">=", 7200))
+
+	rr = s.serveRequest("GET", "/todos/"+todoUUID+"/time-entries", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	entries := struct {
+		Data []response.TimeEntryResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &entries)
+
+	Expect(entries.Data).To(HaveLen(2))
+
+	reportPath := "/time-entries/report?group=day&from=" + day.Format(time.DateOnly) + "&to=" + day.Format(time.DateOnly)
+
+	rr = s.serveRequest("GET", reportPath, "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	report := struct {
+		Data response.TimeReportResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &report)
+
+	Expect(report.Data.Seconds).To(Equal(7200))
+	Expect(report.Data.Rows).To(ConsistOf(response.TimeReportRowResponse{
+		Key: day.Format(time.DateOnly), Label: day.Format(time.DateOnly), Seconds: 7200, Entries: 1,
+	}))
+
+	rr = s.serveRequest("GET", "/time-entries/report?group=tag&format=csv", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+	Expect(rr.Header().Get("Content-Type")).To(ContainSubstring("text/csv"))
+
+	records, err := csv.NewReader(rr.Body).ReadAll()
+
+	Expect(err).ToNot(HaveOccurred())
+	Expect(records).To(HaveLen(2))
+	Expect(records[0]).To(Equal([]string{"tag", "label", "seconds", "hours", "entries"}))
+	Expect(records[1][0]).To(Equal("client"))
+	Expect(records[1][4]).To(Equal("2"))
+
+	rr = s.serveRequest("GET", "/time-entries/report?group=week", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("GET", "/time-entries/report?from=2024-02-01&to=2024-01-01", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("DELETE", entryPath, "", other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("DELETE", entryPath, "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	. "todos/internal/adapter/http/helper"
+	. "todos/internal/adapter/http/validation"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/request"
+	"todos/internal/core/model/response"
+	"todos/internal/core/port"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TodoBatchHandler struct {
+	svc port.TodoBatchService
+}
+
+func NewTodoBatchHandler(batchUseCase port.TodoBatchService) *TodoBatchHandler {
+	return &TodoBatchHandler{
+		svc: batchUseCase,
+	}
+}
+
+// ApplyBatch runs a list of todo operations in one transaction. A malformed
+// operation rejects the whole request before anything runs. An atomic batch
+// that failed answers 422 with every result, nothing of it was kept.
+func (h *TodoBatchHandler) ApplyBatch(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	params, ok := bindRequest[request.TodoBatchRequest](c)
+
+	if !ok {
+		return
+	}
+
+	mode, err := domain.ParseTodoBatchMode(params.Mode)
+
+	if err != nil {
+		SendBadRequestError(c, "mode", err.Error())
+		return
+	}
+
+	operations := make([]domain.TodoOperation, 0, len(params.Operations))
+
+	for i, param := range params.Operations {
+		operation, errs := todoOperationFromRequest(param.Op, param.UUID, param.Version, param.Data)
+
+		if len(errs) > 0 {
+			for j := range errs {
+				errs[j].Field = fmt.Sprintf("operations[%d].%s", i, errs[j].Field)
+			}
+
+			SendError(c, http.StatusBadRequest, "VALIDATION_ERROR", errs)
+			return
+		}
+
+		operation.Force = param.Force
+		operations = append(operations, operation)
+	}
+
+	batch, err := h.svc.Apply(c.Request.Context(), userId, mode, operations)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrBatchEmpty) || errors.Is(err, domain.ErrBatchTooLarge):
+			SendBadRequestError(c, "operations", err.Error())
+		default:
+			slog.Error("Error applying todo batch", "error", err, "user_id", userId)
+			SendInternalError(c, "Error applying batch")
+		}
+
+		return
+	}
+
+	data := response.TodoBatchResponse{
+		Mode:      string(batch.Mode),
+		Committed: batch.Committed,
+		Failed:    batch.Failed(),
+		Results:   make([]response.TodoOperationResponse, 0, len(batch.Results)),
+	}
+
+	data.Succeeded = len(batch.Results) - data.Failed
+
+	for _, result := range batch.Results {
+		item := response.TodoOperationResponse{
+			Index:  result.Index,
+			Op:     string(result.Op),
+			Status: http.StatusOK,
+		}
+
+		if uid := operations[result.Index].Todo.UUID; uid != uuid.Nil {
+			item.UUID = &uid
+		}
+
+		if result.Todo != nil {
+			todo := response.NewTodoResponse(*result.Todo)
+			item.UUID = &todo.UUID
+			item.Data = &todo
+		}
+
+		if result.Op == domain.TodoBatchCreate && result.Err == nil {
+			item.Status = http.StatusCreated
+		}
+
+		if result.Err != nil {
+			status, field, message := todoOperationError(result.Err)
+
+			if status == http.StatusInternalServerError {
+				slog.Error("Error applying todo operation", "error", result.Err, "index", result.Index, "op", result.Op)
+			}
+
+			item.Status = status
+			item.Error = &response.ValidationError{Field: field, Message: message}
+		}
+
+		data.Results = append(data.Results, item)
+	}
+
+	status := http.StatusOK
+
+	if !batch.Committed {
+		status = http.StatusUnprocessableEntity
+	}
+
+	SendSuccess(c, status, data)
+}
+
+// todoOperationFromRequest checks an operation like the matching single todo
+// endpoint would
+func todoOperationFromRequest(op string, uid string, version int, data *request.TodoRequest) (domain.TodoOperation, []response.ValidationError) {
+	invalid := func(field string, err error) []response.ValidationError {
+		return []response.ValidationError{{Field: field, Message: err.Error()}}
+	}
+
+	kind, err := domain.ParseTodoBatchOp(op)
+
+	if err != nil {
+		return domain.TodoOperation{}, invalid("op", err)
+	}
+
+	operation := domain.TodoOperation{Op: kind}
+
+	if kind == domain.TodoBatchCreate || kind == domain.TodoBatchUpdate {
+		if data == nil {
+			return domain.TodoOperation{}, invalid("data", fmt.Errorf("data is required to %s a todo", kind))
+		}
+
+		if kind == domain.TodoBatchUpdate {
+			if err := Validator.Struct(data); err != nil {
+				return domain.TodoOperation{}, FormatValidationErrors(err)
+			}
+		}
+
+		todo, field, err := todoFromRequest(*data)
+
+		if err != nil {
+			return domain.TodoOperation{}, invalid(field, err)
+		}
+
+		if kind == domain.TodoBatchCreate {
+			if err := Validator.Struct(todo); err != nil {
+				return domain.TodoOperation{}, FormatValidationErrors(err)
+			}
+		}
+
+		operation.Todo = todo
+	}
+
+	if kind != domain.TodoBatchCreate {
+		// The request validation already checked the uuid
+		operation.Todo.UUID, _ = uuid.Parse(uid)
+		operation.Todo.Version = version
+	}
+
+	return operation, nil
+}
+
+// todoOperationError maps the error of a batch operation to the status, field
+// and message the single todo endpoints answer with
+func todoOperationError(err error) (int, string, string) {
+	switch {
+	case errors.Is(err, domain.ErrBatchAborted):
+		return http.StatusFailedDependency, "batch", err.Error()
+	case errors.Is(err, domain.ErrTodoNotFound):
+		return http.StatusNotFound, "resource", "Todo not found"
+	case errors.Is(err, domain.ErrVersionConflict):
+		return http.StatusPreconditionFailed, "version", err.Error()
+	case errors.Is(err, domain.ErrTodoChanged):
+		return http.StatusConflict, "version", domain.ErrTodoChanged.Error()
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden, "auth", "You are not allowed to perform this action"
+	case errors.Is(err, domain.ErrInvalidTagName):
+		return http.StatusBadRequest, "tags", err.Error()
+	case errors.Is(err, domain.ErrInvalidRecurrence) || errors.Is(err, domain.ErrRecurrenceNeedsDue):
+		return http.StatusBadRequest, "rrule", err.Error()
+	case errors.Is(err, domain.ErrStatusConflict):
+		return http.StatusBadRequest, "completed", err.Error()
+	case errors.Is(err, domain.ErrUnknownStatus):
+		return http.StatusBadRequest, "status", err.Error()
+	case errors.Is(err, domain.ErrIllegalTransition) || errors.Is(err, domain.ErrReviewsPending) || errors.Is(err, domain.ErrTodoBlocked):
+		return http.StatusConflict, "status", err.Error()
+	case errors.Is(err, domain.ErrProjectArchived):
+		return http.StatusConflict, "project_uuid", "Todos cannot be added to an archived project"
+	case errors.Is(err, domain.ErrProjectNotFound):
+		return http.StatusBadRequest, "project_uuid", "Project not found"
+	default:
+		return http.StatusInternalServerError, "server", "Error applying operation"
+	}
+}
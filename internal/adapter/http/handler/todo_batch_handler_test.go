@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	. "github.com/onsi/gomega"
+
+	"todos/internal/core/model/response"
+)
+
+func (s *TodoHandlerSuite) TestApplyTodoBatch() {
+	owner := CreateUserMock(s)
+	other := CreateOtherUserMock(s)
+
+	first := CreateTodo(s, owner.ID)
+	second := CreateTodo(s, owner.ID)
+	foreign := CreateTodo(s, other.ID)
+
+	apply := func(body string) (int, response.TodoBatchResponse) {
+		rr := s.serveRequest("POST", "/todos/batch", body, owner.ID)
+
+		data := struct {
+			Data response.TodoBatchResponse `json:"data"`
+		}{}
+		raw, _ := io.ReadAll(rr.Body)
+		json.Unmarshal(raw, &data)
+
+		return rr.Code, data.Data
+	}
+
+	statuses := func(batch response.TodoBatchResponse) []int {
+		var codes []int
+
+		for _, result := range batch.Results {
+			codes = append(codes, result.Status)
+		}
+
+		return codes
+	}
+
+	exists := func(uid string) bool {
+		return s.serveRequest("GET", "/todos/"+uid, "", owner.ID).Code == http.StatusOK
+	}
+
+	// An atomic batch keeps nothing once an operation fails
+	code, batch := apply(fmt.Sprintf(`{"operations": [
+		{"op": "create", "data": {"title": "Created in batch"}},
+		{"op": "delete", "uuid": "%s"},
+		{"op": "complete", "uuid": "%s"},
+		{"op": "update", "uuid": "%s", "data": {"title": "Never applied"}}
+	]}`, first.UUID, foreign.UUID, second.UUID))
+
+	Expect(code).To(Equal(http.StatusUnprocessableEntity))
+	Expect(batch.Mode).To(Equal("atomic"))
+	Expect(batch.Committed).To(BeFalse())
+	Expect(statuses(batch)).To(Equal([]int{http.StatusFailedDependency, http.StatusFailedDependency, http.StatusNotFound, http.StatusFailedDependency}))
+	Expect(batch.Results[0].Data).To(BeNil())
+	Expect(batch.Results[2].Error.Field).To(Equal("resource"))
+	Expect(exists(first.UUID.String())).To(BeTrue())
+
+	rr := s.serveRequest("GET", "/todos?limit=50", "", owner.ID)
+	list := response.CursorResponse{}
+	raw, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(raw, &list)
+	Expect(list.Size).To(Equal(2))
+
+	// Best effort keeps every operation that succeeded
+	code, batch = apply(fmt.Sprintf(`{"mode": "best_effort", "operations": [
+		{"op": "create", "data": {"title": "Created in batch", "tags": ["home"]}},
+		{"op": "complete", "uuid": "%s"},
+		{"op": "update", "uuid": "%s", "data": {"title": "Renamed in batch"}},
+		{"op": "delete", "uuid": "%s"},
+		{"op": "delete", "uuid": "%s"}
+	]}`, second.UUID, second.UUID, foreign.UUID, first.UUID))
+
+	Expect(code).To(Equal(http.StatusOK))
+	Expect(batch.Committed).To(BeTrue())
+	Expect(batch.Succeeded).To(Equal(4))
+	Expect(batch.Failed).To(Equal(1))
+	Expect(statuses(batch)).To(Equal([]int{http.StatusCreated, http.StatusOK, http.StatusOK, http.StatusNotFound, http.StatusOK}))
+
+	created := batch.Results[0].Data
+	Expect(created.Title).To(Equal("Created in batch"))
+	Expect(created.Tags).To(Equal([]string{"home"}))
+	Expect(exists(created.UUID.String())).To(BeTrue())
+
+	Expect(batch.Results[2].Data.Title).To(Equal("Renamed in batch"))
+	Expect(batch.Results[2].Data.Completed).To(BeTrue())
+	Expect(*batch.Results[4].UUID).To(Equal(first.UUID))
+	Expect(exists(first.UUID.String())).To(BeFalse())
+
+	// Malformed operations reject the whole request
+	rr = s.serveRequest("POST", "/todos/batch", `{"operations": [{"op": "create", "data": {"title": "Fine"}}, {"op": "create", "data": {"title": "No"}}]}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	Expect(rr.Body.String()).To(ContainSubstring(`"field":"operations[1].title"`))
+
+	rr = s.serveRequest("POST", "/todos/batch", `{"operations": [{"op": "delete"}]}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("POST", "/todos/batch", `{"operations": []}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("POST", "/todos/batch", `{"mode": "sometimes", "operations": [{"op": "delete", "uuid": "`+second.UUID.String()+`"}]}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	Expect(exists(second.UUID.String())).To(BeTrue())
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	. "todos/internal/adapter/http/helper"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/request"
+	"todos/internal/core/model/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetBlockers lists the todos the todo waits on, open ones first
+func (t *TodoHandler) GetBlockers(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	todos, err := t.svc.Blockers(c.Request.Context(), userId, c.Param("uuid"))
+
+	if err != nil {
+		sendDependencyError(c, err, "Error listing blockers")
+		return
+	}
+
+	data := make([]response.TodoResponse, 0, len(todos))
+
+	for _, todo := range todos {
+		data = append(data, response.NewTodoResponse(todo))
+	}
+
+	SendSuccess(c, http.StatusOK, data)
+}
+
+func (t *TodoHandler) AddBlocker(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	params, ok := bindRequest[request.TodoBlockerRequest](c)
+
+	if !ok {
+		return
+	}
+
+	todo, err := t.svc.AddBlocker(c.Request.Context(), userId, c.Param("uuid"), params.BlockerUUID)
+
+	if err != nil {
+		sendDependencyError(c, err, "Error adding blocker")
+		return
+	}
+
+	SendSuccess(c, http.StatusCreated, response.NewTodoResponse(todo))
+}
+
+func (t *TodoHandler) RemoveBlocker(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	todo, err := t.svc.RemoveBlocker(c.Request.Context(), userId, c.Param("uuid"), c.Param("blocker_uuid"))
+
+	if err != nil {
+		sendDependencyError(c, err, "Error removing blocker")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewTodoResponse(todo))
+}
+
+// GetDependencyGraph returns the todos of a project as nodes and what
+// blocks what as edges
+func (t *TodoHandler) GetDependencyGraph(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	graph, err := t.svc.DependencyGraph(c.Request.Context(), userId, c.Param("uuid"))
+
+	if err != nil {
+		sendDependencyError(c, err, "Error getting dependency graph")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewDependencyGraphResponse(graph))
+}
+
+func sendDependencyError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden):
+		sendTodoAccessError(c, err)
+	case errors.Is(err, domain.ErrDependencySelf):
+		SendBadRequestError(c, "blocker_uuid", err.Error())
+	case errors.Is(err, domain.ErrDependencyCycle) || errors.Is(err, domain.ErrDependencyExists):
+		SendConflictError(c, "blocker_uuid", err.Error())
+	case errors.Is(err, domain.ErrDependencyNotFound):
+		SendNotFoundError(c, "Dependency not found")
+	case errors.Is(err, domain.ErrProjectNotFound):
+		SendNotFoundError(c, "Project not found")
+	default:
+		slog.Error(message, "error", err, "uuid", c.Param("uuid"))
+		SendInternalError(c, message)
+	}
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	. "github.com/onsi/gomega"
+
+	"todos/internal/core/model/response"
+)
+
+func (s *TodoHandlerSuite) TestTodoBlockers() {
+	owner := CreateUserMock(s)
+	other := CreateOtherUserMock(s)
+
+	rr := s.serveRequest("POST", "/projects", `{"name": "Release"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	project := struct {
+		Data response.ProjectResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &project)
+
+	create := func(title string) response.TodoResponse {
+		rr := s.serveRequest("POST", "/todos", fmt.Sprintf(`{"title": "%s", "project_uuid": "%s"}`, title, project.Data.UUID), owner.ID)
+		Expect(rr.Code).To(Equal(http.StatusCreated))
+
+		created := struct {
+			Data response.TodoResponse `json:"data"`
+		}{}
+		body, _ := io.ReadAll(rr.Body)
+		json.Unmarshal(body, &created)
+
+		return created.Data
+	}
+
+	ship, test := create("Ship it"), create("Test it")
+	foreign := CreateTodo(s, other.ID)
+
+	blockersPath := "/todos/" + ship.UUID.String() + "/blockers"
+
+	rr = s.serveRequest("POST", blockersPath, fmt.Sprintf(`{"blocker_uuid": "%s"}`, test.UUID), owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	blocked := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &blocked)
+
+	Expect(blocked.Data.Blocked).To(BeTrue())
+
+	rr = s.serveRequest("POST", blockersPath, fmt.Sprintf(`{"blocker_uuid": "%s"}`, test.UUID), owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+
+	rr = s.serveRequest("POST", blockersPath, fmt.Sprintf(`{"blocker_uuid": "%s"}`, ship.UUID), owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("POST", blockersPath, `{"blocker_uuid": "not-a-uuid"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	// The other way round would close a cycle
+	rr = s.serveRequest("POST", "/todos/"+test.UUID.String()+"/blockers", fmt.Sprintf(`{"blocker_uuid": "%s"}`, ship.UUID), owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+
+	// Todos the user cannot see cannot block theirs
+	rr = s.serveRequest("POST", blockersPath, fmt.Sprintf(`{"blocker_uuid": "%s"}`, foreign.UUID), owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("GET", blockersPath, "", other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("GET", blockersPath, "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	blockers := struct {
+		Data []response.TodoResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &blockers)
+
+	Expect(blockers.Data).To(HaveLen(1))
+	Expect(blockers.Data[0].UUID).To(Equal(test.UUID))
+
+	// Completing waits on the blocker unless forced
+	rr = s.serveRequest("PUT", "/todo/"+ship.UUID.String(), `{"title": "Ship it", "completed": true}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+
+	rr = s.serveRequest("PUT", "/todo/"+ship.UUID.String()+"?force=maybe", `{"title": "Ship it", "completed": true}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("GET", "/projects/"+project.Data.UUID.String()+"/dependencies", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	graph := struct {
+		Data response.DependencyGraphResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &graph)
+
+	Expect(graph.Data.Nodes).To(HaveLen(2))
+	Expect(graph.Data.Edges).To(ConsistOf(response.DependencyEdgeResponse{TodoUUID: ship.UUID, BlockerUUID: test.UUID}))
+
+	rr = s.serveRequest("GET", "/projects/"+project.Data.UUID.String()+"/dependencies", "", other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("PUT", "/todo/"+ship.UUID.String()+"?force=true", `{"title": "Ship it", "completed": true}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("DELETE", blockersPath+"/"+test.UUID.String(), "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("DELETE", blockersPath+"/"+test.UUID.String(), "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+}
+
+func (s *TodoHandlerSuite) TestTodoBlockersHidePrivateTodos() {
+	owner := CreateUserMock(s)
+	member := CreateOtherUserMock(s)
+
+	rr := s.serveRequest("POST", "/projects", `{"name": "Release"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	project := struct {
+		Data response.ProjectResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &project)
+
+	rr = s.serveRequest("POST", "/projects/"+project.Data.UUID.String()+"/members", `{"email": "user100@example.com", "role": "editor"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	rr = s.serveRequest("POST", "/todos", fmt.Sprintf(`{"title": "Ship it", "project_uuid": "%s"}`, project.Data.UUID), owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	ship := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &ship)
+
+	private := CreateTodo(s, owner.ID)
+	blockersPath := "/todos/" + ship.Data.UUID.String() + "/blockers"
+
+	rr = s.serveRequest("POST", blockersPath, fmt.Sprintf(`{"blocker_uuid": "%s"}`, private.UUID), owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	blockers := func(userId int) []response.TodoResponse {
+		rr := s.serveRequest("GET", blockersPath, "", userId)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+
+		listed := struct {
+			Data []response.TodoResponse `json:"data"`
+		}{}
+		body, _ := io.ReadAll(rr.Body)
+		json.Unmarshal(body, &listed)
+
+		return listed.Data
+	}
+
+	Expect(blockers(owner.ID)).To(HaveLen(1))
+
+	// The member sees the todo is blocked, not by what
+	Expect(blockers(member.ID)).To(BeEmpty())
+	Expect(s.serveRequest("GET", "/projects/"+project.Data.UUID.String()+"/dependencies", "", member.ID).Body.String()).NotTo(ContainSubstring(private.Title))
+}
@@ -1,9 +1,13 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	. "todos/internal/adapter/http/helper"
 	. "todos/internal/adapter/http/validation"
@@ -41,9 +45,21 @@ func (t *TodoHandler) GetAllTodos(c *gin.Context) {
 		limit = 10
 	}
 
-	data, err := t.svc.GetTodosWithPagination(ctx, userId.(int), limit, cursor)
+	filter, field, err := parseTodoFilter(c)
 
 	if err != nil {
+		SendBadRequestError(c, field, err.Error())
+		return
+	}
+
+	data, err := t.svc.GetTodosWithPagination(ctx, userId.(int), limit, cursor, filter)
+
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			SendBadRequestError(c, "cursor", err.Error())
+			return
+		}
+
 		t.Logger.Logger.Ctx(ctx).Error("Failed to get todos",
 			zap.Error(err),
 			zap.Int("user_id", userId.(int)),
@@ -56,6 +72,56 @@ func (t *TodoHandler) GetAllTodos(c *gin.Context) {
 	c.JSON(http.StatusOK, data)
 }
 
+func (t *TodoHandler) SearchTodos(c *gin.Context) {
+	ctx := c.Request.Context()
+	userId := c.GetInt("x-user-id")
+	cursor := c.Query("cursor")
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	data, err := t.svc.Search(ctx, userId, c.Query("q"), limit, cursor)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEmptySearchQuery):
+			SendBadRequestError(c, "q", err.Error())
+		case errors.Is(err, domain.ErrInvalidCursor):
+			SendBadRequestError(c, "cursor", err.Error())
+		default:
+			slog.Error("Failed to search todos", "error", err, "user_id", userId)
+			SendInternalError(c, "Error searching todos")
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func (t *TodoHandler) GetTodo(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+	ctx := c.Request.Context()
+
+	todo, err := t.svc.GetByUUID(ctx, userId, c.Param("uuid"))
+
+	if err != nil {
+		if errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden) {
+			sendTodoAccessError(c, err)
+			return
+		}
+
+		slog.Error("Error getting todo", "error", err, "uuid", c.Param("uuid"))
+		SendInternalError(c, "Error getting todo")
+		return
+	}
+
+	c.Header("ETag", todo.ETag())
+	SendSuccess(c, http.StatusOK, response.NewTodoResponse(todo))
+}
+
 func (t *TodoHandler) CreateTodo(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -68,21 +134,14 @@ func (t *TodoHandler) CreateTodo(c *gin.Context) {
 		return
 	}
 
-	todo := domain.Todo{
-		Title:       params.Title,
-		Description: params.Description,
-		Completed:   params.Completed,
-		UserId:      userId.(int),
-	}
-
-	status, err := todo.StatusToEnum(params.Status)
+	todo, field, err := todoFromRequest(params)
 
 	if err != nil {
-		SendBadRequestError(c, "status", err.Error())
+		SendBadRequestError(c, field, err.Error())
 		return
 	}
 
-	todo.Status = status
+	todo.UserId = userId.(int)
 
 	if err := Validator.Struct(todo); err != nil {
 		SendValidationError(c, err)
@@ -96,6 +155,31 @@ func (t *TodoHandler) CreateTodo(c *gin.Context) {
 	if err != nil {
 		slog.Error("Error creating todo", "error", err)
 
+		if errors.Is(err, domain.ErrInvalidTagName) {
+			SendBadRequestError(c, "tags", err.Error())
+			return
+		}
+
+		if errors.Is(err, domain.ErrInvalidRecurrence) || errors.Is(err, domain.ErrRecurrenceNeedsDue) {
+			SendBadRequestError(c, "rrule", err.Error())
+			return
+		}
+
+		if errors.Is(err, domain.ErrStatusConflict) {
+			SendBadRequestError(c, "completed", err.Error())
+			return
+		}
+
+		if errors.Is(err, domain.ErrUnknownStatus) {
+			SendBadRequestError(c, "status", err.Error())
+			return
+		}
+
+		if errors.Is(err, domain.ErrProjectNotFound) || errors.Is(err, domain.ErrProjectArchived) || errors.Is(err, domain.ErrForbidden) {
+			sendTodoProjectError(c, err)
+			return
+		}
+
 		if validationErrors := FormatValidationErrors(err); len(validationErrors) > 0 {
 			SendValidationError(c, err)
 			return
@@ -105,17 +189,8 @@ func (t *TodoHandler) CreateTodo(c *gin.Context) {
 		return
 	}
 
-	response := response.TodoResponse{
-		UUID:        todo.UUID,
-		Title:       todo.Title,
-		Description: todo.Description,
-		Status:      todo.StatusOrFallback(),
-		Completed:   todo.Completed,
-		CreatedAt:   todo.CreatedAt,
-		UpdatedAt:   todo.UpdatedAt,
-	}
-
-	SendSuccess(c, http.StatusCreated, response)
+	c.Header("ETag", todo.ETag())
+	SendSuccess(c, http.StatusCreated, response.NewTodoResponse(todo))
 }
 
 func (t *TodoHandler) UpdateTodo(c *gin.Context) {
@@ -134,55 +209,80 @@ func (t *TodoHandler) UpdateTodo(c *gin.Context) {
 		return
 	}
 
-	todo := domain.Todo{
-		UUID:        uuid.MustParse(c.Param("uuid")),
-		Title:       params.Title,
-		Description: params.Description,
-		Completed:   params.Completed,
-		UserId:      userId,
+	uid, err := uuid.Parse(c.Param("uuid"))
+
+	if err != nil {
+		SendNotFoundError(c, "Todo not found")
+		return
 	}
 
-	status, err := todo.StatusToEnum(params.Status)
+	todo, field, err := todoFromRequest(params)
 
 	if err != nil {
-		SendBadRequestError(c, "status", err.Error())
+		SendBadRequestError(c, field, err.Error())
 		return
 	}
 
-	todo.Status = status
+	if todo.Version, err = ifMatchVersion(c); err != nil {
+		SendBadRequestError(c, "If-Match", err.Error())
+		return
+	}
 
-	todo, err = t.svc.UpdateByUUID(ctx, todo)
+	force, err := forceParam(c)
 
 	if err != nil {
-		if validationErrors := FormatValidationErrors(err); len(validationErrors) > 0 {
-			SendValidationError(c, err)
+		SendBadRequestError(c, "force", err.Error())
+		return
+	}
+
+	if todo.StatusChange != nil {
+		todo.StatusChange.Force = force
+	}
+
+	todo.UUID = uid
+	todo.UserId = userId
+
+	todo, err = t.svc.UpdateByUUID(ctx, userId, todo)
+
+	if err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			t.sendVersionConflict(c, userId, uid.String())
 			return
 		}
 
-		SendBadRequestError(c, "update", err.Error())
+		sendTodoUpdateError(c, err)
 		return
 	}
 
-	response := response.TodoResponse{
-		UUID:        todo.UUID,
-		Title:       todo.Title,
-		Description: todo.Description,
-		Status:      todo.StatusOrFallback(),
-		Completed:   todo.Completed,
-		CreatedAt:   todo.CreatedAt,
-		UpdatedAt:   todo.UpdatedAt,
-	}
-
-	c.JSON(http.StatusOK, gin.H{"data": response})
+	c.Header("ETag", todo.ETag())
+	c.JSON(http.StatusOK, gin.H{"data": response.NewTodoResponse(todo)})
 }
 
 func (t *TodoHandler) DeleteByUUID(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
 	ctx := c.Request.Context()
 
-	err := t.svc.DeleteByUUID(ctx, c.Param("uuid"))
+	version, err := ifMatchVersion(c)
+
+	if err != nil {
+		SendBadRequestError(c, "If-Match", err.Error())
+		return
+	}
+
+	err = t.svc.DeleteByUUID(ctx, userId, c.Param("uuid"), version)
 
 	if err != nil {
-		SendNotFoundError(c, err.Error())
+		if errors.Is(err, domain.ErrVersionConflict) {
+			t.sendVersionConflict(c, userId, c.Param("uuid"))
+			return
+		}
+
+		if errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden) {
+			sendTodoAccessError(c, err)
+			return
+		}
+
+		SendInternalError(c, "Error deleting todo")
 		return
 	}
 
@@ -190,3 +290,332 @@ func (t *TodoHandler) DeleteByUUID(c *gin.Context) {
 		"message": "Todo deleted successfully",
 	})
 }
+
+// ifMatchVersion reads the version an If-Match header asks for, 0 when there
+// is none or it is "*". Only a single ETag as GET returns it is accepted.
+func ifMatchVersion(c *gin.Context) (int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	unquoted, ok := strings.CutPrefix(header, `"`)
+
+	if ok {
+		unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	}
+
+	version, err := strconv.Atoi(unquoted)
+
+	if !ok || err != nil || version <= 0 {
+		return 0, fmt.Errorf("If-Match must be a single ETag of the todo, not %s", header)
+	}
+
+	return version, nil
+}
+
+// forceParam reads ?force=, which completes a todo even while todos blocking
+// it are open
+func forceParam(c *gin.Context) (bool, error) {
+	value := c.Query("force")
+
+	if value == "" {
+		return false, nil
+	}
+
+	force, err := strconv.ParseBool(value)
+
+	if err != nil {
+		return false, fmt.Errorf("invalid force: %s", value)
+	}
+
+	return force, nil
+}
+
+// sendVersionConflict answers a write made against an outdated version with
+// the todo as it is now
+func (t *TodoHandler) sendVersionConflict(c *gin.Context, userId int, uid string) {
+	current, err := t.svc.GetByUUID(c.Request.Context(), userId, uid)
+
+	if err != nil {
+		if errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden) {
+			sendTodoAccessError(c, err)
+			return
+		}
+
+		slog.Error("Error getting todo", "error", err, "uuid", uid)
+		SendInternalError(c, "Error getting todo")
+		return
+	}
+
+	c.Header("ETag", current.ETag())
+	SendPreconditionFailedError(c, "If-Match", "The todo was changed since it was read", response.NewTodoResponse(current))
+}
+
+// sendTodoUpdateError reports why an update or patch was refused
+func sendTodoUpdateError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden) {
+		sendTodoAccessError(c, err)
+		return
+	}
+
+	if errors.Is(err, domain.ErrInvalidTagName) {
+		SendBadRequestError(c, "tags", err.Error())
+		return
+	}
+
+	if errors.Is(err, domain.ErrInvalidRecurrence) || errors.Is(err, domain.ErrRecurrenceNeedsDue) {
+		SendBadRequestError(c, "rrule", err.Error())
+		return
+	}
+
+	if errors.Is(err, domain.ErrStatusConflict) {
+		SendBadRequestError(c, "completed", err.Error())
+		return
+	}
+
+	if errors.Is(err, domain.ErrUnknownStatus) {
+		SendBadRequestError(c, "status", err.Error())
+		return
+	}
+
+	if errors.Is(err, domain.ErrIllegalTransition) || errors.Is(err, domain.ErrReviewsPending) || errors.Is(err, domain.ErrTodoBlocked) {
+		SendConflictError(c, "status", err.Error())
+		return
+	}
+
+	if errors.Is(err, domain.ErrVersionConflict) {
+		SendPreconditionFailedError(c, "version", err.Error())
+		return
+	}
+
+	if errors.Is(err, domain.ErrTodoChanged) {
+		SendConflictError(c, "version", domain.ErrTodoChanged.Error())
+		return
+	}
+
+	if errors.Is(err, domain.ErrProjectNotFound) || errors.Is(err, domain.ErrProjectArchived) {
+		sendTodoProjectError(c, err)
+		return
+	}
+
+	if validationErrors := FormatValidationErrors(err); len(validationErrors) > 0 {
+		SendValidationError(c, err)
+		return
+	}
+
+	SendBadRequestError(c, "update", err.Error())
+}
+
+// sendTodoAccessError maps policy denials to 404 (hidden) or 403 (visible but not allowed)
+func sendTodoAccessError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrForbidden) {
+		SendForbiddenError(c, "You are not allowed to perform this action")
+		return
+	}
+
+	SendNotFoundError(c, "Todo not found")
+}
+
+// sendTodoProjectError reports a project_uuid the todo cannot be moved into
+func sendTodoProjectError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrProjectArchived) {
+		SendConflictError(c, "project_uuid", "Todos cannot be added to an archived project")
+		return
+	}
+
+	if errors.Is(err, domain.ErrForbidden) {
+		SendForbiddenError(c, "Only editors can add todos to this project")
+		return
+	}
+
+	SendBadRequestError(c, "project_uuid", "Project not found")
+}
+
+// todoFromRequest builds the todo a create or update request describes,
+// returning the offending field alongside any parsing error
+func todoFromRequest(params request.TodoRequest) (domain.Todo, string, error) {
+	todo := domain.Todo{
+		Title:       params.Title,
+		Description: params.Description,
+		DueAt:       params.DueAt,
+		AllDay:      params.AllDay,
+		Tags:        params.Tags,
+		RRule:       params.RRule,
+	}
+
+	var err error
+
+	if todo.ProjectUUID, err = parseProjectUUID(params.ProjectUUID); err != nil {
+		return domain.Todo{}, "project_uuid", err
+	}
+
+	if todo.StatusChange, err = parseStatusChange(params.Status, params.Completed); err != nil {
+		return domain.Todo{}, "status", err
+	}
+
+	return todo, "", nil
+}
+
+// parseProjectUUID reads project_uuid from a todo request. An empty string
+// becomes uuid.Nil, which moves the todo out of its project.
+func parseProjectUUID(value *string) (*uuid.UUID, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	if *value == "" {
+		return &uuid.Nil, nil
+	}
+
+	uid, err := uuid.Parse(*value)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_uuid: %s", *value)
+	}
+
+	return &uid, nil
+}
+
+// parseStatusChange reads status and completed from a todo request, nil when
+// neither was sent
+func parseStatusChange(status *string, completed *bool) (*domain.TodoStatusChange, error) {
+	if status == nil && completed == nil {
+		return nil, nil
+	}
+
+	change := domain.TodoStatusChange{Completed: completed}
+
+	if status != nil {
+		value, err := domain.ParseTodoStatus(*status)
+
+		if err != nil {
+			return nil, err
+		}
+
+		change.Status = &value
+	}
+
+	return &change, nil
+}
+
+// parseTodoFilter reads the list filters from the query string, returning the
+// offending parameter name alongside any parsing error
+func parseTodoFilter(c *gin.Context) (domain.TodoFilter, string, error) {
+	var filter domain.TodoFilter
+
+	sort, err := domain.ParseTodoSort(c.Query("sort"))
+
+	if err != nil {
+		return filter, "sort", err
+	}
+
+	filter.Sort = sort
+
+	due, err := domain.ParseDueFilter(c.Query("due"))
+
+	if err != nil {
+		return filter, "due", err
+	}
+
+	filter.Due = due
+
+	if value := c.Query("status"); value != "" {
+		for _, name := range strings.Split(value, ",") {
+			status, err := domain.ParseTodoStatus(strings.TrimSpace(name))
+
+			if err != nil {
+				return filter, "status", err
+			}
+
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	if value := c.Query("completed"); value != "" {
+		completed, err := strconv.ParseBool(value)
+
+		if err != nil {
+			return filter, "completed", fmt.Errorf("invalid completed: %s", value)
+		}
+
+		filter.Completed = &completed
+	}
+
+	if value := c.Query("project"); value != "" {
+		if value != domain.NoProject {
+			if _, err := uuid.Parse(value); err != nil {
+				return filter, "project", fmt.Errorf("invalid project: %s", value)
+			}
+		}
+
+		filter.Project = value
+	}
+
+	if value := c.Query("include_archived"); value != "" {
+		includeArchived, err := strconv.ParseBool(value)
+
+		if err != nil {
+			return filter, "include_archived", fmt.Errorf("invalid include_archived: %s", value)
+		}
+
+		filter.IncludeArchived = includeArchived
+	}
+
+	// tag=x is shorthand for a single required tag. Params are read in a
+	// fixed order so the field reported for a bad request never varies.
+	tagParams := []struct {
+		name string
+		dest *[]string
+	}{
+		{"tag", &filter.AllTags},
+		{"all_tags", &filter.AllTags},
+		{"any_tag", &filter.AnyTags},
+	}
+
+	for _, param := range tagParams {
+		name, dest := param.name, param.dest
+		value := c.Query(name)
+
+		if value == "" {
+			continue
+		}
+
+		tags, err := domain.NormalizeTagNames(strings.Split(value, ","))
+
+		if err != nil {
+			return filter, name, err
+		}
+
+		*dest = append(*dest, tags...)
+	}
+
+	timeParams := []struct {
+		name string
+		dest **time.Time
+	}{
+		{"created_after", &filter.CreatedAfter},
+		{"created_before", &filter.CreatedBefore},
+		{"updated_since", &filter.UpdatedSince},
+	}
+
+	for _, param := range timeParams {
+		name, dest := param.name, param.dest
+		value := c.Query(name)
+
+		if value == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, value)
+
+		if err != nil {
+			return filter, name, fmt.Errorf("invalid %s: expected RFC3339 datetime", name)
+		}
+
+		*dest = &parsed
+	}
+
+	return filter, "", nil
+}
@@ -22,11 +22,14 @@ import (
 	"todos/internal/adapter/database/sqlite/repository"
 	"todos/internal/adapter/http/helper"
 	"todos/internal/adapter/http/middleware"
+	"todos/internal/adapter/storage"
 	"todos/internal/core/domain"
 	"todos/internal/core/model/response"
+	"todos/internal/core/policy"
 	"todos/internal/core/port"
 	"todos/internal/core/service"
 	"todos/internal/core/telemetry"
+	"todos/internal/core/util"
 
 	factory "todos/pkg/test/factory"
 )
@@ -42,6 +45,23 @@ type TodoHandlerSuite struct {
 var globalTodoHandler *TodoHandler
 var ctx = context.Background()
 
+// testHandlers mirrors routes.HandlersConfig, which this package cannot import
+type testHandlers struct {
+	Todo     *TodoHandler
+	Reminder *ReminderHandler
+	Item     *TodoItemHandler
+	Tag      *TagHandler
+	Project  *ProjectHandler
+	Comment  *CommentHandler
+	Review   *ReviewHandler
+	Trash    *TrashHandler
+	Batch    *TodoBatchHandler
+	Transfer *TodoTransferHandler
+	Time     *TimeEntryHandler
+
+	Attachment *AttachmentHandler
+}
+
 func (s *TodoHandlerSuite) SetupSuite() {
 	globalTodoHandler = &TodoHandler{}
 }
@@ -54,11 +74,33 @@ func (s *TodoHandlerSuite) SetupTest() {
 	s.UserRepo = repository.NewUserRepository(db, probe)
 
 	// Create use case and handler
-	todoUseCase := service.NewTodoService(s.TodoRepo, probe)
+	projectRepo := repository.NewProjectRepository(db, probe)
+	workflowRepo := repository.NewWorkflowRepository(db, probe)
+	reviewRepo := repository.NewReviewRepository(db, probe)
+	todoUseCase := service.NewTodoService(s.TodoRepo, projectRepo, workflowRepo, reviewRepo, db, probe, policy.NewMembershipPolicy(projectRepo))
 	globalTodoHandler = NewTodoHandler(todoUseCase, nil)
 
+	reminderRepo := repository.NewReminderRepository(db, probe)
+	itemRepo := repository.NewTodoItemRepository(db, probe)
+	attachmentRepo := repository.NewAttachmentRepository(db, probe)
+	blobs := storage.NewFilesystemStore(s.T().TempDir())
+
 	// Setup router directly to avoid import cycle
-	s.Router = setupTodoTestRouter(globalTodoHandler)
+	s.Router = setupTodoTestRouter(testHandlers{
+		Todo:     globalTodoHandler,
+		Reminder: NewReminderHandler(service.NewReminderService(reminderRepo, todoUseCase, probe)),
+		Item:     NewTodoItemHandler(service.NewTodoItemService(itemRepo, todoUseCase, db, probe)),
+		Tag:      NewTagHandler(service.NewTagService(repository.NewTagRepository(db, probe), probe)),
+		Project:  NewProjectHandler(service.NewProjectService(projectRepo, s.UserRepo, workflowRepo, probe)),
+		Comment:  NewCommentHandler(service.NewCommentService(repository.NewCommentRepository(db, probe), todoUseCase, probe)),
+		Review:   NewReviewHandler(service.NewReviewService(reviewRepo, todoUseCase, s.UserRepo, probe)),
+		Trash:    NewTrashHandler(service.NewTrashService(s.TodoRepo, attachmentRepo, blobs, policy.NewMembershipPolicy(projectRepo), util.SystemClock{}, probe)),
+		Batch:    NewTodoBatchHandler(service.NewTodoBatchService(todoUseCase, db, probe)),
+		Transfer: NewTodoTransferHandler(service.NewTodoTransferService(s.TodoRepo, workflowRepo, util.SystemClock{}, probe)),
+		Time:     NewTimeEntryHandler(service.NewTimeEntryService(repository.NewTimeEntryRepository(db, probe), todoUseCase, util.SystemClock{}, probe)),
+
+		Attachment: NewAttachmentHandler(service.NewAttachmentService(attachmentRepo, blobs, todoUseCase, util.SystemClock{}, "secret", probe)),
+	})
 }
 
 func (s *TodoHandlerSuite) TearDownTest() {
@@ -72,27 +114,121 @@ func TestTodoHandlerSuite(t *testing.T) {
 	suite.Run(t, new(TodoHandlerSuite))
 }
 
-func setupTodoTestRouter(todoHandler *TodoHandler) *gin.Engine {
+func setupTodoTestRouter(handlers testHandlers) *gin.Engine {
+	todoHandler := handlers.Todo
+
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 
+	router.GET("/attachments/:uuid/download", handlers.Attachment.DownloadAttachment)
+
 	// Protected routes
 	protected := router.Group("/")
 	protected.Use(middleware.CurrentMiddleware())
 	protected.Use(middleware.GinJwtMiddleware())
 	{
 		protected.GET("/todos", todoHandler.GetAllTodos)
+		protected.GET("/todos/search", todoHandler.SearchTodos)
+		protected.GET("/todos/:uuid", todoHandler.GetTodo)
 		protected.POST("/todos", todoHandler.CreateTodo)
 		protected.PUT("/todo/:uuid", todoHandler.UpdateTodo)
+		protected.PATCH("/todos/:uuid", todoHandler.PatchTodo)
 		protected.DELETE("/todos/:uuid", todoHandler.DeleteByUUID)
+		protected.GET("/todos/:uuid/occurrences", todoHandler.GetOccurrences)
+		protected.POST("/todos/:uuid/occurrences/skip", todoHandler.SkipOccurrence)
+		protected.GET("/todos/:uuid/history", todoHandler.GetHistory)
+		protected.POST("/todos/:uuid/revert/:revision", todoHandler.RevertTodo)
+		protected.GET("/todos/:uuid/blockers", todoHandler.GetBlockers)
+		protected.POST("/todos/:uuid/blockers", todoHandler.AddBlocker)
+		protected.DELETE("/todos/:uuid/blockers/:blocker_uuid", todoHandler.RemoveBlocker)
+		protected.GET("/projects/:uuid/dependencies", todoHandler.GetDependencyGraph)
+
+		protected.GET("/todos/:uuid/reminders", handlers.Reminder.GetReminders)
+		protected.POST("/todos/:uuid/reminders", handlers.Reminder.CreateReminder)
+		protected.DELETE("/todos/:uuid/reminders/:reminder_uuid", handlers.Reminder.DeleteReminder)
+
+		protected.GET("/todos/:uuid/items", handlers.Item.GetItems)
+		protected.GET("/todos/:uuid/items/:item_uuid", handlers.Item.GetItem)
+		protected.POST("/todos/:uuid/items", handlers.Item.CreateItem)
+		protected.PUT("/todos/:uuid/items/:item_uuid", handlers.Item.UpdateItem)
+		protected.DELETE("/todos/:uuid/items/:item_uuid", handlers.Item.DeleteItem)
+
+		protected.GET("/todos/:uuid/comments", handlers.Comment.GetComments)
+		protected.POST("/todos/:uuid/comments", handlers.Comment.CreateComment)
+		protected.PUT("/todos/:uuid/comments/:comment_uuid", handlers.Comment.UpdateComment)
+		protected.DELETE("/todos/:uuid/comments/:comment_uuid", handlers.Comment.DeleteComment)
+		protected.GET("/todos/:uuid/comments/:comment_uuid/history", handlers.Comment.GetCommentHistory)
+
+		protected.GET("/todos/:uuid/reviews", handlers.Review.GetReviews)
+		protected.POST("/todos/:uuid/reviews", handlers.Review.RequestReview)
+		protected.POST("/todos/:uuid/reviews/approve", handlers.Review.ApproveTodo)
+		protected.POST("/todos/:uuid/reviews/reject", handlers.Review.RejectTodo)
+		protected.GET("/reviews/waiting", handlers.Review.GetWaitingReviews)
+
+		protected.POST("/todos/batch", handlers.Batch.ApplyBatch)
+
+		protected.GET("/todos/export", handlers.Transfer.ExportTodos)
+		protected.POST("/todos/import", handlers.Transfer.ImportTodos)
+
+		protected.GET("/todos/:uuid/time-entries", handlers.Time.GetTimeEntries)
+		protected.POST("/todos/:uuid/time-entries", handlers.Time.CreateTimeEntry)
+		protected.POST("/todos/:uuid/timer/start", handlers.Time.StartTimer)
+		protected.GET("/timer", handlers.Time.GetTimer)
+		protected.POST("/timer/stop", handlers.Time.StopTimer)
+		protected.PUT("/time-entries/:uuid", handlers.Time.UpdateTimeEntry)
+		protected.DELETE("/time-entries/:uuid", handlers.Time.DeleteTimeEntry)
+		protected.GET("/time-entries/report", handlers.Time.GetTimeReport)
+
+		protected.GET("/trash", handlers.Trash.GetTrash)
+		protected.POST("/trash/:uuid/restore", handlers.Trash.RestoreTodo)
+		protected.DELETE("/trash/:uuid", handlers.Trash.PurgeTodo)
+
+		protected.GET("/todos/:uuid/attachments", handlers.Attachment.GetAttachments)
+		protected.POST("/todos/:uuid/attachments", handlers.Attachment.UploadAttachment)
+		protected.GET("/todos/:uuid/attachments/:attachment_uuid", handlers.Attachment.GetAttachment)
+		protected.DELETE("/todos/:uuid/attachments/:attachment_uuid", handlers.Attachment.DeleteAttachment)
+
+		protected.GET("/tags", handlers.Tag.GetTags)
+		protected.POST("/tags", handlers.Tag.CreateTag)
+		protected.PUT("/tags/:uuid", handlers.Tag.RenameTag)
+		protected.POST("/tags/:uuid/merge", handlers.Tag.MergeTag)
+		protected.DELETE("/tags/:uuid", handlers.Tag.DeleteTag)
+
+		protected.GET("/projects", handlers.Project.GetProjects)
+		protected.POST("/projects", handlers.Project.CreateProject)
+		protected.GET("/projects/:uuid", handlers.Project.GetProject)
+		protected.PUT("/projects/:uuid", handlers.Project.UpdateProject)
+		protected.DELETE("/projects/:uuid", handlers.Project.DeleteProject)
+		protected.POST("/projects/:uuid/archive", handlers.Project.ArchiveProject)
+		protected.POST("/projects/:uuid/unarchive", handlers.Project.UnarchiveProject)
+		protected.PUT("/projects/:uuid/order", handlers.Project.ReorderProject)
+		protected.GET("/projects/:uuid/members", handlers.Project.GetMembers)
+		protected.POST("/projects/:uuid/members", handlers.Project.InviteMember)
+		protected.PUT("/projects/:uuid/members/:user_uuid", handlers.Project.UpdateMember)
+		protected.DELETE("/projects/:uuid/members/:user_uuid", handlers.Project.RemoveMember)
+		protected.GET("/projects/:uuid/workflow", handlers.Project.GetWorkflow)
+		protected.PUT("/projects/:uuid/workflow", handlers.Project.UpdateWorkflow)
+		protected.DELETE("/projects/:uuid/workflow", handlers.Project.ResetWorkflow)
 	}
 
 	return router
 }
 
+func (s *TodoHandlerSuite) serveRequest(method string, path string, body string, userId int) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	jwtToken, _ := helper.CreateJwtTokenForUser(userId)
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+	s.Router.ServeHTTP(rr, req)
+
+	return rr
+}
+
 func CreateUserMock(s *TodoHandlerSuite) domain.User {
 	user, _ := s.UserRepo.Create(ctx, factory.NewUser[domain.User](map[string]any{
 		"Name":              "User99",
@@ -103,6 +239,16 @@ func CreateUserMock(s *TodoHandlerSuite) domain.User {
 	return user
 }
 
+func CreateOtherUserMock(s *TodoHandlerSuite) domain.User {
+	user, _ := s.UserRepo.Create(ctx, factory.NewUser[domain.User](map[string]any{
+		"Name":              "User100",
+		"Email":             "user100@example.com",
+		"EncryptedPassword": "12345678",
+	}))
+
+	return user
+}
+
 func CreateTodo(s *TodoHandlerSuite, userId int) domain.Todo {
 	data, _ := s.TodoRepo.Create(ctx, factory.NewTodo[domain.Todo](map[string]any{
 		"Title":  "Task Created",
@@ -117,7 +263,7 @@ func (s *TodoHandlerSuite) TestGetAllTodosWithData() {
 
 	s.TodoRepo.Create(ctx, factory.NewTodo[domain.Todo](map[string]any{
 		"Title":  "99",
-		"Status": int(domain.TodoStatusPending),
+		"Status": domain.TodoStatusPending,
 		"UserId": user.ID,
 	}))
 
@@ -234,6 +380,43 @@ func (s *TodoHandlerSuite) TestUpdateTodoToCompleted() {
 	Expect(response.Data.Status).To(Equal("completed"))
 }
 
+func (s *TodoHandlerSuite) TestUpdateTodoLifecycle() {
+	user := CreateUserMock(s)
+	todo := CreateTodo(s, user.ID)
+
+	path := "/todo/" + todo.UUID.String()
+
+	update := func(body string) response.TodoResponse {
+		rr := s.serveRequest("PUT", path, body, user.ID)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+
+		data := struct {
+			Data response.TodoResponse `json:"data"`
+		}{}
+		raw, _ := io.ReadAll(rr.Body)
+		json.Unmarshal(raw, &data)
+
+		return data.Data
+	}
+
+	completed := update(`{"title": "Task Created", "completed": true}`)
+	Expect(completed.Status).To(Equal("completed"))
+	Expect(completed.CompletedAt).NotTo(BeNil())
+
+	// Leaving the lifecycle out of an update keeps it
+	renamed := update(`{"title": "Task Renamed"}`)
+	Expect(renamed.Status).To(Equal("completed"))
+	Expect(renamed.CompletedAt).To(Equal(completed.CompletedAt))
+
+	pending := update(`{"title": "Task Renamed", "status": "pending"}`)
+	Expect(pending.Status).To(Equal("pending"))
+	Expect(pending.Completed).To(BeFalse())
+	Expect(pending.CompletedAt).To(BeNil())
+
+	rr := s.serveRequest("PUT", path, `{"title": "Task Renamed", "status": "in_progress", "completed": true}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+}
+
 func (s *TodoHandlerSuite) TestDeleteByUUIDWhenIdExists() {
 	user := CreateUserMock(s)
 
@@ -332,7 +515,7 @@ func (s *TodoHandlerSuite) TestPaginationWithCursor() {
 	for i := 1; i <= 5; i++ {
 		s.TodoRepo.Create(ctx, factory.NewTodo[domain.Todo](map[string]any{
 			"Title":     fmt.Sprintf("Task %d", i),
-			"Status":    int(domain.TodoStatusPending),
+			"Status":    domain.TodoStatusPending,
 			"UserId":    user.ID,
 			"CreatedAt": baseTime.Add(time.Duration(i) * time.Minute), // Task 5 is newest
 		}))
@@ -428,3 +611,406 @@ func (s *TodoHandlerSuite) TestPaginationWithCursor() {
 	// Should have 5 unique titles
 	Expect(len(allTitles)).To(Equal(5))
 }
+
+func (s *TodoHandlerSuite) TestDeleteTodoFromAnotherUserReturnsNotFound() {
+	owner := CreateUserMock(s)
+	other := CreateOtherUserMock(s)
+	todo := CreateTodo(s, owner.ID)
+
+	path := fmt.Sprintf("/todos/%s", todo.UUID.String())
+	req, _ := http.NewRequest("DELETE", path, nil)
+	rr := httptest.NewRecorder()
+
+	jwtToken, _ := helper.CreateJwtTokenForUser(other.ID)
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+	s.Router.ServeHTTP(rr, req)
+
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	_, err := s.TodoRepo.GetByUUID(ctx, todo.UUID.String())
+	Expect(err).To(BeNil())
+}
+
+func (s *TodoHandlerSuite) TestUpdateTodoFromAnotherUserReturnsNotFound() {
+	owner := CreateUserMock(s)
+	other := CreateOtherUserMock(s)
+	todo := CreateTodo(s, owner.ID)
+
+	reqBody := strings.NewReader(`{"title": "Hijacked", "status": "completed", "completed": true}`)
+
+	path := fmt.Sprintf("/todo/%s", todo.UUID.String())
+	req, _ := http.NewRequest("PUT", path, reqBody)
+	rr := httptest.NewRecorder()
+
+	jwtToken, _ := helper.CreateJwtTokenForUser(other.ID)
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+	s.Router.ServeHTTP(rr, req)
+
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	saved, _ := s.TodoRepo.GetByUUID(ctx, todo.UUID.String())
+	Expect(saved.Title).To(Equal("Task Created"))
+}
+
+func (s *TodoHandlerSuite) TestConditionalWritesWithIfMatch() {
+	user := CreateUserMock(s)
+	todo := CreateTodo(s, user.ID)
+	path := "/todos/" + todo.UUID.String()
+
+	serve := func(method string, body string, ifMatch string) *httptest.ResponseRecorder {
+		target := path
+
+		// Full updates live under the singular route
+		if method == "PUT" {
+			target = "/todo/" + todo.UUID.String()
+		}
+
+		req, _ := http.NewRequest(method, target, strings.NewReader(body))
+
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+
+		jwtToken, _ := helper.CreateJwtTokenForUser(user.ID)
+		req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+		rr := httptest.NewRecorder()
+		s.Router.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := serve("GET", "", "")
+	Expect(rr.Code).To(Equal(http.StatusOK))
+	Expect(rr.Header().Get("ETag")).To(Equal(`"1"`))
+
+	list := s.serveRequest("GET", "/todos", "", user.ID)
+	Expect(list.Body.String()).To(ContainSubstring(`"etag":"\"1\""`))
+
+	rr = serve("PUT", `{"title": "Renamed once"}`, `"1"`)
+	Expect(rr.Code).To(Equal(http.StatusOK), rr.Body.String())
+	Expect(rr.Header().Get("ETag")).To(Equal(`"2"`))
+
+	// A client still holding version 1 gets the todo as it is now
+	for method, body := range map[string]string{"PUT": `{"title": "Renamed twice"}`, "PATCH": `{"title": "Renamed twice"}`, "DELETE": ""} {
+		rr = serve(method, body, `"1"`)
+		Expect(rr.Code).To(Equal(http.StatusPreconditionFailed), method)
+		Expect(rr.Header().Get("ETag")).To(Equal(`"2"`))
+
+		conflict := struct {
+			Error struct {
+				Details response.TodoResponse `json:"details"`
+			} `json:"error"`
+		}{}
+		raw, _ := io.ReadAll(rr.Body)
+		json.Unmarshal(raw, &conflict)
+
+		Expect(conflict.Error.Details.Title).To(Equal("Renamed once"))
+		Expect(conflict.Error.Details.Version).To(Equal(2))
+	}
+
+	// Weak tags never match
+	for _, ifMatch := range []string{"v2", `W/"2"`} {
+		rr = serve("PATCH", `{"title": "Renamed twice"}`, ifMatch)
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+		Expect(rr.Body.String()).To(ContainSubstring(`"field":"If-Match"`))
+	}
+
+	rr = serve("PATCH", `{"title": "Renamed twice"}`, "*")
+	Expect(rr.Code).To(Equal(http.StatusOK))
+	Expect(rr.Header().Get("ETag")).To(Equal(`"3"`))
+
+	// The tag covers what GET returns, adding an item changes the item count
+	rr = s.serveRequest("POST", path+"/items", `{"title": "First step"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	rr = serve("DELETE", "", `"3"`)
+	Expect(rr.Code).To(Equal(http.StatusPreconditionFailed))
+	Expect(rr.Header().Get("ETag")).To(Equal(`"4"`))
+
+	rr = serve("DELETE", "", `"4"`)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+}
+
+func (s *TodoHandlerSuite) TestGetTodoByUUID() {
+	user := CreateUserMock(s)
+	todo := CreateTodo(s, user.ID)
+
+	path := fmt.Sprintf("/todos/%s", todo.UUID.String())
+	req, _ := http.NewRequest("GET", path, nil)
+	rr := httptest.NewRecorder()
+
+	jwtToken, _ := helper.CreateJwtTokenForUser(user.ID)
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+	s.Router.ServeHTTP(rr, req)
+
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	body, _ := io.ReadAll(rr.Body)
+
+	response := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	json.Unmarshal(body, &response)
+
+	Expect(response.Data.UUID).To(Equal(todo.UUID))
+	Expect(response.Data.Title).To(Equal("Task Created"))
+}
+
+func (s *TodoHandlerSuite) TestGetTodoByUUIDNotFound() {
+	owner := CreateUserMock(s)
+	other := CreateOtherUserMock(s)
+	todo := CreateTodo(s, owner.ID)
+
+	paths := []string{
+		fmt.Sprintf("/todos/%s", todo.UUID.String()),
+		"/todos/00000000-0000-0000-0000-000000000000",
+	}
+
+	for _, path := range paths {
+		req, _ := http.NewRequest("GET", path, nil)
+		rr := httptest.NewRecorder()
+
+		jwtToken, _ := helper.CreateJwtTokenForUser(other.ID)
+		req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+		s.Router.ServeHTTP(rr, req)
+
+		Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+		body, _ := io.ReadAll(rr.Body)
+
+		errorResponse := response.ErrorResponse{}
+		json.Unmarshal(body, &errorResponse)
+
+		Expect(errorResponse.Error.Code).To(Equal("NOT_FOUND"))
+	}
+}
+
+func (s *TodoHandlerSuite) TestPaginationWithSortByTitle() {
+	user := CreateUserMock(s)
+
+	// Same creation time for every row, so only the sort key can keep pages stable
+	createdAt := time.Now()
+
+	for _, title := range []string{"delta", "alpha", "echo", "charlie", "bravo"} {
+		s.TodoRepo.Create(ctx, factory.NewTodo[domain.Todo](map[string]any{
+			"Title":     title,
+			"Status":    domain.TodoStatusPending,
+			"UserId":    user.ID,
+			"CreatedAt": createdAt,
+		}))
+	}
+
+	jwtToken, _ := helper.CreateJwtTokenForUser(user.ID)
+
+	titles := []string{}
+	cursor := ""
+
+	for page := 0; page < 3; page++ {
+		path := "/todos?limit=2&sort=title:asc"
+
+		if cursor != "" {
+			path += "&cursor=" + url.QueryEscape(cursor)
+		}
+
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+		s.Router.ServeHTTP(rr, req)
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+
+		body, _ := io.ReadAll(rr.Body)
+
+		data := response.CursorResponse{}
+		json.Unmarshal(body, &data)
+
+		var todos []response.TodoResponse
+		json.Unmarshal(data.Data, &todos)
+
+		for _, todo := range todos {
+			titles = append(titles, todo.Title)
+		}
+
+		cursor = data.Pagination.NextCursor
+	}
+
+	Expect(titles).To(Equal([]string{"alpha", "bravo", "charlie", "delta", "echo"}))
+	Expect(cursor).To(BeEmpty())
+}
+
+func (s *TodoHandlerSuite) TestGetAllTodosRejectsInvalidFilters() {
+	user := CreateUserMock(s)
+	CreateTodo(s, user.ID)
+
+	jwtToken, _ := helper.CreateJwtTokenForUser(user.ID)
+
+	// Grab a cursor issued for the default sort
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/todos?limit=1", nil)
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+	s.Router.ServeHTTP(rr, req)
+
+	CreateTodo(s, user.ID)
+
+	rr = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/todos?limit=1", nil)
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+	s.Router.ServeHTTP(rr, req)
+
+	body, _ := io.ReadAll(rr.Body)
+	data := response.CursorResponse{}
+	json.Unmarshal(body, &data)
+
+	Expect(data.Pagination.NextCursor).ToNot(BeEmpty())
+
+	paths := []string{
+		"/todos?sort=user_id",
+		"/todos?status=Not+A+Status",
+		"/todos?completed=maybe",
+		"/todos?created_after=yesterday",
+		"/todos?sort=title:asc&cursor=" + url.QueryEscape(data.Pagination.NextCursor),
+	}
+
+	for _, path := range paths {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+		s.Router.ServeHTTP(rr, req)
+
+		Expect(rr.Code).To(Equal(http.StatusBadRequest), path)
+	}
+}
+
+func (s *TodoHandlerSuite) TestGetAllTodosReportsTheFirstInvalidFilter() {
+	user := CreateUserMock(s)
+	jwtToken, _ := helper.CreateJwtTokenForUser(user.ID)
+
+	// The same request always blames the same param, whatever else is wrong
+	for i := 0; i < 20; i++ {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/todos?updated_since=x&created_before=x&created_after=x", nil)
+		req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+		s.Router.ServeHTTP(rr, req)
+
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+		Expect(rr.Body.String()).To(ContainSubstring(`"field":"created_after"`))
+	}
+}
+
+func (s *TodoHandlerSuite) TestSearchTodosWithPagination() {
+	user := CreateUserMock(s)
+
+	for i := 1; i <= 3; i++ {
+		s.TodoRepo.Create(ctx, factory.NewTodo[domain.Todo](map[string]any{
+			"Title":       fmt.Sprintf("Report %d", i),
+			"Description": "quarterly numbers",
+			"UserId":      user.ID,
+		}))
+	}
+
+	jwtToken, _ := helper.CreateJwtTokenForUser(user.ID)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/todos/search?q=rep&limit=2", nil)
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+	s.Router.ServeHTTP(rr, req)
+
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	body, _ := io.ReadAll(rr.Body)
+
+	data := response.CursorResponse{}
+	json.Unmarshal(body, &data)
+
+	var results []response.TodoSearchResponse
+	json.Unmarshal(data.Data, &results)
+
+	Expect(results).To(HaveLen(2))
+	Expect(results[0].Highlight.Title).To(ContainSubstring("<mark>Report</mark>"))
+	Expect(data.Pagination.HasNext).To(BeTrue())
+
+	rr = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/todos/search?q=rep&limit=2&cursor="+url.QueryEscape(data.Pagination.NextCursor), nil)
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+	s.Router.ServeHTTP(rr, req)
+
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	body, _ = io.ReadAll(rr.Body)
+
+	data = response.CursorResponse{}
+	json.Unmarshal(body, &data)
+
+	var page2 []response.TodoSearchResponse
+	json.Unmarshal(data.Data, &page2)
+
+	Expect(page2).To(HaveLen(1))
+	Expect(data.Pagination.HasNext).To(BeFalse())
+	Expect(page2[0].UUID).ToNot(BeElementOf(results[0].UUID, results[1].UUID))
+}
+
+func (s *TodoHandlerSuite) TestSearchTodosWithoutQuery() {
+	user := CreateUserMock(s)
+
+	jwtToken, _ := helper.CreateJwtTokenForUser(user.ID)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/todos/search?q=", nil)
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+	s.Router.ServeHTTP(rr, req)
+
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+}
+
+func (s *TodoHandlerSuite) TestCreateTodoWithAllDayDueDate() {
+	user := CreateUserMock(s)
+
+	reqBody := strings.NewReader(`{"title": "Pay taxes", "due_at": "2020-04-15T17:45:00-03:00", "all_day": true}`)
+
+	req, _ := http.NewRequest("POST", "/todos", reqBody)
+	rr := httptest.NewRecorder()
+
+	jwtToken, _ := helper.CreateJwtTokenForUser(user.ID)
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+	s.Router.ServeHTTP(rr, req)
+
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	body, _ := io.ReadAll(rr.Body)
+
+	created := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	json.Unmarshal(body, &created)
+
+	Expect(created.Data.AllDay).To(BeTrue())
+	Expect(created.Data.DueAt.Equal(time.Date(2020, 4, 15, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+	Expect(created.Data.Overdue).To(BeTrue())
+
+	rr = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/todos?due=overdue", nil)
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+	s.Router.ServeHTTP(rr, req)
+
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	body, _ = io.ReadAll(rr.Body)
+
+	data := response.CursorResponse{}
+	json.Unmarshal(body, &data)
+
+	Expect(data.Size).To(Equal(1))
+}
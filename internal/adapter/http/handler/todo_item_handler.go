@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	. "todos/internal/adapter/http/helper"
+	. "todos/internal/adapter/http/validation"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/request"
+	"todos/internal/core/model/response"
+	"todos/internal/core/port"
+	"todos/internal/core/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TodoItemHandler struct {
+	svc port.TodoItemService
+}
+
+func NewTodoItemHandler(itemUseCase port.TodoItemService) *TodoItemHandler {
+	return &TodoItemHandler{
+		svc: itemUseCase,
+	}
+}
+
+func (h *TodoItemHandler) GetItems(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+	ctx := c.Request.Context()
+
+	items, err := h.svc.List(ctx, userId, c.Param("uuid"))
+
+	if err != nil {
+		sendTodoItemError(c, err, "Error listing items")
+		return
+	}
+
+	data := make([]response.TodoItemResponse, 0, len(items))
+
+	for _, item := range items {
+		data = append(data, response.NewTodoItemResponse(item))
+	}
+
+	SendSuccess(c, http.StatusOK, data)
+}
+
+func (h *TodoItemHandler) GetItem(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+	ctx := c.Request.Context()
+
+	item, err := h.svc.GetByUUID(ctx, userId, c.Param("uuid"), c.Param("item_uuid"))
+
+	if err != nil {
+		sendTodoItemError(c, err, "Error getting item")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewTodoItemResponse(item))
+}
+
+func (h *TodoItemHandler) CreateItem(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+	ctx := c.Request.Context()
+
+	params, err := util.ParamsToMap[request.TodoItemRequest](c)
+
+	if err != nil {
+		SendBadRequestError(c, "request", "Invalid request parameters")
+		return
+	}
+
+	if err := Validator.Struct(params); err != nil {
+		SendValidationError(c, err)
+		return
+	}
+
+	if params.Title == nil {
+		SendBadRequestError(c, "title", "Title is required")
+		return
+	}
+
+	item := domain.TodoItem{
+		Title:    *params.Title,
+		Position: domain.TodoItemAppend,
+	}
+
+	if params.Done != nil {
+		item.Done = *params.Done
+	}
+
+	if params.Position != nil {
+		item.Position = *params.Position
+	}
+
+	item, err = h.svc.Create(ctx, userId, c.Param("uuid"), item)
+
+	if err != nil {
+		sendTodoItemError(c, err, "Error creating item")
+		return
+	}
+
+	SendSuccess(c, http.StatusCreated, response.NewTodoItemResponse(item))
+}
+
+func (h *TodoItemHandler) UpdateItem(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+	ctx := c.Request.Context()
+
+	params, err := util.ParamsToMap[request.TodoItemRequest](c)
+
+	if err != nil {
+		SendBadRequestError(c, "request", "Invalid request parameters")
+		return
+	}
+
+	if err := Validator.Struct(params); err != nil {
+		SendValidationError(c, err)
+		return
+	}
+
+	item, err := h.svc.UpdateByUUID(ctx, userId, c.Param("uuid"), c.Param("item_uuid"), domain.TodoItemChanges{
+		Title:          params.Title,
+		Done:           params.Done,
+		Position:       params.Position,
+		CompleteParent: params.CompleteParent,
+	})
+
+	if err != nil {
+		sendTodoItemError(c, err, "Error updating item")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewTodoItemResponse(item))
+}
+
+func (h *TodoItemHandler) DeleteItem(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+	ctx := c.Request.Context()
+
+	completeParent := c.Query("complete_parent") == "true"
+
+	err := h.svc.DeleteByUUID(ctx, userId, c.Param("uuid"), c.Param("item_uuid"), completeParent)
+
+	if err != nil {
+		sendTodoItemError(c, err, "Error deleting item")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Item deleted successfully",
+	})
+}
+
+func sendTodoItemError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden):
+		sendTodoAccessError(c, err)
+	case errors.Is(err, domain.ErrTodoItemNotFound):
+		SendNotFoundError(c, "Item not found")
+	default:
+		slog.Error(message, "error", err, "uuid", c.Param("uuid"))
+		SendInternalError(c, message)
+	}
+}
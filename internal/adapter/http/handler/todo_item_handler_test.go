@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	. "github.com/onsi/gomega"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+
+	factory "todos/pkg/test/factory"
+)
+
+func (s *TodoHandlerSuite) TestTodoItemsLifecycle() {
+	user := CreateUserMock(s)
+
+	todo, _ := s.TodoRepo.Create(ctx, factory.NewTodo[domain.Todo](map[string]any{
+		"Title":     "Fence",
+		"Status":    domain.TodoStatusPending,
+		"Completed": false,
+		"UserId":    user.ID,
+	}))
+
+	path := "/todos/" + todo.UUID.String() + "/items"
+
+	rr := s.serveRequest("POST", path, `{"title": "Buy paint"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	first := struct {
+		Data response.TodoItemResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &first)
+
+	Expect(first.Data.Position).To(Equal(0))
+
+	rr = s.serveRequest("POST", path, `{"title": "Paint the fence", "done": true}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	rr = s.serveRequest("GET", "/todos/"+todo.UUID.String(), "", user.ID)
+
+	parent := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &parent)
+
+	Expect(parent.Data.ItemsTotal).To(Equal(2))
+	Expect(parent.Data.ItemsDone).To(Equal(1))
+
+	rr = s.serveRequest("PUT", path+"/"+first.Data.UUID.String(), `{"done": true, "complete_parent": true}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("GET", "/todos/"+todo.UUID.String(), "", user.ID)
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &parent)
+
+	Expect(parent.Data.ItemsDone).To(Equal(2))
+	Expect(parent.Data.Completed).To(BeTrue())
+	Expect(parent.Data.Status).To(Equal("completed"))
+
+	rr = s.serveRequest("DELETE", path+"/"+first.Data.UUID.String(), "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("GET", path+"/"+first.Data.UUID.String(), "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("GET", path, "", user.ID)
+
+	listed := struct {
+		Data []response.TodoItemResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &listed)
+
+	Expect(listed.Data).To(HaveLen(1))
+}
+
+func (s *TodoHandlerSuite) TestTodoItemsAreScopedToOwner() {
+	user := CreateUserMock(s)
+	other := CreateOtherUserMock(s)
+	todo := CreateTodo(s, user.ID)
+	otherTodo := CreateTodo(s, other.ID)
+
+	path := "/todos/" + todo.UUID.String() + "/items"
+
+	rr := s.serveRequest("POST", path, `{"title": "Mine"}`, user.ID)
+
+	created := struct {
+		Data response.TodoItemResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &created)
+
+	rr = s.serveRequest("POST", path, `{"title": "Sneaky"}`, other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	// The item cannot be reached through a todo it does not belong to
+	rr = s.serveRequest("PUT", "/todos/"+otherTodo.UUID.String()+"/items/"+created.Data.UUID.String(), `{"done": true}`, other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("POST", path, `{"done": true}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+}
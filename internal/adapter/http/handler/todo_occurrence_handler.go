@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	. "todos/internal/adapter/http/helper"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/request"
+	"todos/internal/core/model/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxOccurrencePreview = 50
+
+// GetOccurrences previews the next dates of a recurring todo, ?count=5 by default
+func (t *TodoHandler) GetOccurrences(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+	count := 5
+
+	if value := c.Query("count"); value != "" {
+		parsed, err := strconv.Atoi(value)
+
+		if err != nil || parsed < 1 || parsed > maxOccurrencePreview {
+			SendBadRequestError(c, "count", "count must be between 1 and "+strconv.Itoa(maxOccurrencePreview))
+			return
+		}
+
+		count = parsed
+	}
+
+	occurrences, err := t.svc.Occurrences(c.Request.Context(), userId, c.Param("uuid"), count)
+
+	if err != nil {
+		sendOccurrenceError(c, err, "Error previewing occurrences")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewOccurrencesResponse(occurrences))
+}
+
+// SkipOccurrence skips the occurrence in the optional body, or the current one
+func (t *TodoHandler) SkipOccurrence(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	var params request.OccurrenceSkipRequest
+
+	if c.Request.ContentLength != 0 {
+		var ok bool
+
+		if params, ok = bindRequest[request.OccurrenceSkipRequest](c); !ok {
+			return
+		}
+	}
+
+	todo, err := t.svc.SkipOccurrence(c.Request.Context(), userId, c.Param("uuid"), params.At)
+
+	if err != nil {
+		sendOccurrenceError(c, err, "Error skipping occurrence")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewTodoResponse(todo))
+}
+
+func sendOccurrenceError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden):
+		sendTodoAccessError(c, err)
+	case errors.Is(err, domain.ErrNotRecurring) || errors.Is(err, domain.ErrNoNextOccurrence):
+		SendConflictError(c, "rrule", err.Error())
+	case errors.Is(err, domain.ErrInvalidOccurrence):
+		SendBadRequestError(c, "at", err.Error())
+	default:
+		slog.Error(message, "error", err, "uuid", c.Param("uuid"))
+		SendInternalError(c, message)
+	}
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"todos/internal/core/model/response"
+)
+
+func (s *TodoHandlerSuite) TestRecurringTodoSpawnsNextOccurrence() {
+	user := CreateUserMock(s)
+
+	decode := func(body io.Reader) response.TodoResponse {
+		data := struct {
+			Data response.TodoResponse `json:"data"`
+		}{}
+		raw, _ := io.ReadAll(body)
+		json.Unmarshal(raw, &data)
+
+		return data.Data
+	}
+
+	listTodos := func() []response.TodoResponse {
+		rr := s.serveRequest("GET", "/todos", "", user.ID)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+
+		data := response.CursorResponse{}
+		raw, _ := io.ReadAll(rr.Body)
+		json.Unmarshal(raw, &data)
+
+		var todos []response.TodoResponse
+		json.Unmarshal(data.Data, &todos)
+
+		return todos
+	}
+
+	rr := s.serveRequest("POST", "/todos", `{"title": "Water plants", "rrule": "FREQ=WEEKLY"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("POST", "/todos", `{"title": "Water plants", "rrule": "FREQ=HOURLY", "due_at": "2030-01-07T09:00:00Z"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = s.serveRequest("POST", "/todos", `{"title": "Water plants", "description": "Balcony too", "tags": ["home"], "rrule": "RRULE:FREQ=WEEKLY;BYDAY=MO,TH", "due_at": "2030-01-07T09:00:00Z"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	created := decode(rr.Body)
+	Expect(created.RRule).To(Equal("FREQ=WEEKLY;BYDAY=MO,TH"))
+
+	path := "/todos/" + created.UUID.String()
+
+	rr = s.serveRequest("GET", path+"/occurrences?count=3", "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	preview := struct {
+		Data response.OccurrencesResponse `json:"data"`
+	}{}
+	raw, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(raw, &preview)
+
+	Expect(preview.Data.Occurrences).To(Equal([]time.Time{
+		time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC),
+		time.Date(2030, 1, 10, 9, 0, 0, 0, time.UTC),
+		time.Date(2030, 1, 14, 9, 0, 0, 0, time.UTC),
+	}))
+
+	rr = s.serveRequest("GET", path+"/occurrences?count=0", "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	// Skipping the Thursday leaves the current occurrence alone
+	rr = s.serveRequest("POST", path+"/occurrences/skip", `{"at": "2030-01-10T09:00:00Z"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	skipped := decode(rr.Body)
+	Expect(*skipped.DueAt).To(Equal(time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)))
+	Expect(skipped.Skipped).To(Equal([]time.Time{time.Date(2030, 1, 10, 9, 0, 0, 0, time.UTC)}))
+
+	rr = s.serveRequest("POST", path+"/occurrences/skip", `{"at": "2030-01-08T09:00:00Z"}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	// Completing spawns the next occurrence with the content carried over
+	rr = s.serveRequest("PUT", "/todo/"+created.UUID.String(), `{"title": "Water plants", "completed": true}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	todos := listTodos()
+	Expect(todos).To(HaveLen(2))
+
+	var next response.TodoResponse
+
+	for _, todo := range todos {
+		if todo.UUID != created.UUID {
+			next = todo
+		}
+	}
+
+	Expect(next.Title).To(Equal("Water plants"))
+	Expect(next.Description).To(Equal("Balcony too"))
+	Expect(next.Tags).To(Equal([]string{"home"}))
+	Expect(next.Completed).To(BeFalse())
+	Expect(*next.DueAt).To(Equal(time.Date(2030, 1, 14, 9, 0, 0, 0, time.UTC)))
+
+	// Completing the same todo again does not spawn twice
+	rr = s.serveRequest("PUT", "/todo/"+created.UUID.String(), `{"title": "Water plants", "completed": false}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("PUT", "/todo/"+created.UUID.String(), `{"title": "Water plants", "completed": true}`, user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	Expect(listTodos()).To(HaveLen(2))
+
+	// Skipping without a body moves the series along
+	rr = s.serveRequest("POST", "/todos/"+next.UUID.String()+"/occurrences/skip", "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+	Expect(*decode(rr.Body).DueAt).To(Equal(time.Date(2030, 1, 17, 9, 0, 0, 0, time.UTC)))
+
+	// Plain todos have nothing to preview
+	plain := CreateTodo(s, user.ID)
+
+	rr = s.serveRequest("GET", "/todos/"+plain.UUID.String()+"/occurrences", "", user.ID)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+}
@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	. "todos/internal/adapter/http/helper"
+	. "todos/internal/adapter/http/validation"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/request"
+	"todos/internal/core/model/response"
+	"todos/internal/core/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// jsonPatchContentType selects a JSON patch, any other body is read as a
+// JSON merge patch
+const jsonPatchContentType = "application/json-patch+json"
+
+// patchableTodoFields are the members of a todo a patch may change
+var patchableTodoFields = []string{"title", "description", "status", "completed", "due_at", "all_day", "tags", "project_uuid", "rrule"}
+
+// PatchTodo changes only the fields the patch mentions. A merge patch
+// (RFC 7396) clears fields set to null, a JSON patch (RFC 6902) is applied to
+// the todo as GET returns it and may test its current values.
+func (t *TodoHandler) PatchTodo(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+	ctx := c.Request.Context()
+	uid := c.Param("uuid")
+
+	body, err := c.GetRawData()
+
+	if err != nil {
+		SendBadRequestError(c, "request", "Invalid request parameters")
+		return
+	}
+
+	version, err := ifMatchVersion(c)
+
+	if err != nil {
+		SendBadRequestError(c, "If-Match", err.Error())
+		return
+	}
+
+	force, err := forceParam(c)
+
+	if err != nil {
+		SendBadRequestError(c, "force", err.Error())
+		return
+	}
+
+	// pinned is set when the version to write against was not sent but taken
+	// from the todo the JSON patch was applied to
+	pinned := false
+
+	if c.ContentType() == jsonPatchContentType {
+		current, err := t.svc.GetByUUID(ctx, userId, uid)
+
+		if err != nil {
+			if errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden) {
+				sendTodoAccessError(c, err)
+				return
+			}
+
+			slog.Error("Error getting todo", "error", err, "uuid", uid)
+			SendInternalError(c, "Error patching todo")
+			return
+		}
+
+		// The operations only make sense against the version they were
+		// written for
+		if version != 0 && version != current.Version {
+			t.sendVersionConflict(c, userId, uid)
+			return
+		}
+
+		// Without If-Match the tests still hold only if nobody changed the
+		// todo before the patch is written
+		if version == 0 {
+			version = current.Version
+			pinned = true
+		}
+
+		if body, err = jsonPatchToMergePatch(body, current); err != nil {
+			if errors.Is(err, util.ErrJSONPatchTestFailed) {
+				SendConflictError(c, "patch", err.Error())
+				return
+			}
+
+			SendBadRequestError(c, "patch", err.Error())
+			return
+		}
+	}
+
+	patch, field, err := parseMergePatch(body)
+
+	if err != nil {
+		if validationErrors := FormatValidationErrors(err); len(validationErrors) > 0 {
+			SendValidationError(c, err)
+			return
+		}
+
+		SendBadRequestError(c, field, err.Error())
+		return
+	}
+
+	patch.Version = version
+	patch.Force = force
+	todo, err := t.svc.Patch(ctx, userId, uid, patch)
+
+	if err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) && pinned {
+			SendConflictError(c, "patch", "The todo changed while the patch was applied, send it again")
+			return
+		}
+
+		if errors.Is(err, domain.ErrVersionConflict) {
+			t.sendVersionConflict(c, userId, uid)
+			return
+		}
+
+		sendTodoUpdateError(c, err)
+		return
+	}
+
+	c.Header("ETag", todo.ETag())
+	c.JSON(http.StatusOK, gin.H{"data": response.NewTodoResponse(todo)})
+}
+
+// parseMergePatch reads a merge patch, keeping apart the fields it leaves out
+// from those it sets to null
+func parseMergePatch(body []byte) (domain.TodoPatch, string, error) {
+	var patch domain.TodoPatch
+	var fields map[string]json.RawMessage
+
+	if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+		return patch, "request", errors.New("the patch must be a JSON object")
+	}
+
+	for name, value := range fields {
+		if !slices.Contains(patchableTodoFields, name) {
+			return patch, name, fmt.Errorf("%s cannot be patched", name)
+		}
+
+		if isJSONNull(value) && (name == "title" || name == "status" || name == "completed") {
+			return patch, name, fmt.Errorf("%s cannot be null", name)
+		}
+	}
+
+	var params request.TodoPatchRequest
+
+	if err := json.Unmarshal(body, &params); err != nil {
+		var typeError *json.UnmarshalTypeError
+
+		if errors.As(err, &typeError) {
+			return patch, typeError.Field, fmt.Errorf("invalid %s", typeError.Field)
+		}
+
+		return patch, "request", errors.New("Invalid request parameters")
+	}
+
+	if err := Validator.Struct(params); err != nil {
+		return patch, "", err
+	}
+
+	patch.Title = patchField(fields, "title", params.Title)
+	patch.Description = patchField(fields, "description", params.Description)
+	patch.Completed = patchField(fields, "completed", params.Completed)
+	patch.DueAt = patchField(fields, "due_at", params.DueAt)
+	patch.AllDay = patchField(fields, "all_day", params.AllDay)
+	patch.RRule = patchField(fields, "rrule", params.RRule)
+
+	if params.Status != nil {
+		status, err := domain.ParseTodoStatus(*params.Status)
+
+		if err != nil {
+			return patch, "status", err
+		}
+
+		patch.Status = domain.Some(status)
+	}
+
+	if _, ok := fields["tags"]; ok {
+		patch.Tags = domain.Null[[]string]()
+
+		if params.Tags != nil {
+			patch.Tags = domain.Some(params.Tags)
+		}
+	}
+
+	if _, ok := fields["project_uuid"]; ok {
+		project, err := parseProjectUUID(params.ProjectUUID)
+
+		if err != nil {
+			return patch, "project_uuid", err
+		}
+
+		patch.ProjectUUID = domain.Null[uuid.UUID]()
+
+		if project != nil && *project != uuid.Nil {
+			patch.ProjectUUID = domain.Some(*project)
+		}
+	}
+
+	return patch, "", nil
+}
+
+func patchField[T any](fields map[string]json.RawMessage, name string, value *T) domain.Optional[T] {
+	if _, ok := fields[name]; !ok {
+		return domain.Optional[T]{}
+	}
+
+	return domain.Optional[T]{Set: true, Value: value}
+}
+
+func isJSONNull(value json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(value), []byte("null"))
+}
+
+// jsonPatchToMergePatch applies a JSON patch to the patchable fields of the
+// current todo and returns the merge patch with the same effect
+func jsonPatchToMergePatch(body []byte, current domain.Todo) ([]byte, error) {
+	var operations []util.JSONPatchOperation
+
+	if err := json.Unmarshal(body, &operations); err != nil {
+		return nil, errors.New("the patch must be a JSON array of operations")
+	}
+
+	for _, operation := range operations {
+		paths := []string{operation.Path}
+
+		if operation.Op == "move" || operation.Op == "copy" {
+			paths = append(paths, operation.From)
+		}
+
+		for _, path := range paths {
+			name, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+
+			if !strings.HasPrefix(path, "/") || !slices.Contains(patchableTodoFields, name) {
+				return nil, fmt.Errorf("%s cannot be patched", path)
+			}
+		}
+	}
+
+	original := todoPatchDocument(current)
+	patched, err := util.ApplyJSONPatch(todoPatchDocument(current), operations)
+
+	if err != nil {
+		return nil, err
+	}
+
+	document := patched.(map[string]any)
+	merge := map[string]any{}
+
+	for _, name := range patchableTodoFields {
+		value, ok := document[name]
+		previous, had := original[name]
+
+		switch {
+		case ok && (!had || !reflect.DeepEqual(value, previous)):
+			merge[name] = value
+		case !ok && had:
+			merge[name] = nil
+		}
+	}
+
+	return json.Marshal(merge)
+}
+
+// todoPatchDocument is the todo as GET returns it, cut down to the fields a
+// patch may change
+func todoPatchDocument(todo domain.Todo) map[string]any {
+	data, _ := json.Marshal(response.NewTodoResponse(todo))
+
+	var document map[string]any
+	json.Unmarshal(data, &document)
+
+	for name := range document {
+		if !slices.Contains(patchableTodoFields, name) {
+			delete(document, name)
+		}
+	}
+
+	return document
+}
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/gomega"
+
+	"todos/internal/adapter/http/helper"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+	"todos/internal/core/port"
+)
+
+func (s *TodoHandlerSuite) TestPatchTodo() {
+	owner := CreateUserMock(s)
+	other := CreateOtherUserMock(s)
+
+	rr := s.serveRequest("POST", "/todos", `{"title": "Write guide", "description": "First draft", "due_at": "2030-01-07T00:00:00Z", "all_day": true, "tags": ["docs"], "status": "in_progress"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	todo := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &todo)
+
+	path := "/todos/" + todo.Data.UUID.String()
+
+	patch := func(body string) response.TodoResponse {
+		rr := s.serveRequest("PATCH", path, body, owner.ID)
+		Expect(rr.Code).To(Equal(http.StatusOK), rr.Body.String())
+
+		patched := struct {
+			Data response.TodoResponse `json:"data"`
+		}{}
+		raw, _ := io.ReadAll(rr.Body)
+		json.Unmarshal(raw, &patched)
+
+		return patched.Data
+	}
+
+	// Fields left out stay as they were, the title is not needed
+	patched := patch(`{"all_day": false}`)
+
+	Expect(patched.Title).To(Equal("Write guide"))
+	Expect(patched.Description).To(Equal("First draft"))
+	Expect(patched.DueAt.UTC().Format("2006-01-02")).To(Equal("2030-01-07"))
+	Expect(patched.AllDay).To(BeFalse())
+	Expect(patched.Tags).To(Equal([]string{"docs"}))
+	Expect(patched.Status).To(Equal("in_progress"))
+
+	// Null clears a field
+	patched = patch(`{"description": null, "due_at": null, "tags": null}`)
+
+	Expect(patched.Title).To(Equal("Write guide"))
+	Expect(patched.Description).To(BeEmpty())
+	Expect(patched.DueAt).To(BeNil())
+	Expect(patched.Tags).To(BeEmpty())
+
+	// The status can go back to pending and completed back to false
+	patched = patch(`{"completed": true}`)
+	Expect(patched.Completed).To(BeTrue())
+
+	patched = patch(`{"completed": false}`)
+	Expect(patched.Completed).To(BeFalse())
+
+	patched = patch(`{"status": "pending"}`)
+	Expect(patched.Status).To(Equal("pending"))
+
+	invalid := map[string]string{
+		`{"title": null}`:          "title",
+		`{"title": "No"}`:          "title",
+		`{"uuid": "x"}`:            "uuid",
+		`{"completed": "yes"}`:     "completed",
+		`{"status": "unknown"}`:    "status",
+		`{"project_uuid": "nope"}`: "project_uuid",
+		`[]`:                       "request",
+	}
+
+	for body, field := range invalid {
+		rr = s.serveRequest("PATCH", path, body, owner.ID)
+		Expect(rr.Code).To(Equal(http.StatusBadRequest), body)
+		Expect(rr.Body.String()).To(ContainSubstring(`"field":"`+field+`"`), body)
+	}
+
+	rr = s.serveRequest("PATCH", path, `{"title": "Taken over"}`, other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	// JSON patches apply to the todo as GET returns it
+	jsonPatch := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("PATCH", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json-patch+json")
+
+		jwtToken, _ := helper.CreateJwtTokenForUser(owner.ID)
+		req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+		rr := httptest.NewRecorder()
+		s.Router.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr = jsonPatch(`[{"op": "test", "path": "/title", "value": "Someone else's"}, {"op": "replace", "path": "/title", "value": "Never"}]`)
+	Expect(rr.Code).To(Equal(http.StatusConflict))
+
+	rr = jsonPatch(`[{"op": "replace", "path": "/uuid", "value": "x"}]`)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = jsonPatch(`[{"op": "remove", "path": "/tags/3"}]`)
+	Expect(rr.Code).To(Equal(http.StatusBadRequest))
+
+	rr = jsonPatch(`[
+		{"op": "test", "path": "/title", "value": "Write guide"},
+		{"op": "replace", "path": "/title", "value": "Write the guide"},
+		{"op": "add", "path": "/tags", "value": ["docs"]},
+		{"op": "add", "path": "/tags/-", "value": "urgent"},
+		{"op": "add", "path": "/description", "value": "Second draft"}
+	]`)
+	Expect(rr.Code).To(Equal(http.StatusOK), rr.Body.String())
+
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &todo)
+	patched = todo.Data
+
+	Expect(patched.Title).To(Equal("Write the guide"))
+	Expect(patched.Tags).To(Equal([]string{"docs", "urgent"}))
+	Expect(patched.Description).To(Equal("Second draft"))
+
+	rr = jsonPatch(`[{"op": "remove", "path": "/description"}]`)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+	Expect(rr.Body.String()).NotTo(ContainSubstring("Second draft"))
+}
+
+func (s *TodoHandlerSuite) TestRevertClearsDescriptionAndDueDate() {
+	owner := CreateUserMock(s)
+
+	rr := s.serveRequest("POST", "/todos", `{"title": "Write guide"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	todo := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &todo)
+
+	uid := todo.Data.UUID.String()
+
+	rr = s.serveRequest("PATCH", "/todos/"+uid, `{"description": "First draft", "due_at": "2030-01-07T09:00:00Z"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	// Revision 1 set both, reverting it leaves them empty again
+	rr = s.serveRequest("POST", "/todos/"+uid+"/revert/1", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	reverted := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &reverted)
+
+	Expect(reverted.Data.Title).To(Equal("Write guide"))
+	Expect(reverted.Data.Description).To(BeEmpty())
+	Expect(reverted.Data.DueAt).To(BeNil())
+}
+
+// racingTodoService changes the todo right after the handler read it
+type racingTodoService struct {
+	port.TodoService
+	race func()
+}
+
+func (r racingTodoService) GetByUUID(ctx context.Context, userId int, uid string) (domain.Todo, error) {
+	todo, err := r.TodoService.GetByUUID(ctx, userId, uid)
+	r.race()
+
+	return todo, err
+}
+
+func (s *TodoHandlerSuite) TestJSONPatchWithoutIfMatchFailsWhenTodoChanges() {
+	owner := CreateUserMock(s)
+
+	rr := s.serveRequest("POST", "/todos", `{"title": "Write guide"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	todo := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &todo)
+
+	uid := todo.Data.UUID.String()
+	svc := globalTodoHandler.svc
+
+	globalTodoHandler.svc = racingTodoService{TodoService: svc, race: func() {
+		title := "Renamed meanwhile"
+		svc.Patch(ctx, owner.ID, uid, domain.TodoPatch{Title: domain.Some(title)})
+	}}
+
+	req, _ := http.NewRequest("PATCH", "/todos/"+uid, strings.NewReader(`[{"op": "test", "path": "/title", "value": "Write guide"}, {"op": "replace", "path": "/title", "value": "Write the guide"}]`))
+	req.Header.Set("Content-Type", "application/json-patch+json")
+
+	jwtToken, _ := helper.CreateJwtTokenForUser(owner.ID)
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+	rr = httptest.NewRecorder()
+	s.Router.ServeHTTP(rr, req)
+
+	Expect(rr.Code).To(Equal(http.StatusConflict), rr.Body.String())
+	Expect(rr.Body.String()).To(ContainSubstring(`"field":"patch"`))
+
+	globalTodoHandler.svc = svc
+
+	current, err := svc.GetByUUID(ctx, owner.ID, uid)
+	Expect(err).To(BeNil())
+	Expect(current.Title).To(Equal("Renamed meanwhile"))
+}
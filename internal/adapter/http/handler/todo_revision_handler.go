@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	. "todos/internal/adapter/http/helper"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetHistory lists the revisions of a todo, newest first
+func (t *TodoHandler) GetHistory(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	if limit <= 0 {
+		limit = 20
+	}
+
+	data, err := t.svc.History(c.Request.Context(), userId, c.Param("uuid"), limit, c.Query("cursor"))
+
+	if err != nil {
+		sendRevisionError(c, err, "Error listing todo history")
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// RevertTodo restores the todo as it was before the given revision
+func (t *TodoHandler) RevertTodo(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	number, err := strconv.Atoi(c.Param("revision"))
+
+	if err != nil || number < 1 {
+		SendNotFoundError(c, "Revision not found")
+		return
+	}
+
+	todo, err := t.svc.Revert(c.Request.Context(), userId, c.Param("uuid"), number)
+
+	if err != nil {
+		sendRevisionError(c, err, "Error reverting todo")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewTodoResponse(todo))
+}
+
+func sendRevisionError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden):
+		sendTodoAccessError(c, err)
+	case errors.Is(err, domain.ErrRevisionNotFound):
+		SendNotFoundError(c, "Revision not found")
+	case errors.Is(err, domain.ErrInvalidCursor):
+		SendBadRequestError(c, "cursor", err.Error())
+	// The status or project kept in the revision may no longer be reachable
+	case errors.Is(err, domain.ErrUnknownStatus) || errors.Is(err, domain.ErrIllegalTransition) || errors.Is(err, domain.ErrReviewsPending) || errors.Is(err, domain.ErrTodoBlocked):
+		SendConflictError(c, "status", err.Error())
+	case errors.Is(err, domain.ErrProjectNotFound) || errors.Is(err, domain.ErrProjectArchived):
+		sendTodoProjectError(c, err)
+	case errors.Is(err, domain.ErrRecurrenceNeedsDue):
+		SendConflictError(c, "rrule", err.Error())
+	default:
+		slog.Error(message, "error", err, "uuid", c.Param("uuid"))
+		SendInternalError(c, message)
+	}
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	. "github.com/onsi/gomega"
+
+	"todos/internal/core/model/response"
+)
+
+func (s *TodoHandlerSuite) TestTodoHistoryAndRevert() {
+	owner := CreateUserMock(s)
+	other := CreateOtherUserMock(s)
+
+	rr := s.serveRequest("POST", "/todos", `{"title": "Write guide", "tags": ["docs"]}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	created := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &created)
+
+	todoPath := "/todo/" + created.Data.UUID.String()
+	historyPath := "/todos/" + created.Data.UUID.String() + "/history"
+	revertPath := "/todos/" + created.Data.UUID.String() + "/revert/"
+
+	history := func() []response.TodoRevisionResponse {
+		rr := s.serveRequest("GET", historyPath, "", owner.ID)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+
+		data := response.CursorResponse{}
+		body, _ := io.ReadAll(rr.Body)
+		json.Unmarshal(body, &data)
+
+		var revisions []response.TodoRevisionResponse
+		json.Unmarshal(data.Data, &revisions)
+
+		return revisions
+	}
+
+	Expect(history()).To(BeEmpty())
+
+	rr = s.serveRequest("PUT", todoPath, `{"title": "Write the guide"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("PUT", todoPath, `{"title": "Write the guide", "tags": ["docs", "urgent"]}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	// Resending the same values records nothing
+	rr = s.serveRequest("PUT", todoPath, `{"title": "Write the guide"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	revisions := history()
+	Expect(revisions).To(HaveLen(2))
+	Expect(revisions[0].Number).To(Equal(2))
+	Expect(revisions[0].Changes).To(HaveKey("tags"))
+	Expect(revisions[0].Author.UUID).To(Equal(owner.UUID))
+	Expect(revisions[1].Number).To(Equal(1))
+	Expect(revisions[1].Changes["title"].From).To(Equal("Write guide"))
+	Expect(revisions[1].Changes["title"].To).To(Equal("Write the guide"))
+	Expect(revisions[1].Snapshot.Title).To(Equal("Write guide"))
+
+	// Others cannot see or revert the todo
+	rr = s.serveRequest("GET", historyPath, "", other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("POST", revertPath+"1", "", other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("POST", revertPath+"9", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("POST", revertPath+"first", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	// Reverting to revision 1 undoes it and every later change
+	rr = s.serveRequest("POST", revertPath+"1", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	reverted := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ = io.ReadAll(rr.Body)
+	json.Unmarshal(body, &reverted)
+
+	Expect(reverted.Data.Title).To(Equal("Write guide"))
+	Expect(reverted.Data.Tags).To(Equal([]string{"docs"}))
+
+	revisions = history()
+	Expect(revisions).To(HaveLen(3))
+	Expect(revisions[0].Number).To(Equal(3))
+	Expect(revisions[0].Changes).To(HaveKey("title"))
+	Expect(revisions[0].Changes).To(HaveKey("tags"))
+}
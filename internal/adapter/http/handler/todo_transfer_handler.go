@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	. "todos/internal/adapter/http/helper"
+	"todos/internal/adapter/transfer"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+	"todos/internal/core/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize caps the file of an import
+const maxImportSize = 5 << 20
+
+type TodoTransferHandler struct {
+	svc port.TodoTransferService
+}
+
+func NewTodoTransferHandler(transferUseCase port.TodoTransferService) *TodoTransferHandler {
+	return &TodoTransferHandler{
+		svc: transferUseCase,
+	}
+}
+
+// ExportTodos streams every todo of the user, archived ones included, in
+// the format of ?format=, CSV by default
+func (h *TodoTransferHandler) ExportTodos(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	format := transfer.FormatCSV
+
+	if value := c.Query("format"); value != "" {
+		parsed, err := transfer.ParseFormat(value)
+
+		if err != nil {
+			SendBadRequestError(c, "format", err.Error())
+			return
+		}
+
+		format = parsed
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", format.Filename()))
+
+	encoder := transfer.NewEncoder(format, c.Writer)
+
+	err := h.svc.Export(c.Request.Context(), userId, func(todo domain.Todo) error {
+		return encoder.Encode(todo)
+	})
+
+	if err == nil {
+		err = encoder.Close()
+	}
+
+	if err != nil {
+		slog.Error("Error exporting todos", "error", err, "user_id", userId, "format", format)
+
+		// Once the export started streaming the status can no longer change,
+		// the client sees a truncated file
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			SendInternalError(c, "Error exporting todos")
+		}
+
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// ImportTodos creates todos from a file sent as the request body or as the
+// "file" field of a form. The format is taken from ?format=, then from the
+// file name or the content type. With ?dry_run=true nothing is created and
+// the answer tells what would be, with ?skip_invalid=true the valid todos
+// are created even when others fail.
+func (h *TodoTransferHandler) ImportTodos(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	var options domain.TodoImportOptions
+
+	flags := []struct {
+		field string
+		flag  *bool
+	}{
+		{"dry_run", &options.DryRun},
+		{"skip_invalid", &options.SkipInvalid},
+	}
+
+	for _, param := range flags {
+		if value := c.Query(param.field); value != "" {
+			parsed, err := strconv.ParseBool(value)
+
+			if err != nil {
+				SendBadRequestError(c, param.field, fmt.Sprintf("invalid %s: %s", param.field, value))
+				return
+			}
+
+			*param.flag = parsed
+		}
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize+multipartOverhead)
+
+	file, format, err := importFile(c)
+
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+
+		if errors.As(err, &tooLarge) {
+			SendBadRequestError(c, "file", fmt.Sprintf("file must be at most %d MB", maxImportSize>>20))
+			return
+		}
+
+		SendBadRequestError(c, "format", err.Error())
+		return
+	}
+
+	defer file.Close()
+
+	todos, err := transfer.Decode(format, file)
+
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+
+		if errors.As(err, &tooLarge) {
+			err = fmt.Errorf("file must be at most %d MB", maxImportSize>>20)
+		}
+
+		SendBadRequestError(c, "file", err.Error())
+		return
+	}
+
+	result, err := h.svc.Import(c.Request.Context(), userId, todos, options)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrImportEmpty) || errors.Is(err, domain.ErrImportTooLarge):
+			SendBadRequestError(c, "file", err.Error())
+		default:
+			slog.Error("Error importing todos", "error", err, "user_id", userId, "format", format)
+			SendInternalError(c, "Error importing todos")
+		}
+
+		return
+	}
+
+	data := response.TodoImportResponse{
+		DryRun:   result.DryRun,
+		Imported: result.Imported,
+		Total:    len(result.Rows),
+		Failed:   result.Failed(),
+		Rows:     make([]response.TodoImportRowResponse, 0, len(result.Rows)),
+	}
+
+	data.Valid = data.Total - data.Failed
+
+	for _, row := range result.Rows {
+		item := response.TodoImportRowResponse{Line: row.Line}
+
+		if row.Err != nil {
+			field, message := todoImportError(row.Err)
+			item.Error = &response.ValidationError{Field: field, Message: message}
+		} else {
+			uid := row.Todo.UUID
+			item.UUID = &uid
+			item.Title = row.Todo.Title
+			item.Status = row.Todo.StatusOrFallback()
+		}
+
+		data.Rows = append(data.Rows, item)
+	}
+
+	status := http.StatusOK
+
+	switch {
+	case result.Imported:
+		status = http.StatusCreated
+	case !result.DryRun:
+		status = http.StatusUnprocessableEntity
+	}
+
+	SendSuccess(c, status, data)
+}
+
+// importFile finds the file of an import and its format
+func importFile(c *gin.Context) (io.ReadCloser, transfer.Format, error) {
+	var format transfer.Format
+	var err error
+
+	if value := c.Query("format"); value != "" {
+		if format, err = transfer.ParseFormat(value); err != nil {
+			return nil, "", err
+		}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+
+	if mediaType != "multipart/form-data" {
+		if format == "" {
+			if format, err = transfer.FormatOf(c.ContentType()); err != nil {
+				return nil, "", errors.New("format is required when the content type does not tell it")
+			}
+		}
+
+		return c.Request.Body, format, nil
+	}
+
+	file, header, err := c.Request.FormFile("file")
+
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+
+		if errors.As(err, &tooLarge) {
+			return nil, "", err
+		}
+
+		return nil, "", errors.New("file is required")
+	}
+
+	if format == "" {
+		if format, err = transfer.FormatOfFilename(header.Filename); err != nil {
+			format, err = transfer.FormatOf(header.Header.Get("Content-Type"))
+		}
+
+		if err != nil {
+			file.Close()
+			return nil, "", errors.New("format is required when the file name does not tell it")
+		}
+	}
+
+	return file, format, nil
+}
+
+// todoImportError maps why a todo cannot be imported to the field at fault
+// and a message
+func todoImportError(err error) (string, string) {
+	switch {
+	case errors.Is(err, domain.ErrImportTitle):
+		return "title", err.Error()
+	case errors.Is(err, domain.ErrImportDescription):
+		return "description", err.Error()
+	case errors.Is(err, domain.ErrInvalidTagName):
+		return "tags", err.Error()
+	case errors.Is(err, domain.ErrInvalidRecurrence) || errors.Is(err, domain.ErrRecurrenceNeedsDue):
+		return "rrule", err.Error()
+	case errors.Is(err, domain.ErrUnknownStatus):
+		return "status", err.Error()
+	}
+
+	// Errors reading a todo name the field they are about
+	if field, message, ok := strings.Cut(err.Error(), ": "); ok && !strings.Contains(field, " ") {
+		return field, message
+	}
+
+	return "todo", err.Error()
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/gomega"
+
+	"todos/internal/core/model/response"
+)
+
+func (s *TodoHandlerSuite) TestImportAndExportTodos() {
+	owner := CreateUserMock(s)
+	other := CreateOtherUserMock(s)
+
+	CreateTodo(s, other.ID)
+
+	// A todo someone else created in a shared project is theirs to export
+	rr := s.serveRequest("POST", "/projects", `{"name": "Shared"}`, other.ID)
+
+	project := struct {
+		Data response.ProjectResponse `json:"data"`
+	}{}
+	raw, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(raw, &project)
+
+	rr = s.serveRequest("POST", "/projects/"+project.Data.UUID.String()+"/members", `{"email": "user99@example.com", "role": "editor"}`, other.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	rr = s.serveRequest("POST", "/todos", `{"title": "Shared todo", "project_uuid": "`+project.Data.UUID.String()+`"}`, other.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	importTodos := func(query string, body string) (int, response.TodoImportResponse) {
+		rr := s.serveRequest("POST", "/todos/import"+query, body, owner.ID)
+
+		data := struct {
+			Data response.TodoImportResponse `json:"data"`
+		}{}
+		raw, _ := io.ReadAll(rr.Body)
+		json.Unmarshal(raw, &data)
+
+		return rr.Code, data.Data
+	}
+
+	file := strings.Join([]string{
+		"Title,Status,Due,Tags",
+		"Write the report,in progress,2030-05-01,work report",
+		"No,todo,,",
+		"Ship the release,done,2030-05-02T10:00:00Z,",
+		"Plan the offsite,someday,,",
+	}, "\n")
+
+	// A dry run reports every row and creates nothing
+	code, result := importTodos("?format=csv&dry_run=true", file)
+
+	Expect(code).To(Equal(http.StatusOK))
+	Expect(result.DryRun).To(BeTrue())
+	Expect(result.Imported).To(BeFalse())
+	Expect(result.Total).To(Equal(4))
+	Expect(result.Valid).To(Equal(2))
+	Expect(result.Failed).To(Equal(2))
+	Expect(result.Rows[0].Line).To(Equal(2))
+	Expect(result.Rows[0].Status).To(Equal("in_progress"))
+	Expect(result.Rows[1].Error.Field).To(Equal("title"))
+	Expect(result.Rows[2].Status).To(Equal("completed"))
+	Expect(result.Rows[3].Error.Field).To(Equal("status"))
+
+	export := func(format string) *http.Response {
+		return s.serveRequest("GET", "/todos/export?format="+format, "", owner.ID).Result()
+	}
+
+	empty := export("json")
+	raw, _ = io.ReadAll(empty.Body)
+
+	Expect(empty.StatusCode).To(Equal(http.StatusOK))
+	Expect(strings.TrimSpace(string(raw))).To(Equal("[]"))
+
+	// Invalid rows keep the whole file out
+	code, result = importTodos("?format=csv", file)
+
+	Expect(code).To(Equal(http.StatusUnprocessableEntity))
+	Expect(result.Imported).To(BeFalse())
+
+	// unless they are skipped
+	code, result = importTodos("?format=csv&skip_invalid=true", file)
+
+	Expect(code).To(Equal(http.StatusCreated))
+	Expect(result.Imported).To(BeTrue())
+	Expect(result.Valid).To(Equal(2))
+
+	created := s.serveRequest("GET", "/todos/"+result.Rows[0].UUID.String(), "", owner.ID)
+
+	Expect(created.Code).To(Equal(http.StatusOK))
+	Expect(created.Body.String()).To(ContainSubstring(`"tags":["report","work"]`))
+
+	// The export streams the user's todos only
+	exported := export("csv")
+
+	Expect(exported.StatusCode).To(Equal(http.StatusOK))
+	Expect(exported.Header.Get("Content-Type")).To(Equal("text/csv; charset=utf-8"))
+	Expect(exported.Header.Get("Content-Disposition")).To(Equal(`attachment; filename="todos.csv"`))
+
+	records, err := csv.NewReader(exported.Body).ReadAll()
+
+	Expect(err).ToNot(HaveOccurred())
+	Expect(records).To(HaveLen(3))
+	Expect(records[0][1]).To(Equal("title"))
+	Expect([]string{records[1][1], records[2][1]}).To(ConsistOf("Write the report", "Ship the release"))
+
+	// What one format exports another import reads back
+	ical := export("ical")
+	raw, _ = io.ReadAll(ical.Body)
+
+	code, result = importTodos("?format=ics&dry_run=true", string(raw))
+
+	Expect(code).To(Equal(http.StatusOK))
+	Expect(result.Total).To(Equal(2))
+	Expect(result.Failed).To(Equal(0))
+}
+
+func (s *TodoHandlerSuite) TestImportTodosRejectsBadRequests() {
+	user := CreateUserMock(s)
+
+	Expect(s.serveRequest("GET", "/todos/export?format=xml", "", user.ID).Code).To(Equal(http.StatusBadRequest))
+
+	// Without ?format= the content type must tell the format
+	Expect(s.serveRequest("POST", "/todos/import", "Title\nSomething", user.ID).Code).To(Equal(http.StatusBadRequest))
+	Expect(s.serveRequest("POST", "/todos/import?format=csv", "", user.ID).Code).To(Equal(http.StatusBadRequest))
+	Expect(s.serveRequest("POST", "/todos/import?format=csv", "status\npending", user.ID).Code).To(Equal(http.StatusBadRequest))
+	Expect(s.serveRequest("POST", "/todos/import?format=json", `{"title": "Not a list"}`, user.ID).Code).To(Equal(http.StatusBadRequest))
+	Expect(s.serveRequest("POST", "/todos/import?format=csv&dry_run=maybe", "title\nSomething", user.ID).Code).To(Equal(http.StatusBadRequest))
+}
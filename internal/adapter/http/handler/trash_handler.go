@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	. "todos/internal/adapter/http/helper"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+	"todos/internal/core/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	svc port.TrashService
+}
+
+func NewTrashHandler(trashUseCase port.TrashService) *TrashHandler {
+	return &TrashHandler{
+		svc: trashUseCase,
+	}
+}
+
+// GetTrash lists the deleted todos the caller may restore, newest first
+func (h *TrashHandler) GetTrash(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	if limit <= 0 {
+		limit = 20
+	}
+
+	data, err := h.svc.List(c.Request.Context(), userId, limit, c.Query("cursor"))
+
+	if err != nil {
+		sendTrashError(c, err, "Error listing trash")
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *TrashHandler) RestoreTodo(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	todo, err := h.svc.Restore(c.Request.Context(), userId, c.Param("uuid"))
+
+	if err != nil {
+		sendTrashError(c, err, "Error restoring todo")
+		return
+	}
+
+	SendSuccess(c, http.StatusOK, response.NewTodoResponse(todo))
+}
+
+// PurgeTodo deletes a todo in the trash for good, it cannot be restored after
+func (h *TrashHandler) PurgeTodo(c *gin.Context) {
+	userId := c.GetInt("x-user-id")
+
+	if err := h.svc.Delete(c.Request.Context(), userId, c.Param("uuid")); err != nil {
+		sendTrashError(c, err, "Error deleting todo")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Todo deleted permanently",
+	})
+}
+
+func sendTrashError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden):
+		sendTodoAccessError(c, err)
+	case errors.Is(err, domain.ErrInvalidCursor):
+		SendBadRequestError(c, "cursor", err.Error())
+	default:
+		slog.Error(message, "error", err, "uuid", c.Param("uuid"))
+		SendInternalError(c, message)
+	}
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	. "github.com/onsi/gomega"
+
+	"todos/internal/core/model/response"
+)
+
+func (s *TodoHandlerSuite) TestTrashRestoreAndPurge() {
+	owner := CreateUserMock(s)
+	other := CreateOtherUserMock(s)
+
+	todo := CreateTodo(s, owner.ID)
+	uid := todo.UUID.String()
+
+	rr := s.serveRequest("POST", "/todos/"+uid+"/items", `{"title": "First step"}`, owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusCreated))
+
+	listTrash := func(userId int) []response.TrashedTodoResponse {
+		rr := s.serveRequest("GET", "/trash", "", userId)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+
+		data := response.CursorResponse{}
+		body, _ := io.ReadAll(rr.Body)
+		json.Unmarshal(body, &data)
+
+		var todos []response.TrashedTodoResponse
+		json.Unmarshal(data.Data, &todos)
+
+		return todos
+	}
+
+	Expect(listTrash(owner.ID)).To(BeEmpty())
+
+	// Only deleted todos can be restored or purged
+	rr = s.serveRequest("POST", "/trash/"+uid+"/restore", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("DELETE", "/trash/"+uid, "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("DELETE", "/todos/"+uid, "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	trashed := listTrash(owner.ID)
+	Expect(trashed).To(HaveLen(1))
+	Expect(trashed[0].UUID).To(Equal(todo.UUID))
+	Expect(trashed[0].DeletedAt).NotTo(BeNil())
+	Expect(trashed[0].PurgeAt.After(*trashed[0].DeletedAt)).To(BeTrue())
+
+	Expect(listTrash(other.ID)).To(BeEmpty())
+
+	rr = s.serveRequest("POST", "/trash/"+uid+"/restore", "", other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	// Restoring brings the checklist back with the todo
+	rr = s.serveRequest("POST", "/trash/"+uid+"/restore", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	restored := struct {
+		Data response.TodoResponse `json:"data"`
+	}{}
+	body, _ := io.ReadAll(rr.Body)
+	json.Unmarshal(body, &restored)
+
+	Expect(restored.Data.ItemsTotal).To(Equal(1))
+	Expect(listTrash(owner.ID)).To(BeEmpty())
+
+	rr = s.serveRequest("GET", "/todos/"+uid, "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	// Purged todos are gone for good
+	rr = s.serveRequest("DELETE", "/todos/"+uid, "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	rr = s.serveRequest("DELETE", "/trash/"+uid, "", other.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+
+	rr = s.serveRequest("DELETE", "/trash/"+uid, "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusOK))
+
+	Expect(listTrash(owner.ID)).To(BeEmpty())
+
+	rr = s.serveRequest("POST", "/trash/"+uid+"/restore", "", owner.ID)
+	Expect(rr.Code).To(Equal(http.StatusNotFound))
+}
@@ -63,6 +63,17 @@ func SendUnauthorizedError(c *gin.Context, message string) {
 	SendError(c, http.StatusUnauthorized, "UNAUTHORIZED", errors)
 }
 
+func SendForbiddenError(c *gin.Context, message string) {
+	errors := []response.ValidationError{
+		{
+			Field:   "auth",
+			Message: message,
+		},
+	}
+
+	SendError(c, http.StatusForbidden, "FORBIDDEN", errors)
+}
+
 func SendBadRequestError(c *gin.Context, field string, message string) {
 	errors := []response.ValidationError{
 		{
@@ -84,3 +95,27 @@ func SendNotFoundError(c *gin.Context, message string) {
 
 	SendError(c, http.StatusNotFound, "NOT_FOUND", errors)
 }
+
+func SendConflictError(c *gin.Context, field string, message string) {
+	errors := []response.ValidationError{
+		{
+			Field:   field,
+			Message: message,
+		},
+	}
+
+	SendError(c, http.StatusConflict, "CONFLICT", errors)
+}
+
+// SendPreconditionFailedError refuses a conditional request, details carry
+// the current representation when there is one
+func SendPreconditionFailedError(c *gin.Context, field string, message string, details ...any) {
+	errors := []response.ValidationError{
+		{
+			Field:   field,
+			Message: message,
+		},
+	}
+
+	SendError(c, http.StatusPreconditionFailed, "PRECONDITION_FAILED", errors, details...)
+}
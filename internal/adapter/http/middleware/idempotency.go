@@ -0,0 +1,255 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"todos/internal/adapter/http/helper"
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+	"todos/internal/core/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// DefaultIdempotencyTTL is how long a response can be replayed
+	DefaultIdempotencyTTL = 24 * time.Hour
+
+	maxIdempotencyKeyLength = 255
+
+	// maxIdempotentBodySize bounds the body kept in memory to fingerprint a
+	// request, room for the largest upload with its multipart framing
+	maxIdempotentBodySize = domain.MaxAttachmentSize + 1<<20
+)
+
+// idempotencyLockTTL bounds how long a request that never finished, say
+// because the instance died, keeps its key locked. The lock is renewed while
+// the request runs, so slow imports and uploads keep it however long they take.
+var idempotencyLockTTL = time.Minute
+
+// replayedHeaders are the response headers a replay sends again
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// idempotencyRecord is what the cache holds for a key. A zero status marks a
+// request still in flight.
+type idempotencyRecord struct {
+	Fingerprint string            `json:"fingerprint"`
+	Status      int               `json:"status"`
+	Header      map[string]string `json:"header,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+}
+
+// idempotencyWriter keeps a copy of the response on its way to the client
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+// IdempotencyMiddleware makes POST and PATCH requests carrying an
+// Idempotency-Key safe to retry. The first response for a key is kept per
+// user for ttl and sent again to retries of the same request. A retry while
+// the first is still running gets 409, the key reused for another request
+// gets 422. Server errors are not kept so the request can be retried.
+// It runs after GinJwtMiddleware, which sets the user.
+func IdempotencyMiddleware(cache port.CacheRepository, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+
+		if key == "" || (c.Request.Method != http.MethodPost && c.Request.Method != http.MethodPatch) {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			helper.SendBadRequestError(c, IdempotencyKeyHeader, fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodySize))
+
+		var tooLarge *http.MaxBytesError
+
+		if errors.As(err, &tooLarge) {
+			helper.SendError(c, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", []response.ValidationError{{
+				Field:   "request",
+				Message: fmt.Sprintf("Request body must be at most %d MB", maxIdempotentBodySize>>20),
+			}})
+			c.Abort()
+			return
+		}
+
+		if err != nil {
+			helper.SendBadRequestError(c, "request", "Invalid request body")
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		cacheKey := fmt.Sprintf("idempotency:%d:%s", c.GetInt("x-user-id"), key)
+		fingerprint := requestFingerprint(c.Request, body)
+
+		lock, _ := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+
+		// Losing the cache should not take writes down with it, the request
+		// then goes through without the guarantee
+		reserved, err := cache.SetNX(ctx, cacheKey, lock, idempotencyLockTTL)
+
+		if err != nil {
+			slog.Error("Error reserving idempotency key", "error", err, "key", key)
+			c.Next()
+			return
+		}
+
+		if !reserved {
+			replayIdempotentResponse(c, cache, cacheKey, fingerprint)
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		release := holdIdempotencyLock(cache, cacheKey, lock)
+		c.Next()
+		release()
+
+		status := writer.Status()
+
+		if status >= http.StatusInternalServerError {
+			if err := cache.Delete(ctx, cacheKey); err != nil {
+				slog.Error("Error releasing idempotency key", "error", err, "key", key)
+			}
+
+			return
+		}
+
+		record := idempotencyRecord{
+			Fingerprint: fingerprint,
+			Status:      status,
+			Header:      map[string]string{},
+			Body:        writer.body.Bytes(),
+		}
+
+		for _, name := range replayedHeaders {
+			if value := writer.Header().Get(name); value != "" {
+				record.Header[name] = value
+			}
+		}
+
+		data, _ := json.Marshal(record)
+
+		if err := cache.Set(ctx, cacheKey, data, ttl); err != nil {
+			slog.Error("Error storing idempotent response", "error", err, "key", key)
+		}
+	}
+}
+
+// holdIdempotencyLock renews the lock on a key every half of its ttl until
+// the returned func is called, which waits for a renewal under way so none
+// lands on the response stored after it
+func holdIdempotencyLock(cache port.CacheRepository, cacheKey string, lock []byte) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(idempotencyLockTTL / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				// Not the request context, the handler may outlive the client
+				if err := cache.Set(context.Background(), cacheKey, lock, idempotencyLockTTL); err != nil {
+					slog.Error("Error renewing idempotency key", "error", err, "key", cacheKey)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// replayIdempotentResponse answers a request whose key is already taken
+func replayIdempotentResponse(c *gin.Context, cache port.CacheRepository, cacheKey string, fingerprint string) {
+	defer c.Abort()
+
+	var record idempotencyRecord
+
+	data, err := cache.Get(c.Request.Context(), cacheKey)
+
+	if err == nil && data != nil {
+		err = json.Unmarshal(data, &record)
+	}
+
+	if err != nil {
+		slog.Error("Error reading idempotency key", "error", err, "key", cacheKey)
+	}
+
+	// Nothing readable, most likely the key expired since SetNX saw it
+	if err != nil || data == nil {
+		helper.SendConflictError(c, IdempotencyKeyHeader, "The request could not be matched to its key, retry it")
+		return
+	}
+
+	if record.Fingerprint != fingerprint {
+		helper.SendError(c, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", []response.ValidationError{{
+			Field:   IdempotencyKeyHeader,
+			Message: "The key was already used for a different request",
+		}})
+		return
+	}
+
+	if record.Status == 0 {
+		helper.SendConflictError(c, IdempotencyKeyHeader, "A request with this key is still in progress")
+		return
+	}
+
+	for name, value := range record.Header {
+		c.Header(name, value)
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	c.Status(record.Status)
+	c.Writer.Write(record.Body)
+}
+
+// requestFingerprint tells apart requests reusing a key, by what they ask for
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+
+	fmt.Fprintf(hash, "%s %s?%s\n", r.Method, r.URL.Path, r.URL.RawQuery)
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
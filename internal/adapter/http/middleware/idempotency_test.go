@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"todos/internal/adapter/database/memory"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/gomega"
+)
+
+func setupIdempotencyRouter(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		userId, _ := strconv.Atoi(c.GetHeader("X-Test-User"))
+		c.Set("x-user-id", userId)
+	})
+
+	router.Use(IdempotencyMiddleware(memory.NewMemoryRepository(), time.Hour))
+	router.POST("/todos", handler)
+	router.PUT("/todos", handler)
+
+	return router
+}
+
+func serveIdempotent(router *gin.Engine, method string, body string, key string, userId int) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, "/todos", strings.NewReader(body))
+	req.Header.Set("X-Test-User", strconv.Itoa(userId))
+
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestIdempotencyMiddleware_ReplaysResponse(t *testing.T) {
+	RegisterTestingT(t)
+
+	var created atomic.Int32
+
+	router := setupIdempotencyRouter(func(c *gin.Context) {
+		n := created.Add(1)
+		c.Header("Location", "/todos/"+strconv.Itoa(int(n)))
+		c.JSON(http.StatusCreated, gin.H{"id": n})
+	})
+
+	first := serveIdempotent(router, "POST", `{"title": "Buy milk"}`, "key-1", 1)
+	Expect(first.Code).To(Equal(http.StatusCreated))
+
+	retry := serveIdempotent(router, "POST", `{"title": "Buy milk"}`, "key-1", 1)
+	Expect(retry.Code).To(Equal(http.StatusCreated))
+	Expect(retry.Body.String()).To(Equal(first.Body.String()))
+	Expect(retry.Header().Get("Location")).To(Equal("/todos/1"))
+	Expect(retry.Header().Get("Content-Type")).To(ContainSubstring("application/json"))
+	Expect(retry.Header().Get("Idempotent-Replayed")).To(Equal("true"))
+	Expect(created.Load()).To(Equal(int32(1)))
+
+	// Keys belong to a user, and requests without one are never replayed
+	Expect(serveIdempotent(router, "POST", `{"title": "Buy milk"}`, "key-1", 2).Body.String()).To(Equal(`{"id":2}`))
+	Expect(serveIdempotent(router, "POST", `{"title": "Buy milk"}`, "", 1).Body.String()).To(Equal(`{"id":3}`))
+
+	// Idempotent methods are left alone
+	Expect(serveIdempotent(router, "PUT", `{}`, "key-1", 1).Body.String()).To(Equal(`{"id":4}`))
+}
+
+func TestIdempotencyMiddleware_RejectsKeyReusedForAnotherRequest(t *testing.T) {
+	RegisterTestingT(t)
+
+	router := setupIdempotencyRouter(func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"status": "ok"})
+	})
+
+	Expect(serveIdempotent(router, "POST", `{"title": "Buy milk"}`, "key-1", 1).Code).To(Equal(http.StatusCreated))
+
+	w := serveIdempotent(router, "POST", `{"title": "Buy bread"}`, "key-1", 1)
+	Expect(w.Code).To(Equal(http.StatusUnprocessableEntity))
+	Expect(w.Body.String()).To(ContainSubstring(`"field":"Idempotency-Key"`))
+
+	w = serveIdempotent(router, "POST", `{}`, strings.Repeat("k", 256), 1)
+	Expect(w.Code).To(Equal(http.StatusBadRequest))
+}
+
+func TestIdempotencyMiddleware_RejectsOversizedBody(t *testing.T) {
+	RegisterTestingT(t)
+
+	var called atomic.Bool
+
+	router := setupIdempotencyRouter(func(c *gin.Context) {
+		called.Store(true)
+		c.JSON(http.StatusCreated, gin.H{"status": "ok"})
+	})
+
+	w := serveIdempotent(router, "POST", strings.Repeat("x", int(maxIdempotentBodySize)+1), "key-1", 1)
+
+	Expect(w.Code).To(Equal(http.StatusRequestEntityTooLarge))
+	Expect(called.Load()).To(BeFalse())
+
+	// The key was never taken, a request that fits still goes through
+	Expect(serveIdempotent(router, "POST", `{}`, "key-1", 1).Code).To(Equal(http.StatusCreated))
+}
+
+func TestIdempotencyMiddleware_RejectsConcurrentDuplicate(t *testing.T) {
+	RegisterTestingT(t)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	router := setupIdempotencyRouter(func(c *gin.Context) {
+		close(started)
+		<-release
+		c.JSON(http.StatusCreated, gin.H{"status": "ok"})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+
+	go func() {
+		done <- serveIdempotent(router, "POST", `{"title": "Buy milk"}`, "key-1", 1)
+	}()
+
+	<-started
+
+	w := serveIdempotent(router, "POST", `{"title": "Buy milk"}`, "key-1", 1)
+	Expect(w.Code).To(Equal(http.StatusConflict))
+
+	close(release)
+	Expect((<-done).Code).To(Equal(http.StatusCreated))
+
+	Expect(serveIdempotent(router, "POST", `{"title": "Buy milk"}`, "key-1", 1).Code).To(Equal(http.StatusCreated))
+}
+
+func TestIdempotencyMiddleware_HoldsKeyForSlowRequests(t *testing.T) {
+	RegisterTestingT(t)
+
+	lockTTL := idempotencyLockTTL
+	idempotencyLockTTL = 40 * time.Millisecond
+	defer func() { idempotencyLockTTL = lockTTL }()
+
+	var calls atomic.Int32
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	router := setupIdempotencyRouter(func(c *gin.Context) {
+		calls.Add(1)
+		close(started)
+		<-release
+		c.JSON(http.StatusCreated, gin.H{"status": "ok"})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+
+	go func() {
+		done <- serveIdempotent(router, "POST", `{"title": "Import"}`, "key-1", 1)
+	}()
+
+	<-started
+
+	// The request outlives the lock ttl several times over
+	time.Sleep(200 * time.Millisecond)
+
+	Expect(serveIdempotent(router, "POST", `{"title": "Import"}`, "key-1", 1).Code).To(Equal(http.StatusConflict))
+
+	close(release)
+	Expect((<-done).Code).To(Equal(http.StatusCreated))
+
+	// The stored response is not overwritten by a late renewal
+	time.Sleep(100 * time.Millisecond)
+
+	Expect(serveIdempotent(router, "POST", `{"title": "Import"}`, "key-1", 1).Header().Get("Idempotent-Replayed")).To(Equal("true"))
+	Expect(calls.Load()).To(Equal(int32(1)))
+}
+
+func TestIdempotencyMiddleware_ForgetsServerErrors(t *testing.T) {
+	RegisterTestingT(t)
+
+	var calls atomic.Int32
+
+	router := setupIdempotencyRouter(func(c *gin.Context) {
+		if calls.Add(1) == 1 {
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"status": "ok"})
+	})
+
+	Expect(serveIdempotent(router, "POST", `{}`, "key-1", 1).Code).To(Equal(http.StatusInternalServerError))
+	Expect(serveIdempotent(router, "POST", `{}`, "key-1", 1).Code).To(Equal(http.StatusCreated))
+	Expect(serveIdempotent(router, "POST", `{}`, "key-1", 1).Code).To(Equal(http.StatusCreated))
+	Expect(calls.Load()).To(Equal(int32(2)))
+}
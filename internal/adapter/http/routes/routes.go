@@ -1,8 +1,11 @@
 package routes
 
 import (
+	"time"
+
 	"todos/internal/adapter/http/handler"
 	"todos/internal/adapter/http/middleware"
+	"todos/internal/core/port"
 	"todos/internal/core/telemetry"
 	"todos/pkg/config"
 
@@ -10,8 +13,26 @@ import (
 )
 
 type HandlersConfig struct {
-	AuthHandler *handler.AuthHandler
-	TodoHandler *handler.TodoHandler
+	AuthHandler     *handler.AuthHandler
+	TodoHandler     *handler.TodoHandler
+	ReminderHandler *handler.ReminderHandler
+	TodoItemHandler *handler.TodoItemHandler
+	TagHandler      *handler.TagHandler
+	ProjectHandler  *handler.ProjectHandler
+	CommentHandler  *handler.CommentHandler
+	ReviewHandler   *handler.ReviewHandler
+	TrashHandler    *handler.TrashHandler
+	BatchHandler    *handler.TodoBatchHandler
+	TransferHandler *handler.TodoTransferHandler
+	TimeHandler     *handler.TimeEntryHandler
+
+	AttachmentHandler *handler.AttachmentHandler
+
+	// IdempotencyCache keeps responses to requests sent with an
+	// Idempotency-Key for IdempotencyTTL, 0 means the default. Without a
+	// cache the header is ignored.
+	IdempotencyCache port.CacheRepository
+	IdempotencyTTL   time.Duration
 }
 
 func SetupRouter(handlers HandlersConfig, metrics *telemetry.AppMetrics, logger *config.LokiLogger) *gin.Engine {
@@ -34,8 +55,12 @@ func SetupRouterWithConfig(handlers HandlersConfig, metrics *telemetry.AppMetric
 		setupPublicRoutes(router, handlers.AuthHandler)
 	}
 
+	if handlers.AttachmentHandler != nil {
+		setupSignedRoutes(router, handlers.AttachmentHandler)
+	}
+
 	if handlers.TodoHandler != nil {
-		setupProtectedRoutes(router, handlers.TodoHandler)
+		setupProtectedRoutes(router, handlers)
 	}
 
 	return router
@@ -49,23 +74,142 @@ func setupPublicRoutes(router *gin.Engine, authHandler *handler.AuthHandler) {
 	}
 }
 
-func setupProtectedRoutes(router *gin.Engine, todoHandler *handler.TodoHandler) {
+// setupSignedRoutes serves links that carry their own signature instead of a JWT
+func setupSignedRoutes(router *gin.Engine, attachmentHandler *handler.AttachmentHandler) {
+	router.GET("/attachments/:uuid/download", attachmentHandler.DownloadAttachment)
+}
+
+func setupProtectedRoutes(router *gin.Engine, handlers HandlersConfig) {
+	todoHandler := handlers.TodoHandler
+
 	protected := router.Group("/")
 	protected.Use(middleware.CurrentMiddleware())
 	protected.Use(middleware.GinJwtMiddleware())
+
+	if handlers.IdempotencyCache != nil {
+		ttl := handlers.IdempotencyTTL
+
+		if ttl == 0 {
+			ttl = middleware.DefaultIdempotencyTTL
+		}
+
+		protected.Use(middleware.IdempotencyMiddleware(handlers.IdempotencyCache, ttl))
+	}
+
 	{
 		protected.GET("/todos", todoHandler.GetAllTodos)
+		protected.GET("/todos/search", todoHandler.SearchTodos)
+		protected.GET("/todos/:uuid", todoHandler.GetTodo)
 		protected.POST("/todos", todoHandler.CreateTodo)
 		protected.PUT("/todo/:uuid", todoHandler.UpdateTodo)
+		protected.PATCH("/todos/:uuid", todoHandler.PatchTodo)
 		protected.DELETE("/todos/:uuid", todoHandler.DeleteByUUID)
+		protected.GET("/todos/:uuid/occurrences", todoHandler.GetOccurrences)
+		protected.POST("/todos/:uuid/occurrences/skip", todoHandler.SkipOccurrence)
+		protected.GET("/todos/:uuid/history", todoHandler.GetHistory)
+		protected.POST("/todos/:uuid/revert/:revision", todoHandler.RevertTodo)
+		protected.GET("/todos/:uuid/blockers", todoHandler.GetBlockers)
+		protected.POST("/todos/:uuid/blockers", todoHandler.AddBlocker)
+		protected.DELETE("/todos/:uuid/blockers/:blocker_uuid", todoHandler.RemoveBlocker)
+		protected.GET("/projects/:uuid/dependencies", todoHandler.GetDependencyGraph)
+	}
+
+	if reminderHandler := handlers.ReminderHandler; reminderHandler != nil {
+		protected.GET("/todos/:uuid/reminders", reminderHandler.GetReminders)
+		protected.POST("/todos/:uuid/reminders", reminderHandler.CreateReminder)
+		protected.DELETE("/todos/:uuid/reminders/:reminder_uuid", reminderHandler.DeleteReminder)
+	}
+
+	if itemHandler := handlers.TodoItemHandler; itemHandler != nil {
+		protected.GET("/todos/:uuid/items", itemHandler.GetItems)
+		protected.GET("/todos/:uuid/items/:item_uuid", itemHandler.GetItem)
+		protected.POST("/todos/:uuid/items", itemHandler.CreateItem)
+		protected.PUT("/todos/:uuid/items/:item_uuid", itemHandler.UpdateItem)
+		protected.DELETE("/todos/:uuid/items/:item_uuid", itemHandler.DeleteItem)
+	}
+
+	if commentHandler := handlers.CommentHandler; commentHandler != nil {
+		protected.GET("/todos/:uuid/comments", commentHandler.GetComments)
+		protected.POST("/todos/:uuid/comments", commentHandler.CreateComment)
+		protected.PUT("/todos/:uuid/comments/:comment_uuid", commentHandler.UpdateComment)
+		protected.DELETE("/todos/:uuid/comments/:comment_uuid", commentHandler.DeleteComment)
+		protected.GET("/todos/:uuid/comments/:comment_uuid/history", commentHandler.GetCommentHistory)
+	}
+
+	if reviewHandler := handlers.ReviewHandler; reviewHandler != nil {
+		protected.GET("/todos/:uuid/reviews", reviewHandler.GetReviews)
+		protected.POST("/todos/:uuid/reviews", reviewHandler.RequestReview)
+		protected.POST("/todos/:uuid/reviews/approve", reviewHandler.ApproveTodo)
+		protected.POST("/todos/:uuid/reviews/reject", reviewHandler.RejectTodo)
+		protected.GET("/reviews/waiting", reviewHandler.GetWaitingReviews)
+	}
+
+	if batchHandler := handlers.BatchHandler; batchHandler != nil {
+		protected.POST("/todos/batch", batchHandler.ApplyBatch)
+	}
+
+	if transferHandler := handlers.TransferHandler; transferHandler != nil {
+		protected.GET("/todos/export", transferHandler.ExportTodos)
+		protected.POST("/todos/import", transferHandler.ImportTodos)
+	}
+
+	if timeHandler := handlers.TimeHandler; timeHandler != nil {
+		protected.GET("/todos/:uuid/time-entries", timeHandler.GetTimeEntries)
+		protected.POST("/todos/:uuid/time-entries", timeHandler.CreateTimeEntry)
+		protected.POST("/todos/:uuid/timer/start", timeHandler.StartTimer)
+		protected.GET("/timer", timeHandler.GetTimer)
+		protected.POST("/timer/stop", timeHandler.StopTimer)
+		protected.PUT("/time-entries/:uuid", timeHandler.UpdateTimeEntry)
+		protected.DELETE("/time-entries/:uuid", timeHandler.DeleteTimeEntry)
+		protected.GET("/time-entries/report", timeHandler.GetTimeReport)
+	}
+
+	if trashHandler := handlers.TrashHandler; trashHandler != nil {
+		protected.GET("/trash", trashHandler.GetTrash)
+		protected.POST("/trash/:uuid/restore", trashHandler.RestoreTodo)
+		protected.DELETE("/trash/:uuid", trashHandler.PurgeTodo)
+	}
+
+	if attachmentHandler := handlers.AttachmentHandler; attachmentHandler != nil {
+		protected.GET("/todos/:uuid/attachments", attachmentHandler.GetAttachments)
+		protected.POST("/todos/:uuid/attachments", attachmentHandler.UploadAttachment)
+		protected.GET("/todos/:uuid/attachments/:attachment_uuid", attachmentHandler.GetAttachment)
+		protected.DELETE("/todos/:uuid/attachments/:attachment_uuid", attachmentHandler.DeleteAttachment)
+	}
+
+	if tagHandler := handlers.TagHandler; tagHandler != nil {
+		protected.GET("/tags", tagHandler.GetTags)
+		protected.POST("/tags", tagHandler.CreateTag)
+		protected.PUT("/tags/:uuid", tagHandler.RenameTag)
+		protected.POST("/tags/:uuid/merge", tagHandler.MergeTag)
+		protected.DELETE("/tags/:uuid", tagHandler.DeleteTag)
+	}
+
+	if projectHandler := handlers.ProjectHandler; projectHandler != nil {
+		protected.GET("/projects", projectHandler.GetProjects)
+		protected.POST("/projects", projectHandler.CreateProject)
+		protected.GET("/projects/:uuid", projectHandler.GetProject)
+		protected.PUT("/projects/:uuid", projectHandler.UpdateProject)
+		protected.DELETE("/projects/:uuid", projectHandler.DeleteProject)
+		protected.POST("/projects/:uuid/archive", projectHandler.ArchiveProject)
+		protected.POST("/projects/:uuid/unarchive", projectHandler.UnarchiveProject)
+		protected.PUT("/projects/:uuid/order", projectHandler.ReorderProject)
+		protected.GET("/projects/:uuid/members", projectHandler.GetMembers)
+		protected.POST("/projects/:uuid/members", projectHandler.InviteMember)
+		protected.PUT("/projects/:uuid/members/:user_uuid", projectHandler.UpdateMember)
+		protected.DELETE("/projects/:uuid/members/:user_uuid", projectHandler.RemoveMember)
+		protected.GET("/projects/:uuid/workflow", projectHandler.GetWorkflow)
+		protected.PUT("/projects/:uuid/workflow", projectHandler.UpdateWorkflow)
+		protected.DELETE("/projects/:uuid/workflow", projectHandler.ResetWorkflow)
 	}
 }
 
 func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, If-Match, Idempotency-Key")
+		c.Header("Access-Control-Expose-Headers", "ETag, Idempotent-Replayed")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -91,8 +235,12 @@ func SetupRouterForTests(handlers HandlersConfig) *gin.Engine {
 		setupPublicRoutes(router, handlers.AuthHandler)
 	}
 
+	if handlers.AttachmentHandler != nil {
+		setupSignedRoutes(router, handlers.AttachmentHandler)
+	}
+
 	if handlers.TodoHandler != nil {
-		setupProtectedRoutes(router, handlers.TodoHandler)
+		setupProtectedRoutes(router, handlers)
 	}
 
 	return router
@@ -0,0 +1,91 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "todos/pkg/test"
+
+	apphttp "todos/internal/adapter/http"
+	"todos/internal/adapter/http/helper"
+	"todos/internal/adapter/http/routes"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+)
+
+func setupRouter(t *testing.T) *gin.Engine {
+	t.Setenv("ATTACHMENTS_PATH", t.TempDir())
+	t.Setenv("ATTACHMENT_SIGNING_KEY", "secret")
+	gin.SetMode(gin.TestMode)
+
+	container := apphttp.NewContainer(InitTestDB(), nil)
+
+	return routes.SetupRouterForTests(routes.HandlersConfig{
+		AuthHandler:     container.AuthHandler,
+		TodoHandler:     container.TodoHandler,
+		ReminderHandler: container.ReminderHandler,
+		TodoItemHandler: container.TodoItemHandler,
+		TagHandler:      container.TagHandler,
+		ProjectHandler:  container.ProjectHandler,
+		CommentHandler:  container.CommentHandler,
+		ReviewHandler:   container.ReviewHandler,
+		TrashHandler:    container.TrashHandler,
+		BatchHandler:    container.BatchHandler,
+		TransferHandler: container.TransferHandler,
+		TimeHandler:     container.TimeHandler,
+
+		AttachmentHandler: container.AttachmentHandler,
+
+		IdempotencyCache: container.Cache,
+	})
+}
+
+func TestSetupRouterForTests_RegistersEveryRouteOnce(t *testing.T) {
+	RegisterTestingT(t)
+
+	router := setupRouter(t)
+
+	// gin panics on a duplicate route, so reaching here already means no
+	// route was registered twice
+	registered := make(map[string]int, len(router.Routes()))
+
+	for _, route := range router.Routes() {
+		registered[route.Method+" "+route.Path]++
+	}
+
+	Expect(registered).To(HaveKeyWithValue("GET /attachments/:uuid/download", 1))
+	Expect(registered).To(HaveKeyWithValue("GET /todos/:uuid/attachments", 1))
+}
+
+func TestSetupRouterForTests_RoutesReachHandlers(t *testing.T) {
+	RegisterTestingT(t)
+
+	router := setupRouter(t)
+	token, _ := helper.CreateJwtTokenForUser(1)
+
+	for _, route := range router.Routes() {
+		// Every path parameter points at a record that does not exist
+		segments := strings.Split(route.Path, "/")
+
+		for i, segment := range segments {
+			if strings.HasPrefix(segment, ":") {
+				segments[i] = uuid.NewString()
+			}
+		}
+
+		req, _ := http.NewRequest(route.Method, strings.Join(segments, "/"), strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		// gin answers unmatched paths with a plain text body
+		Expect(rr.Body.String()).ToNot(Equal("404 page not found"), route.Method+" "+route.Path)
+		Expect(rr.Code).To(BeNumerically("<", http.StatusInternalServerError), route.Method+" "+route.Path)
+	}
+}
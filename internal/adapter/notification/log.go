@@ -0,0 +1,34 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+)
+
+// LogDelivery writes reminders to the application log, useful in development
+// and whenever no outbound channel is configured
+type LogDelivery struct {
+	logger *slog.Logger
+}
+
+func NewLogDelivery(logger *slog.Logger) port.NotificationDelivery {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &LogDelivery{logger: logger}
+}
+
+func (ld *LogDelivery) Deliver(ctx context.Context, notification domain.Notification) error {
+	ld.logger.InfoContext(ctx, "Reminder",
+		"reminder_uuid", notification.ReminderUUID.String(),
+		"todo_uuid", notification.TodoUUID.String(),
+		"user_id", notification.UserId,
+		"title", notification.Title,
+		"remind_at", notification.RemindAt)
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	"todos/internal/core/util"
+)
+
+const (
+	SignatureHeader = "X-Todos-Signature"
+	TimestampHeader = "X-Todos-Timestamp"
+)
+
+// WebhookDelivery POSTs reminders as JSON. When a secret is set each request
+// carries an HMAC-SHA256 of "<timestamp>.<body>" so receivers can verify it.
+type WebhookDelivery struct {
+	url    string
+	secret []byte
+	client *http.Client
+	clock  port.Clock
+}
+
+func NewWebhookDelivery(url string, secret string, client *http.Client, clock port.Clock) port.NotificationDelivery {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if clock == nil {
+		clock = util.SystemClock{}
+	}
+
+	return &WebhookDelivery{
+		url:    url,
+		secret: []byte(secret),
+		client: client,
+		clock:  clock,
+	}
+}
+
+func (wd *WebhookDelivery) Deliver(ctx context.Context, notification domain.Notification) error {
+	body, err := json.Marshal(notification)
+
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wd.url, bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(wd.clock.Now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+
+	if len(wd.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(wd.secret, timestamp, body))
+	}
+
+	resp, err := wd.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 a webhook receiver should expect
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
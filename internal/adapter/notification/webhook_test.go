@@ -0,0 +1,70 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"todos/internal/core/domain"
+	. "todos/pkg/test"
+)
+
+func TestWebhookDelivery_SignsPayload(t *testing.T) {
+	RegisterTestingT(t)
+
+	clock := NewFakeClock(time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC))
+
+	var received domain.Notification
+	var timestamp, signature string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+
+		timestamp = r.Header.Get(TimestampHeader)
+		signature = r.Header.Get(SignatureHeader)
+
+		Expect(signature).To(Equal(Sign([]byte("secret"), timestamp, body)))
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	delivery := NewWebhookDelivery(server.URL, "secret", server.Client(), clock)
+
+	notification := domain.Notification{
+		ReminderUUID: uuid.New(),
+		TodoUUID:     uuid.New(),
+		Title:        "Pay rent",
+		RemindAt:     clock.Now(),
+	}
+
+	err := delivery.Deliver(context.Background(), notification)
+
+	Expect(err).To(BeNil())
+	Expect(received.TodoUUID).To(Equal(notification.TodoUUID))
+	Expect(timestamp).To(Equal("1741593600"))
+	Expect(signature).NotTo(BeEmpty())
+}
+
+func TestWebhookDelivery_FailsOnErrorStatus(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	delivery := NewWebhookDelivery(server.URL, "", server.Client(), nil)
+
+	err := delivery.Deliver(context.Background(), domain.Notification{})
+
+	Expect(err).To(MatchError(ContainSubstring("502")))
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+)
+
+// FilesystemStore keeps blobs as files below a root directory. Writes go to
+// a temporary file that is renamed into place, so readers never see a
+// partially written blob.
+type FilesystemStore struct {
+	root string
+}
+
+func NewFilesystemStore(root string) port.BlobStore {
+	return &FilesystemStore{root: filepath.Clean(root)}
+}
+
+func (s *FilesystemStore) Put(ctx context.Context, key string, body io.Reader) (int64, error) {
+	path, err := s.path(key)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, body)
+
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return written, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return written, err
+	}
+
+	return written, os.Rename(tmp.Name(), path)
+}
+
+func (s *FilesystemStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, domain.ErrBlobNotFound
+	}
+
+	return file, err
+}
+
+func (s *FilesystemStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// path maps a key below the root, refusing keys that would escape it
+func (s *FilesystemStore) path(key string) (string, error) {
+	if !fs.ValidPath(key) || key == "." {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
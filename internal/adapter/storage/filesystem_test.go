@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"todos/internal/core/domain"
+)
+
+func TestFilesystemStore_PutOpenDelete(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := context.Background()
+	root := t.TempDir()
+	store := NewFilesystemStore(root)
+
+	written, err := store.Put(ctx, "todos/abc/file", strings.NewReader("hello"))
+	Expect(err).To(BeNil())
+	Expect(written).To(Equal(int64(5)))
+
+	reader, err := store.Open(ctx, "todos/abc/file")
+	Expect(err).To(BeNil())
+
+	body, _ := io.ReadAll(reader)
+	reader.Close()
+	Expect(string(body)).To(Equal("hello"))
+
+	// No temporary files are left next to the blob
+	entries, _ := os.ReadDir(filepath.Join(root, "todos", "abc"))
+	Expect(entries).To(HaveLen(1))
+
+	Expect(store.Delete(ctx, "todos/abc/file")).To(Succeed())
+	Expect(store.Delete(ctx, "todos/abc/file")).To(Succeed())
+
+	_, err = store.Open(ctx, "todos/abc/file")
+	Expect(err).To(MatchError(domain.ErrBlobNotFound))
+}
+
+func TestFilesystemStore_RejectsKeysOutsideRoot(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := context.Background()
+	store := NewFilesystemStore(t.TempDir())
+
+	for _, key := range []string{"", "/etc/passwd", "../escape", "todos/../../escape"} {
+		_, err := store.Put(ctx, key, strings.NewReader("x"))
+		Expect(err).To(HaveOccurred(), key)
+
+		_, err = store.Open(ctx, key)
+		Expect(err).To(HaveOccurred(), key)
+	}
+}
@@ -0,0 +1,252 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"todos/internal/core/domain"
+)
+
+var csvHeader = []string{"uuid", "title", "description", "status", "completed", "completed_at", "due_at", "all_day", "tags", "rrule", "created_at", "updated_at"}
+
+// csvColumns maps the headers other tools use to the fields an import reads
+var csvColumns = map[string]string{
+	"title":        "title",
+	"name":         "title",
+	"summary":      "title",
+	"task":         "title",
+	"description":  "description",
+	"notes":        "description",
+	"status":       "status",
+	"state":        "status",
+	"completed":    "completed",
+	"done":         "completed",
+	"completed_at": "completed_at",
+	"due_at":       "due_at",
+	"due":          "due_at",
+	"due_date":     "due_at",
+	"all_day":      "all_day",
+	"tags":         "tags",
+	"labels":       "tags",
+	"rrule":        "rrule",
+	"created_at":   "created_at",
+	"created":      "created_at",
+}
+
+type csvEncoder struct {
+	w       *csv.Writer
+	started bool
+}
+
+func newCSVEncoder(w io.Writer) *csvEncoder {
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) start() error {
+	if e.started {
+		return nil
+	}
+
+	e.started = true
+
+	return e.w.Write(csvHeader)
+}
+
+func (e *csvEncoder) Encode(todo domain.Todo) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	rrule := ""
+
+	if todo.RRule != nil {
+		rrule = *todo.RRule
+	}
+
+	e.w.Write([]string{
+		todo.UUID.String(),
+		todo.Title,
+		todo.Description,
+		todo.StatusOrFallback(),
+		strconv.FormatBool(todo.Completed),
+		formatCSVTime(todo.CompletedAt),
+		formatCSVDue(todo),
+		strconv.FormatBool(todo.AllDay),
+		strings.Join(todo.Tags, ","),
+		rrule,
+		todo.CreatedAt.UTC().Format(time.RFC3339),
+		todo.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+
+	// Every row goes out as it is written, exports stream
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+func (e *csvEncoder) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+func formatCSVTime(at *time.Time) string {
+	if at == nil {
+		return ""
+	}
+
+	return at.UTC().Format(time.RFC3339)
+}
+
+// formatCSVDue writes all-day due dates as a plain date
+func formatCSVDue(todo domain.Todo) string {
+	if todo.DueAt != nil && todo.AllDay {
+		return todo.DueAt.UTC().Format(time.DateOnly)
+	}
+
+	return formatCSVTime(todo.DueAt)
+}
+
+// decodeCSV reads a CSV file whose first row names the columns. Columns are
+// matched by name, unknown ones are ignored.
+func decodeCSV(r io.Reader) ([]domain.ImportedTodo, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := map[string]int{}
+
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+
+		if field, ok := csvColumns[strings.ReplaceAll(name, " ", "_")]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New("invalid CSV: the header has no title column")
+	}
+
+	var todos []domain.ImportedTodo
+
+	for {
+		record, err := reader.Read()
+
+		if errors.Is(err, io.EOF) {
+			return todos, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		value := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+
+			return ""
+		}
+
+		todos = append(todos, csvTodo(line, value))
+	}
+}
+
+func csvTodo(line int, value func(string) string) domain.ImportedTodo {
+	todo := domain.ImportedTodo{
+		Line:        line,
+		Title:       value("title"),
+		Description: value("description"),
+		Status:      value("status"),
+		Tags:        splitTags(value("tags")),
+		RRule:       value("rrule"),
+	}
+
+	fail := func(field string, err error) domain.ImportedTodo {
+		todo.Err = fmt.Errorf("%s: %w", field, err)
+		return todo
+	}
+
+	if completed := value("completed"); completed != "" {
+		switch strings.ToLower(completed) {
+		case "x", "yes", "y":
+			todo.Completed = true
+		case "no", "n":
+		default:
+			done, err := strconv.ParseBool(completed)
+
+			if err != nil {
+				return fail("completed", fmt.Errorf("invalid boolean %q", completed))
+			}
+
+			todo.Completed = done
+		}
+	}
+
+	if text := value("completed_at"); text != "" {
+		at, _, err := parseTime(text)
+
+		if err != nil {
+			return fail("completed_at", err)
+		}
+
+		todo.CompletedAt = &at
+	}
+
+	if text := value("created_at"); text != "" {
+		at, _, err := parseTime(text)
+
+		if err != nil {
+			return fail("created_at", err)
+		}
+
+		todo.CreatedAt = &at
+	}
+
+	if text := value("due_at"); text != "" {
+		due, dateOnly, err := parseTime(text)
+
+		if err != nil {
+			return fail("due_at", err)
+		}
+
+		todo.DueAt = &due
+		todo.AllDay = dateOnly
+	}
+
+	if allDay := value("all_day"); allDay != "" {
+		flag, err := strconv.ParseBool(allDay)
+
+		if err != nil {
+			return fail("all_day", fmt.Errorf("invalid boolean %q", allDay))
+		}
+
+		todo.AllDay = todo.AllDay || flag
+	}
+
+	return todo
+}
@@ -0,0 +1,401 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"todos/internal/core/domain"
+)
+
+const (
+	icalDate     = "20060102"
+	icalDateTime = "20060102T150405"
+	icalUTC      = "20060102T150405Z"
+
+	// icalLineLength is how many octets a content line may have before it is
+	// folded
+	icalLineLength = 75
+
+	// icalStatus keeps statuses VTODO has no word for
+	icalStatus = "X-TODOS-STATUS"
+)
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+var icalUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+// icalEncoder writes a VCALENDAR with one VTODO a todo
+type icalEncoder struct {
+	w       io.Writer
+	started bool
+}
+
+func (e *icalEncoder) start() error {
+	if e.started {
+		return nil
+	}
+
+	e.started = true
+
+	return e.write("BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//todos//todos api//EN")
+}
+
+func (e *icalEncoder) Encode(todo domain.Todo) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	lines := []string{
+		"BEGIN:VTODO",
+		"UID:" + todo.UUID.String(),
+		"DTSTAMP:" + todo.UpdatedAt.UTC().Format(icalUTC),
+		"CREATED:" + todo.CreatedAt.UTC().Format(icalUTC),
+		"LAST-MODIFIED:" + todo.UpdatedAt.UTC().Format(icalUTC),
+		"SUMMARY:" + icalEscaper.Replace(todo.Title),
+	}
+
+	if todo.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+icalEscaper.Replace(todo.Description))
+	}
+
+	status := todo.StatusOrFallback()
+
+	switch {
+	case todo.Completed:
+		lines = append(lines, "STATUS:COMPLETED")
+	case status == string(domain.TodoStatusPending):
+		lines = append(lines, "STATUS:NEEDS-ACTION")
+	default:
+		lines = append(lines, "STATUS:IN-PROCESS")
+	}
+
+	if status != string(domain.TodoStatusPending) && status != string(domain.TodoStatusCompleted) {
+		lines = append(lines, icalStatus+":"+icalEscaper.Replace(status))
+	}
+
+	if todo.CompletedAt != nil {
+		lines = append(lines, "COMPLETED:"+todo.CompletedAt.UTC().Format(icalUTC))
+	}
+
+	if todo.DueAt != nil {
+		if todo.AllDay {
+			lines = append(lines, "DUE;VALUE=DATE:"+todo.DueAt.UTC().Format(icalDate))
+		} else {
+			lines = append(lines, "DUE:"+todo.DueAt.UTC().Format(icalUTC))
+		}
+	}
+
+	if todo.RRule != nil {
+		lines = append(lines, "RRULE:"+*todo.RRule)
+	}
+
+	if len(todo.Tags) > 0 {
+		lines = append(lines, "CATEGORIES:"+strings.Join(todo.Tags, ","))
+	}
+
+	return e.write(append(lines, "END:VTODO")...)
+}
+
+func (e *icalEncoder) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	return e.write("END:VCALENDAR")
+}
+
+func (e *icalEncoder) write(lines ...string) error {
+	var b strings.Builder
+
+	for _, line := range lines {
+		b.WriteString(foldICalLine(line))
+	}
+
+	_, err := io.WriteString(e.w, b.String())
+
+	return err
+}
+
+// foldICalLine ends a content line with CRLF, folding it so no line is
+// longer than icalLineLength octets without splitting a character
+func foldICalLine(line string) string {
+	var b strings.Builder
+
+	limit := icalLineLength
+	length := 0
+
+	for _, r := range line {
+		size := len(string(r))
+
+		if length+size > limit {
+			b.WriteString("\r\n ")
+
+			// The space starting a continuation counts against its length
+			limit = icalLineLength - 1
+			length = 0
+		}
+
+		b.WriteRune(r)
+		length += size
+	}
+
+	b.WriteString("\r\n")
+
+	return b.String()
+}
+
+// icalProperty is a content line split into its name, parameters and value
+type icalProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+func parseICalProperty(line string) (icalProperty, bool) {
+	quoted := false
+	colon := -1
+
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		}
+
+		if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+
+	if colon < 0 {
+		return icalProperty{}, false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+
+	property := icalProperty{
+		name:   strings.ToUpper(parts[0]),
+		params: map[string]string{},
+		value:  line[colon+1:],
+	}
+
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			property.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+
+	return property, true
+}
+
+// icalTodo is a VTODO being read. The status of our own is kept apart, it
+// takes precedence over STATUS wherever the two appear.
+type icalTodo struct {
+	todo   domain.ImportedTodo
+	status string
+}
+
+// decodeICal reads the VTODOs of a calendar, the events and everything else
+// in it are ignored. Line is where a VTODO begins.
+func decodeICal(r io.Reader) ([]domain.ImportedTodo, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var todos []domain.ImportedTodo
+	var current *icalTodo
+
+	// A content line is handled once the next one shows it is not folded
+	// further
+	handle := func(line string, number int) {
+		property, ok := parseICalProperty(line)
+
+		if !ok {
+			return
+		}
+
+		switch {
+		case property.name == "BEGIN" && strings.EqualFold(property.value, "VTODO"):
+			current = &icalTodo{todo: domain.ImportedTodo{Line: number}}
+		case property.name == "END" && strings.EqualFold(property.value, "VTODO") && current != nil:
+			if current.status != "" {
+				current.todo.Status = current.status
+			}
+
+			todos = append(todos, current.todo)
+			current = nil
+		case current != nil:
+			current.read(property)
+		}
+	}
+
+	unfolded := ""
+	started := 0
+
+	for number := 1; scanner.Scan(); number++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+
+		if number == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+
+		if strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t") {
+			unfolded += text[1:]
+			continue
+		}
+
+		if unfolded != "" {
+			handle(unfolded, started)
+		}
+
+		unfolded = text
+		started = number
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if unfolded != "" {
+		handle(unfolded, started)
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("invalid iCalendar: the VTODO on line %d is never closed", current.todo.Line)
+	}
+
+	return todos, nil
+}
+
+func (t *icalTodo) read(property icalProperty) {
+	todo := &t.todo
+
+	fail := func(err error) {
+		if todo.Err == nil {
+			todo.Err = fmt.Errorf("%s: %w", strings.ToLower(property.name), err)
+		}
+	}
+
+	switch property.name {
+	case "SUMMARY":
+		todo.Title = icalUnescaper.Replace(property.value)
+	case "DESCRIPTION":
+		todo.Description = icalUnescaper.Replace(property.value)
+	case "STATUS":
+		todo.Status = property.value
+
+		if strings.EqualFold(property.value, "COMPLETED") {
+			todo.Completed = true
+		}
+	case icalStatus:
+		t.status = icalUnescaper.Replace(property.value)
+	case "PERCENT-COMPLETE":
+		if percent, err := strconv.Atoi(property.value); err == nil && percent >= 100 {
+			todo.Completed = true
+		}
+	case "COMPLETED":
+		at, _, err := parseICalTime(property)
+
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		todo.Completed = true
+		todo.CompletedAt = &at
+	case "CREATED":
+		at, _, err := parseICalTime(property)
+
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		todo.CreatedAt = &at
+	case "DUE":
+		at, dateOnly, err := parseICalTime(property)
+
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		todo.DueAt = &at
+		todo.AllDay = dateOnly
+	case "RRULE":
+		todo.RRule = property.value
+	case "CATEGORIES":
+		for _, tag := range splitICalList(property.value) {
+			todo.Tags = append(todo.Tags, splitTags(tag)...)
+		}
+	}
+}
+
+// parseICalTime reads DATE and DATE-TIME values, times in a zone the server
+// does not know are taken as UTC
+func parseICalTime(property icalProperty) (time.Time, bool, error) {
+	value := strings.TrimSpace(property.value)
+
+	if property.params["VALUE"] == "DATE" || len(value) == len(icalDate) {
+		date, err := time.Parse(icalDate, value)
+
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+
+		return date, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		at, err := time.Parse(icalUTC, value)
+
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+
+		return at, false, nil
+	}
+
+	location := time.UTC
+
+	if zone, ok := property.params["TZID"]; ok {
+		if loaded, err := time.LoadLocation(zone); err == nil {
+			location = loaded
+		}
+	}
+
+	at, err := time.ParseInLocation(icalDateTime, value, location)
+
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+	}
+
+	return at, false, nil
+}
+
+// splitICalList splits a list value on the commas that are not escaped
+func splitICalList(value string) []string {
+	var items []string
+	var item strings.Builder
+
+	escaped := false
+
+	for _, r := range value {
+		switch {
+		case escaped:
+			item.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			items = append(items, item.String())
+			item.Reset()
+		default:
+			item.WriteRune(r)
+		}
+	}
+
+	return append(items, item.String())
+}
@@ -0,0 +1,117 @@
+package transfer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+)
+
+// jsonTodo is a todo of a JSON import, laid out as the API returns todos
+type jsonTodo struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at"`
+	DueAt       *time.Time `json:"due_at"`
+	AllDay      bool       `json:"all_day"`
+	Tags        []string   `json:"tags"`
+	RRule       string     `json:"rrule"`
+	CreatedAt   *time.Time `json:"created_at"`
+}
+
+// jsonEncoder writes a JSON array of todos as the API returns them
+type jsonEncoder struct {
+	w       io.Writer
+	started bool
+}
+
+func (e *jsonEncoder) Encode(todo domain.Todo) error {
+	separator := ",\n"
+
+	if !e.started {
+		separator = "[\n"
+		e.started = true
+	}
+
+	data, err := json.Marshal(response.NewTodoResponse(todo))
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+
+	_, err = e.w.Write(data)
+
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	closing := "\n]\n"
+
+	if !e.started {
+		closing = "[]\n"
+	}
+
+	_, err := io.WriteString(e.w, closing)
+
+	return err
+}
+
+// decodeJSON reads an array of todos. Line is the position of a todo in the
+// array, starting at 1.
+func decodeJSON(r io.Reader) ([]domain.ImportedTodo, error) {
+	var items []json.RawMessage
+
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+
+		return nil, errors.New("invalid JSON: expected an array of todos")
+	}
+
+	todos := make([]domain.ImportedTodo, 0, len(items))
+
+	for i, item := range items {
+		var parsed jsonTodo
+
+		todo := domain.ImportedTodo{Line: i + 1}
+
+		if err := json.Unmarshal(item, &parsed); err != nil {
+			var typeError *json.UnmarshalTypeError
+
+			todo.Err = errors.New("expected a todo object")
+
+			if errors.As(err, &typeError) && typeError.Field != "" {
+				todo.Err = fmt.Errorf("%s: invalid %s", typeError.Field, typeError.Value)
+			}
+
+			todos = append(todos, todo)
+			continue
+		}
+
+		todo.Title = parsed.Title
+		todo.Description = parsed.Description
+		todo.Status = parsed.Status
+		todo.Completed = parsed.Completed
+		todo.CompletedAt = parsed.CompletedAt
+		todo.DueAt = parsed.DueAt
+		todo.AllDay = parsed.AllDay
+		todo.Tags = parsed.Tags
+		todo.RRule = parsed.RRule
+		todo.CreatedAt = parsed.CreatedAt
+
+		todos = append(todos, todo)
+	}
+
+	return todos, nil
+}
@@ -0,0 +1,161 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"todos/internal/core/domain"
+)
+
+// todoTxtPriority is the "(A) " a todo.txt line may start with, imports
+// have no priority to keep it in
+var todoTxtPriority = regexp.MustCompile(`^\([A-Z]\)\s+`)
+
+// todoTxtEncoder writes todo.txt lines: "x" and the completion date for
+// completed todos, the creation date, the title, tags as "+tag" and the due
+// date, status and recurrence as key:value pairs
+type todoTxtEncoder struct {
+	w io.Writer
+}
+
+func (e *todoTxtEncoder) Encode(todo domain.Todo) error {
+	var parts []string
+
+	if todo.Completed {
+		parts = append(parts, "x")
+
+		if todo.CompletedAt != nil {
+			parts = append(parts, todo.CompletedAt.UTC().Format(time.DateOnly))
+		}
+	}
+
+	parts = append(parts, todo.CreatedAt.UTC().Format(time.DateOnly))
+	parts = append(parts, strings.Join(strings.Fields(todo.Title), " "))
+
+	for _, tag := range todo.Tags {
+		parts = append(parts, "+"+tag)
+	}
+
+	if todo.DueAt != nil {
+		parts = append(parts, "due:"+formatCSVDue(todo))
+	}
+
+	if status := todo.StatusOrFallback(); status != string(domain.TodoStatusPending) && status != string(domain.TodoStatusCompleted) {
+		parts = append(parts, "status:"+status)
+	}
+
+	if todo.RRule != nil {
+		parts = append(parts, "rrule:"+*todo.RRule)
+	}
+
+	_, err := io.WriteString(e.w, strings.Join(parts, " ")+"\n")
+
+	return err
+}
+
+func (e *todoTxtEncoder) Close() error {
+	return nil
+}
+
+// decodeTodoTxt reads one todo a line. Contexts ("@home") become tags like
+// projects ("+work") do, only the due, status and rrule keys are read as
+// metadata and anything else stays in the title.
+func decodeTodoTxt(r io.Reader) ([]domain.ImportedTodo, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var todos []domain.ImportedTodo
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+
+		if text == "" {
+			continue
+		}
+
+		todos = append(todos, todoTxtTodo(line, text))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return todos, nil
+}
+
+func todoTxtTodo(line int, text string) domain.ImportedTodo {
+	todo := domain.ImportedTodo{Line: line}
+
+	if strings.HasPrefix(text, "x ") {
+		todo.Completed = true
+		text = strings.TrimSpace(text[2:])
+
+		if at, rest, ok := todoTxtDate(text); ok {
+			todo.CompletedAt = &at
+			text = rest
+		}
+	}
+
+	text = todoTxtPriority.ReplaceAllString(text, "")
+
+	if at, rest, ok := todoTxtDate(text); ok {
+		todo.CreatedAt = &at
+		text = rest
+	}
+
+	var words []string
+
+	for _, word := range strings.Fields(text) {
+		if len(word) > 1 && (word[0] == '+' || word[0] == '@') {
+			todo.Tags = append(todo.Tags, word[1:])
+			continue
+		}
+
+		key, value, ok := strings.Cut(word, ":")
+
+		if !ok || value == "" {
+			words = append(words, word)
+			continue
+		}
+
+		switch key {
+		case "due":
+			due, dateOnly, err := parseTime(value)
+
+			if err != nil {
+				todo.Err = fmt.Errorf("due: %w", err)
+				continue
+			}
+
+			todo.DueAt = &due
+			todo.AllDay = dateOnly
+		case "status":
+			todo.Status = value
+		case "rrule":
+			todo.RRule = value
+		default:
+			words = append(words, word)
+		}
+	}
+
+	todo.Title = strings.Join(words, " ")
+
+	return todo
+}
+
+// todoTxtDate reads the date a todo.txt line may continue with
+func todoTxtDate(text string) (time.Time, string, bool) {
+	word, rest, _ := strings.Cut(text, " ")
+
+	at, err := time.Parse(time.DateOnly, word)
+
+	if err != nil {
+		return time.Time{}, text, false
+	}
+
+	return at, strings.TrimSpace(rest), true
+}
@@ -0,0 +1,160 @@
+// Package transfer reads and writes todos in the file formats other tools
+// use, for exports and imports
+package transfer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"time"
+
+	"todos/internal/core/domain"
+)
+
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatJSON    Format = "json"
+	FormatTodoTxt Format = "todotxt"
+	FormatICal    Format = "ical"
+)
+
+var ErrUnknownFormat = errors.New("format must be one of csv, json, todotxt or ical")
+
+// contentTypes are the media types of the formats, imports also recognize a
+// format by them
+var contentTypes = map[Format]string{
+	FormatCSV:     "text/csv",
+	FormatJSON:    "application/json",
+	FormatTodoTxt: "text/plain",
+	FormatICal:    "text/calendar",
+}
+
+var extensions = map[Format]string{
+	FormatCSV:     "csv",
+	FormatJSON:    "json",
+	FormatTodoTxt: "txt",
+	FormatICal:    "ics",
+}
+
+func ParseFormat(value string) (Format, error) {
+	format := Format(strings.ToLower(strings.TrimSpace(value)))
+
+	switch format {
+	case "ics", "icalendar":
+		return FormatICal, nil
+	case "txt":
+		return FormatTodoTxt, nil
+	}
+
+	if _, ok := contentTypes[format]; !ok {
+		return "", ErrUnknownFormat
+	}
+
+	return format, nil
+}
+
+// FormatOf recognizes a format by the media type of a request body
+func FormatOf(contentType string) (Format, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	for format, known := range contentTypes {
+		if mediaType == known {
+			return format, nil
+		}
+	}
+
+	return "", ErrUnknownFormat
+}
+
+// FormatOfFilename recognizes a format by the extension of an uploaded file
+func FormatOfFilename(name string) (Format, error) {
+	extension := strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))
+
+	for format, known := range extensions {
+		if extension == known {
+			return format, nil
+		}
+	}
+
+	return "", ErrUnknownFormat
+}
+
+func (f Format) ContentType() string {
+	return contentTypes[f] + "; charset=utf-8"
+}
+
+// Filename names an export of the format
+func (f Format) Filename() string {
+	return "todos." + extensions[f]
+}
+
+// Encoder writes todos out one at a time as they come. Close finishes the
+// document, which is valid even when no todo was written.
+type Encoder interface {
+	Encode(todo domain.Todo) error
+	Close() error
+}
+
+// NewEncoder writes the format to w. Nothing reaches w before the first todo
+// or Close.
+func NewEncoder(format Format, w io.Writer) Encoder {
+	switch format {
+	case FormatJSON:
+		return &jsonEncoder{w: w}
+	case FormatTodoTxt:
+		return &todoTxtEncoder{w: w}
+	case FormatICal:
+		return &icalEncoder{w: w}
+	default:
+		return newCSVEncoder(w)
+	}
+}
+
+// Decode reads the todos of an import. Todos that cannot be read carry the
+// reason in their Err, an error is only returned when the file as a whole
+// cannot be read.
+func Decode(format Format, r io.Reader) ([]domain.ImportedTodo, error) {
+	switch format {
+	case FormatCSV:
+		return decodeCSV(r)
+	case FormatJSON:
+		return decodeJSON(r)
+	case FormatTodoTxt:
+		return decodeTodoTxt(r)
+	case FormatICal:
+		return decodeICal(r)
+	}
+
+	return nil, ErrUnknownFormat
+}
+
+// parseTime reads the dates and times imports carry. A date alone is
+// reported as such, times without a zone are taken as UTC.
+func parseTime(value string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, true, nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04"} {
+		if at, err := time.Parse(layout, value); err == nil {
+			return at, false, nil
+		}
+	}
+
+	return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+}
+
+// splitTags reads tags separated by commas or spaces, which tag names never
+// contain
+func splitTags(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
@@ -0,0 +1,275 @@
+package transfer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"todos/internal/core/domain"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+)
+
+func sampleTodos() []domain.Todo {
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	completed := time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC)
+	due := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	timed := time.Date(2024, 3, 12, 14, 15, 0, 0, time.UTC)
+	rrule := "FREQ=WEEKLY;BYDAY=MO"
+
+	return []domain.Todo{
+		{
+			UUID:        uuid.New(),
+			Title:       "Write the report, with numbers",
+			Description: "Line one\nLine two; with \\ and ,",
+			Status:      domain.TodoStatusInReview,
+			DueAt:       &due,
+			AllDay:      true,
+			Tags:        []string{"report", "work"},
+			RRule:       &rrule,
+			RRuleStart:  &due,
+			CreatedAt:   created,
+			UpdatedAt:   created,
+		},
+		{
+			UUID:        uuid.New(),
+			Title:       strings.TrimSpace(strings.Repeat("Ship the release ", 8)),
+			Status:      domain.TodoStatusCompleted,
+			Completed:   true,
+			CompletedAt: &completed,
+			DueAt:       &timed,
+			CreatedAt:   created,
+			UpdatedAt:   completed,
+		},
+	}
+}
+
+func roundTrip(t *testing.T, format Format) []domain.ImportedTodo {
+	var b bytes.Buffer
+
+	encoder := NewEncoder(format, &b)
+
+	for _, todo := range sampleTodos() {
+		Expect(encoder.Encode(todo)).To(Succeed())
+	}
+
+	Expect(encoder.Close()).To(Succeed())
+
+	todos, err := Decode(format, &b)
+
+	Expect(err).ToNot(HaveOccurred())
+	Expect(todos).To(HaveLen(2))
+
+	return todos
+}
+
+func TestTransfer_RoundTrip(t *testing.T) {
+	RegisterTestingT(t)
+
+	samples := sampleTodos()
+
+	for _, format := range []Format{FormatCSV, FormatJSON, FormatTodoTxt, FormatICal} {
+		todos := roundTrip(t, format)
+
+		first, second := todos[0], todos[1]
+
+		// Todo.txt only keeps the day todos were created and completed on
+		same := func(at *time.Time, expected time.Time) bool {
+			if format == FormatTodoTxt {
+				return at.Equal(expected.Truncate(24 * time.Hour))
+			}
+
+			return at.Equal(expected)
+		}
+
+		Expect(first.Err).ToNot(HaveOccurred(), string(format))
+		Expect(domain.ParseImportStatus(first.Status)).To(Equal(domain.TodoStatusInReview), string(format))
+		Expect(first.DueAt.Equal(*samples[0].DueAt)).To(BeTrue(), string(format))
+		Expect(first.AllDay).To(BeTrue(), string(format))
+		Expect(first.Tags).To(Equal([]string{"report", "work"}), string(format))
+		Expect(first.RRule).To(Equal("FREQ=WEEKLY;BYDAY=MO"), string(format))
+		Expect(same(first.CreatedAt, samples[0].CreatedAt)).To(BeTrue(), string(format))
+
+		Expect(second.Err).ToNot(HaveOccurred(), string(format))
+		Expect(second.Title).To(Equal(samples[1].Title), string(format))
+		Expect(second.Completed).To(BeTrue(), string(format))
+		Expect(second.DueAt.Equal(*samples[1].DueAt)).To(BeTrue(), string(format))
+		Expect(second.AllDay).To(BeFalse(), string(format))
+		Expect(same(second.CompletedAt, *samples[1].CompletedAt)).To(BeTrue(), string(format))
+
+		// Todo.txt keeps a todo on one line and drops its description
+		if format != FormatTodoTxt {
+			Expect(first.Title).To(Equal(samples[0].Title), string(format))
+			Expect(first.Description).To(Equal(samples[0].Description), string(format))
+		}
+	}
+}
+
+func TestTransfer_EmptyExports(t *testing.T) {
+	RegisterTestingT(t)
+
+	for _, format := range []Format{FormatCSV, FormatJSON, FormatTodoTxt, FormatICal} {
+		var b bytes.Buffer
+
+		Expect(NewEncoder(format, &b).Close()).To(Succeed())
+
+		todos, err := Decode(format, &b)
+
+		Expect(err).ToNot(HaveOccurred(), string(format))
+		Expect(todos).To(BeEmpty(), string(format))
+	}
+}
+
+func TestTransfer_ICalFoldsLongLines(t *testing.T) {
+	RegisterTestingT(t)
+
+	var b bytes.Buffer
+
+	encoder := NewEncoder(FormatICal, &b)
+	Expect(encoder.Encode(sampleTodos()[1])).To(Succeed())
+	Expect(encoder.Close()).To(Succeed())
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+
+	Expect(lines[0]).To(Equal("BEGIN:VCALENDAR"))
+	Expect(lines[len(lines)-1]).To(Equal("END:VCALENDAR"))
+
+	folded := 0
+
+	for _, line := range lines {
+		Expect(len(line)).To(BeNumerically("<=", 75))
+
+		if strings.HasPrefix(line, " ") {
+			folded++
+		}
+	}
+
+	Expect(folded).To(BeNumerically(">", 0))
+}
+
+func TestTransfer_DecodeForeignCSV(t *testing.T) {
+	RegisterTestingT(t)
+
+	file := "\ufeffName,Notes,State,Done,Due Date,Labels,Extra\n" +
+		"Buy milk,Two litres,Not Started,no,2024-05-01,home errands,ignored\n" +
+		"Call the bank,,,yes,2024-05-02 10:30,,\n" +
+		"Broken row,,,,tomorrow,,\n"
+
+	todos, err := Decode(FormatCSV, strings.NewReader(file))
+
+	Expect(err).ToNot(HaveOccurred())
+	Expect(todos).To(HaveLen(3))
+
+	Expect(todos[0].Line).To(Equal(2))
+	Expect(todos[0].Title).To(Equal("Buy milk"))
+	Expect(todos[0].Description).To(Equal("Two litres"))
+	Expect(domain.ParseImportStatus(todos[0].Status)).To(Equal(domain.TodoStatusPending))
+	Expect(todos[0].AllDay).To(BeTrue())
+	Expect(todos[0].Tags).To(Equal([]string{"home", "errands"}))
+
+	Expect(todos[1].Completed).To(BeTrue())
+	Expect(*todos[1].DueAt).To(Equal(time.Date(2024, 5, 2, 10, 30, 0, 0, time.UTC)))
+	Expect(todos[1].AllDay).To(BeFalse())
+
+	Expect(todos[2].Line).To(Equal(4))
+	Expect(todos[2].Err).To(MatchError(ContainSubstring("due_at")))
+
+	_, err = Decode(FormatCSV, strings.NewReader("description\nno title"))
+	Expect(err).To(HaveOccurred())
+}
+
+func TestTransfer_DecodeTodoTxt(t *testing.T) {
+	RegisterTestingT(t)
+
+	file := "(A) 2024-01-02 Call mom +family @phone due:2024-01-05 http://example.com\n" +
+		"\n" +
+		"x 2024-01-04 2024-01-01 Pay rent +home\n" +
+		"Fix the sink due:soon\n"
+
+	todos, err := Decode(FormatTodoTxt, strings.NewReader(file))
+
+	Expect(err).ToNot(HaveOccurred())
+	Expect(todos).To(HaveLen(3))
+
+	Expect(todos[0].Line).To(Equal(1))
+	Expect(todos[0].Title).To(Equal("Call mom http://example.com"))
+	Expect(todos[0].Tags).To(Equal([]string{"family", "phone"}))
+	Expect(*todos[0].CreatedAt).To(Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)))
+	Expect(todos[0].AllDay).To(BeTrue())
+	Expect(todos[0].Completed).To(BeFalse())
+
+	Expect(todos[1].Line).To(Equal(3))
+	Expect(todos[1].Title).To(Equal("Pay rent"))
+	Expect(todos[1].Completed).To(BeTrue())
+	Expect(*todos[1].CompletedAt).To(Equal(time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)))
+	Expect(*todos[1].CreatedAt).To(Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	Expect(todos[2].Err).To(MatchError(ContainSubstring("due")))
+}
+
+func TestTransfer_DecodeForeignICal(t *testing.T) {
+	RegisterTestingT(t)
+
+	file := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"SUMMARY:Not a todo",
+		"END:VEVENT",
+		"BEGIN:VTODO",
+		"UID:1",
+		"SUMMARY:Prepare the quarterly",
+		"  review",
+		"DUE;TZID=America/Sao_Paulo:20240510T090000",
+		"CATEGORIES:work,planning",
+		"CATEGORIES:q2",
+		"STATUS:IN-PROCESS",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"SUMMARY:Water the plants",
+		"PERCENT-COMPLETE:100",
+		"DUE;VALUE=DATE:20240511",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	todos, err := Decode(FormatICal, strings.NewReader(file))
+
+	Expect(err).ToNot(HaveOccurred())
+	Expect(todos).To(HaveLen(2))
+
+	Expect(todos[0].Line).To(Equal(6))
+	Expect(todos[0].Title).To(Equal("Prepare the quarterly review"))
+	Expect(todos[0].Tags).To(Equal([]string{"work", "planning", "q2"}))
+	Expect(domain.ParseImportStatus(todos[0].Status)).To(Equal(domain.TodoStatusInProgress))
+	Expect(todos[0].DueAt.UTC()).To(Equal(time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)))
+
+	Expect(todos[1].Completed).To(BeTrue())
+	Expect(todos[1].AllDay).To(BeTrue())
+
+	_, err = Decode(FormatICal, strings.NewReader("BEGIN:VTODO\r\nSUMMARY:Never closed\r\n"))
+	Expect(err).To(HaveOccurred())
+}
+
+func TestTransfer_Formats(t *testing.T) {
+	RegisterTestingT(t)
+
+	for value, expected := range map[string]Format{"CSV": FormatCSV, "ics": FormatICal, "icalendar": FormatICal, "txt": FormatTodoTxt, "json": FormatJSON} {
+		format, err := ParseFormat(value)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(format).To(Equal(expected))
+	}
+
+	_, err := ParseFormat("xml")
+	Expect(err).To(MatchError(ErrUnknownFormat))
+
+	format, _ := FormatOf("text/calendar; charset=utf-8")
+	Expect(format).To(Equal(FormatICal))
+
+	format, _ = FormatOfFilename("Backup.TXT")
+	Expect(format).To(Equal(FormatTodoTxt))
+	Expect(FormatICal.Filename()).To(Equal("todos.ics"))
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAttachmentNotFound  = errors.New("attachment not found")
+	ErrAttachmentTooLarge  = errors.New("attachment exceeds the 10MB limit")
+	ErrAttachmentEmpty     = errors.New("attachment is empty")
+	ErrAttachmentType      = errors.New("attachment type is not allowed")
+	ErrInvalidDownloadLink = errors.New("download link is invalid or has expired")
+	ErrBlobNotFound        = errors.New("blob not found")
+)
+
+// MaxAttachmentSize caps a single upload
+const MaxAttachmentSize int64 = 10 << 20
+
+// attachmentTypes lists the content types accepted for upload, as sniffed
+// from the file itself rather than taken from the client
+var attachmentTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+	"text/plain":      true,
+}
+
+// CheckAttachment validates an upload's content type and declared size
+func CheckAttachment(contentType string, size int64) error {
+	if !attachmentTypes[contentType] {
+		return ErrAttachmentType
+	}
+
+	if size > MaxAttachmentSize {
+		return ErrAttachmentTooLarge
+	}
+
+	return nil
+}
+
+// Attachment is the metadata of a file stored on a todo. The contents live
+// in a BlobStore under StorageKey and stay there after the attachment or
+// its todo is soft deleted, until a purge removes them.
+type Attachment struct {
+	ID          int
+	UUID        uuid.UUID
+	TodoId      int
+	UserId      int
+	Filename    string
+	ContentType string
+	Size        int64
+	StorageKey  string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time
+}
+
+// AttachmentLink is a signed download URL valid until ExpiresAt
+type AttachmentLink struct {
+	URL       string
+	ExpiresAt time.Time
+}
@@ -0,0 +1,16 @@
+package domain
+
+// TodoAction identifies what an actor is trying to do with a todo
+type TodoAction string
+
+const (
+	TodoActionList   TodoAction = "list"
+	TodoActionView   TodoAction = "view"
+	TodoActionCreate TodoAction = "create"
+	TodoActionUpdate TodoAction = "update"
+	TodoActionDelete TodoAction = "delete"
+)
+
+func (a TodoAction) String() string {
+	return string(a)
+}
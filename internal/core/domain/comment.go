@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCommentNotFound      = errors.New("comment not found")
+	ErrInvalidCommentParent = errors.New("replies must answer a comment on the same todo")
+)
+
+// CommentCursorSort tags comment cursors, so a todo list cursor cannot be
+// replayed against a comment thread
+const CommentCursorSort = "comment:asc"
+
+// Comment is a message on a todo. Replies point at the comment they answer
+// through ParentId, the author's uuid and name are loaded for display.
+type Comment struct {
+	ID         int
+	UUID       uuid.UUID
+	TodoId     int
+	UserId     int
+	ParentId   *int
+	ParentUUID *uuid.UUID `db:"parent_uuid"`
+	AuthorUUID uuid.UUID  `db:"author_uuid"`
+	AuthorName string     `db:"author_name"`
+	Body       string     `validate:"min=1,max=5000"`
+	EditCount  int        // number of stored revisions, computed when loaded
+	EditedAt   *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  *time.Time
+}
+
+func (c *Comment) IsAuthoredBy(userId int) bool {
+	return c.UserId == userId
+}
+
+// CommentRevision is a body a comment had before one of its edits
+type CommentRevision struct {
+	ID        int
+	CommentId int
+	Body      string
+	CreatedAt time.Time // when this body was replaced
+}
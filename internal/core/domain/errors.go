@@ -0,0 +1,16 @@
+package domain
+
+import "errors"
+
+var (
+	ErrTodoNotFound  = errors.New("todo not found")
+	ErrForbidden     = errors.New("action not allowed")
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrUserNotFound  = errors.New("user not found")
+
+	ErrVersionConflict = errors.New("todo was changed since it was read")
+
+	// ErrTodoChanged is a write made without a version that lost a race with
+	// another one, sending it again may succeed
+	ErrTodoChanged = errors.New("todo was changed while it was being updated, try again")
+)
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrProjectNotFound = errors.New("project not found")
+	ErrProjectArchived = errors.New("project is archived")
+
+	// ErrInvalidProjectOrder is returned when a reorder does not list every
+	// todo of the project exactly once
+	ErrInvalidProjectOrder = errors.New("order must list every todo of the project exactly once")
+)
+
+// Project groups todos into a list of their own
+type Project struct {
+	ID          int
+	UUID        uuid.UUID
+	UserId      int
+	Name        string `validate:"min=1,max=255"`
+	Description string `validate:"max=1000"`
+	Position    int
+	ArchivedAt  *time.Time
+	TodoCount   int         // live todos in the project, computed when loaded
+	Role        ProjectRole // role of the user the project was loaded for
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time
+}
+
+func (p *Project) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"name":        p.Name,
+		"description": p.Description,
+		"position":    p.Position,
+		"archived_at": p.ArchivedAt,
+		"updated_at":  p.UpdatedAt,
+	}
+}
+
+func (p *Project) IsArchived() bool {
+	return p.ArchivedAt != nil
+}
+
+func (p *Project) BelongsToUser(userId int) bool {
+	return p.UserId == userId
+}
+
+// ProjectChanges carries a partial project update, nil fields stay untouched
+type ProjectChanges struct {
+	Name        *string
+	Description *string
+	Position    *int
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectRole is what a member may do inside a shared project
+type ProjectRole string
+
+const (
+	// ProjectViewer can read the project and its todos
+	ProjectViewer ProjectRole = "viewer"
+	// ProjectEditor can also create, update and delete the project's todos
+	ProjectEditor ProjectRole = "editor"
+	// ProjectOwner can also change the project itself and its members
+	ProjectOwner ProjectRole = "owner"
+)
+
+var (
+	ErrInvalidProjectRole    = errors.New("invalid project role")
+	ErrProjectMemberNotFound = errors.New("project member not found")
+	ErrProjectMemberExists   = errors.New("user is already a member of the project")
+	ErrLastProjectOwner      = errors.New("a project must keep at least one owner")
+)
+
+var projectRoleRanks = map[ProjectRole]int{
+	ProjectViewer: 1,
+	ProjectEditor: 2,
+	ProjectOwner:  3,
+}
+
+func (r ProjectRole) Validate() error {
+	if _, ok := projectRoleRanks[r]; !ok {
+		return fmt.Errorf("%w: %q, use viewer, editor or owner", ErrInvalidProjectRole, string(r))
+	}
+
+	return nil
+}
+
+// AtLeast reports whether r grants everything other does
+func (r ProjectRole) AtLeast(other ProjectRole) bool {
+	return projectRoleRanks[r] >= projectRoleRanks[other] && projectRoleRanks[r] > 0
+}
+
+// Allows maps a todo action onto the role needed to perform it
+func (r ProjectRole) Allows(action TodoAction) bool {
+	switch action {
+	case TodoActionList, TodoActionView:
+		return r.AtLeast(ProjectViewer)
+	default:
+		return r.AtLeast(ProjectEditor)
+	}
+}
+
+// ProjectMember grants a user a role on a project, the user fields are
+// loaded alongside for display
+type ProjectMember struct {
+	ID        int
+	ProjectId int
+	UserId    int
+	UserUUID  uuid.UUID `db:"user_uuid"`
+	Name      string
+	Email     string
+	Role      ProjectRole
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
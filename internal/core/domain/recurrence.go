@@ -0,0 +1,683 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidRecurrence  = errors.New("invalid recurrence rule")
+	ErrRecurrenceNeedsDue = errors.New("recurring todos need a due date")
+	ErrNotRecurring       = errors.New("todo does not repeat")
+	ErrNoNextOccurrence   = errors.New("recurrence has no further occurrences")
+	ErrInvalidOccurrence  = errors.New("date is not an upcoming occurrence of this todo")
+	ErrOccurrenceExists   = errors.New("next occurrence was already created")
+)
+
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+	FrequencyYearly  Frequency = "YEARLY"
+)
+
+const (
+	// exdateLayout is the iCalendar UTC date-time form used for EXDATE and UNTIL
+	exdateLayout = "20060102T150405Z"
+
+	// Rules such as BYMONTH=2;BYMONTHDAY=30 never match. Iteration gives up
+	// after this many periods in a row without an occurrence.
+	maxEmptyPeriods = 2000
+	maxPeriods      = 100000
+)
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// RecurrenceDay is a BYDAY entry. Nth picks one weekday inside the month or
+// year (1 is the first, -1 the last), zero means every such weekday.
+type RecurrenceDay struct {
+	Nth     int
+	Weekday time.Weekday
+}
+
+// RRule is the subset of the iCalendar RRULE (RFC 5545) todos support:
+// FREQ, INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH and BYSETPOS.
+// Weeks start on Monday.
+type RRule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []RecurrenceDay
+	ByMonthDay []int
+	ByMonth    []int
+	BySetPos   []int
+}
+
+// ParseRRule reads a rule such as "FREQ=WEEKLY;BYDAY=MO,WE,FR". A leading
+// "RRULE:" is accepted.
+func ParseRRule(value string) (RRule, error) {
+	rule := RRule{Interval: 1}
+
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+
+	if value == "" {
+		return rule, fmt.Errorf("%w: empty rule", ErrInvalidRecurrence)
+	}
+
+	invalid := func(part string) (RRule, error) {
+		return RRule{}, fmt.Errorf("%w: %s", ErrInvalidRecurrence, part)
+	}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(part)), "=")
+
+		if !ok || val == "" {
+			return invalid(part)
+		}
+
+		switch key {
+		case "FREQ":
+			rule.Freq = Frequency(val)
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+
+			if err != nil || interval < 1 {
+				return invalid(part)
+			}
+
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+
+			if err != nil || count < 1 {
+				return invalid(part)
+			}
+
+			rule.Count = count
+		case "UNTIL":
+			until, err := parseUntil(val)
+
+			if err != nil {
+				return invalid(part)
+			}
+
+			rule.Until = &until
+		case "BYDAY":
+			for _, code := range strings.Split(val, ",") {
+				day, err := parseRecurrenceDay(code)
+
+				if err != nil {
+					return invalid(part)
+				}
+
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			days, err := parseIntList(val, -31, 31)
+
+			if err != nil {
+				return invalid(part)
+			}
+
+			rule.ByMonthDay = days
+		case "BYMONTH":
+			months, err := parseIntList(val, 1, 12)
+
+			if err != nil {
+				return invalid(part)
+			}
+
+			rule.ByMonth = months
+		case "BYSETPOS":
+			positions, err := parseIntList(val, -366, 366)
+
+			if err != nil {
+				return invalid(part)
+			}
+
+			rule.BySetPos = positions
+		case "WKST":
+			if val != "MO" {
+				return invalid(part + " (weeks start on Monday)")
+			}
+		default:
+			return invalid(key + " is not supported")
+		}
+	}
+
+	switch rule.Freq {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+	case "":
+		return invalid("FREQ is required")
+	default:
+		return invalid("FREQ=" + string(rule.Freq))
+	}
+
+	if rule.Count > 0 && rule.Until != nil {
+		return invalid("COUNT and UNTIL cannot be combined")
+	}
+
+	if len(rule.BySetPos) > 0 && len(rule.ByDay) == 0 && len(rule.ByMonthDay) == 0 && len(rule.ByMonth) == 0 {
+		return invalid("BYSETPOS needs another BY rule")
+	}
+
+	for _, day := range rule.ByDay {
+		if day.Nth != 0 && rule.Freq != FrequencyMonthly && rule.Freq != FrequencyYearly {
+			return invalid("numbered BYDAY needs FREQ=MONTHLY or FREQ=YEARLY")
+		}
+	}
+
+	if rule.Freq == FrequencyWeekly && len(rule.ByMonthDay) > 0 {
+		return invalid("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+
+	return rule, nil
+}
+
+// String renders the rule in canonical form, the way it is stored
+func (r RRule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(exdateLayout))
+	}
+
+	if len(r.ByDay) > 0 {
+		codes := make([]string, 0, len(r.ByDay))
+
+		for _, day := range r.ByDay {
+			codes = append(codes, day.String())
+		}
+
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+
+	if len(r.ByMonth) > 0 {
+		parts = append(parts, "BYMONTH="+joinInts(r.ByMonth))
+	}
+
+	if len(r.BySetPos) > 0 {
+		parts = append(parts, "BYSETPOS="+joinInts(r.BySetPos))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func (d RecurrenceDay) String() string {
+	for code, weekday := range weekdayCodes {
+		if weekday == d.Weekday {
+			if d.Nth != 0 {
+				return strconv.Itoa(d.Nth) + code
+			}
+
+			return code
+		}
+	}
+
+	return ""
+}
+
+// each calls yield with every occurrence in order, starting with start
+// itself as RFC 5545 counts DTSTART as the first instance. It stops when
+// yield returns false or the rule runs out.
+func (r RRule) each(start time.Time, yield func(time.Time) bool) {
+	if !yield(start) {
+		return
+	}
+
+	emitted := 1
+	empty := 0
+
+	for period := 0; period < maxPeriods && empty < maxEmptyPeriods; period++ {
+		if r.Count > 0 && emitted >= r.Count {
+			return
+		}
+
+		dates := r.expand(start, period)
+
+		if len(dates) == 0 {
+			empty++
+			continue
+		}
+
+		empty = 0
+
+		for _, date := range dates {
+			occurrence := time.Date(date.Year(), date.Month(), date.Day(), start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+
+			if !occurrence.After(start) {
+				continue
+			}
+
+			if r.Until != nil && occurrence.After(*r.Until) {
+				return
+			}
+
+			emitted++
+
+			if !yield(occurrence) || (r.Count > 0 && emitted >= r.Count) {
+				return
+			}
+		}
+	}
+}
+
+// expand lists the dates of the nth period after the one holding start
+func (r RRule) expand(start time.Time, n int) []time.Time {
+	step := n * r.Interval
+	var dates []time.Time
+
+	switch r.Freq {
+	case FrequencyDaily:
+		day := civilDate(start).AddDate(0, 0, step)
+
+		if r.matchesMonth(day) && r.matchesMonthDay(day) && r.matchesWeekday(day) {
+			dates = append(dates, day)
+		}
+	case FrequencyWeekly:
+		monday := civilDate(start).AddDate(0, 0, -mondayOffset(start.Weekday())+7*step)
+		weekdays := []time.Weekday{start.Weekday()}
+
+		if len(r.ByDay) > 0 {
+			weekdays = weekdays[:0]
+
+			for _, day := range r.ByDay {
+				weekdays = append(weekdays, day.Weekday)
+			}
+		}
+
+		for _, weekday := range weekdays {
+			day := monday.AddDate(0, 0, mondayOffset(weekday))
+
+			if r.matchesMonth(day) {
+				dates = append(dates, day)
+			}
+		}
+	case FrequencyMonthly:
+		month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, step, 0)
+
+		if r.matchesMonth(month) {
+			dates = r.monthDates(month, start.Day())
+		}
+	case FrequencyYearly:
+		year := start.Year() + step
+
+		switch {
+		case len(r.ByMonth) > 0:
+			for _, month := range r.ByMonth {
+				dates = append(dates, r.monthDates(time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC), start.Day())...)
+			}
+		case len(r.ByMonthDay) == 0 && len(r.ByDay) > 0:
+			dates = r.yearWeekdays(year)
+		default:
+			dates = r.monthDates(time.Date(year, start.Month(), 1, 0, 0, 0, 0, time.UTC), start.Day())
+		}
+	}
+
+	return r.applySetPos(sortDates(dates))
+}
+
+// monthDates expands BYMONTHDAY and BYDAY inside one month, falling back to
+// the start's day of the month. Months too short for that day are skipped.
+func (r RRule) monthDates(month time.Time, fallbackDay int) []time.Time {
+	last := month.AddDate(0, 1, -1).Day()
+	var dates []time.Time
+
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, monthDay := range r.ByMonthDay {
+			day := monthDay
+
+			if day < 0 {
+				day = last + monthDay + 1
+			}
+
+			date := month.AddDate(0, 0, day-1)
+
+			if day >= 1 && day <= last && r.matchesWeekday(date) {
+				dates = append(dates, date)
+			}
+		}
+	case len(r.ByDay) > 0:
+		for _, byDay := range r.ByDay {
+			dates = append(dates, nthWeekdays(month, last, byDay)...)
+		}
+	case fallbackDay <= last:
+		dates = append(dates, month.AddDate(0, 0, fallbackDay-1))
+	}
+
+	return dates
+}
+
+// yearWeekdays expands BYDAY across a whole year, as in "the 20th Monday"
+func (r RRule) yearWeekdays(year int) []time.Time {
+	first := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(1, 0, -1).YearDay()
+	var dates []time.Time
+
+	for _, byDay := range r.ByDay {
+		dates = append(dates, nthWeekdays(first, last, byDay)...)
+	}
+
+	return dates
+}
+
+// nthWeekdays finds the days matching byDay among the span days from first
+func nthWeekdays(first time.Time, span int, byDay RecurrenceDay) []time.Time {
+	offset := (int(byDay.Weekday) - int(first.Weekday()) + 7) % 7
+	var matches []time.Time
+
+	for day := offset; day < span; day += 7 {
+		matches = append(matches, first.AddDate(0, 0, day))
+	}
+
+	switch {
+	case byDay.Nth > 0 && byDay.Nth <= len(matches):
+		return matches[byDay.Nth-1 : byDay.Nth]
+	case byDay.Nth < 0 && -byDay.Nth <= len(matches):
+		index := len(matches) + byDay.Nth
+		return matches[index : index+1]
+	case byDay.Nth == 0:
+		return matches
+	}
+
+	return nil
+}
+
+func (r RRule) applySetPos(dates []time.Time) []time.Time {
+	if len(r.BySetPos) == 0 || len(dates) == 0 {
+		return dates
+	}
+
+	var picked []time.Time
+
+	for _, position := range r.BySetPos {
+		index := position - 1
+
+		if position < 0 {
+			index = len(dates) + position
+		}
+
+		if index >= 0 && index < len(dates) {
+			picked = append(picked, dates[index])
+		}
+	}
+
+	return sortDates(picked)
+}
+
+func (r RRule) matchesMonth(date time.Time) bool {
+	return len(r.ByMonth) == 0 || containsInt(r.ByMonth, int(date.Month()))
+}
+
+func (r RRule) matchesMonthDay(date time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+
+	last := date.AddDate(0, 1, -date.Day()).Day()
+
+	return containsInt(r.ByMonthDay, date.Day()) || containsInt(r.ByMonthDay, date.Day()-last-1)
+}
+
+func (r RRule) matchesWeekday(date time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+
+	for _, day := range r.ByDay {
+		if day.Weekday == date.Weekday() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Recurrence is a rule anchored at the due date of the series' first todo,
+// minus the occurrences that were skipped
+type Recurrence struct {
+	Rule       RRule
+	Start      time.Time
+	Exceptions []time.Time
+}
+
+// Upcoming returns up to n occurrences at or after from
+func (r Recurrence) Upcoming(from time.Time, n int) []time.Time {
+	occurrences := []time.Time{}
+
+	if n <= 0 {
+		return occurrences
+	}
+
+	r.Rule.each(r.Start, func(occurrence time.Time) bool {
+		if occurrence.Before(from) || r.isException(occurrence) {
+			return true
+		}
+
+		occurrences = append(occurrences, occurrence)
+
+		return len(occurrences) < n
+	})
+
+	return occurrences
+}
+
+// Next returns the first occurrence strictly after the given time
+func (r Recurrence) Next(after time.Time) (time.Time, bool) {
+	next := r.Upcoming(after.Add(time.Second), 1)
+
+	if len(next) == 0 {
+		return time.Time{}, false
+	}
+
+	return next[0], true
+}
+
+// Includes reports whether at is an occurrence that has not been skipped
+func (r Recurrence) Includes(at time.Time) bool {
+	upcoming := r.Upcoming(at, 1)
+
+	return len(upcoming) == 1 && upcoming[0].Equal(at)
+}
+
+func (r Recurrence) isException(at time.Time) bool {
+	for _, exception := range r.Exceptions {
+		if exception.Equal(at) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsRecurring reports whether completing the todo spawns another one
+func (t *Todo) IsRecurring() bool {
+	return t.RRule != nil && *t.RRule != "" && t.RRuleStart != nil
+}
+
+// Recurrence parses the stored rule and skipped occurrences of the todo
+func (t *Todo) Recurrence() (Recurrence, error) {
+	if !t.IsRecurring() {
+		return Recurrence{}, ErrNotRecurring
+	}
+
+	rule, err := ParseRRule(*t.RRule)
+
+	if err != nil {
+		return Recurrence{}, err
+	}
+
+	recurrence := Recurrence{Rule: rule, Start: t.RRuleStart.UTC()}
+
+	if t.RRuleExdates != nil {
+		if recurrence.Exceptions, err = ParseExdates(*t.RRuleExdates); err != nil {
+			return Recurrence{}, err
+		}
+	}
+
+	return recurrence, nil
+}
+
+// ParseExdates reads the comma separated UTC date-times skipped occurrences
+// are stored as
+func ParseExdates(value string) ([]time.Time, error) {
+	dates := []time.Time{}
+
+	if value == "" {
+		return dates, nil
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		date, err := time.Parse(exdateLayout, part)
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: EXDATE %s", ErrInvalidRecurrence, part)
+		}
+
+		dates = append(dates, date)
+	}
+
+	return dates, nil
+}
+
+func FormatExdates(dates []time.Time) string {
+	parts := make([]string, 0, len(dates))
+
+	for _, date := range sortDates(append([]time.Time(nil), dates...)) {
+		parts = append(parts, date.UTC().Format(exdateLayout))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func parseRecurrenceDay(code string) (RecurrenceDay, error) {
+	code = strings.TrimSpace(code)
+
+	if len(code) < 2 {
+		return RecurrenceDay{}, ErrInvalidRecurrence
+	}
+
+	weekday, ok := weekdayCodes[code[len(code)-2:]]
+
+	if !ok {
+		return RecurrenceDay{}, ErrInvalidRecurrence
+	}
+
+	day := RecurrenceDay{Weekday: weekday}
+
+	if prefix := code[:len(code)-2]; prefix != "" {
+		nth, err := strconv.Atoi(prefix)
+
+		if err != nil || nth == 0 || nth < -53 || nth > 53 {
+			return RecurrenceDay{}, ErrInvalidRecurrence
+		}
+
+		day.Nth = nth
+	}
+
+	return day, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	if until, err := time.Parse(exdateLayout, value); err == nil {
+		return until, nil
+	}
+
+	// A bare date runs until the end of that day
+	until, err := time.Parse("20060102", value)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return until.AddDate(0, 0, 1).Add(-time.Second), nil
+}
+
+func parseIntList(value string, min int, max int) ([]int, error) {
+	var numbers []int
+
+	for _, part := range strings.Split(value, ",") {
+		number, err := strconv.Atoi(strings.TrimSpace(part))
+
+		if err != nil || number == 0 || number < min || number > max {
+			return nil, ErrInvalidRecurrence
+		}
+
+		numbers = append(numbers, number)
+	}
+
+	return numbers, nil
+}
+
+func joinInts(numbers []int) string {
+	parts := make([]string, 0, len(numbers))
+
+	for _, number := range numbers {
+		parts = append(parts, strconv.Itoa(number))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func containsInt(numbers []int, value int) bool {
+	for _, number := range numbers {
+		if number == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortDates orders dates and drops duplicates
+func sortDates(dates []time.Time) []time.Time {
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	unique := dates[:0]
+
+	for i, date := range dates {
+		if i == 0 || !date.Equal(dates[i-1]) {
+			unique = append(unique, date)
+		}
+	}
+
+	return unique
+}
+
+// civilDate drops the time of day, keeping the calendar date
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// mondayOffset is how many days weekday comes after Monday
+func mondayOffset(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func upcoming(t *testing.T, rule string, start time.Time, n int) []string {
+	parsed, err := ParseRRule(rule)
+	require.NoError(t, err)
+
+	var dates []string
+
+	for _, occurrence := range (Recurrence{Rule: parsed, Start: start}).Upcoming(start, n) {
+		dates = append(dates, occurrence.Format("2006-01-02 Mon 15:04"))
+	}
+
+	return dates
+}
+
+func TestRRule_Occurrences(t *testing.T) {
+	// Monday 10 March 2025, 09:00 UTC
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	t.Run("every weekday", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"2025-03-10 Mon 09:00",
+			"2025-03-11 Tue 09:00",
+			"2025-03-12 Wed 09:00",
+			"2025-03-13 Thu 09:00",
+			"2025-03-14 Fri 09:00",
+			"2025-03-17 Mon 09:00",
+		}, upcoming(t, "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", start, 6))
+	})
+
+	t.Run("first Monday of the month", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"2025-03-10 Mon 09:00",
+			"2025-04-07 Mon 09:00",
+			"2025-05-05 Mon 09:00",
+			"2025-06-02 Mon 09:00",
+		}, upcoming(t, "RRULE:FREQ=MONTHLY;BYDAY=1MO", start, 4))
+	})
+
+	t.Run("last weekday of the month", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"2025-03-10 Mon 09:00",
+			"2025-03-31 Mon 09:00",
+			"2025-04-30 Wed 09:00",
+			"2025-05-30 Fri 09:00",
+		}, upcoming(t, "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", start, 4))
+	})
+
+	t.Run("every other day with a count", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"2025-03-10 Mon 09:00",
+			"2025-03-12 Wed 09:00",
+			"2025-03-14 Fri 09:00",
+		}, upcoming(t, "FREQ=DAILY;INTERVAL=2;COUNT=3", start, 10))
+	})
+
+	t.Run("months without the day are skipped", func(t *testing.T) {
+		monthEnd := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+
+		assert.Equal(t, []string{
+			"2025-01-31 Fri 09:00",
+			"2025-03-31 Mon 09:00",
+			"2025-05-31 Sat 09:00",
+		}, upcoming(t, "FREQ=MONTHLY", monthEnd, 3))
+	})
+
+	t.Run("until is inclusive", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"2025-03-10 Mon 09:00",
+			"2025-03-17 Mon 09:00",
+		}, upcoming(t, "FREQ=WEEKLY;UNTIL=20250317", start, 10))
+	})
+
+	t.Run("rules that never match stop", func(t *testing.T) {
+		assert.Equal(t, []string{"2025-03-10 Mon 09:00"}, upcoming(t, "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", start, 3))
+	})
+}
+
+func TestRecurrence_SkipsExceptions(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	rule, _ := ParseRRule("FREQ=WEEKLY")
+
+	recurrence := Recurrence{
+		Rule:       rule,
+		Start:      start,
+		Exceptions: []time.Time{start.AddDate(0, 0, 7)},
+	}
+
+	next, ok := recurrence.Next(start)
+	assert.True(t, ok)
+	assert.Equal(t, start.AddDate(0, 0, 14), next)
+
+	assert.False(t, recurrence.Includes(start.AddDate(0, 0, 7)))
+	assert.True(t, recurrence.Includes(start.AddDate(0, 0, 21)))
+	assert.False(t, recurrence.Includes(start.AddDate(0, 0, 22)))
+}
+
+func TestParseRRule(t *testing.T) {
+	t.Run("renders a canonical rule", func(t *testing.T) {
+		rule, err := ParseRRule("freq=monthly;byday=-1fr;interval=2")
+		require.NoError(t, err)
+
+		assert.Equal(t, "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR", rule.String())
+	})
+
+	for _, value := range []string{
+		"",
+		"BYDAY=MO",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250101",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;BYHOUR=9",
+	} {
+		t.Run("rejects "+value, func(t *testing.T) {
+			_, err := ParseRRule(value)
+			assert.ErrorIs(t, err, ErrInvalidRecurrence)
+		})
+	}
+}
+
+func TestExdates_RoundTrip(t *testing.T) {
+	dates := []time.Time{
+		time.Date(2025, 3, 17, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC),
+	}
+
+	value := FormatExdates(dates)
+	assert.Equal(t, "20250310T090000Z,20250317T090000Z", value)
+
+	parsed, err := ParseExdates(value)
+	require.NoError(t, err)
+	assert.Len(t, parsed, 2)
+	assert.True(t, parsed[0].Equal(dates[1]))
+	assert.True(t, dates[0].After(dates[1]), "input is left unsorted")
+}
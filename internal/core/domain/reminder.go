@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReminderKind string
+
+const (
+	// ReminderBeforeDue fires OffsetMinutes before the todo is due
+	ReminderBeforeDue ReminderKind = "before_due"
+	// ReminderDayOf fires at TimeOfDay (minutes after midnight UTC) on the due date
+	ReminderDayOf ReminderKind = "day_of"
+)
+
+// MaxReminderAttempts is how many failed deliveries a reminder gets before it is given up
+const MaxReminderAttempts = 5
+
+var (
+	ErrReminderNotFound  = errors.New("reminder not found")
+	ErrTodoHasNoDueDate  = errors.New("todo has no due date")
+	ErrInvalidReminder   = errors.New("invalid reminder")
+	ErrInvalidTimeOfDay  = errors.New("time of day must use the HH:MM format")
+	maxReminderOffsetMin = 60 * 24 * 28
+)
+
+type Reminder struct {
+	ID            int
+	UUID          uuid.UUID
+	TodoId        int
+	UserId        int
+	Kind          ReminderKind
+	OffsetMinutes int
+	TimeOfDay     int
+	Attempts      int
+	LastError     string
+	ClaimToken    string
+	ClaimedAt     *time.Time
+	SentAt        *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     *time.Time
+}
+
+func (r *Reminder) Validate() error {
+	switch r.Kind {
+	case ReminderBeforeDue:
+		if r.OffsetMinutes < 0 || r.OffsetMinutes > maxReminderOffsetMin {
+			return fmt.Errorf("%w: offset_minutes must be between 0 and %d", ErrInvalidReminder, maxReminderOffsetMin)
+		}
+	case ReminderDayOf:
+		if r.TimeOfDay < 0 || r.TimeOfDay >= 24*60 {
+			return fmt.Errorf("%w: time_of_day must be within the day", ErrInvalidReminder)
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidReminder, r.Kind)
+	}
+
+	return nil
+}
+
+func (r *Reminder) IsSent() bool {
+	return r.SentAt != nil
+}
+
+// RemindAt computes when the reminder fires for the todo's current due date
+func (r *Reminder) RemindAt(todo Todo) (time.Time, error) {
+	if todo.DueAt == nil {
+		return time.Time{}, ErrTodoHasNoDueDate
+	}
+
+	if r.Kind == ReminderDayOf {
+		return StartOfDay(*todo.DueAt).Add(time.Duration(r.TimeOfDay) * time.Minute), nil
+	}
+
+	return todo.DueAt.UTC().Add(-time.Duration(r.OffsetMinutes) * time.Minute), nil
+}
+
+// ParseTimeOfDay converts "HH:MM" into minutes after midnight
+func ParseTimeOfDay(value string) (int, error) {
+	hours, minutes, found := strings.Cut(value, ":")
+
+	if !found {
+		return 0, ErrInvalidTimeOfDay
+	}
+
+	h, err := strconv.Atoi(hours)
+	if err != nil || h < 0 || h > 23 {
+		return 0, ErrInvalidTimeOfDay
+	}
+
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 || m > 59 || len(minutes) != 2 {
+		return 0, ErrInvalidTimeOfDay
+	}
+
+	return h*60 + m, nil
+}
+
+func FormatTimeOfDay(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Notification is what a delivery channel receives when a reminder fires
+type Notification struct {
+	ReminderId   int          `json:"-"`
+	ReminderUUID uuid.UUID    `json:"reminder_uuid"`
+	Kind         ReminderKind `json:"kind"`
+	TodoUUID     uuid.UUID    `json:"todo_uuid"`
+	UserId       int          `json:"user_id"`
+	Title        string       `json:"title"`
+	DueAt        *time.Time   `json:"due_at,omitempty"`
+	AllDay       bool         `json:"all_day"`
+	RemindAt     time.Time    `json:"remind_at"`
+	Attempts     int          `json:"-"`
+}
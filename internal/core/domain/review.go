@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewDecision is where a reviewer stands on a todo
+type ReviewDecision string
+
+const (
+	ReviewPending  ReviewDecision = "pending"
+	ReviewApproved ReviewDecision = "approved"
+	ReviewRejected ReviewDecision = "rejected"
+)
+
+// ReviewRule decides when the reviews of a todo let it reach a terminal
+// status. The rule belongs to the workflow the todo follows.
+type ReviewRule string
+
+const (
+	// ReviewRuleAll needs every reviewer to approve
+	ReviewRuleAll ReviewRule = "all"
+	// ReviewRuleAny needs one approval and no rejection
+	ReviewRuleAny ReviewRule = "any"
+	// ReviewRuleMajority needs more than half of the reviewers to approve
+	ReviewRuleMajority ReviewRule = "majority"
+	// ReviewRuleNone never holds a todo back
+	ReviewRuleNone ReviewRule = "none"
+)
+
+var (
+	ErrReviewNotFound        = errors.New("review not found")
+	ErrInvalidReviewRule     = errors.New("invalid review rule")
+	ErrInvalidReviewDecision = errors.New("invalid review decision")
+	ErrReviewCommentRequired = errors.New("rejecting a todo needs a comment")
+	ErrSelfReview            = errors.New("users cannot review their own request")
+	ErrReviewerNotAllowed    = errors.New("reviewer cannot see the todo")
+	ErrReviewsPending        = errors.New("the reviews do not allow completing the todo yet")
+	ErrTodoNotInReview       = errors.New("the todo is not in review")
+)
+
+const (
+	MaxReviewersPerRequest = 10
+	maxReviewCommentLength = 5000
+)
+
+// ReviewCursorSort tags cursors of the review queue
+const ReviewCursorSort = "review:asc"
+
+func (r ReviewRule) Validate() error {
+	switch r {
+	case ReviewRuleAll, ReviewRuleAny, ReviewRuleMajority, ReviewRuleNone:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q, use all, any, majority or none", ErrInvalidReviewRule, string(r))
+}
+
+// Satisfied reports whether the decisions let the todo be completed. A todo
+// nobody was asked to review is never held back.
+func (r ReviewRule) Satisfied(reviews []Review) bool {
+	if r == ReviewRuleNone || len(reviews) == 0 {
+		return true
+	}
+
+	approved, rejected := 0, 0
+
+	for _, review := range reviews {
+		switch review.Decision {
+		case ReviewApproved:
+			approved++
+		case ReviewRejected:
+			rejected++
+		}
+	}
+
+	switch r {
+	case ReviewRuleAny:
+		return approved > 0 && rejected == 0
+	case ReviewRuleMajority:
+		return approved*2 > len(reviews)
+	default:
+		return approved == len(reviews)
+	}
+}
+
+// Review asks a user to approve or reject a todo. The reviewer and the todo
+// are loaded alongside for display.
+type Review struct {
+	ID           int
+	UUID         uuid.UUID
+	TodoId       int
+	TodoUUID     uuid.UUID `db:"todo_uuid"`
+	TodoTitle    string    `db:"todo_title"`
+	ReviewerId   int
+	ReviewerUUID uuid.UUID `db:"reviewer_uuid"`
+	ReviewerName string    `db:"reviewer_name"`
+	RequestedBy  int
+	Decision     ReviewDecision
+	Comment      *string
+	DecidedAt    *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Decide records the reviewer's decision, replacing an earlier one
+func (r *Review) Decide(decision ReviewDecision, comment string, now time.Time) error {
+	if decision != ReviewApproved && decision != ReviewRejected {
+		return fmt.Errorf("%w: %q", ErrInvalidReviewDecision, string(decision))
+	}
+
+	comment = strings.TrimSpace(comment)
+
+	if decision == ReviewRejected && comment == "" {
+		return ErrReviewCommentRequired
+	}
+
+	if len(comment) > maxReviewCommentLength {
+		return fmt.Errorf("%w: comment is longer than %d characters", ErrInvalidReviewDecision, maxReviewCommentLength)
+	}
+
+	r.Decision = decision
+	r.Comment = nil
+	r.DecidedAt = &now
+
+	if comment != "" {
+		r.Comment = &comment
+	}
+
+	return nil
+}
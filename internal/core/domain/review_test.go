@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reviews(decisions ...ReviewDecision) []Review {
+	list := make([]Review, 0, len(decisions))
+
+	for _, decision := range decisions {
+		list = append(list, Review{Decision: decision})
+	}
+
+	return list
+}
+
+func TestReviewRule_Satisfied(t *testing.T) {
+	tests := []struct {
+		rule    ReviewRule
+		reviews []Review
+		want    bool
+	}{
+		{ReviewRuleAll, nil, true},
+		{ReviewRuleAll, reviews(ReviewApproved, ReviewApproved), true},
+		{ReviewRuleAll, reviews(ReviewApproved, ReviewPending), false},
+		{ReviewRuleAny, reviews(ReviewApproved, ReviewPending), true},
+		{ReviewRuleAny, reviews(ReviewApproved, ReviewRejected), false},
+		{ReviewRuleAny, reviews(ReviewPending), false},
+		{ReviewRuleMajority, reviews(ReviewApproved, ReviewApproved, ReviewRejected), true},
+		{ReviewRuleMajority, reviews(ReviewApproved, ReviewPending), false},
+		{ReviewRuleNone, reviews(ReviewRejected), true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.rule.Satisfied(tt.reviews), "%s %v", tt.rule, tt.reviews)
+	}
+
+	assert.ErrorIs(t, ReviewRule("quorum").Validate(), ErrInvalidReviewRule)
+}
+
+func TestReview_Decide(t *testing.T) {
+	now := time.Date(2026, 4, 2, 9, 30, 0, 0, time.UTC)
+	review := Review{Decision: ReviewPending}
+
+	assert.ErrorIs(t, review.Decide(ReviewPending, "", now), ErrInvalidReviewDecision)
+	assert.ErrorIs(t, review.Decide(ReviewRejected, "  ", now), ErrReviewCommentRequired)
+
+	require.NoError(t, review.Decide(ReviewRejected, " Needs tests ", now))
+	assert.Equal(t, ReviewRejected, review.Decision)
+	assert.Equal(t, "Needs tests", *review.Comment)
+	assert.Equal(t, now, *review.DecidedAt)
+
+	// Approving without a comment drops the earlier one
+	require.NoError(t, review.Decide(ReviewApproved, "", now.Add(time.Hour)))
+	assert.Equal(t, ReviewApproved, review.Decision)
+	assert.Nil(t, review.Comment)
+}
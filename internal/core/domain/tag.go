@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+const MaxTagNameLength = 50
+
+var (
+	ErrTagNotFound    = errors.New("tag not found")
+	ErrTagExists      = errors.New("tag already exists")
+	ErrInvalidTagName = errors.New("invalid tag name")
+	ErrTagMergeSelf   = errors.New("cannot merge a tag into itself")
+)
+
+// Tag labels todos. Names are unique per user, ignoring case.
+type Tag struct {
+	ID        int
+	UUID      uuid.UUID
+	UserId    int
+	Name      string
+	TodoCount int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (t *Tag) BelongsToUser(userId int) bool {
+	return t.UserId == userId
+}
+
+// NormalizeTagName trims the name and drops a leading '#', so "#work" and
+// "work" refer to the same tag
+func NormalizeTagName(name string) (string, error) {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "#")
+
+	if name == "" || len(name) > MaxTagNameLength {
+		return "", fmt.Errorf("%w: must have between 1 and %d characters", ErrInvalidTagName, MaxTagNameLength)
+	}
+
+	for _, r := range name {
+		if unicode.IsSpace(r) || r == ',' || r == '#' {
+			return "", fmt.Errorf("%w: %q cannot contain spaces, commas or '#'", ErrInvalidTagName, name)
+		}
+	}
+
+	return name, nil
+}
+
+// NormalizeTagNames normalizes every name and drops case-insensitive duplicates,
+// keeping the first spelling
+func NormalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+
+	for _, name := range names {
+		normalized, err := NormalizeTagName(name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		key := strings.ToLower(normalized)
+
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		result = append(result, normalized)
+	}
+
+	return result, nil
+}
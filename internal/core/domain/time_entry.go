@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTimeEntryNotFound = errors.New("time entry not found")
+	ErrTimerRunning      = errors.New("a timer is already running, stop it first")
+	ErrNoTimerRunning    = errors.New("no timer is running")
+	ErrInvalidTimeEntry  = errors.New("invalid time entry")
+)
+
+// TimeEntry is time a user spent on a todo. A running timer has no EndedAt,
+// Seconds is set once it stops. The todo and its project are loaded for
+// display and reports.
+type TimeEntry struct {
+	ID          int
+	UUID        uuid.UUID
+	TodoId      int
+	TodoUUID    uuid.UUID  `db:"todo_uuid"`
+	TodoTitle   string     `db:"todo_title"`
+	ProjectUUID *uuid.UUID `db:"project_uuid"`
+	ProjectName *string    `db:"project_name"`
+	UserId      int
+	UserUUID    uuid.UUID `db:"user_uuid"`
+	Note        string    `validate:"max=1000"`
+	StartedAt   time.Time
+	EndedAt     *time.Time
+	Seconds     int
+	Tags        []string // of the todo, only loaded for reports
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (e *TimeEntry) IsRunning() bool {
+	return e.EndedAt == nil
+}
+
+// Duration is the tracked time, up to now for a running timer
+func (e *TimeEntry) Duration(now time.Time) time.Duration {
+	if e.EndedAt == nil {
+		if now.Before(e.StartedAt) {
+			return 0
+		}
+
+		return now.Sub(e.StartedAt)
+	}
+
+	return time.Duration(e.Seconds) * time.Second
+}
+
+// Stop ends a running timer at the given time
+func (e *TimeEntry) Stop(at time.Time) error {
+	if !e.IsRunning() {
+		return ErrNoTimerRunning
+	}
+
+	e.EndedAt = &at
+
+	return e.Validate(at)
+}
+
+// Validate checks the entry neither starts nor ends after now and, once
+// stopped, does not end before it starts. Seconds follows the times.
+func (e *TimeEntry) Validate(now time.Time) error {
+	if e.StartedAt.IsZero() {
+		return fmt.Errorf("%w: started_at is required", ErrInvalidTimeEntry)
+	}
+
+	if e.StartedAt.After(now) {
+		return fmt.Errorf("%w: started_at cannot be in the future", ErrInvalidTimeEntry)
+	}
+
+	if e.EndedAt == nil {
+		e.Seconds = 0
+		return nil
+	}
+
+	if e.EndedAt.After(now) {
+		return fmt.Errorf("%w: ended_at cannot be in the future", ErrInvalidTimeEntry)
+	}
+
+	if e.EndedAt.Before(e.StartedAt) {
+		return fmt.Errorf("%w: ended_at cannot be before started_at", ErrInvalidTimeEntry)
+	}
+
+	e.Seconds = int(e.EndedAt.Sub(e.StartedAt) / time.Second)
+
+	return nil
+}
+
+// TimeEntryChange is an edit to an entry, nil fields are left untouched.
+// Setting EndedAt on a running timer stops it.
+type TimeEntryChange struct {
+	StartedAt *time.Time
+	EndedAt   *time.Time
+	Note      *string
+}
+
+func (c TimeEntryChange) Apply(entry *TimeEntry, now time.Time) error {
+	if c.StartedAt != nil {
+		entry.StartedAt = c.StartedAt.UTC()
+	}
+
+	if c.EndedAt != nil {
+		ended := c.EndedAt.UTC()
+		entry.EndedAt = &ended
+	}
+
+	if c.Note != nil {
+		entry.Note = *c.Note
+	}
+
+	return entry.Validate(now)
+}
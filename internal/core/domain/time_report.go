@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type TimeReportGroup string
+
+const (
+	TimeReportByTodo    TimeReportGroup = "todo"
+	TimeReportByTag     TimeReportGroup = "tag"
+	TimeReportByProject TimeReportGroup = "project"
+	TimeReportByDay     TimeReportGroup = "day"
+)
+
+// MaxTimeReportRange bounds how many entries a report loads
+const MaxTimeReportRange = 366 * 24 * time.Hour
+
+var ErrInvalidTimeReport = errors.New("invalid time report")
+
+func ParseTimeReportGroup(value string) (TimeReportGroup, error) {
+	switch group := TimeReportGroup(strings.ToLower(strings.TrimSpace(value))); group {
+	case "":
+		return TimeReportByTodo, nil
+	case TimeReportByTodo, TimeReportByTag, TimeReportByProject, TimeReportByDay:
+		return group, nil
+	default:
+		return "", fmt.Errorf("%w: group must be todo, tag, project or day", ErrInvalidTimeReport)
+	}
+}
+
+// TimeReportFilter picks the time tracked within [From, To). Days are cut
+// at midnight in Location.
+type TimeReportFilter struct {
+	Group    TimeReportGroup
+	From     time.Time
+	To       time.Time
+	Location *time.Location
+}
+
+func (f TimeReportFilter) Validate() error {
+	if !f.To.After(f.From) {
+		return fmt.Errorf("%w: to must be after from", ErrInvalidTimeReport)
+	}
+
+	if f.To.Sub(f.From) > MaxTimeReportRange {
+		return fmt.Errorf("%w: a report covers at most 366 days", ErrInvalidTimeReport)
+	}
+
+	return nil
+}
+
+// TimeReportRow is the time tracked under one todo, tag, project or day. Key
+// is empty for entries without a tag or a project.
+type TimeReportRow struct {
+	Key     string
+	Label   string
+	Seconds int
+	Entries int
+}
+
+type TimeReport struct {
+	Group   TimeReportGroup
+	From    time.Time
+	To      time.Time
+	Seconds int
+	Rows    []TimeReportRow
+}
+
+// BuildTimeReport sums entries within the filter's range, a running timer
+// counts up to now. An entry counts for each tag of its todo, so the rows
+// of a tag report can add up to more than the total. Days come in order,
+// other rows with the most time first.
+func BuildTimeReport(entries []TimeEntry, filter TimeReportFilter, now time.Time) TimeReport {
+	report := TimeReport{Group: filter.Group, From: filter.From, To: filter.To, Rows: []TimeReportRow{}}
+
+	location := filter.Location
+
+	if location == nil {
+		location = time.UTC
+	}
+
+	rows := map[string]*TimeReportRow{}
+
+	add := func(key string, label string, seconds int) {
+		row, ok := rows[key]
+
+		if !ok {
+			row = &TimeReportRow{Key: key, Label: label}
+			rows[key] = row
+		}
+
+		row.Seconds += seconds
+		row.Entries++
+	}
+
+	for _, entry := range entries {
+		start, end := entry.StartedAt, now
+
+		if entry.EndedAt != nil {
+			end = *entry.EndedAt
+		}
+
+		if start.Before(filter.From) {
+			start = filter.From
+		}
+
+		if end.After(filter.To) {
+			end = filter.To
+		}
+
+		if !end.After(start) {
+			continue
+		}
+
+		seconds := int(end.Sub(start) / time.Second)
+		report.Seconds += seconds
+
+		switch filter.Group {
+		case TimeReportByTag:
+			if len(entry.Tags) == 0 {
+				add("", "Untagged", seconds)
+			}
+
+			for _, tag := range entry.Tags {
+				add(tag, tag, seconds)
+			}
+		case TimeReportByProject:
+			if entry.ProjectUUID == nil {
+				add("", "No project", seconds)
+				break
+			}
+
+			name := ""
+
+			if entry.ProjectName != nil {
+				name = *entry.ProjectName
+			}
+
+			add(entry.ProjectUUID.String(), name, seconds)
+		case TimeReportByDay:
+			// An entry running past midnight counts for both days
+			for day := start.In(location); day.Before(end); {
+				year, month, date := day.Date()
+				next := time.Date(year, month, date+1, 0, 0, 0, 0, location)
+
+				until := end
+
+				if next.Before(end) {
+					until = next
+				}
+
+				key := day.Format(time.DateOnly)
+				add(key, key, int(until.Sub(day)/time.Second))
+
+				day = next
+			}
+		default:
+			add(entry.TodoUUID.String(), entry.TodoTitle, seconds)
+		}
+	}
+
+	for _, row := range rows {
+		report.Rows = append(report.Rows, *row)
+	}
+
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+
+		if filter.Group != TimeReportByDay && a.Seconds != b.Seconds {
+			return a.Seconds > b.Seconds
+		}
+
+		return a.Key < b.Key
+	})
+
+	return report
+}
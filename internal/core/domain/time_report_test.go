@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func entry(todo uuid.UUID, title string, start time.Time, minutes int, tags ...string) TimeEntry {
+	end := start.Add(time.Duration(minutes) * time.Minute)
+
+	return TimeEntry{TodoUUID: todo, TodoTitle: title, StartedAt: start, EndedAt: &end, Seconds: minutes * 60, Tags: tags}
+}
+
+func TestBuildTimeReport(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	report, billing := uuid.New(), uuid.New()
+
+	entries := []TimeEntry{
+		entry(report, "Report", day.Add(9*time.Hour), 90, "client", "writing"),
+		entry(billing, "Billing", day.Add(14*time.Hour), 30, "client"),
+		// Runs past midnight into the next day
+		entry(report, "Report", day.Add(23*time.Hour), 120),
+		// Starts before the range, only its last half hour counts
+		entry(billing, "Billing", day.Add(-30*time.Minute), 60),
+	}
+
+	filter := TimeReportFilter{Group: TimeReportByTodo, From: day, To: day.AddDate(0, 0, 7), Location: time.UTC}
+	now := day.AddDate(0, 0, 7)
+
+	byTodo := BuildTimeReport(entries, filter, now)
+
+	require.Len(t, byTodo.Rows, 2)
+	assert.Equal(t, (90+30+120+30)*60, byTodo.Seconds)
+	assert.Equal(t, TimeReportRow{Key: report.String(), Label: "Report", Seconds: 210 * 60, Entries: 2}, byTodo.Rows[0])
+	assert.Equal(t, TimeReportRow{Key: billing.String(), Label: "Billing", Seconds: 60 * 60, Entries: 2}, byTodo.Rows[1])
+
+	filter.Group = TimeReportByTag
+	byTag := BuildTimeReport(entries, filter, now)
+
+	require.Len(t, byTag.Rows, 3)
+	assert.Equal(t, "", byTag.Rows[0].Key)
+	assert.Equal(t, "Untagged", byTag.Rows[0].Label)
+	assert.Equal(t, 150*60, byTag.Rows[0].Seconds)
+	assert.Equal(t, "client", byTag.Rows[1].Key)
+	assert.Equal(t, 120*60, byTag.Rows[1].Seconds)
+	assert.Equal(t, "writing", byTag.Rows[2].Key)
+
+	filter.Group = TimeReportByDay
+	byDay := BuildTimeReport(entries, filter, now)
+
+	require.Len(t, byDay.Rows, 2)
+	assert.Equal(t, TimeReportRow{Key: "2024-03-04", Label: "2024-03-04", Seconds: (30 + 90 + 30 + 60) * 60, Entries: 4}, byDay.Rows[0])
+	assert.Equal(t, TimeReportRow{Key: "2024-03-05", Label: "2024-03-05", Seconds: 60 * 60, Entries: 1}, byDay.Rows[1])
+
+	// Days follow the report's time zone
+	filter.Location = time.FixedZone("UTC-3", -3*60*60)
+	byDay = BuildTimeReport(entries, filter, now)
+
+	require.Len(t, byDay.Rows, 2)
+	assert.Equal(t, "2024-03-03", byDay.Rows[0].Key)
+	assert.Equal(t, "2024-03-04", byDay.Rows[1].Key)
+	assert.Equal(t, byDay.Seconds, byDay.Rows[0].Seconds+byDay.Rows[1].Seconds)
+}
+
+func TestBuildTimeReport_RunningTimer(t *testing.T) {
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	project := uuid.New()
+	name := "Acme"
+
+	entries := []TimeEntry{
+		{TodoUUID: uuid.New(), StartedAt: start, ProjectUUID: &project, ProjectName: &name},
+		entry(uuid.New(), "Loose", start.Add(-2*time.Hour), 60),
+	}
+
+	filter := TimeReportFilter{Group: TimeReportByProject, From: start.Add(-24 * time.Hour), To: start.Add(24 * time.Hour)}
+
+	report := BuildTimeReport(entries, filter, start.Add(45*time.Minute))
+
+	require.Len(t, report.Rows, 2)
+	assert.Equal(t, TimeReportRow{Key: "", Label: "No project", Seconds: 3600, Entries: 1}, report.Rows[0])
+	assert.Equal(t, TimeReportRow{Key: project.String(), Label: "Acme", Seconds: 45 * 60, Entries: 1}, report.Rows[1])
+}
+
+func TestTimeEntry_Validate(t *testing.T) {
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+	later := now.Add(time.Hour)
+
+	valid := TimeEntry{StartedAt: before, EndedAt: &now}
+	require.NoError(t, valid.Validate(now))
+	assert.Equal(t, 3600, valid.Seconds)
+
+	running := TimeEntry{StartedAt: before}
+	require.NoError(t, running.Validate(now))
+	assert.Equal(t, 30*time.Minute, running.Duration(before.Add(30*time.Minute)))
+
+	for _, invalid := range []TimeEntry{
+		{},
+		{StartedAt: later},
+		{StartedAt: before, EndedAt: &later},
+		{StartedAt: now, EndedAt: &before},
+	} {
+		assert.ErrorIs(t, invalid.Validate(now), ErrInvalidTimeEntry)
+	}
+
+	assert.ErrorIs(t, valid.Stop(now), ErrNoTimerRunning)
+
+	_, err := ParseTimeReportGroup("week")
+	assert.ErrorIs(t, err, ErrInvalidTimeReport)
+}
@@ -7,86 +7,142 @@ import (
 	"github.com/google/uuid"
 )
 
-type TodoStatus int
+// TodoStatus is the key of a status in the todo's workflow. The default
+// workflow uses the four statuses below, projects may define their own.
+type TodoStatus string
 
 const (
-	TodoStatusPending TodoStatus = iota
-	TodoStatusInProgress
-	TodoStatusInReview
-	TodoStatusCompleted
+	TodoStatusPending    TodoStatus = "pending"
+	TodoStatusInProgress TodoStatus = "in_progress"
+	TodoStatusInReview   TodoStatus = "in_review"
+	TodoStatusCompleted  TodoStatus = "completed"
 )
 
+// TrashCursorSort tags cursors of the trash, most recently deleted first
+const TrashCursorSort = "trash:desc"
+
 type Todo struct {
 	ID          int
 	UUID        uuid.UUID
-	Title       string `validate:"min=3,max=255"`
-	Description string `validate:"max=255"`
-	Status      int    `validate:"oneof=0 1 2 3"`
-	Completed   bool   `validate:"boolean"`
+	Title       string     `validate:"min=3,max=255"`
+	Description string     `validate:"max=255"`
+	Status      TodoStatus // on update empty leaves the lifecycle untouched
+	Completed   bool       `validate:"boolean"` // the status is terminal, see MoveTo
+	CompletedAt *time.Time
+	DueAt       *time.Time
+	AllDay      bool
 	UserId      int
+	ProjectId   *int       // on update a pointer to 0 moves the todo out of its project
+	ProjectUUID *uuid.UUID `db:"project_uuid"`
+	Position    int        // order within the project
+	ItemsTotal  int        // checklist progress, computed when the todo is loaded
+	ItemsDone   int
+	Blocked     bool     // a blocker is still open, computed when the todo is loaded
+	Tracked     int      `db:"tracked_seconds"` // seconds of stopped time entries, computed when loaded
+	Tags        []string // nil leaves tags untouched on update, empty clears them
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	DeletedAt   *time.Time
+
+	// Version goes up with every write. On update and delete a non-zero
+	// version is the one the caller read, the write fails with
+	// ErrVersionConflict once the todo moved past it.
+	Version int
+
+	// Repetition follows an iCalendar RRULE anchored at RRuleStart. On update
+	// a nil RRule leaves it untouched and an empty one stops the repetition.
+	RRule            *string    `db:"rrule"`
+	RRuleStart       *time.Time `db:"rrule_start"`
+	RRuleExdates     *string    `db:"rrule_exdates"` // skipped occurrences, see ParseExdates
+	NextOccurrenceId *int       // set once completing the todo spawned the next one
+
+	// On update a nil StatusChange leaves status, completed and completed_at
+	// untouched
+	StatusChange *TodoStatusChange
+
+	// On update the user making the change, recorded on its revision
+	EditorId int
+
+	// On update clear the description or the due date, which their zero
+	// values above leave untouched
+	ClearDescription bool
+	ClearDue         bool
 }
 
 func (t *Todo) ToMap() map[string]interface{} {
 	return map[string]interface{}{
-		"id":          t.ID,
-		"uuid":        t.UUID,
-		"title":       t.Title,
-		"description": t.Description,
-		"status":      t.Status,
-		"completed":   t.Completed,
-		"user_id":     t.UserId,
-		"created_at":  t.CreatedAt,
-		"updated_at":  t.UpdatedAt,
+		"id":            t.ID,
+		"uuid":          t.UUID,
+		"title":         t.Title,
+		"description":   t.Description,
+		"status":        t.Status,
+		"completed":     t.Completed,
+		"completed_at":  t.CompletedAt,
+		"due_at":        t.DueAt,
+		"all_day":       t.AllDay,
+		"project_id":    t.ProjectId,
+		"position":      t.Position,
+		"user_id":       t.UserId,
+		"rrule":         t.RRule,
+		"rrule_start":   t.RRuleStart,
+		"rrule_exdates": t.RRuleExdates,
+		"created_at":    t.CreatedAt,
+		"updated_at":    t.UpdatedAt,
 	}
 }
 
+// ETag is the strong entity tag of the todo's current version. The version
+// also moves when its items, tracked time or blockers change.
+func (t *Todo) ETag() string {
+	return fmt.Sprintf(`"%d"`, t.Version)
+}
+
 func (t *Todo) IsDeleted() bool {
 	return t.DeletedAt != nil
 }
 
+// PurgeAt is when a deleted todo leaves the trash for good
+func (t *Todo) PurgeAt(retention time.Duration) *time.Time {
+	if t.DeletedAt == nil {
+		return nil
+	}
+
+	at := t.DeletedAt.Add(retention)
+
+	return &at
+}
+
+// IsDone reports whether the todo reached a terminal status
+func (t *Todo) IsDone() bool {
+	return t.Completed
+}
+
 func (t *Todo) BelongsToUser(userID int) bool {
 	return t.UserId == userID
 }
 
 func (t *Todo) StatusOrFallback(fallback ...string) string {
-	status := func() string {
-		defer func() {
-			if r := recover(); r != nil {
-			}
-		}()
-
-		return TodoStatus(t.Status).String()
-	}()
-
-	if status == "" {
-		if len(fallback) > 0 && fallback[0] != "" {
-			status = fallback[0]
-		} else {
-			status = "unknown"
-		}
+	if t.Status != "" {
+		return t.Status.String()
+	}
+
+	if len(fallback) > 0 && fallback[0] != "" {
+		return fallback[0]
 	}
 
-	return status
+	return "unknown"
 }
 
 func (t TodoStatus) String() string {
-	return []string{"pending", "in_progress", "in_review", "completed"}[t]
+	return string(t)
 }
 
-func (t *Todo) StatusToEnum(status string) (int, error) {
-	switch status {
-	case "pending", "":
-		return int(TodoStatusPending), nil
-	case "in_progress":
-		return int(TodoStatusInProgress), nil
-	case "in_review":
-		return int(TodoStatusInReview), nil
-	case "completed":
-		return int(TodoStatusCompleted), nil
-	default:
-		return -1, fmt.Errorf("invalid status: %s", status)
+// ParseTodoStatus checks that value is shaped like a status key, whether the
+// status exists depends on the workflow
+func ParseTodoStatus(value string) (TodoStatus, error) {
+	if !statusKeyPattern.MatchString(value) {
+		return "", fmt.Errorf("invalid status: %s", value)
 	}
+
+	return TodoStatus(value), nil
 }
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxBatchOperations caps the operations of a single batch
+const MaxBatchOperations = 100
+
+var (
+	ErrBatchEmpty       = errors.New("batch has no operations")
+	ErrBatchTooLarge    = fmt.Errorf("batch has more than %d operations", MaxBatchOperations)
+	ErrInvalidBatchMode = errors.New("invalid batch mode")
+	ErrInvalidBatchOp   = errors.New("invalid batch operation")
+
+	// ErrBatchAborted is reported for the operations of an atomic batch that
+	// were rolled back or never run because another one failed
+	ErrBatchAborted = errors.New("not applied because another operation failed")
+)
+
+type TodoBatchOp string
+
+const (
+	TodoBatchCreate   TodoBatchOp = "create"
+	TodoBatchUpdate   TodoBatchOp = "update"
+	TodoBatchDelete   TodoBatchOp = "delete"
+	TodoBatchComplete TodoBatchOp = "complete"
+)
+
+func ParseTodoBatchOp(value string) (TodoBatchOp, error) {
+	switch op := TodoBatchOp(value); op {
+	case TodoBatchCreate, TodoBatchUpdate, TodoBatchDelete, TodoBatchComplete:
+		return op, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrInvalidBatchOp, value)
+}
+
+// TodoBatchMode decides what happens to a batch when an operation fails.
+// Atomic batches are rolled back as a whole, best effort ones keep what
+// succeeded.
+type TodoBatchMode string
+
+const (
+	TodoBatchAtomic     TodoBatchMode = "atomic"
+	TodoBatchBestEffort TodoBatchMode = "best_effort"
+)
+
+// ParseTodoBatchMode defaults to atomic
+func ParseTodoBatchMode(value string) (TodoBatchMode, error) {
+	switch mode := TodoBatchMode(value); mode {
+	case "":
+		return TodoBatchAtomic, nil
+	case TodoBatchAtomic, TodoBatchBestEffort:
+		return mode, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrInvalidBatchMode, value)
+}
+
+// TodoOperation is one operation of a batch. Todo carries the fields to
+// create or update, the other operations only use its UUID.
+type TodoOperation struct {
+	Op   TodoBatchOp
+	Todo Todo
+
+	// Force completes the todo even while todos blocking it are open
+	Force bool
+}
+
+// TodoOperationResult is the outcome of an operation, Todo is set when it
+// succeeded and left a todo to show
+type TodoOperationResult struct {
+	Index int
+	Op    TodoBatchOp
+	Todo  *Todo
+	Err   error
+}
+
+// TodoBatchResult lists the outcome of every operation in order. Committed
+// is false when nothing of the batch was kept.
+type TodoBatchResult struct {
+	Mode      TodoBatchMode
+	Committed bool
+	Results   []TodoOperationResult
+}
+
+// Failed counts the operations that did not succeed
+func (r TodoBatchResult) Failed() int {
+	failed := 0
+
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+
+	return failed
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrDependencySelf     = errors.New("a todo cannot block itself")
+	ErrDependencyCycle    = errors.New("the blocker already depends on the todo, the dependency would close a cycle")
+	ErrDependencyExists   = errors.New("the todo is already blocked by this blocker")
+	ErrDependencyNotFound = errors.New("dependency not found")
+	ErrTodoBlocked        = errors.New("todos blocking this one are still open")
+)
+
+// TodoDependency says the todo cannot be completed before its blocker
+type TodoDependency struct {
+	TodoUUID    uuid.UUID
+	BlockerUUID uuid.UUID
+}
+
+// DependencyGraph is a project's todos with the dependencies between them.
+// Todos of other projects show up when they block or are blocked by one of
+// the project's todos.
+type DependencyGraph struct {
+	Todos        []Todo
+	Dependencies []TodoDependency
+}
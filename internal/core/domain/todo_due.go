@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// DueFilter selects todos by where their due date falls relative to now
+type DueFilter string
+
+const (
+	DueOverdue  DueFilter = "overdue"
+	DueToday    DueFilter = "due_today"
+	DueThisWeek DueFilter = "due_this_week"
+	DueNone     DueFilter = "no_due_date"
+)
+
+func ParseDueFilter(value string) (DueFilter, error) {
+	switch due := DueFilter(value); due {
+	case "", DueOverdue, DueToday, DueThisWeek, DueNone:
+		return due, nil
+	default:
+		return "", fmt.Errorf("invalid due filter: %s", value)
+	}
+}
+
+// StartOfDay truncates t to midnight UTC, days are always computed in UTC
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// StartOfNextWeek returns the midnight of the next monday after t
+func StartOfNextWeek(t time.Time) time.Time {
+	today := StartOfDay(t)
+	daysUntilMonday := (8 - int(today.Weekday())) % 7
+
+	if daysUntilMonday == 0 {
+		daysUntilMonday = 7
+	}
+
+	return today.AddDate(0, 0, daysUntilMonday)
+}
+
+// NormalizeDue stores due dates in UTC, all-day todos only keep their date
+func (t *Todo) NormalizeDue() {
+	if t.DueAt == nil {
+		t.AllDay = false
+		return
+	}
+
+	due := t.DueAt.UTC()
+
+	if t.AllDay {
+		due = StartOfDay(due)
+	}
+
+	t.DueAt = &due
+}
+
+// DueDeadline is the instant after which the todo is overdue. All-day
+// todos stay on time until their whole day has passed.
+func (t *Todo) DueDeadline() *time.Time {
+	if t.DueAt == nil {
+		return nil
+	}
+
+	deadline := *t.DueAt
+
+	if t.AllDay {
+		deadline = StartOfDay(deadline).AddDate(0, 0, 1)
+	}
+
+	return &deadline
+}
+
+func (t *Todo) IsOverdue(now time.Time) bool {
+	deadline := t.DueDeadline()
+
+	if deadline == nil || t.Completed {
+		return false
+	}
+
+	return !now.Before(*deadline)
+}
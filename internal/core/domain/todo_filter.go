@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type TodoSortField string
+
+const (
+	TodoSortCreatedAt TodoSortField = "created_at"
+	TodoSortUpdatedAt TodoSortField = "updated_at"
+	TodoSortTitle     TodoSortField = "title"
+	TodoSortStatus    TodoSortField = "status"
+	TodoSortPosition  TodoSortField = "position"
+)
+
+// NoProject filters the todos that are not in any project
+const NoProject = "none"
+
+type TodoSort struct {
+	Field TodoSortField
+	Desc  bool
+}
+
+// TodoFilter narrows down and orders the todos returned by a list query
+type TodoFilter struct {
+	Statuses      []TodoStatus
+	Completed     *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedSince  *time.Time
+	Due           DueFilter
+	AnyTags       []string // at least one of these tags
+	AllTags       []string // every one of these tags
+	Project       string   // project uuid, or NoProject
+	Owner         int      // user id, only the todos this user created
+	Sort          TodoSort
+
+	// IncludeArchived also lists todos from archived projects. Filtering by
+	// a single project always includes its todos.
+	IncludeArchived bool
+
+	// Now anchors relative filters such as Due, defaults to the current time
+	Now time.Time
+}
+
+func DefaultTodoSort() TodoSort {
+	return TodoSort{Field: TodoSortCreatedAt, Desc: true}
+}
+
+// ParseTodoSort accepts "field", "field:asc" or "field:desc". Fields sort
+// descending by default, except position which reads top to bottom.
+func ParseTodoSort(value string) (TodoSort, error) {
+	if value == "" {
+		return DefaultTodoSort(), nil
+	}
+
+	field, direction, _ := strings.Cut(strings.ToLower(value), ":")
+
+	sort := TodoSort{Field: TodoSortField(field), Desc: field != string(TodoSortPosition)}
+
+	switch sort.Field {
+	case TodoSortCreatedAt, TodoSortUpdatedAt, TodoSortTitle, TodoSortStatus, TodoSortPosition:
+	default:
+		return TodoSort{}, fmt.Errorf("invalid sort field: %s", field)
+	}
+
+	switch direction {
+	case "":
+	case "desc":
+		sort.Desc = true
+	case "asc":
+		sort.Desc = false
+	default:
+		return TodoSort{}, fmt.Errorf("invalid sort direction: %s", direction)
+	}
+
+	return sort, nil
+}
+
+func (s TodoSort) IsZero() bool {
+	return s.Field == ""
+}
+
+func (s TodoSort) Direction() string {
+	if s.Desc {
+		return "desc"
+	}
+
+	return "asc"
+}
+
+func (s TodoSort) String() string {
+	return string(s.Field) + ":" + s.Direction()
+}
+
+// SortValue returns the value of the given sort field as stored in a cursor
+func (t *Todo) SortValue(field TodoSortField) string {
+	switch field {
+	case TodoSortUpdatedAt:
+		return t.UpdatedAt.Format(time.RFC3339Nano)
+	case TodoSortTitle:
+		return t.Title
+	case TodoSortStatus:
+		return string(t.Status)
+	case TodoSortPosition:
+		return strconv.Itoa(t.Position)
+	default:
+		return t.CreatedAt.Format(time.RFC3339Nano)
+	}
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxImportRows caps how many todos one import may carry
+const MaxImportRows = 5000
+
+var (
+	ErrImportEmpty       = errors.New("import has no todos")
+	ErrImportTooLarge    = fmt.Errorf("import may have at most %d todos", MaxImportRows)
+	ErrImportTitle       = errors.New("title must have between 3 and 255 characters")
+	ErrImportDescription = errors.New("description must have at most 1000 characters")
+)
+
+// ImportedTodo is a todo as an import file describes it, before it is
+// checked. Line is where it starts in the file and Err why it could not be
+// read at all.
+type ImportedTodo struct {
+	Line        int
+	Title       string
+	Description string
+	Status      string // as the file spells it, see ParseImportStatus
+	Completed   bool   // the file only says whether the todo is done
+	CompletedAt *time.Time
+	DueAt       *time.Time
+	AllDay      bool
+	Tags        []string
+	RRule       string
+	CreatedAt   *time.Time
+	Err         error
+}
+
+// Validate checks what the import can tell without a workflow
+func (t ImportedTodo) Validate() error {
+	if t.Err != nil {
+		return t.Err
+	}
+
+	if length := len([]rune(strings.TrimSpace(t.Title))); length < 3 || length > 255 {
+		return ErrImportTitle
+	}
+
+	if len([]rune(t.Description)) > 1000 {
+		return ErrImportDescription
+	}
+
+	return nil
+}
+
+type TodoImportOptions struct {
+	DryRun bool
+
+	// SkipInvalid imports the valid todos when others fail, otherwise one
+	// failing todo keeps the whole import out
+	SkipInvalid bool
+}
+
+// TodoImportRow is the outcome for one imported todo. Todo is what was or,
+// on a dry run, would be created.
+type TodoImportRow struct {
+	Line int
+	Todo Todo
+	Err  error
+}
+
+type TodoImport struct {
+	DryRun   bool
+	Imported bool
+	Rows     []TodoImportRow
+}
+
+func (i TodoImport) Failed() int {
+	failed := 0
+
+	for _, row := range i.Rows {
+		if row.Err != nil {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+// importStatuses maps the statuses other tools use onto the default workflow
+var importStatuses = map[string]TodoStatus{
+	"pending":      TodoStatusPending,
+	"todo":         TodoStatusPending,
+	"open":         TodoStatusPending,
+	"new":          TodoStatusPending,
+	"not_started":  TodoStatusPending,
+	"needs_action": TodoStatusPending,
+	"in_progress":  TodoStatusInProgress,
+	"in_process":   TodoStatusInProgress,
+	"doing":        TodoStatusInProgress,
+	"started":      TodoStatusInProgress,
+	"active":       TodoStatusInProgress,
+	"in_review":    TodoStatusInReview,
+	"review":       TodoStatusInReview,
+	"completed":    TodoStatusCompleted,
+	"complete":     TodoStatusCompleted,
+	"done":         TodoStatusCompleted,
+	"closed":       TodoStatusCompleted,
+	"finished":     TodoStatusCompleted,
+	"cancelled":    TodoStatusCompleted,
+	"canceled":     TodoStatusCompleted,
+}
+
+// ParseImportStatus maps a status read from an import, ignoring case and
+// whether words are split by spaces, dashes or underscores. Statuses it does
+// not know are returned as keys, for the workflow to check. Empty means the
+// file has none.
+func ParseImportStatus(value string) TodoStatus {
+	key := strings.ToLower(strings.TrimSpace(value))
+	key = strings.NewReplacer(" ", "_", "-", "_").Replace(key)
+
+	if status, ok := importStatuses[key]; ok {
+		return status
+	}
+
+	return TodoStatus(key)
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrTodoItemNotFound = errors.New("todo item not found")
+
+// TodoItemAppend places a new item after the last one on its todo
+const TodoItemAppend = -1
+
+// TodoItem is a checklist entry under a todo
+type TodoItem struct {
+	ID        int
+	UUID      uuid.UUID
+	TodoId    int
+	Title     string `validate:"min=1,max=255"`
+	Done      bool
+	Position  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (i *TodoItem) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"title":      i.Title,
+		"done":       i.Done,
+		"position":   i.Position,
+		"updated_at": i.UpdatedAt,
+	}
+}
+
+// TodoItemChanges carries a partial item update, nil fields stay untouched
+type TodoItemChanges struct {
+	Title    *string
+	Done     *bool
+	Position *int
+
+	// CompleteParent completes the todo once every item on it is done
+	CompleteParent bool
+}
+
+// IsChecklistDone reports whether a todo has items and all of them are done
+func (t *Todo) IsChecklistDone() bool {
+	return t.ItemsTotal > 0 && t.ItemsDone == t.ItemsTotal
+}
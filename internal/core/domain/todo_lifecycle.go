@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrStatusConflict = errors.New("completed contradicts status")
+
+// TodoStatusChange is the lifecycle move a request asks for. Status is the
+// source of truth and completed is only a shorthand: true moves the todo to
+// the workflow's done status, false reopens a todo in a terminal status and
+// leaves the others where they are.
+type TodoStatusChange struct {
+	Status    *TodoStatus
+	Completed *bool
+
+	// Force completes the todo even while todos blocking it are open
+	Force bool
+}
+
+// Resolve finds the status the change leads to from current
+func (c TodoStatusChange) Resolve(workflow Workflow, current TodoStatus) (WorkflowStatus, error) {
+	from, ok := workflow.Status(current)
+
+	if !ok {
+		from = workflow.Initial()
+	}
+
+	if c.Status != nil {
+		status, ok := workflow.Status(*c.Status)
+
+		if !ok {
+			return from, fmt.Errorf("%w: %s", ErrUnknownStatus, *c.Status)
+		}
+
+		if c.Completed != nil && *c.Completed != status.Terminal {
+			return from, ErrStatusConflict
+		}
+
+		return status, nil
+	}
+
+	switch {
+	case c.Completed == nil || *c.Completed == from.Terminal:
+		return from, nil
+	case *c.Completed:
+		return workflow.Done(), nil
+	default:
+		return workflow.Initial(), nil
+	}
+}
+
+// Transition applies a change under the rules of the workflow, refusing moves
+// it does not allow with a *TransitionError
+func (t *Todo) Transition(workflow Workflow, change TodoStatusChange, now time.Time) error {
+	next, err := change.Resolve(workflow, t.Status)
+
+	if err != nil {
+		return err
+	}
+
+	if !workflow.Allows(t.Status, next.Key) {
+		return &TransitionError{From: t.Status, To: next.Key}
+	}
+
+	t.MoveTo(next, now)
+
+	return nil
+}
+
+// MoveTo sets the status and keeps Completed and CompletedAt in step with it.
+// CompletedAt keeps its first value while the todo stays in terminal statuses.
+func (t *Todo) MoveTo(status WorkflowStatus, now time.Time) {
+	t.Status = status.Key
+	t.Completed = status.Terminal
+
+	switch {
+	case !t.Completed:
+		t.CompletedAt = nil
+	case t.CompletedAt == nil:
+		completedAt := now
+		t.CompletedAt = &completedAt
+	}
+}
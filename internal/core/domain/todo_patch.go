@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Optional is a field of a partial update. Set is false when the field was
+// left out, a nil Value with Set means it was sent as null.
+type Optional[T any] struct {
+	Set   bool
+	Value *T
+}
+
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{Set: true, Value: &value}
+}
+
+func Null[T any]() Optional[T] {
+	return Optional[T]{Set: true}
+}
+
+// IsNull reports a field sent as null
+func (o Optional[T]) IsNull() bool {
+	return o.Set && o.Value == nil
+}
+
+// TodoPatch is a partial update of a todo, following JSON merge patch: fields
+// left out stay untouched and null clears them. Title, status and completed
+// cannot be cleared.
+type TodoPatch struct {
+	Title       Optional[string]
+	Description Optional[string]
+	Status      Optional[TodoStatus]
+	Completed   Optional[bool]
+	DueAt       Optional[time.Time]
+	AllDay      Optional[bool]
+	Tags        Optional[[]string]
+	ProjectUUID Optional[uuid.UUID]
+	RRule       Optional[string]
+
+	// Version the patch was made against, 0 applies it to any version
+	Version int
+
+	// Force completes the todo even while todos blocking it are open
+	Force bool
+}
+
+// Update turns the patch into the todo UpdateByUUID applies to current. The
+// due date and all_day are only stored together, so patching either sends
+// both, and all_day is dropped from a todo left without a due date.
+func (p TodoPatch) Update(current Todo) Todo {
+	todo := Todo{UUID: current.UUID, Version: p.Version}
+
+	if p.Title.Value != nil {
+		todo.Title = *p.Title.Value
+	}
+
+	if p.Description.Set {
+		if p.Description.Value == nil || *p.Description.Value == "" {
+			todo.ClearDescription = true
+		} else {
+			todo.Description = *p.Description.Value
+		}
+	}
+
+	if p.DueAt.Set || p.AllDay.Set {
+		due, allDay := current.DueAt, current.AllDay
+
+		if p.DueAt.Set {
+			due = p.DueAt.Value
+		}
+
+		if p.AllDay.Set {
+			allDay = p.AllDay.Value != nil && *p.AllDay.Value
+		}
+
+		if due == nil {
+			todo.ClearDue = current.DueAt != nil
+		} else {
+			value := *due
+			todo.DueAt = &value
+			todo.AllDay = allDay
+		}
+	}
+
+	if p.Status.Value != nil || p.Completed.Value != nil {
+		todo.StatusChange = &TodoStatusChange{Status: p.Status.Value, Completed: p.Completed.Value, Force: p.Force}
+	}
+
+	if p.Tags.Set {
+		todo.Tags = []string{}
+
+		if p.Tags.Value != nil && *p.Tags.Value != nil {
+			todo.Tags = *p.Tags.Value
+		}
+	}
+
+	// uuid.Nil and an empty rule are how an update moves a todo out of its
+	// project and stops its repetition
+	if p.ProjectUUID.Set {
+		project := uuid.Nil
+
+		if p.ProjectUUID.Value != nil {
+			project = *p.ProjectUUID.Value
+		}
+
+		todo.ProjectUUID = &project
+	}
+
+	if p.RRule.Set {
+		rule := ""
+
+		if p.RRule.Value != nil {
+			rule = *p.RRule.Value
+		}
+
+		todo.RRule = &rule
+	}
+
+	return todo
+}
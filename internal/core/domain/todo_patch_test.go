@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTodoPatch_Update(t *testing.T) {
+	due := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+	current := Todo{UUID: uuid.New(), Title: "Write guide", Description: "Draft", DueAt: &due, AllDay: true, Tags: []string{"docs"}}
+
+	// Fields left out stay untouched
+	assert.Equal(t, Todo{UUID: current.UUID}, TodoPatch{}.Update(current))
+
+	// Null clears what an update cannot clear with zero values
+	update := TodoPatch{
+		Description: Null[string](),
+		DueAt:       Null[time.Time](),
+		Tags:        Null[[]string](),
+		ProjectUUID: Null[uuid.UUID](),
+		RRule:       Null[string](),
+	}.Update(current)
+
+	assert.True(t, update.ClearDescription)
+	assert.True(t, update.ClearDue)
+	assert.Nil(t, update.DueAt)
+	assert.Equal(t, []string{}, update.Tags)
+	assert.Equal(t, uuid.Nil, *update.ProjectUUID)
+	assert.Equal(t, "", *update.RRule)
+	assert.Nil(t, update.StatusChange)
+
+	// An empty description clears it too
+	assert.True(t, TodoPatch{Description: Some("")}.Update(current).ClearDescription)
+
+	// all_day alone keeps the current due date
+	update = TodoPatch{AllDay: Some(false)}.Update(current)
+
+	assert.Equal(t, &due, update.DueAt)
+	assert.False(t, update.AllDay)
+	assert.False(t, update.ClearDue)
+
+	// A new due date keeps the current all_day
+	later := due.Add(24 * time.Hour)
+	update = TodoPatch{DueAt: Some(later)}.Update(current)
+
+	assert.Equal(t, later, *update.DueAt)
+	assert.True(t, update.AllDay)
+
+	// all_day on a todo without a due date has nothing to apply to
+	assert.Equal(t, Todo{}, TodoPatch{AllDay: Some(true)}.Update(Todo{}))
+
+	completed := false
+	update = TodoPatch{Title: Some("Write the guide"), Completed: Some(completed)}.Update(current)
+
+	assert.Equal(t, "Write the guide", update.Title)
+	assert.Equal(t, &TodoStatusChange{Completed: &completed}, update.StatusChange)
+}
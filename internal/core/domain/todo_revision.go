@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// RevisionCursorSort tags cursors of a todo's history, newest first
+const RevisionCursorSort = "revision:desc"
+
+// TodoSnapshot is the editable state of a todo at one point in time
+type TodoSnapshot struct {
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	Status       TodoStatus `json:"status"`
+	Completed    bool       `json:"completed"`
+	CompletedAt  *time.Time `json:"completed_at"`
+	DueAt        *time.Time `json:"due_at"`
+	AllDay       bool       `json:"all_day"`
+	ProjectUUID  *uuid.UUID `json:"project_uuid"`
+	Tags         []string   `json:"tags"`
+	RRule        *string    `json:"rrule"`
+	RRuleStart   *time.Time `json:"rrule_start"`
+	RRuleExdates *string    `json:"rrule_exdates"`
+}
+
+// FieldChange is the value a field had before a revision and the one after
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// TodoRevision records one update of a todo: who made it, the fields it
+// changed and the todo as it was before. Numbers count up from 1 per todo.
+type TodoRevision struct {
+	ID        int
+	UUID      uuid.UUID
+	TodoId    int
+	Number    int
+	UserId    *int // nil for updates made outside a user request
+	UserUUID  *uuid.UUID
+	UserName  *string
+	Changes   map[string]FieldChange
+	Snapshot  TodoSnapshot // the todo before the change, what reverting restores
+	CreatedAt time.Time
+}
+
+// Snapshot captures the editable fields, tags sorted the way they are listed
+func (t *Todo) Snapshot() TodoSnapshot {
+	tags := append([]string{}, t.Tags...)
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return strings.ToLower(tags[i]) < strings.ToLower(tags[j])
+	})
+
+	return TodoSnapshot{
+		Title:        t.Title,
+		Description:  t.Description,
+		Status:       t.Status,
+		Completed:    t.Completed,
+		CompletedAt:  t.CompletedAt,
+		DueAt:        t.DueAt,
+		AllDay:       t.AllDay,
+		ProjectUUID:  t.ProjectUUID,
+		Tags:         tags,
+		RRule:        t.RRule,
+		RRuleStart:   t.RRuleStart,
+		RRuleExdates: t.RRuleExdates,
+	}
+}
+
+// Diff lists the fields that differ in next, keyed by their json name
+func (s TodoSnapshot) Diff(next TodoSnapshot) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+
+	before := reflect.ValueOf(s)
+	after := reflect.ValueOf(next)
+
+	for i := 0; i < before.NumField(); i++ {
+		from := before.Field(i).Interface()
+		to := after.Field(i).Interface()
+
+		if sameValue(from, to) {
+			continue
+		}
+
+		changes[before.Type().Field(i).Tag.Get("json")] = FieldChange{From: from, To: to}
+	}
+
+	return changes
+}
+
+// sameValue compares snapshot fields, times by instant rather than location
+func sameValue(a interface{}, b interface{}) bool {
+	if at, ok := a.(*time.Time); ok {
+		bt := b.(*time.Time)
+
+		if at == nil || bt == nil {
+			return at == nil && bt == nil
+		}
+
+		return at.Equal(*bt)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
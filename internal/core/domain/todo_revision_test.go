@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTodoSnapshot_Diff(t *testing.T) {
+	due := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+	sameDue := due.In(time.FixedZone("BRT", -3*60*60))
+
+	before := (&Todo{Title: "Write guide", Status: TodoStatusPending, DueAt: &due, Tags: []string{"work", "Docs"}}).Snapshot()
+	after := (&Todo{Title: "Write guide", Status: TodoStatusPending, DueAt: &sameDue, Tags: []string{"docs", "work"}}).Snapshot()
+
+	assert.Equal(t, []string{"Docs", "work"}, before.Tags)
+	assert.Equal(t, map[string]FieldChange{
+		"tags": {From: []string{"Docs", "work"}, To: []string{"docs", "work"}},
+	}, before.Diff(after))
+
+	after.Title = "Write the guide"
+	after.DueAt = nil
+
+	changes := before.Diff(after)
+
+	assert.Len(t, changes, 3)
+	assert.Equal(t, FieldChange{From: "Write guide", To: "Write the guide"}, changes["title"])
+	assert.Equal(t, FieldChange{From: &due, To: (*time.Time)(nil)}, changes["due_at"])
+	assert.Empty(t, before.Diff(before))
+}
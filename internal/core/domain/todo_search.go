@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// TodoSearchSort is the sort key signed into search cursors
+const TodoSearchSort = "rank:desc"
+
+var ErrEmptySearchQuery = errors.New("search query must contain at least one word")
+
+// TodoSearchResult is a todo matched by a full-text search
+type TodoSearchResult struct {
+	Todo                 Todo
+	Rank                 float64
+	TitleHighlight       string
+	DescriptionHighlight string
+}
+
+// SearchTerms splits free text into lowercase words, dropping any punctuation
+// so the terms are safe to use in a MATCH expression
+func SearchTerms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return words
+}
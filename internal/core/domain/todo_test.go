@@ -60,4 +60,148 @@ func TestTodoStatus_String(t *testing.T) {
 			assert.Equal(t, tt.expected, tt.status.String())
 		})
 	}
+
+	t.Run("should fall back without a status instead of panicking", func(t *testing.T) {
+		todo := Todo{}
+
+		assert.Equal(t, "unknown", todo.StatusOrFallback())
+		assert.Equal(t, "pending", todo.StatusOrFallback("pending"))
+
+		todo.Status = "qa"
+		assert.Equal(t, "qa", todo.StatusOrFallback())
+	})
+}
+
+func TestParseTodoSort(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected TodoSort
+	}{
+		{"", TodoSort{Field: TodoSortCreatedAt, Desc: true}},
+		{"title", TodoSort{Field: TodoSortTitle, Desc: true}},
+		{"title:asc", TodoSort{Field: TodoSortTitle, Desc: false}},
+		{"updated_at:desc", TodoSort{Field: TodoSortUpdatedAt, Desc: true}},
+		{"STATUS:ASC", TodoSort{Field: TodoSortStatus, Desc: false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			sort, err := ParseTodoSort(tt.input)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, sort)
+		})
+	}
+
+	t.Run("should reject unknown fields and directions", func(t *testing.T) {
+		_, err := ParseTodoSort("user_id")
+		assert.Error(t, err)
+
+		_, err = ParseTodoSort("title:up")
+		assert.Error(t, err)
+	})
+}
+
+func TestTodo_IsOverdue(t *testing.T) {
+	now := time.Date(2026, 3, 11, 15, 0, 0, 0, time.UTC)
+
+	t.Run("should not be overdue without a due date", func(t *testing.T) {
+		todo := Todo{}
+
+		assert.False(t, todo.IsOverdue(now))
+	})
+
+	t.Run("should be overdue once the due time has passed", func(t *testing.T) {
+		due := now.Add(-time.Minute)
+		todo := Todo{DueAt: &due}
+
+		assert.True(t, todo.IsOverdue(now))
+
+		todo.Completed = true
+		assert.False(t, todo.IsOverdue(now))
+	})
+
+	t.Run("should keep all-day todos on time during their day", func(t *testing.T) {
+		due := time.Date(2026, 3, 11, 18, 30, 0, 0, time.UTC)
+		todo := Todo{DueAt: &due, AllDay: true}
+		todo.NormalizeDue()
+
+		assert.Equal(t, StartOfDay(now), *todo.DueAt)
+		assert.False(t, todo.IsOverdue(now))
+		assert.True(t, todo.IsOverdue(now.AddDate(0, 0, 1)))
+	})
+}
+
+func TestStartOfNextWeek(t *testing.T) {
+	wednesday := time.Date(2026, 3, 11, 15, 0, 0, 0, time.UTC)
+	sunday := time.Date(2026, 3, 15, 23, 0, 0, 0, time.UTC)
+	monday := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, monday, StartOfNextWeek(wednesday))
+	assert.Equal(t, monday, StartOfNextWeek(sunday))
+	assert.Equal(t, monday.AddDate(0, 0, 7), StartOfNextWeek(monday))
+}
+
+func TestTodoStatusChange_Resolve(t *testing.T) {
+	workflow := Workflow{Statuses: []WorkflowStatus{
+		{Key: TodoStatusPending},
+		{Key: TodoStatusInProgress},
+		{Key: TodoStatusInReview},
+		{Key: TodoStatusCompleted, Terminal: true},
+		{Key: "wont_do", Terminal: true},
+	}}
+
+	status := func(s TodoStatus) *TodoStatus { return &s }
+	flag := func(b bool) *bool { return &b }
+
+	tests := []struct {
+		name     string
+		change   TodoStatusChange
+		current  TodoStatus
+		expected TodoStatus
+	}{
+		{"nothing sent", TodoStatusChange{}, TodoStatusInReview, TodoStatusInReview},
+		{"back to pending", TodoStatusChange{Status: status(TodoStatusPending)}, TodoStatusInProgress, TodoStatusPending},
+		{"complete", TodoStatusChange{Completed: flag(true)}, TodoStatusInProgress, TodoStatusCompleted},
+		{"complete keeps other terminal statuses", TodoStatusChange{Completed: flag(true)}, "wont_do", "wont_do"},
+		{"reopen", TodoStatusChange{Completed: flag(false)}, TodoStatusCompleted, TodoStatusPending},
+		{"not completed stays put", TodoStatusChange{Completed: flag(false)}, TodoStatusInReview, TodoStatusInReview},
+		{"both agree", TodoStatusChange{Status: status("wont_do"), Completed: flag(true)}, TodoStatusPending, "wont_do"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := tt.change.Resolve(workflow, tt.current)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, next.Key)
+		})
+	}
+
+	t.Run("should reject a completed flag contradicting the status", func(t *testing.T) {
+		_, err := TodoStatusChange{Status: status(TodoStatusInProgress), Completed: flag(true)}.Resolve(workflow, TodoStatusPending)
+		assert.ErrorIs(t, err, ErrStatusConflict)
+	})
+
+	t.Run("should reject statuses outside the workflow", func(t *testing.T) {
+		_, err := TodoStatusChange{Status: status("qa")}.Resolve(workflow, TodoStatusPending)
+		assert.ErrorIs(t, err, ErrUnknownStatus)
+	})
+}
+
+func TestTodo_MoveTo(t *testing.T) {
+	now := time.Date(2026, 3, 11, 15, 0, 0, 0, time.UTC)
+	todo := Todo{}
+
+	todo.MoveTo(WorkflowStatus{Key: TodoStatusCompleted, Terminal: true}, now)
+	assert.True(t, todo.Completed)
+	assert.Equal(t, now, *todo.CompletedAt)
+
+	// Moving between terminal statuses keeps the first timestamp
+	todo.MoveTo(WorkflowStatus{Key: "wont_do", Terminal: true}, now.Add(time.Hour))
+	assert.Equal(t, now, *todo.CompletedAt)
+
+	todo.MoveTo(WorkflowStatus{Key: TodoStatusInProgress}, now)
+	assert.False(t, todo.Completed)
+	assert.Nil(t, todo.CompletedAt)
 }
@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrWorkflowNotFound  = errors.New("workflow not found")
+	ErrInvalidWorkflow   = errors.New("invalid workflow")
+	ErrUnknownStatus     = errors.New("status is not part of the workflow")
+	ErrIllegalTransition = errors.New("transition is not allowed by the workflow")
+	ErrStatusInUse       = errors.New("todos are still in a status the workflow drops")
+)
+
+const (
+	MaxWorkflowStatuses = 20
+	maxStatusNameLength = 64
+)
+
+var statusKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// TransitionError is returned when the workflow does not allow moving a todo
+// from one status to another. It matches ErrIllegalTransition.
+type TransitionError struct {
+	From TodoStatus
+	To   TodoStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot move a todo from %s to %s", e.From, e.To)
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrIllegalTransition
+}
+
+type WorkflowStatus struct {
+	ID         int
+	WorkflowId int
+	Key        TodoStatus
+	Name       string
+	Position   int
+	Terminal   bool // todos in a terminal status count as completed
+}
+
+type WorkflowTransition struct {
+	ID         int
+	WorkflowId int
+	From       TodoStatus `db:"from_key"`
+	To         TodoStatus `db:"to_key"`
+}
+
+// Workflow is the ordered list of statuses todos move through and the moves
+// allowed between them. Without transitions every move is allowed.
+type Workflow struct {
+	ID          int
+	UUID        uuid.UUID
+	ProjectId   *int // nil for the default workflow
+	Statuses    []WorkflowStatus
+	Transitions []WorkflowTransition
+	ReviewRule  ReviewRule // when reviewed todos may leave in_review for a terminal status
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (w *Workflow) IsDefault() bool {
+	return w.ProjectId == nil
+}
+
+// Validate checks the definition. The first status is where new todos start,
+// so it cannot be terminal, and at least one status must be.
+func (w *Workflow) Validate() error {
+	if len(w.Statuses) == 0 || len(w.Statuses) > MaxWorkflowStatuses {
+		return fmt.Errorf("%w: between 1 and %d statuses are required", ErrInvalidWorkflow, MaxWorkflowStatuses)
+	}
+
+	seen := make(map[TodoStatus]bool, len(w.Statuses))
+	terminal := false
+
+	for _, status := range w.Statuses {
+		if !statusKeyPattern.MatchString(string(status.Key)) {
+			return fmt.Errorf("%w: invalid status key %q", ErrInvalidWorkflow, status.Key)
+		}
+
+		if status.Name == "" || len(status.Name) > maxStatusNameLength {
+			return fmt.Errorf("%w: status %s needs a name of at most %d characters", ErrInvalidWorkflow, status.Key, maxStatusNameLength)
+		}
+
+		if seen[status.Key] {
+			return fmt.Errorf("%w: duplicate status %s", ErrInvalidWorkflow, status.Key)
+		}
+
+		seen[status.Key] = true
+		terminal = terminal || status.Terminal
+	}
+
+	if w.Statuses[0].Terminal {
+		return fmt.Errorf("%w: the first status cannot be terminal", ErrInvalidWorkflow)
+	}
+
+	if !terminal {
+		return fmt.Errorf("%w: at least one status must be terminal", ErrInvalidWorkflow)
+	}
+
+	if err := w.ReviewRule.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWorkflow, err)
+	}
+
+	for _, transition := range w.Transitions {
+		if !seen[transition.From] || !seen[transition.To] {
+			return fmt.Errorf("%w: transition %s -> %s uses an unknown status", ErrInvalidWorkflow, transition.From, transition.To)
+		}
+
+		if transition.From == transition.To {
+			return fmt.Errorf("%w: transition %s -> %s goes nowhere", ErrInvalidWorkflow, transition.From, transition.To)
+		}
+	}
+
+	return nil
+}
+
+// Status looks a status up by key
+func (w *Workflow) Status(key TodoStatus) (WorkflowStatus, bool) {
+	for _, status := range w.Statuses {
+		if status.Key == key {
+			return status, true
+		}
+	}
+
+	return WorkflowStatus{}, false
+}
+
+// Initial is the status new todos start in
+func (w *Workflow) Initial() WorkflowStatus {
+	return w.Statuses[0]
+}
+
+// Done is the status completing a todo moves it to, the first terminal one
+func (w *Workflow) Done() WorkflowStatus {
+	for _, status := range w.Statuses {
+		if status.Terminal {
+			return status
+		}
+	}
+
+	return w.Statuses[len(w.Statuses)-1]
+}
+
+func (w *Workflow) Allows(from TodoStatus, to TodoStatus) bool {
+	if from == to || len(w.Transitions) == 0 {
+		return true
+	}
+
+	for _, transition := range w.Transitions {
+		if transition.From == from && transition.To == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Settle maps a status from another workflow onto this one. Statuses with
+// the same key are kept, otherwise completed todos land on Done and the
+// others on Initial.
+func (w *Workflow) Settle(key TodoStatus, completed bool) WorkflowStatus {
+	if status, ok := w.Status(key); ok {
+		return status
+	}
+
+	if completed {
+		return w.Done()
+	}
+
+	return w.Initial()
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func kanban() Workflow {
+	return Workflow{
+		Statuses: []WorkflowStatus{
+			{Key: "backlog", Name: "Backlog"},
+			{Key: "doing", Name: "Doing"},
+			{Key: "qa", Name: "QA"},
+			{Key: "done", Name: "Done", Terminal: true},
+		},
+		Transitions: []WorkflowTransition{
+			{From: "backlog", To: "doing"},
+			{From: "doing", To: "qa"},
+			{From: "qa", To: "doing"},
+			{From: "qa", To: "done"},
+		},
+		ReviewRule: ReviewRuleAll,
+	}
+}
+
+func TestWorkflow_Validate(t *testing.T) {
+	workflow := kanban()
+	require.NoError(t, workflow.Validate())
+
+	tests := map[string]func(w *Workflow){
+		"no statuses":          func(w *Workflow) { w.Statuses = nil },
+		"bad key":              func(w *Workflow) { w.Statuses[1].Key = "In Progress" },
+		"missing name":         func(w *Workflow) { w.Statuses[1].Name = "" },
+		"duplicate key":        func(w *Workflow) { w.Statuses[2].Key = "doing" },
+		"terminal first":       func(w *Workflow) { w.Statuses[0].Terminal = true },
+		"nothing terminal":     func(w *Workflow) { w.Statuses[3].Terminal = false },
+		"unknown transition":   func(w *Workflow) { w.Transitions[0].To = "blocked" },
+		"transition to itself": func(w *Workflow) { w.Transitions[0].To = "backlog" },
+		"unknown review rule":  func(w *Workflow) { w.ReviewRule = "quorum" },
+	}
+
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			workflow := kanban()
+			mutate(&workflow)
+
+			assert.ErrorIs(t, workflow.Validate(), ErrInvalidWorkflow)
+		})
+	}
+}
+
+func TestWorkflow_Allows(t *testing.T) {
+	workflow := kanban()
+
+	assert.True(t, workflow.Allows("backlog", "doing"))
+	assert.True(t, workflow.Allows("qa", "qa"))
+	assert.False(t, workflow.Allows("backlog", "done"))
+
+	workflow.Transitions = nil
+	assert.True(t, workflow.Allows("backlog", "done"))
+}
+
+func TestWorkflow_Settle(t *testing.T) {
+	workflow := kanban()
+
+	assert.Equal(t, TodoStatus("qa"), workflow.Settle("qa", false).Key)
+	assert.Equal(t, TodoStatus("backlog"), workflow.Settle(TodoStatusInReview, false).Key)
+	assert.Equal(t, TodoStatus("done"), workflow.Settle(TodoStatusCompleted, true).Key)
+}
+
+func TestTodo_Transition(t *testing.T) {
+	now := time.Date(2026, 3, 11, 15, 0, 0, 0, time.UTC)
+	workflow := kanban()
+	todo := Todo{Status: "backlog"}
+
+	done := TodoStatus("done")
+	err := todo.Transition(workflow, TodoStatusChange{Status: &done}, now)
+
+	var transitionErr *TransitionError
+	require.ErrorAs(t, err, &transitionErr)
+	assert.ErrorIs(t, err, ErrIllegalTransition)
+	assert.Equal(t, TodoStatus("backlog"), transitionErr.From)
+	assert.Equal(t, done, transitionErr.To)
+	assert.Equal(t, TodoStatus("backlog"), todo.Status)
+
+	doing := TodoStatus("doing")
+	require.NoError(t, todo.Transition(workflow, TodoStatusChange{Status: &doing}, now))
+	assert.Equal(t, doing, todo.Status)
+	assert.False(t, todo.Completed)
+}
@@ -15,13 +15,133 @@ type LoginRequest struct {
 type TodoRequest struct {
 	Title       string     `json:"title,omitempty" validate:"min=3,max=255"`
 	Description string     `json:"description,omitempty" validate:"max=1000"`
-	Status      string     `json:"status,omitempty"`
-	Completed   bool       `json:"completed,omitempty"`
+	Status      *string    `json:"status"`
+	Completed   *bool      `json:"completed"` // shorthand for the completed status
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	AllDay      bool       `json:"all_day,omitempty"`
+	Tags        []string   `json:"tags"`
+	ProjectUUID *string    `json:"project_uuid"`                       // empty string moves the todo out of its project
+	RRule       *string    `json:"rrule" validate:"omitempty,max=255"` // empty string stops the repetition
 	CreatedAt   time.Time  `json:"created_at,omitempty"`
 	UpdatedAt   time.Time  `json:"updated_at,omitempty"`
 	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
 
+// TodoPatchRequest is a JSON merge patch of a todo (RFC 7396). The pointers
+// cannot tell a field left out from one sent as null, the handler looks at
+// which keys the body has for that.
+type TodoPatchRequest struct {
+	Title       *string    `json:"title" validate:"omitempty,min=3,max=255"`
+	Description *string    `json:"description" validate:"omitempty,max=1000"`
+	Status      *string    `json:"status"`
+	Completed   *bool      `json:"completed"`
+	DueAt       *time.Time `json:"due_at"`
+	AllDay      *bool      `json:"all_day"`
+	Tags        []string   `json:"tags"`
+	ProjectUUID *string    `json:"project_uuid"`                       // empty string moves the todo out of its project
+	RRule       *string    `json:"rrule" validate:"omitempty,max=255"` // empty string stops the repetition
+}
+
+// TodoBatchRequest applies up to 100 operations in one transaction. Data
+// holds the todo fields for create and update, the others only need uuid.
+// Version works as If-Match does on the single todo endpoints.
+type TodoBatchRequest struct {
+	Mode       string `json:"mode" validate:"omitempty,oneof=atomic best_effort"` // defaults to atomic
+	Operations []struct {
+		Op      string       `json:"op" validate:"required,oneof=create update delete complete"`
+		UUID    string       `json:"uuid" validate:"required_unless=Op create,omitempty,uuid"`
+		Version int          `json:"version" validate:"omitempty,min=1"`
+		Force   bool         `json:"force"`             // completes todos whose blockers are still open
+		Data    *TodoRequest `json:"data" validate:"-"` // checked per operation
+	} `json:"operations" validate:"required,min=1,max=100,dive"`
+}
+
+type OccurrenceSkipRequest struct {
+	At *time.Time `json:"at"` // defaults to the todo's current occurrence
+}
+
+type ReminderRequest struct {
+	Kind          string `json:"kind" validate:"required,oneof=before_due day_of"`
+	OffsetMinutes int    `json:"offset_minutes,omitempty" validate:"min=0"`
+	TimeOfDay     string `json:"time_of_day,omitempty"`
+}
+
+type TodoItemRequest struct {
+	Title          *string `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
+	Done           *bool   `json:"done,omitempty"`
+	Position       *int    `json:"position,omitempty" validate:"omitempty,min=0"`
+	CompleteParent bool    `json:"complete_parent,omitempty"`
+}
+
+type TodoBlockerRequest struct {
+	BlockerUUID string `json:"blocker_uuid" validate:"required,uuid"`
+}
+
+type TimerStartRequest struct {
+	Note string `json:"note,omitempty" validate:"max=1000"`
+}
+
+type TimeEntryRequest struct {
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Note      *string    `json:"note,omitempty" validate:"omitempty,max=1000"`
+}
+
+type TagRequest struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+type TagMergeRequest struct {
+	Into string `json:"into" validate:"required,uuid"`
+}
+
+type ProjectRequest struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Position    *int    `json:"position,omitempty" validate:"omitempty,min=0"`
+}
+
+type ProjectOrderRequest struct {
+	Todos []string `json:"todos" validate:"dive,uuid"`
+}
+
+type ProjectMemberRequest struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+	Role  string `json:"role" validate:"required,oneof=viewer editor owner"`
+}
+
+type ProjectRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=viewer editor owner"`
+}
+
+// WorkflowRequest replaces a project's workflow. The first status is where new
+// todos start, no transitions lets todos move freely.
+type WorkflowRequest struct {
+	Statuses []struct {
+		Key      string `json:"key" validate:"required,max=32"`
+		Name     string `json:"name" validate:"required,max=64"`
+		Terminal bool   `json:"terminal"`
+	} `json:"statuses" validate:"required,min=1,max=20,dive"`
+	Transitions []struct {
+		From string `json:"from" validate:"required"`
+		To   string `json:"to" validate:"required"`
+	} `json:"transitions" validate:"dive"`
+	ReviewRule *string `json:"review_rule" validate:"omitempty,oneof=all any majority none"` // defaults to all
+}
+
+type ReviewRequest struct {
+	Reviewers []string `json:"reviewers" validate:"required,min=1,max=10,dive,email,max=255"`
+}
+
+type ReviewDecisionRequest struct {
+	Comment string `json:"comment" validate:"max=5000"`
+}
+
+type CommentRequest struct {
+	Body       string  `json:"body" validate:"required,max=5000"`
+	ParentUUID *string `json:"parent_uuid,omitempty" validate:"omitempty,uuid"`
+}
+
 type UserRequest struct {
 	Name     string `json:"name,omitempty"`
 	Email    string `json:"email,omitempty"`
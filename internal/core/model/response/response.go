@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"todos/internal/core/domain"
 )
 
 type UserResponse struct {
@@ -16,18 +18,571 @@ type UserResponse struct {
 }
 
 type TodoResponse struct {
-	UUID        uuid.UUID `json:"uuid"`
-	Title       string    `json:"title,omitempty"`
-	Description string    `json:"description,omitempty"`
-	Status      string    `json:"status,omitempty"`
-	Completed   bool      `json:"completed"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	UUID        uuid.UUID   `json:"uuid"`
+	Title       string      `json:"title,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Status      string      `json:"status,omitempty"`
+	Completed   bool        `json:"completed"`
+	CompletedAt *time.Time  `json:"completed_at,omitempty"`
+	DueAt       *time.Time  `json:"due_at,omitempty"`
+	AllDay      bool        `json:"all_day"`
+	Overdue     bool        `json:"overdue"`
+	ItemsTotal  int         `json:"items_total"`
+	ItemsDone   int         `json:"items_done"`
+	Blocked     bool        `json:"blocked"` // a todo blocking this one is still open
+	Tracked     int         `json:"tracked_seconds"`
+	Tags        []string    `json:"tags"`
+	ProjectUUID *uuid.UUID  `json:"project_uuid"`
+	Position    int         `json:"position"`
+	RRule       string      `json:"rrule,omitempty"`
+	Skipped     []time.Time `json:"skipped_occurrences,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	Version     int         `json:"version"`
+	ETag        string      `json:"etag"` // for If-Match, list items have no header of their own
+}
+
+func NewTodoResponse(todo domain.Todo) TodoResponse {
+	data := TodoResponse{
+		UUID:        todo.UUID,
+		Title:       todo.Title,
+		Description: todo.Description,
+		Status:      todo.StatusOrFallback(),
+		Completed:   todo.Completed,
+		CompletedAt: todo.CompletedAt,
+		DueAt:       todo.DueAt,
+		AllDay:      todo.AllDay,
+		Overdue:     todo.IsOverdue(time.Now()),
+		ItemsTotal:  todo.ItemsTotal,
+		ItemsDone:   todo.ItemsDone,
+		Blocked:     todo.Blocked,
+		Tracked:     todo.Tracked,
+		Tags:        todo.Tags,
+		ProjectUUID: todo.ProjectUUID,
+		Position:    todo.Position,
+		CreatedAt:   todo.CreatedAt,
+		UpdatedAt:   todo.UpdatedAt,
+		Version:     todo.Version,
+		ETag:        todo.ETag(),
+	}
+
+	if recurrence, err := todo.Recurrence(); err == nil {
+		data.RRule = recurrence.Rule.String()
+
+		data.Skipped = recurrence.Exceptions
+	}
+
+	return data
+}
+
+// TrashedTodoResponse is a deleted todo with the time it leaves the trash
+type TrashedTodoResponse struct {
+	TodoResponse
+	DeletedAt *time.Time `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at"`
+}
+
+func NewTrashedTodoResponse(todo domain.Todo, retention time.Duration) TrashedTodoResponse {
+	return TrashedTodoResponse{
+		TodoResponse: NewTodoResponse(todo),
+		DeletedAt:    todo.DeletedAt,
+		PurgeAt:      todo.PurgeAt(retention),
+	}
+}
+
+// TodoOperationResponse is the outcome of one batch operation. Status is the
+// HTTP status the operation would have had on its own.
+type TodoOperationResponse struct {
+	Index  int              `json:"index"`
+	Op     string           `json:"op"`
+	UUID   *uuid.UUID       `json:"uuid,omitempty"`
+	Status int              `json:"status"`
+	Data   *TodoResponse    `json:"data,omitempty"`
+	Error  *ValidationError `json:"error,omitempty"`
+}
+
+type TodoBatchResponse struct {
+	Mode      string                  `json:"mode"`
+	Committed bool                    `json:"committed"`
+	Succeeded int                     `json:"succeeded"`
+	Failed    int                     `json:"failed"`
+	Results   []TodoOperationResponse `json:"results"`
+}
+
+// TodoImportRowResponse is the outcome for one todo of an import. UUID is
+// the todo that was or, on a dry run, would be created.
+type TodoImportRowResponse struct {
+	Line   int              `json:"line"`
+	UUID   *uuid.UUID       `json:"uuid,omitempty"`
+	Title  string           `json:"title,omitempty"`
+	Status string           `json:"status,omitempty"`
+	Error  *ValidationError `json:"error,omitempty"`
+}
+
+type TodoImportResponse struct {
+	DryRun   bool                    `json:"dry_run"`
+	Imported bool                    `json:"imported"`
+	Total    int                     `json:"total"`
+	Valid    int                     `json:"valid"`
+	Failed   int                     `json:"failed"`
+	Rows     []TodoImportRowResponse `json:"rows"`
+}
+
+// DependencyNodeResponse is a todo of a dependency graph
+type DependencyNodeResponse struct {
+	UUID        uuid.UUID  `json:"uuid"`
+	Title       string     `json:"title"`
+	Status      string     `json:"status"`
+	Completed   bool       `json:"completed"`
+	Blocked     bool       `json:"blocked"`
+	ProjectUUID *uuid.UUID `json:"project_uuid"`
+}
+
+// DependencyEdgeResponse says the todo waits on the blocker
+type DependencyEdgeResponse struct {
+	TodoUUID    uuid.UUID `json:"todo_uuid"`
+	BlockerUUID uuid.UUID `json:"blocker_uuid"`
+}
+
+type DependencyGraphResponse struct {
+	Nodes []DependencyNodeResponse `json:"nodes"`
+	Edges []DependencyEdgeResponse `json:"edges"`
+}
+
+func NewDependencyGraphResponse(graph domain.DependencyGraph) DependencyGraphResponse {
+	data := DependencyGraphResponse{
+		Nodes: make([]DependencyNodeResponse, 0, len(graph.Todos)),
+		Edges: make([]DependencyEdgeResponse, 0, len(graph.Dependencies)),
+	}
+
+	for _, todo := range graph.Todos {
+		data.Nodes = append(data.Nodes, DependencyNodeResponse{
+			UUID:        todo.UUID,
+			Title:       todo.Title,
+			Status:      todo.StatusOrFallback(),
+			Completed:   todo.Completed,
+			Blocked:     todo.Blocked,
+			ProjectUUID: todo.ProjectUUID,
+		})
+	}
+
+	for _, dependency := range graph.Dependencies {
+		data.Edges = append(data.Edges, DependencyEdgeResponse{
+			TodoUUID:    dependency.TodoUUID,
+			BlockerUUID: dependency.BlockerUUID,
+		})
+	}
+
+	return data
+}
+
+type OccurrencesResponse struct {
+	Occurrences []time.Time `json:"occurrences"`
+}
+
+func NewOccurrencesResponse(occurrences []time.Time) OccurrencesResponse {
+	return OccurrencesResponse{Occurrences: occurrences}
+}
+
+type TodoItemResponse struct {
+	UUID      uuid.UUID `json:"uuid"`
+	Title     string    `json:"title"`
+	Done      bool      `json:"done"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewTodoItemResponse(item domain.TodoItem) TodoItemResponse {
+	return TodoItemResponse{
+		UUID:      item.UUID,
+		Title:     item.Title,
+		Done:      item.Done,
+		Position:  item.Position,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+}
+
+type TimeEntryResponse struct {
+	UUID      uuid.UUID  `json:"uuid"`
+	TodoUUID  uuid.UUID  `json:"todo_uuid"`
+	TodoTitle string     `json:"todo_title"`
+	UserUUID  uuid.UUID  `json:"user_uuid"`
+	Note      string     `json:"note"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Seconds   int        `json:"seconds"` // up to now while the timer runs
+	Running   bool       `json:"running"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func NewTimeEntryResponse(entry domain.TimeEntry) TimeEntryResponse {
+	return TimeEntryResponse{
+		UUID:      entry.UUID,
+		TodoUUID:  entry.TodoUUID,
+		TodoTitle: entry.TodoTitle,
+		UserUUID:  entry.UserUUID,
+		Note:      entry.Note,
+		StartedAt: entry.StartedAt,
+		EndedAt:   entry.EndedAt,
+		Seconds:   int(entry.Duration(time.Now()) / time.Second),
+		Running:   entry.IsRunning(),
+		CreatedAt: entry.CreatedAt,
+		UpdatedAt: entry.UpdatedAt,
+	}
+}
+
+type TimeReportRowResponse struct {
+	Key     string `json:"key"`
+	Label   string `json:"label"`
+	Seconds int    `json:"seconds"`
+	Entries int    `json:"entries"`
+}
+
+type TimeReportResponse struct {
+	Group   string                  `json:"group"`
+	From    time.Time               `json:"from"`
+	To      time.Time               `json:"to"`
+	Seconds int                     `json:"total_seconds"`
+	Rows    []TimeReportRowResponse `json:"rows"`
+}
+
+func NewTimeReportResponse(report domain.TimeReport) TimeReportResponse {
+	data := TimeReportResponse{
+		Group:   string(report.Group),
+		From:    report.From,
+		To:      report.To,
+		Seconds: report.Seconds,
+		Rows:    make([]TimeReportRowResponse, 0, len(report.Rows)),
+	}
+
+	for _, row := range report.Rows {
+		data.Rows = append(data.Rows, TimeReportRowResponse(row))
+	}
+
+	return data
+}
+
+type TagResponse struct {
+	UUID      uuid.UUID `json:"uuid"`
+	Name      string    `json:"name"`
+	TodoCount int       `json:"todo_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewTagResponse(tag domain.Tag) TagResponse {
+	return TagResponse{
+		UUID:      tag.UUID,
+		Name:      tag.Name,
+		TodoCount: tag.TodoCount,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+}
+
+type ProjectResponse struct {
+	UUID        uuid.UUID  `json:"uuid"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Position    int        `json:"position"`
+	TodoCount   int        `json:"todo_count"`
+	Role        string     `json:"role,omitempty"`
+	Archived    bool       `json:"archived"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func NewProjectResponse(project domain.Project) ProjectResponse {
+	return ProjectResponse{
+		UUID:        project.UUID,
+		Name:        project.Name,
+		Description: project.Description,
+		Position:    project.Position,
+		TodoCount:   project.TodoCount,
+		Role:        string(project.Role),
+		Archived:    project.IsArchived(),
+		ArchivedAt:  project.ArchivedAt,
+		CreatedAt:   project.CreatedAt,
+		UpdatedAt:   project.UpdatedAt,
+	}
+}
+
+type ProjectMemberResponse struct {
+	UserUUID  uuid.UUID `json:"user_uuid"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewProjectMemberResponse(member domain.ProjectMember) ProjectMemberResponse {
+	return ProjectMemberResponse{
+		UserUUID:  member.UserUUID,
+		Name:      member.Name,
+		Email:     member.Email,
+		Role:      string(member.Role),
+		CreatedAt: member.CreatedAt,
+		UpdatedAt: member.UpdatedAt,
+	}
+}
+
+type WorkflowStatusResponse struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Terminal bool   `json:"terminal"`
+}
+
+type WorkflowTransitionResponse struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type WorkflowResponse struct {
+	UUID        uuid.UUID                    `json:"uuid"`
+	Default     bool                         `json:"default"`
+	Statuses    []WorkflowStatusResponse     `json:"statuses"`
+	Transitions []WorkflowTransitionResponse `json:"transitions"`
+	ReviewRule  string                       `json:"review_rule"`
+	UpdatedAt   time.Time                    `json:"updated_at"`
+}
+
+func NewWorkflowResponse(workflow domain.Workflow) WorkflowResponse {
+	data := WorkflowResponse{
+		UUID:        workflow.UUID,
+		Default:     workflow.IsDefault(),
+		Statuses:    make([]WorkflowStatusResponse, 0, len(workflow.Statuses)),
+		Transitions: make([]WorkflowTransitionResponse, 0, len(workflow.Transitions)),
+		ReviewRule:  string(workflow.ReviewRule),
+		UpdatedAt:   workflow.UpdatedAt,
+	}
+
+	for _, status := range workflow.Statuses {
+		data.Statuses = append(data.Statuses, WorkflowStatusResponse{
+			Key:      string(status.Key),
+			Name:     status.Name,
+			Terminal: status.Terminal,
+		})
+	}
+
+	for _, transition := range workflow.Transitions {
+		data.Transitions = append(data.Transitions, WorkflowTransitionResponse{
+			From: string(transition.From),
+			To:   string(transition.To),
+		})
+	}
+
+	return data
+}
+
+type ReviewResponse struct {
+	UUID uuid.UUID `json:"uuid"`
+	Todo struct {
+		UUID  uuid.UUID `json:"uuid"`
+		Title string    `json:"title"`
+	} `json:"todo"`
+	Reviewer struct {
+		UUID uuid.UUID `json:"uuid"`
+		Name string    `json:"name"`
+	} `json:"reviewer"`
+	Decision  string     `json:"decision"`
+	Comment   *string    `json:"comment,omitempty"`
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func NewReviewResponse(review domain.Review) ReviewResponse {
+	data := ReviewResponse{
+		UUID:      review.UUID,
+		Decision:  string(review.Decision),
+		Comment:   review.Comment,
+		DecidedAt: review.DecidedAt,
+		CreatedAt: review.CreatedAt,
+		UpdatedAt: review.UpdatedAt,
+	}
+
+	data.Todo.UUID = review.TodoUUID
+	data.Todo.Title = review.TodoTitle
+	data.Reviewer.UUID = review.ReviewerUUID
+	data.Reviewer.Name = review.ReviewerName
+
+	return data
+}
+
+type CommentResponse struct {
+	UUID       uuid.UUID  `json:"uuid"`
+	ParentUUID *uuid.UUID `json:"parent_uuid,omitempty"`
+	Author     struct {
+		UUID uuid.UUID `json:"uuid"`
+		Name string    `json:"name"`
+	} `json:"author"`
+	Body      string     `json:"body"`
+	Edited    bool       `json:"edited"`
+	EditCount int        `json:"edit_count"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func NewCommentResponse(comment domain.Comment) CommentResponse {
+	data := CommentResponse{
+		UUID:       comment.UUID,
+		ParentUUID: comment.ParentUUID,
+		Body:       comment.Body,
+		Edited:     comment.EditedAt != nil,
+		EditCount:  comment.EditCount,
+		EditedAt:   comment.EditedAt,
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
+	}
+
+	data.Author.UUID = comment.AuthorUUID
+	data.Author.Name = comment.AuthorName
+
+	return data
+}
+
+type CommentRevisionResponse struct {
+	Body       string    `json:"body"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+func NewCommentRevisionResponse(revision domain.CommentRevision) CommentRevisionResponse {
+	return CommentRevisionResponse{
+		Body:       revision.Body,
+		ReplacedAt: revision.CreatedAt,
+	}
+}
+
+type TodoRevisionResponse struct {
+	UUID   uuid.UUID `json:"uuid"`
+	Number int       `json:"number"`
+	Author *struct {
+		UUID uuid.UUID `json:"uuid"`
+		Name string    `json:"name"`
+	} `json:"author"`
+	Changes   map[string]domain.FieldChange `json:"changes"`
+	Snapshot  domain.TodoSnapshot           `json:"snapshot"`
+	CreatedAt time.Time                     `json:"created_at"`
+}
+
+// NewTodoRevisionResponse leaves the author out for changes no user made
+func NewTodoRevisionResponse(revision domain.TodoRevision) TodoRevisionResponse {
+	data := TodoRevisionResponse{
+		UUID:      revision.UUID,
+		Number:    revision.Number,
+		Changes:   revision.Changes,
+		Snapshot:  revision.Snapshot,
+		CreatedAt: revision.CreatedAt,
+	}
+
+	if revision.UserUUID != nil {
+		data.Author = &struct {
+			UUID uuid.UUID `json:"uuid"`
+			Name string    `json:"name"`
+		}{UUID: *revision.UserUUID}
+
+		if revision.UserName != nil {
+			data.Author.Name = *revision.UserName
+		}
+	}
+
+	return data
+}
+
+type AttachmentResponse struct {
+	UUID        uuid.UUID  `json:"uuid"`
+	Filename    string     `json:"filename"`
+	ContentType string     `json:"content_type"`
+	Size        int64      `json:"size"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	ExpiresAt   *time.Time `json:"download_expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func NewAttachmentResponse(attachment domain.Attachment, link domain.AttachmentLink) AttachmentResponse {
+	res := AttachmentResponse{
+		UUID:        attachment.UUID,
+		Filename:    attachment.Filename,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		CreatedAt:   attachment.CreatedAt,
+	}
+
+	// No link is issued while downloads are turned off
+	if link.URL != "" {
+		res.DownloadURL = link.URL
+		res.ExpiresAt = &link.ExpiresAt
+	}
+
+	return res
+}
+
+type ReminderResponse struct {
+	UUID          uuid.UUID  `json:"uuid"`
+	Kind          string     `json:"kind"`
+	OffsetMinutes int        `json:"offset_minutes"`
+	TimeOfDay     string     `json:"time_of_day,omitempty"`
+	RemindAt      *time.Time `json:"remind_at,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	Attempts      int        `json:"attempts"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// NewReminderResponse resolves remind_at against the todo's current due date
+func NewReminderResponse(reminder domain.Reminder, todo domain.Todo) ReminderResponse {
+	data := ReminderResponse{
+		UUID:          reminder.UUID,
+		Kind:          string(reminder.Kind),
+		OffsetMinutes: reminder.OffsetMinutes,
+		SentAt:        reminder.SentAt,
+		Attempts:      reminder.Attempts,
+		CreatedAt:     reminder.CreatedAt,
+	}
+
+	if reminder.Kind == domain.ReminderDayOf {
+		data.TimeOfDay = domain.FormatTimeOfDay(reminder.TimeOfDay)
+	}
+
+	if remindAt, err := reminder.RemindAt(todo); err == nil {
+		data.RemindAt = &remindAt
+	}
+
+	return data
+}
+
+type TodoSearchResponse struct {
+	TodoResponse
+	Rank      float64       `json:"rank"`
+	Highlight TodoHighlight `json:"highlight"`
+}
+
+type TodoHighlight struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func NewTodoSearchResponse(result domain.TodoSearchResult) TodoSearchResponse {
+	return TodoSearchResponse{
+		TodoResponse: NewTodoResponse(result.Todo),
+		Rank:         result.Rank,
+		Highlight: TodoHighlight{
+			Title:       result.TitleHighlight,
+			Description: result.DescriptionHighlight,
+		},
+	}
 }
 
 type CursorData struct {
-	Datetime string `json:"datetime"`
+	Datetime string `json:"datetime,omitempty"`
 	ID       int    `json:"id,omitempty"`
+	Sort     string `json:"sort,omitempty"`
+	Value    string `json:"value,omitempty"`
 }
 
 type CursorResponse struct {
@@ -0,0 +1,48 @@
+package policy
+
+import (
+	"context"
+	"errors"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+)
+
+// MembershipPolicy lets owners act on their personal todos like OwnerPolicy,
+// and opens todos in projects to the project's members according to their
+// role: viewers may read them, editors and owners may also change them. Who
+// created a todo in a project counts for nothing once their role changes.
+type MembershipPolicy struct {
+	members port.ProjectMembership
+}
+
+func NewMembershipPolicy(members port.ProjectMembership) port.TodoPolicy {
+	return &MembershipPolicy{members: members}
+}
+
+func (p *MembershipPolicy) Authorize(ctx context.Context, userId int, action domain.TodoAction, todo domain.Todo) error {
+	if todo.ProjectId == nil {
+		if todo.BelongsToUser(userId) {
+			return nil
+		}
+
+		return domain.ErrTodoNotFound
+	}
+
+	member, err := p.members.GetMember(ctx, *todo.ProjectId, userId)
+
+	if errors.Is(err, domain.ErrProjectMemberNotFound) {
+		// Outsiders must not learn the todo exists
+		return domain.ErrTodoNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !member.Role.Allows(action) {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package policy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/policy"
+)
+
+type fakeMembership map[int]domain.ProjectRole
+
+func (f fakeMembership) GetMember(ctx context.Context, projectId int, userId int) (domain.ProjectMember, error) {
+	role, ok := f[userId]
+
+	if !ok {
+		return domain.ProjectMember{}, domain.ErrProjectMemberNotFound
+	}
+
+	return domain.ProjectMember{ProjectId: projectId, UserId: userId, Role: role}, nil
+}
+
+func TestMembershipPolicy_Authorize(t *testing.T) {
+	projectId := 7
+	members := fakeMembership{1: domain.ProjectOwner, 2: domain.ProjectViewer, 3: domain.ProjectEditor, 5: domain.ProjectViewer}
+	p := policy.NewMembershipPolicy(members)
+
+	shared := domain.Todo{UserId: 1, ProjectId: &projectId}
+	private := domain.Todo{UserId: 1}
+
+	t.Run("should allow the owner of the todo", func(t *testing.T) {
+		assert.NoError(t, p.Authorize(context.Background(), 1, domain.TodoActionDelete, shared))
+		assert.NoError(t, p.Authorize(context.Background(), 1, domain.TodoActionUpdate, private))
+	})
+
+	t.Run("should let viewers read but not write", func(t *testing.T) {
+		assert.NoError(t, p.Authorize(context.Background(), 2, domain.TodoActionView, shared))
+		assert.ErrorIs(t, p.Authorize(context.Background(), 2, domain.TodoActionUpdate, shared), domain.ErrForbidden)
+	})
+
+	t.Run("should let editors update", func(t *testing.T) {
+		assert.NoError(t, p.Authorize(context.Background(), 3, domain.TodoActionUpdate, shared))
+	})
+
+	t.Run("should go by the role of whoever created the todo", func(t *testing.T) {
+		demoted := domain.Todo{UserId: 5, ProjectId: &projectId}
+		removed := domain.Todo{UserId: 4, ProjectId: &projectId}
+
+		assert.ErrorIs(t, p.Authorize(context.Background(), 5, domain.TodoActionUpdate, demoted), domain.ErrForbidden)
+		assert.ErrorIs(t, p.Authorize(context.Background(), 4, domain.TodoActionDelete, removed), domain.ErrTodoNotFound)
+	})
+
+	t.Run("should hide todos from non members", func(t *testing.T) {
+		assert.ErrorIs(t, p.Authorize(context.Background(), 4, domain.TodoActionView, shared), domain.ErrTodoNotFound)
+		assert.ErrorIs(t, p.Authorize(context.Background(), 3, domain.TodoActionView, private), domain.ErrTodoNotFound)
+	})
+}
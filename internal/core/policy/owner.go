@@ -0,0 +1,24 @@
+package policy
+
+import (
+	"context"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+)
+
+// OwnerPolicy only allows the owner of a todo to act on it
+type OwnerPolicy struct{}
+
+func NewOwnerPolicy() port.TodoPolicy {
+	return &OwnerPolicy{}
+}
+
+func (p *OwnerPolicy) Authorize(ctx context.Context, userId int, action domain.TodoAction, todo domain.Todo) error {
+	if !todo.BelongsToUser(userId) {
+		// Never tell other users that the todo exists
+		return domain.ErrTodoNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package policy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/policy"
+)
+
+func TestOwnerPolicy_Authorize(t *testing.T) {
+	p := policy.NewOwnerPolicy()
+	todo := domain.Todo{UserId: 123}
+
+	t.Run("should allow the owner for every action", func(t *testing.T) {
+		actions := []domain.TodoAction{
+			domain.TodoActionList,
+			domain.TodoActionView,
+			domain.TodoActionCreate,
+			domain.TodoActionUpdate,
+			domain.TodoActionDelete,
+		}
+
+		for _, action := range actions {
+			assert.NoError(t, p.Authorize(context.Background(), 123, action, todo))
+		}
+	})
+
+	t.Run("should hide the todo from other users", func(t *testing.T) {
+		err := p.Authorize(context.Background(), 456, domain.TodoActionDelete, todo)
+
+		assert.ErrorIs(t, err, domain.ErrTodoNotFound)
+	})
+}
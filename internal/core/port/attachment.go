@@ -0,0 +1,50 @@
+package port
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"todos/internal/core/domain"
+)
+
+// BlobStore keeps file contents outside the database. Keys are slash
+// separated paths chosen by the caller, deleting a missing key is not an error.
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type AttachmentRepository interface {
+	ListByTodo(ctx context.Context, todoId int) ([]domain.Attachment, error)
+	GetByUUID(ctx context.Context, todoId int, uid string) (domain.Attachment, error)
+
+	// Find loads an attachment by uuid alone, as long as neither it nor its
+	// todo has been deleted. Signed download links carry no todo.
+	Find(ctx context.Context, uid string) (domain.Attachment, error)
+	Create(ctx context.Context, attachment domain.Attachment) (domain.Attachment, error)
+	Delete(ctx context.Context, attachment domain.Attachment) error
+
+	// ListPurgeable returns attachments deleted before the given time. Purge
+	// then removes the row for good. Attachments of a deleted todo stay until
+	// the todo leaves the trash, ListStored lists them, deleted ones included.
+	ListPurgeable(ctx context.Context, deletedBefore time.Time, limit int) ([]domain.Attachment, error)
+	ListStored(ctx context.Context, todoId int) ([]domain.Attachment, error)
+	Purge(ctx context.Context, id int) error
+}
+
+type AttachmentService interface {
+	List(ctx context.Context, userId int, todoUUID string) ([]domain.Attachment, error)
+	GetByUUID(ctx context.Context, userId int, todoUUID string, attachmentUUID string) (domain.Attachment, error)
+	Upload(ctx context.Context, userId int, todoUUID string, attachment domain.Attachment, body io.Reader) (domain.Attachment, error)
+	Delete(ctx context.Context, userId int, todoUUID string, attachmentUUID string) error
+
+	// Sign issues a time-limited download link for an attachment the caller
+	// already loaded through one of the methods above, an empty one when no
+	// signing key is configured
+	Sign(attachment domain.Attachment) domain.AttachmentLink
+
+	// Download verifies a signed link and opens the attachment contents
+	Download(ctx context.Context, attachmentUUID string, expires int64, signature string) (domain.Attachment, io.ReadCloser, error)
+}
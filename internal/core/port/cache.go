@@ -7,6 +7,12 @@ import (
 
 type CacheRepository interface {
 	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// SetNX stores the value only when the key is missing, reporting whether
+	// it did
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+
+	// Get returns nil without an error for a missing key
 	Get(ctx context.Context, key string) ([]byte, error)
 	Delete(ctx context.Context, key string) error
 	DeleteByPrefix(ctx context.Context, prefix string) error
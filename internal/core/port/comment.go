@@ -0,0 +1,29 @@
+package port
+
+import (
+	"context"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+)
+
+// CommentRepository scopes lookups to the parent todo id like the checklist
+// items, so a comment can never be reached through another todo
+type CommentRepository interface {
+	ListByTodo(ctx context.Context, todoId int, limit int, cursor string) ([]domain.Comment, bool, error)
+	GetByUUID(ctx context.Context, todoId int, uid string) (domain.Comment, error)
+	Create(ctx context.Context, comment domain.Comment) (domain.Comment, error)
+
+	// Update stores the previous body as a revision in the same transaction
+	Update(ctx context.Context, comment domain.Comment, body string) (domain.Comment, error)
+	Delete(ctx context.Context, comment domain.Comment) error
+	ListRevisions(ctx context.Context, commentId int) ([]domain.CommentRevision, error)
+}
+
+type CommentService interface {
+	List(ctx context.Context, userId int, todoUUID string, limit int, cursor string) (*response.CursorResponse, error)
+	Create(ctx context.Context, userId int, todoUUID string, comment domain.Comment) (domain.Comment, error)
+	Update(ctx context.Context, userId int, todoUUID string, commentUUID string, body string) (domain.Comment, error)
+	Delete(ctx context.Context, userId int, todoUUID string, commentUUID string) error
+	History(ctx context.Context, userId int, todoUUID string, commentUUID string) ([]domain.CommentRevision, error)
+}
@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+
+	"todos/internal/core/domain"
+)
+
+// TodoPolicy decides whether a user may perform an action on a todo.
+// Implementations return domain.ErrTodoNotFound when the todo must stay invisible
+// to the user and domain.ErrForbidden when it is visible but the action is denied.
+type TodoPolicy interface {
+	Authorize(ctx context.Context, userId int, action domain.TodoAction, todo domain.Todo) error
+}
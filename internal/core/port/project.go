@@ -0,0 +1,68 @@
+package port
+
+import (
+	"context"
+
+	"todos/internal/core/domain"
+)
+
+// ProjectMembership looks up the role a user holds on a project, it returns
+// domain.ErrProjectMemberNotFound for users outside the project
+type ProjectMembership interface {
+	GetMember(ctx context.Context, projectId int, userId int) (domain.ProjectMember, error)
+}
+
+type ProjectRepository interface {
+	ProjectMembership
+
+	// ListByUser returns the projects the user is a member of, with Role set
+	ListByUser(ctx context.Context, userId int, archived bool) ([]domain.Project, error)
+	GetByUUID(ctx context.Context, uid string) (domain.Project, error)
+
+	// Create also makes the creator the project's first owner
+	Create(ctx context.Context, project domain.Project) (domain.Project, error)
+	Update(ctx context.Context, project domain.Project) (domain.Project, error)
+	SetArchived(ctx context.Context, project domain.Project, archived bool) (domain.Project, error)
+
+	// Delete soft deletes the project and moves its todos out of it in the
+	// same transaction, the todos themselves are kept
+	Delete(ctx context.Context, project domain.Project) error
+
+	// Reorder numbers the given todos 0..n-1 in the order received, every
+	// uuid must belong to a live todo of the project
+	Reorder(ctx context.Context, project domain.Project, todoUUIDs []string) error
+
+	ListMembers(ctx context.Context, projectId int) ([]domain.ProjectMember, error)
+	AddMember(ctx context.Context, member domain.ProjectMember) (domain.ProjectMember, error)
+
+	// UpdateMemberRole and RemoveMember refuse to leave the project without
+	// an owner, returning domain.ErrLastProjectOwner
+	UpdateMemberRole(ctx context.Context, member domain.ProjectMember, role domain.ProjectRole) (domain.ProjectMember, error)
+	RemoveMember(ctx context.Context, member domain.ProjectMember) error
+}
+
+type ProjectService interface {
+	List(ctx context.Context, userId int, archived bool) ([]domain.Project, error)
+	GetByUUID(ctx context.Context, userId int, uid string) (domain.Project, error)
+	Create(ctx context.Context, project domain.Project) (domain.Project, error)
+	Update(ctx context.Context, userId int, uid string, changes domain.ProjectChanges) (domain.Project, error)
+	Archive(ctx context.Context, userId int, uid string) (domain.Project, error)
+	Unarchive(ctx context.Context, userId int, uid string) (domain.Project, error)
+	Delete(ctx context.Context, userId int, uid string) error
+	Reorder(ctx context.Context, userId int, uid string, todoUUIDs []string) error
+
+	ListMembers(ctx context.Context, userId int, uid string) ([]domain.ProjectMember, error)
+	InviteMember(ctx context.Context, userId int, uid string, email string, role domain.ProjectRole) (domain.ProjectMember, error)
+	UpdateMemberRole(ctx context.Context, userId int, uid string, memberUUID string, role domain.ProjectRole) (domain.ProjectMember, error)
+
+	// RemoveMember is open to owners, and to any member removing themselves
+	RemoveMember(ctx context.Context, userId int, uid string, memberUUID string) error
+
+	// GetWorkflow returns the project's workflow, or the default one
+	GetWorkflow(ctx context.Context, userId int, uid string) (domain.Workflow, error)
+
+	// DefineWorkflow and ResetWorkflow refuse to drop a status todos are in,
+	// returning domain.ErrStatusInUse
+	DefineWorkflow(ctx context.Context, userId int, uid string, workflow domain.Workflow) (domain.Workflow, error)
+	ResetWorkflow(ctx context.Context, userId int, uid string) (domain.Workflow, error)
+}
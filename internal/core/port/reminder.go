@@ -0,0 +1,37 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"todos/internal/core/domain"
+)
+
+type ReminderRepository interface {
+	Create(ctx context.Context, reminder domain.Reminder) (domain.Reminder, error)
+	GetByUUID(ctx context.Context, uid string) (domain.Reminder, error)
+	ListByTodo(ctx context.Context, todoId int, userId int) ([]domain.Reminder, error)
+	DeleteByUUID(ctx context.Context, uid string) error
+
+	// ClaimDue atomically claims up to limit reminders that are due at now and
+	// not claimed by anyone since staleBefore, returning them ready to deliver
+	ClaimDue(ctx context.Context, now time.Time, staleBefore time.Time, limit int) ([]domain.Notification, error)
+	MarkSent(ctx context.Context, id int, sentAt time.Time) error
+	MarkFailed(ctx context.Context, id int, reason string) error
+}
+
+type ReminderService interface {
+	Create(ctx context.Context, userId int, todoUUID string, reminder domain.Reminder) (domain.Reminder, domain.Todo, error)
+	ListByTodo(ctx context.Context, userId int, todoUUID string) ([]domain.Reminder, domain.Todo, error)
+	DeleteByUUID(ctx context.Context, userId int, todoUUID string, reminderUUID string) error
+}
+
+// NotificationDelivery sends a fired reminder to the user through some channel
+type NotificationDelivery interface {
+	Deliver(ctx context.Context, notification domain.Notification) error
+}
+
+// Clock abstracts time so schedulers can be driven by a fake clock in tests
+type Clock interface {
+	Now() time.Time
+}
@@ -0,0 +1,36 @@
+package port
+
+import (
+	"context"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+)
+
+type ReviewRepository interface {
+	ListByTodo(ctx context.Context, todoId int) ([]domain.Review, error)
+
+	// ListWaiting pages through the pending reviews of a reviewer on live
+	// todos still in review that the reviewer can see, oldest request first
+	ListWaiting(ctx context.Context, reviewerId int, limit int, cursor string) ([]domain.Review, bool, error)
+	GetByReviewer(ctx context.Context, todoId int, reviewerId int) (domain.Review, error)
+
+	// Request adds the reviewers in one transaction, asking a reviewer again
+	// resets their earlier decision
+	Request(ctx context.Context, reviews []domain.Review) ([]domain.Review, error)
+	Decide(ctx context.Context, review domain.Review) (domain.Review, error)
+}
+
+type ReviewService interface {
+	List(ctx context.Context, userId int, todoUUID string) ([]domain.Review, error)
+
+	// Request asks users, found by email, to review the todo. Reviewers must
+	// be able to see it.
+	Request(ctx context.Context, userId int, todoUUID string, emails []string) ([]domain.Review, error)
+
+	// Decide approves or rejects the todo for the calling reviewer
+	Decide(ctx context.Context, userId int, todoUUID string, decision domain.ReviewDecision, comment string) (domain.Review, error)
+
+	// Waiting lists the reviews the user has yet to decide
+	Waiting(ctx context.Context, userId int, limit int, cursor string) (*response.CursorResponse, error)
+}
@@ -0,0 +1,27 @@
+package port
+
+import (
+	"context"
+
+	"todos/internal/core/domain"
+)
+
+type TagRepository interface {
+	ListByUser(ctx context.Context, userId int) ([]domain.Tag, error)
+	GetByUUID(ctx context.Context, uid string) (domain.Tag, error)
+	Create(ctx context.Context, tag domain.Tag) (domain.Tag, error)
+
+	// Rename, Merge and Delete run in one transaction each and touch
+	// updated_at on every affected todo, so incremental syncs pick them up
+	Rename(ctx context.Context, tag domain.Tag, name string) (domain.Tag, error)
+	Merge(ctx context.Context, source domain.Tag, target domain.Tag) (domain.Tag, error)
+	Delete(ctx context.Context, tag domain.Tag) error
+}
+
+type TagService interface {
+	List(ctx context.Context, userId int) ([]domain.Tag, error)
+	Create(ctx context.Context, userId int, name string) (domain.Tag, error)
+	Rename(ctx context.Context, userId int, uid string, name string) (domain.Tag, error)
+	Merge(ctx context.Context, userId int, sourceUUID string, targetUUID string) (domain.Tag, error)
+	Delete(ctx context.Context, userId int, uid string) error
+}
@@ -0,0 +1,40 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"todos/internal/core/domain"
+)
+
+type TimeEntryRepository interface {
+	GetByUUID(ctx context.Context, uid string) (domain.TimeEntry, error)
+
+	// GetRunning finds the user's running timer, ErrNoTimerRunning when
+	// there is none
+	GetRunning(ctx context.Context, userId int) (domain.TimeEntry, error)
+	ListByTodo(ctx context.Context, todoId int) ([]domain.TimeEntry, error)
+
+	// ListForReport loads the user's entries overlapping [from, to) on todos
+	// that are not in the trash, with the tags of their todos
+	ListForReport(ctx context.Context, userId int, from time.Time, to time.Time) ([]domain.TimeEntry, error)
+
+	// Create fails with ErrTimerRunning when the entry is a timer and the
+	// user already runs one
+	Create(ctx context.Context, entry domain.TimeEntry) (domain.TimeEntry, error)
+	Update(ctx context.Context, entry domain.TimeEntry) (domain.TimeEntry, error)
+	Delete(ctx context.Context, entry domain.TimeEntry) error
+}
+
+// TimeEntryService tracks the time users spend on todos. Anyone who can see
+// a todo can track time on it, entries are only edited by their owner.
+type TimeEntryService interface {
+	Start(ctx context.Context, userId int, todoUUID string, note string) (domain.TimeEntry, error)
+	Stop(ctx context.Context, userId int) (domain.TimeEntry, error)
+	Running(ctx context.Context, userId int) (domain.TimeEntry, error)
+	List(ctx context.Context, userId int, todoUUID string) ([]domain.TimeEntry, error)
+	Create(ctx context.Context, userId int, todoUUID string, entry domain.TimeEntry) (domain.TimeEntry, error)
+	Update(ctx context.Context, userId int, uid string, change domain.TimeEntryChange) (domain.TimeEntry, error)
+	Delete(ctx context.Context, userId int, uid string) error
+	Report(ctx context.Context, userId int, filter domain.TimeReportFilter) (domain.TimeReport, error)
+}
@@ -2,22 +2,82 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"todos/internal/core/domain"
 	"todos/internal/core/model/response"
 )
 
 type TodoRepository interface {
-	GetAllWithCursor(ctx context.Context, userId int, limit int, cursor string) ([]domain.Todo, bool, error)
+	GetAllWithCursor(ctx context.Context, userId int, limit int, cursor string, filter domain.TodoFilter) ([]domain.Todo, bool, error)
 	GetByUUID(ctx context.Context, id string) (domain.Todo, error)
+	Search(ctx context.Context, userId int, query string, limit int, cursor string) ([]domain.TodoSearchResult, bool, error)
 	Create(ctx context.Context, todo domain.Todo) (domain.Todo, error)
 	UpdateByUUID(ctx context.Context, todo domain.Todo) (domain.Todo, error)
-	DeleteByUUID(ctx context.Context, uuid string) error
+	DeleteByUUID(ctx context.Context, uuid string, version int) error
+
+	// CreateMany inserts many new todos with their tags at once, all or none
+	CreateMany(ctx context.Context, todos []domain.Todo) error
+
+	// CreateNextOccurrence inserts the todo following a completed recurring
+	// one and links them, failing with ErrOccurrenceExists when already done
+	CreateNextOccurrence(ctx context.Context, previous domain.Todo, next domain.Todo) (domain.Todo, error)
+
+	// ListRevisions pages through a todo's revisions newest first, UpdateByUUID
+	// records one whenever an update changes something
+	ListRevisions(ctx context.Context, todoId int, limit int, cursor string) ([]domain.TodoRevision, bool, error)
+	GetRevision(ctx context.Context, todoId int, number int) (domain.TodoRevision, error)
+
+	// ListTrash, GetTrashedByUUID and Restore work on deleted todos. Purge
+	// removes one for good with its comments, items, attachment rows and the
+	// rest, ListPurgeable finds those deleted before the given time.
+	ListTrash(ctx context.Context, userId int, limit int, cursor string) ([]domain.Todo, bool, error)
+	GetTrashedByUUID(ctx context.Context, uid string) (domain.Todo, error)
+	Restore(ctx context.Context, todo domain.Todo) (domain.Todo, error)
+	ListPurgeable(ctx context.Context, deletedBefore time.Time, limit int) ([]domain.Todo, error)
+	Purge(ctx context.Context, todo domain.Todo) error
+
+	// AddBlocker and RemoveBlocker keep the todos a todo waits on. AddBlocker
+	// fails with ErrDependencyCycle when the blocker already waits on the
+	// todo. GetDependencyGraph loads a project's todos with the dependencies
+	// between them and the todos elsewhere the user can see.
+	AddBlocker(ctx context.Context, todoId int, blockerId int) error
+	RemoveBlocker(ctx context.Context, todoId int, blockerId int) error
+	ListBlockers(ctx context.Context, userId int, todoId int) ([]domain.Todo, error)
+	GetDependencyGraph(ctx context.Context, userId int, projectId int) (domain.DependencyGraph, error)
 }
 
 type TodoService interface {
-	GetTodosWithPagination(ctx context.Context, userId int, limit int, cursor string) (*response.CursorResponse, error)
+	GetTodosWithPagination(ctx context.Context, userId int, limit int, cursor string, filter domain.TodoFilter) (*response.CursorResponse, error)
+	GetByUUID(ctx context.Context, userId int, uid string) (domain.Todo, error)
+	Search(ctx context.Context, userId int, query string, limit int, cursor string) (*response.CursorResponse, error)
 	Create(ctx context.Context, todo domain.Todo) (domain.Todo, error)
-	UpdateByUUID(ctx context.Context, todo domain.Todo) (domain.Todo, error)
-	DeleteByUUID(ctx context.Context, uid string) error
+	UpdateByUUID(ctx context.Context, userId int, todo domain.Todo) (domain.Todo, error)
+	DeleteByUUID(ctx context.Context, userId int, uid string, version int) error
+
+	// Patch applies a partial update where fields left out stay untouched and
+	// null ones are cleared, see domain.TodoPatch
+	Patch(ctx context.Context, userId int, uid string, patch domain.TodoPatch) (domain.Todo, error)
+
+	// Occurrences previews the next n occurrences of a recurring todo, starting
+	// with its current due date. SkipOccurrence drops one of them, the current
+	// one when at is nil, moving the todo on to the following date.
+	Occurrences(ctx context.Context, userId int, uid string, n int) ([]time.Time, error)
+	SkipOccurrence(ctx context.Context, userId int, uid string, at *time.Time) (domain.Todo, error)
+
+	// History pages through the changes made to a todo. Revert restores the
+	// todo as it was before a revision, recorded as a revision of its own.
+	History(ctx context.Context, userId int, uid string, limit int, cursor string) (*response.CursorResponse, error)
+	Revert(ctx context.Context, userId int, uid string, number int) (domain.Todo, error)
+
+	// Blockers lists the todos a todo waits on. A todo cannot be completed
+	// while one of them is open unless the status change forces it.
+	Blockers(ctx context.Context, userId int, uid string) ([]domain.Todo, error)
+	AddBlocker(ctx context.Context, userId int, uid string, blockerUid string) (domain.Todo, error)
+	RemoveBlocker(ctx context.Context, userId int, uid string, blockerUid string) (domain.Todo, error)
+	DependencyGraph(ctx context.Context, userId int, projectUid string) (domain.DependencyGraph, error)
+
+	// Authorize loads a todo and checks the caller may perform action on it,
+	// for use cases that hang off a todo such as reminders and checklist items
+	Authorize(ctx context.Context, userId int, action domain.TodoAction, uid string) (domain.Todo, error)
 }
@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+
+	"todos/internal/core/domain"
+)
+
+// TodoBatchService applies a list of todo operations in one transaction.
+// Every operation is authorized and validated as if sent on its own.
+type TodoBatchService interface {
+	Apply(ctx context.Context, userId int, mode domain.TodoBatchMode, operations []domain.TodoOperation) (domain.TodoBatchResult, error)
+}
@@ -0,0 +1,25 @@
+package port
+
+import (
+	"context"
+
+	"todos/internal/core/domain"
+)
+
+// TodoItemRepository scopes every lookup to the parent todo id, so an item
+// can never be reached through another todo
+type TodoItemRepository interface {
+	ListByTodo(ctx context.Context, todoId int) ([]domain.TodoItem, error)
+	GetByUUID(ctx context.Context, todoId int, uid string) (domain.TodoItem, error)
+	Create(ctx context.Context, item domain.TodoItem) (domain.TodoItem, error)
+	UpdateByUUID(ctx context.Context, item domain.TodoItem) (domain.TodoItem, error)
+	DeleteByUUID(ctx context.Context, todoId int, uid string) error
+}
+
+type TodoItemService interface {
+	List(ctx context.Context, userId int, todoUUID string) ([]domain.TodoItem, error)
+	GetByUUID(ctx context.Context, userId int, todoUUID string, itemUUID string) (domain.TodoItem, error)
+	Create(ctx context.Context, userId int, todoUUID string, item domain.TodoItem) (domain.TodoItem, error)
+	UpdateByUUID(ctx context.Context, userId int, todoUUID string, itemUUID string, changes domain.TodoItemChanges) (domain.TodoItem, error)
+	DeleteByUUID(ctx context.Context, userId int, todoUUID string, itemUUID string, completeParent bool) error
+}
@@ -0,0 +1,18 @@
+package port
+
+import (
+	"context"
+
+	"todos/internal/core/domain"
+)
+
+// TodoTransferService moves a user's todos in and out of the API in bulk
+type TodoTransferService interface {
+	// Export hands every todo the user sees to each, oldest first. Todos are
+	// loaded a page at a time, so each can stream them out.
+	Export(ctx context.Context, userId int, each func(domain.Todo) error) error
+
+	// Import creates the user's todos read from a file, reporting on every
+	// one of them. A dry run only reports.
+	Import(ctx context.Context, userId int, todos []domain.ImportedTodo, options domain.TodoImportOptions) (domain.TodoImport, error)
+}
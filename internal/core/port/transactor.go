@@ -0,0 +1,10 @@
+package port
+
+import "context"
+
+// Transactor runs fn in a database transaction carried by the context fn
+// receives. Repositories called with that context join the transaction,
+// nested calls only roll back their own work when fn fails.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
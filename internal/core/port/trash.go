@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+)
+
+// TrashService works on deleted todos. Restoring and deleting for good are
+// open to whoever may delete the todo.
+type TrashService interface {
+	List(ctx context.Context, userId int, limit int, cursor string) (*response.CursorResponse, error)
+	Restore(ctx context.Context, userId int, uid string) (domain.Todo, error)
+	Delete(ctx context.Context, userId int, uid string) error
+}
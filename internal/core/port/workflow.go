@@ -0,0 +1,24 @@
+package port
+
+import (
+	"context"
+
+	"todos/internal/core/domain"
+)
+
+type WorkflowRepository interface {
+	// GetDefault loads the workflow todos outside projects follow
+	GetDefault(ctx context.Context) (domain.Workflow, error)
+
+	// GetByProject returns domain.ErrWorkflowNotFound when the project has no
+	// workflow of its own
+	GetByProject(ctx context.Context, projectId int) (domain.Workflow, error)
+
+	// Save replaces the statuses and transitions of the project's workflow,
+	// creating it when the project had none
+	Save(ctx context.Context, workflow domain.Workflow) (domain.Workflow, error)
+	Delete(ctx context.Context, workflow domain.Workflow) error
+
+	// StatusesInUse lists the distinct statuses of the project's live todos
+	StatusesInUse(ctx context.Context, projectId int) ([]domain.TodoStatus, error)
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+)
+
+const DefaultAttachmentLinkTTL = 15 * time.Minute
+
+type AttachmentService struct {
+	repo      port.AttachmentRepository
+	blobs     port.BlobStore
+	todos     port.TodoService
+	clock     port.Clock
+	secret    []byte
+	telemetry port.Telemetry
+
+	LinkTTL time.Duration
+}
+
+func NewAttachmentService(repo port.AttachmentRepository, blobs port.BlobStore, todos port.TodoService, clock port.Clock, secret string, telemetry port.Telemetry) *AttachmentService {
+	return &AttachmentService{
+		repo:      repo,
+		blobs:     blobs,
+		todos:     todos,
+		clock:     clock,
+		secret:    []byte(secret),
+		telemetry: telemetry,
+		LinkTTL:   DefaultAttachmentLinkTTL,
+	}
+}
+
+func (as *AttachmentService) List(ctx context.Context, userId int, todoUUID string) ([]domain.Attachment, error) {
+	start := time.Now()
+
+	todo, err := as.todos.Authorize(ctx, userId, domain.TodoActionView, todoUUID)
+
+	if err != nil {
+		return []domain.Attachment{}, err
+	}
+
+	attachments, err := as.repo.ListByTodo(ctx, todo.ID)
+
+	as.telemetry.RecordServiceOperation(ctx, "attachment", "List", userId, time.Since(start), err)
+
+	return attachments, err
+}
+
+func (as *AttachmentService) GetByUUID(ctx context.Context, userId int, todoUUID string, attachmentUUID string) (domain.Attachment, error) {
+	todo, err := as.todos.Authorize(ctx, userId, domain.TodoActionView, todoUUID)
+
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	return as.repo.GetByUUID(ctx, todo.ID, attachmentUUID)
+}
+
+// Upload stores body as a new attachment. The content type must already be
+// sniffed from the contents; the size is counted while writing, so a client
+// understating it still cannot go past the limit.
+func (as *AttachmentService) Upload(ctx context.Context, userId int, todoUUID string, attachment domain.Attachment, body io.Reader) (domain.Attachment, error) {
+	start := time.Now()
+
+	if err := domain.CheckAttachment(attachment.ContentType, attachment.Size); err != nil {
+		return domain.Attachment{}, err
+	}
+
+	todo, err := as.todos.Authorize(ctx, userId, domain.TodoActionUpdate, todoUUID)
+
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	now := time.Now()
+
+	attachment.UUID = uuid.New()
+	attachment.TodoId = todo.ID
+	attachment.UserId = userId
+	attachment.StorageKey = fmt.Sprintf("todos/%s/%s", todo.UUID, attachment.UUID)
+	attachment.CreatedAt = now
+	attachment.UpdatedAt = now
+
+	written, err := as.blobs.Put(ctx, attachment.StorageKey, io.LimitReader(body, domain.MaxAttachmentSize+1))
+
+	if err == nil && written > domain.MaxAttachmentSize {
+		err = domain.ErrAttachmentTooLarge
+	}
+
+	if err == nil && written == 0 {
+		err = domain.ErrAttachmentEmpty
+	}
+
+	var saved domain.Attachment
+
+	if err == nil {
+		attachment.Size = written
+		saved, err = as.repo.Create(ctx, attachment)
+	}
+
+	as.telemetry.RecordServiceOperation(ctx, "attachment", "Upload", userId, time.Since(start), err)
+
+	if err != nil {
+		as.blobs.Delete(ctx, attachment.StorageKey)
+		return domain.Attachment{}, err
+	}
+
+	as.telemetry.RecordBusinessEvent(ctx, "uploaded", "attachment", saved.UUID.String(), userId, map[string]interface{}{
+		"todo_uuid":    todoUUID,
+		"content_type": saved.ContentType,
+		"size":         saved.Size,
+	})
+
+	return saved, nil
+}
+
+// Delete hides the attachment right away and leaves its blob to the purge
+func (as *AttachmentService) Delete(ctx context.Context, userId int, todoUUID string, attachmentUUID string) error {
+	start := time.Now()
+
+	todo, err := as.todos.Authorize(ctx, userId, domain.TodoActionUpdate, todoUUID)
+
+	if err != nil {
+		return err
+	}
+
+	attachment, err := as.repo.GetByUUID(ctx, todo.ID, attachmentUUID)
+
+	if err != nil {
+		return err
+	}
+
+	err = as.repo.Delete(ctx, attachment)
+
+	as.telemetry.RecordServiceOperation(ctx, "attachment", "Delete", userId, time.Since(start), err)
+
+	if err != nil {
+		return err
+	}
+
+	as.telemetry.RecordBusinessEvent(ctx, "deleted", "attachment", attachmentUUID, userId, map[string]interface{}{
+		"todo_uuid": todoUUID,
+	})
+
+	return nil
+}
+
+// Sign issues no link without a key, attachments can then be listed and
+// uploaded but not downloaded
+func (as *AttachmentService) Sign(attachment domain.Attachment) domain.AttachmentLink {
+	if len(as.secret) == 0 {
+		return domain.AttachmentLink{}
+	}
+
+	expiresAt := as.clock.Now().Add(as.LinkTTL).Truncate(time.Second)
+	expires := expiresAt.Unix()
+
+	return domain.AttachmentLink{
+		URL:       fmt.Sprintf("/attachments/%s/download?expires=%d&signature=%s", attachment.UUID, expires, as.signature(attachment.UUID.String(), expires)),
+		ExpiresAt: expiresAt,
+	}
+}
+
+// Download needs no user: holding a valid, unexpired link is the permission
+func (as *AttachmentService) Download(ctx context.Context, attachmentUUID string, expires int64, signature string) (domain.Attachment, io.ReadCloser, error) {
+	start := time.Now()
+
+	// Without a key every signature could be computed by anyone
+	if len(as.secret) == 0 || as.clock.Now().Unix() > expires || !hmac.Equal([]byte(signature), []byte(as.signature(attachmentUUID, expires))) {
+		return domain.Attachment{}, nil, domain.ErrInvalidDownloadLink
+	}
+
+	attachment, err := as.repo.Find(ctx, attachmentUUID)
+
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+
+	body, err := as.blobs.Open(ctx, attachment.StorageKey)
+
+	as.telemetry.RecordServiceOperation(ctx, "attachment", "Download", 0, time.Since(start), err)
+
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+
+	return attachment, body, nil
+}
+
+// signature is a hex HMAC-SHA256 of "<uuid>.<expires>"
+func (as *AttachmentService) signature(attachmentUUID string, expires int64) string {
+	mac := hmac.New(sha256.New, as.secret)
+	mac.Write([]byte(attachmentUUID + "." + strconv.FormatInt(expires, 10)))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
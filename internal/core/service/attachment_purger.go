@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"todos/internal/core/port"
+)
+
+const (
+	DefaultAttachmentPurgeInterval  = time.Hour
+	DefaultAttachmentPurgeRetention = 7 * 24 * time.Hour
+	DefaultAttachmentPurgeBatchSize = 100
+)
+
+// AttachmentPurger removes the blobs of attachments that were deleted more
+// than Retention ago. The blob goes first and the row after, so a failed run
+// leaves the row behind to be retried. Attachments of a deleted todo stay
+// until the todo leaves the trash, see TrashService.
+type AttachmentPurger struct {
+	repo      port.AttachmentRepository
+	blobs     port.BlobStore
+	clock     port.Clock
+	telemetry port.Telemetry
+
+	Interval  time.Duration
+	Retention time.Duration
+	BatchSize int
+}
+
+func NewAttachmentPurger(repo port.AttachmentRepository, blobs port.BlobStore, clock port.Clock, telemetry port.Telemetry) *AttachmentPurger {
+	return &AttachmentPurger{
+		repo:      repo,
+		blobs:     blobs,
+		clock:     clock,
+		telemetry: telemetry,
+		Interval:  DefaultAttachmentPurgeInterval,
+		Retention: DefaultAttachmentPurgeRetention,
+		BatchSize: DefaultAttachmentPurgeBatchSize,
+	}
+}
+
+// Run purges until ctx is cancelled
+func (ap *AttachmentPurger) Run(ctx context.Context) {
+	slog.Info("Attachment purger started", "interval", ap.Interval, "retention", ap.Retention)
+
+	ticker := time.NewTicker(ap.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := ap.Purge(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("Attachment purge failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("Attachment purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge removes every purgeable attachment, returning how many were removed
+func (ap *AttachmentPurger) Purge(ctx context.Context) (int, error) {
+	start := time.Now()
+	purged := 0
+
+	for {
+		attachments, err := ap.repo.ListPurgeable(ctx, ap.clock.Now().Add(-ap.Retention), ap.BatchSize)
+
+		if err != nil {
+			ap.telemetry.RecordServiceOperation(ctx, "attachment", "Purge", 0, time.Since(start), err)
+			return purged, err
+		}
+
+		progress := 0
+
+		for _, attachment := range attachments {
+			if err := ap.blobs.Delete(ctx, attachment.StorageKey); err != nil {
+				slog.Warn("Error deleting attachment blob", "attachment", attachment.UUID, "error", err)
+				continue
+			}
+
+			if err := ap.repo.Purge(ctx, attachment.ID); err != nil {
+				slog.Error("Error purging attachment", "attachment", attachment.UUID, "error", err)
+				continue
+			}
+
+			progress++
+		}
+
+		purged += progress
+
+		// A batch that failed entirely would come back unchanged next time
+		if len(attachments) < ap.BatchSize || progress == 0 {
+			break
+		}
+	}
+
+	ap.telemetry.RecordServiceOperation(ctx, "attachment", "Purge", 0, time.Since(start), nil)
+
+	return purged, nil
+}
@@ -0,0 +1,103 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	. "todos/pkg/test"
+
+	"todos/internal/adapter/database/sqlite/repository"
+	"todos/internal/adapter/storage"
+	"todos/internal/core/domain"
+	"todos/internal/core/service"
+	"todos/internal/core/telemetry"
+)
+
+func TestAttachmentPurger_KeepsBlobsUntilRetentionPasses(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := context.Background()
+	db := InitTestDB()
+	probe := telemetry.NewNoOpProbe()
+
+	userRepo := repository.NewUserRepository(db, probe)
+	todoRepo := repository.NewTodoRepository(db, probe)
+	attachmentRepo := repository.NewAttachmentRepository(db, probe)
+	blobs := storage.NewFilesystemStore(t.TempDir())
+	clock := NewFakeClock(time.Now())
+
+	purger := service.NewAttachmentPurger(attachmentRepo, blobs, clock, probe)
+
+	user, _ := userRepo.Create(ctx, domain.User{UUID: uuid.New(), Name: "Test User", Email: "test@example.com"})
+
+	createAttachment := func(todo domain.Todo) domain.Attachment {
+		attachment := domain.Attachment{
+			UUID:        uuid.New(),
+			TodoId:      todo.ID,
+			UserId:      user.ID,
+			Filename:    "notes.txt",
+			ContentType: "text/plain",
+			Size:        5,
+			StorageKey:  "todos/" + todo.UUID.String() + "/" + uuid.NewString(),
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+
+		_, err := blobs.Put(ctx, attachment.StorageKey, strings.NewReader("notes"))
+		Expect(err).To(BeNil())
+
+		saved, err := attachmentRepo.Create(ctx, attachment)
+		Expect(err).To(BeNil())
+
+		return saved
+	}
+
+	createTodo := func(title string) domain.Todo {
+		todo, err := todoRepo.Create(ctx, domain.Todo{UUID: uuid.New(), Title: title, UserId: user.ID, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+		Expect(err).To(BeNil())
+
+		return todo
+	}
+
+	kept := createAttachment(createTodo("Kept"))
+	removed := createAttachment(createTodo("Has a removed attachment"))
+
+	trashedTodo := createTodo("Trashed")
+	trashed := createAttachment(trashedTodo)
+
+	Expect(attachmentRepo.Delete(ctx, removed)).To(Succeed())
+	Expect(todoRepo.DeleteByUUID(ctx, trashedTodo.UUID.String(), 0)).To(Succeed())
+
+	// A deleted todo hides its attachments but keeps the blobs for now
+	_, err := attachmentRepo.Find(ctx, trashed.UUID.String())
+	Expect(err).To(MatchError(domain.ErrAttachmentNotFound))
+
+	purged, err := purger.Purge(ctx)
+	Expect(err).To(BeNil())
+	Expect(purged).To(Equal(0))
+
+	body, err := blobs.Open(ctx, trashed.StorageKey)
+	Expect(err).To(BeNil())
+	body.Close()
+
+	clock.Advance(service.DefaultAttachmentPurgeRetention + time.Hour)
+
+	purged, err = purger.Purge(ctx)
+	Expect(err).To(BeNil())
+	Expect(purged).To(Equal(1))
+
+	_, err = blobs.Open(ctx, removed.StorageKey)
+	Expect(err).To(MatchError(domain.ErrBlobNotFound))
+
+	// The trashed todo may still be restored, its attachments go with it
+	for _, attachment := range []domain.Attachment{kept, trashed} {
+		body, err = blobs.Open(ctx, attachment.StorageKey)
+		Expect(err).To(BeNil())
+		body.Close()
+	}
+}
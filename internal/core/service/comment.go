@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+	"todos/internal/core/port"
+	"todos/internal/core/util"
+)
+
+type CommentService struct {
+	repo      port.CommentRepository
+	todos     port.TodoService
+	telemetry port.Telemetry
+}
+
+func NewCommentService(repo port.CommentRepository, todos port.TodoService, telemetry port.Telemetry) *CommentService {
+	return &CommentService{
+		repo:      repo,
+		todos:     todos,
+		telemetry: telemetry,
+	}
+}
+
+// List pages through the comments of a todo the user can see
+func (cs *CommentService) List(ctx context.Context, userId int, todoUUID string, limit int, cursor string) (*response.CursorResponse, error) {
+	start := time.Now()
+
+	todo, err := cs.todos.Authorize(ctx, userId, domain.TodoActionView, todoUUID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	comments, hasNext, err := cs.repo.ListByTodo(ctx, todo.ID, limit, cursor)
+
+	cs.telemetry.RecordServiceOperation(ctx, "comment", "List", userId, time.Since(start), err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]response.CommentResponse, 0, len(comments))
+
+	for _, comment := range comments {
+		data = append(data, response.NewCommentResponse(comment))
+	}
+
+	var nextCursor string
+
+	if hasNext && len(comments) > 0 {
+		nextCursor = util.EncodeSortCursor(domain.CommentCursorSort, "", comments[len(comments)-1].ID)
+	}
+
+	dataBytes, _ := util.Serialize(data)
+
+	responsable := response.CursorResponse{
+		Size: len(data),
+		Data: dataBytes,
+	}
+
+	responsable.Pagination.HasNext = hasNext
+	responsable.Pagination.NextCursor = nextCursor
+
+	return &responsable, nil
+}
+
+// Create adds a comment, or a reply when ParentUUID is set. Anyone who can
+// see the todo can take part in its discussion.
+func (cs *CommentService) Create(ctx context.Context, userId int, todoUUID string, comment domain.Comment) (domain.Comment, error) {
+	start := time.Now()
+
+	todo, err := cs.todos.Authorize(ctx, userId, domain.TodoActionView, todoUUID)
+
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	if comment.ParentUUID != nil {
+		parent, err := cs.repo.GetByUUID(ctx, todo.ID, comment.ParentUUID.String())
+
+		if errors.Is(err, domain.ErrCommentNotFound) {
+			return domain.Comment{}, domain.ErrInvalidCommentParent
+		}
+
+		if err != nil {
+			return domain.Comment{}, err
+		}
+
+		comment.ParentId = &parent.ID
+	}
+
+	now := time.Now()
+
+	comment.UUID = uuid.New()
+	comment.TodoId = todo.ID
+	comment.UserId = userId
+	comment.CreatedAt = now
+	comment.UpdatedAt = now
+
+	saved, err := cs.repo.Create(ctx, comment)
+
+	cs.telemetry.RecordServiceOperation(ctx, "comment", "Create", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	cs.telemetry.RecordBusinessEvent(ctx, "created", "comment", saved.UUID.String(), userId, map[string]interface{}{
+		"todo_uuid": todoUUID,
+		"reply":     saved.ParentId != nil,
+	})
+
+	return saved, nil
+}
+
+// Update edits the body of a comment, only its author may do so
+func (cs *CommentService) Update(ctx context.Context, userId int, todoUUID string, commentUUID string, body string) (domain.Comment, error) {
+	start := time.Now()
+
+	comment, err := cs.find(ctx, userId, todoUUID, commentUUID)
+
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	if !comment.IsAuthoredBy(userId) {
+		return domain.Comment{}, domain.ErrForbidden
+	}
+
+	if body == comment.Body {
+		return comment, nil
+	}
+
+	updated, err := cs.repo.Update(ctx, comment, body)
+
+	cs.telemetry.RecordServiceOperation(ctx, "comment", "Update", userId, time.Since(start), err)
+
+	return updated, err
+}
+
+// Delete removes a comment. Authors can delete their own comments, and
+// whoever may delete the todo can moderate the discussion under it.
+func (cs *CommentService) Delete(ctx context.Context, userId int, todoUUID string, commentUUID string) error {
+	start := time.Now()
+
+	comment, err := cs.find(ctx, userId, todoUUID, commentUUID)
+
+	if err != nil {
+		return err
+	}
+
+	if !comment.IsAuthoredBy(userId) {
+		if _, err := cs.todos.Authorize(ctx, userId, domain.TodoActionDelete, todoUUID); err != nil {
+			return err
+		}
+	}
+
+	err = cs.repo.Delete(ctx, comment)
+
+	cs.telemetry.RecordServiceOperation(ctx, "comment", "Delete", userId, time.Since(start), err)
+
+	if err != nil {
+		return err
+	}
+
+	cs.telemetry.RecordBusinessEvent(ctx, "deleted", "comment", commentUUID, userId, map[string]interface{}{
+		"todo_uuid": todoUUID,
+		"author":    comment.AuthorUUID.String(),
+	})
+
+	return nil
+}
+
+// History lists the earlier bodies of a comment, newest first
+func (cs *CommentService) History(ctx context.Context, userId int, todoUUID string, commentUUID string) ([]domain.CommentRevision, error) {
+	start := time.Now()
+
+	comment, err := cs.find(ctx, userId, todoUUID, commentUUID)
+
+	if err != nil {
+		return []domain.CommentRevision{}, err
+	}
+
+	revisions, err := cs.repo.ListRevisions(ctx, comment.ID)
+
+	cs.telemetry.RecordServiceOperation(ctx, "comment", "History", userId, time.Since(start), err)
+
+	return revisions, err
+}
+
+// find loads a comment through a todo the user can see
+func (cs *CommentService) find(ctx context.Context, userId int, todoUUID string, commentUUID string) (domain.Comment, error) {
+	todo, err := cs.todos.Authorize(ctx, userId, domain.TodoActionView, todoUUID)
+
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	return cs.repo.GetByUUID(ctx, todo.ID, commentUUID)
+}
@@ -0,0 +1,507 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+)
+
+type ProjectService struct {
+	repo      port.ProjectRepository
+	users     port.UserRepository
+	workflows port.WorkflowRepository
+	telemetry port.Telemetry
+}
+
+func NewProjectService(repo port.ProjectRepository, users port.UserRepository, workflows port.WorkflowRepository, telemetry port.Telemetry) *ProjectService {
+	return &ProjectService{
+		repo:      repo,
+		users:     users,
+		workflows: workflows,
+		telemetry: telemetry,
+	}
+}
+
+func (ps *ProjectService) List(ctx context.Context, userId int, archived bool) ([]domain.Project, error) {
+	start := time.Now()
+
+	projects, err := ps.repo.ListByUser(ctx, userId, archived)
+
+	ps.telemetry.RecordServiceOperation(ctx, "project", "List", userId, time.Since(start), err)
+
+	return projects, err
+}
+
+func (ps *ProjectService) GetByUUID(ctx context.Context, userId int, uid string) (domain.Project, error) {
+	start := time.Now()
+
+	project, err := ps.findWithRole(ctx, userId, uid, domain.ProjectViewer)
+
+	ps.telemetry.RecordServiceOperation(ctx, "project", "GetByUUID", userId, time.Since(start), err)
+
+	return project, err
+}
+
+func (ps *ProjectService) Create(ctx context.Context, project domain.Project) (domain.Project, error) {
+	start := time.Now()
+	now := time.Now()
+
+	created, err := ps.repo.Create(ctx, domain.Project{
+		UUID:        uuid.New(),
+		UserId:      project.UserId,
+		Name:        project.Name,
+		Description: project.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+
+	ps.telemetry.RecordServiceOperation(ctx, "project", "Create", project.UserId, time.Since(start), err)
+
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	created.Role = domain.ProjectOwner
+
+	ps.telemetry.RecordBusinessEvent(ctx, "created", "project", created.UUID.String(), created.UserId, map[string]interface{}{
+		"name": created.Name,
+	})
+
+	return created, nil
+}
+
+func (ps *ProjectService) Update(ctx context.Context, userId int, uid string, changes domain.ProjectChanges) (domain.Project, error) {
+	start := time.Now()
+
+	project, err := ps.findWithRole(ctx, userId, uid, domain.ProjectOwner)
+
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	if changes.Name != nil {
+		project.Name = *changes.Name
+	}
+
+	if changes.Description != nil {
+		project.Description = *changes.Description
+	}
+
+	if changes.Position != nil {
+		project.Position = *changes.Position
+	}
+
+	updated, err := ps.repo.Update(ctx, project)
+
+	ps.telemetry.RecordServiceOperation(ctx, "project", "Update", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	updated.Role = project.Role
+
+	return updated, nil
+}
+
+// Archive hides the project and its todos from the default lists
+func (ps *ProjectService) Archive(ctx context.Context, userId int, uid string) (domain.Project, error) {
+	return ps.setArchived(ctx, userId, uid, true)
+}
+
+func (ps *ProjectService) Unarchive(ctx context.Context, userId int, uid string) (domain.Project, error) {
+	return ps.setArchived(ctx, userId, uid, false)
+}
+
+func (ps *ProjectService) Delete(ctx context.Context, userId int, uid string) error {
+	start := time.Now()
+
+	project, err := ps.findWithRole(ctx, userId, uid, domain.ProjectOwner)
+
+	if err != nil {
+		return err
+	}
+
+	err = ps.repo.Delete(ctx, project)
+
+	ps.telemetry.RecordServiceOperation(ctx, "project", "Delete", userId, time.Since(start), err)
+
+	if err != nil {
+		return err
+	}
+
+	ps.telemetry.RecordBusinessEvent(ctx, "deleted", "project", uid, userId, map[string]interface{}{
+		"name":       project.Name,
+		"todo_count": project.TodoCount,
+	})
+
+	return nil
+}
+
+func (ps *ProjectService) Reorder(ctx context.Context, userId int, uid string, todoUUIDs []string) error {
+	start := time.Now()
+
+	project, err := ps.findWithRole(ctx, userId, uid, domain.ProjectEditor)
+
+	if err != nil {
+		return err
+	}
+
+	err = ps.repo.Reorder(ctx, project, todoUUIDs)
+
+	ps.telemetry.RecordServiceOperation(ctx, "project", "Reorder", userId, time.Since(start), err)
+
+	return err
+}
+
+func (ps *ProjectService) setArchived(ctx context.Context, userId int, uid string, archived bool) (domain.Project, error) {
+	start := time.Now()
+
+	project, err := ps.findWithRole(ctx, userId, uid, domain.ProjectOwner)
+
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	if project.IsArchived() == archived {
+		return project, nil
+	}
+
+	role := project.Role
+
+	project, err = ps.repo.SetArchived(ctx, project, archived)
+
+	ps.telemetry.RecordServiceOperation(ctx, "project", "SetArchived", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	project.Role = role
+
+	event := "unarchived"
+
+	if archived {
+		event = "archived"
+	}
+
+	ps.telemetry.RecordBusinessEvent(ctx, event, "project", uid, userId, map[string]interface{}{
+		"name":       project.Name,
+		"todo_count": project.TodoCount,
+	})
+
+	return project, nil
+}
+
+func (ps *ProjectService) ListMembers(ctx context.Context, userId int, uid string) ([]domain.ProjectMember, error) {
+	start := time.Now()
+
+	project, err := ps.findWithRole(ctx, userId, uid, domain.ProjectViewer)
+
+	if err != nil {
+		return []domain.ProjectMember{}, err
+	}
+
+	members, err := ps.repo.ListMembers(ctx, project.ID)
+
+	ps.telemetry.RecordServiceOperation(ctx, "project", "ListMembers", userId, time.Since(start), err)
+
+	return members, err
+}
+
+// InviteMember adds an existing user, found by email, to the project
+func (ps *ProjectService) InviteMember(ctx context.Context, userId int, uid string, email string, role domain.ProjectRole) (domain.ProjectMember, error) {
+	start := time.Now()
+
+	if err := role.Validate(); err != nil {
+		return domain.ProjectMember{}, err
+	}
+
+	project, err := ps.findWithRole(ctx, userId, uid, domain.ProjectOwner)
+
+	if err != nil {
+		return domain.ProjectMember{}, err
+	}
+
+	user, err := ps.users.GetByEmail(ctx, email)
+
+	if err != nil || user.ID == 0 {
+		return domain.ProjectMember{}, domain.ErrUserNotFound
+	}
+
+	member, err := ps.repo.AddMember(ctx, domain.ProjectMember{
+		ProjectId: project.ID,
+		UserId:    user.ID,
+		Role:      role,
+	})
+
+	ps.telemetry.RecordServiceOperation(ctx, "project", "InviteMember", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.ProjectMember{}, err
+	}
+
+	ps.telemetry.RecordBusinessEvent(ctx, "member_added", "project", uid, userId, map[string]interface{}{
+		"member": member.UserUUID.String(),
+		"role":   string(member.Role),
+	})
+
+	return member, nil
+}
+
+func (ps *ProjectService) UpdateMemberRole(ctx context.Context, userId int, uid string, memberUUID string, role domain.ProjectRole) (domain.ProjectMember, error) {
+	start := time.Now()
+
+	if err := role.Validate(); err != nil {
+		return domain.ProjectMember{}, err
+	}
+
+	project, err := ps.findWithRole(ctx, userId, uid, domain.ProjectOwner)
+
+	if err != nil {
+		return domain.ProjectMember{}, err
+	}
+
+	member, err := ps.findMember(ctx, project, memberUUID)
+
+	if err != nil {
+		return domain.ProjectMember{}, err
+	}
+
+	updated, err := ps.repo.UpdateMemberRole(ctx, member, role)
+
+	ps.telemetry.RecordServiceOperation(ctx, "project", "UpdateMemberRole", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.ProjectMember{}, err
+	}
+
+	ps.telemetry.RecordBusinessEvent(ctx, "member_role_changed", "project", uid, userId, map[string]interface{}{
+		"member": memberUUID,
+		"from":   string(member.Role),
+		"to":     string(updated.Role),
+	})
+
+	return updated, nil
+}
+
+func (ps *ProjectService) RemoveMember(ctx context.Context, userId int, uid string, memberUUID string) error {
+	start := time.Now()
+
+	project, err := ps.findWithRole(ctx, userId, uid, domain.ProjectViewer)
+
+	if err != nil {
+		return err
+	}
+
+	member, err := ps.findMember(ctx, project, memberUUID)
+
+	if err != nil {
+		return err
+	}
+
+	if member.UserId != userId && !project.Role.AtLeast(domain.ProjectOwner) {
+		return domain.ErrForbidden
+	}
+
+	err = ps.repo.RemoveMember(ctx, member)
+
+	ps.telemetry.RecordServiceOperation(ctx, "project", "RemoveMember", userId, time.Since(start), err)
+
+	if err != nil {
+		return err
+	}
+
+	ps.telemetry.RecordBusinessEvent(ctx, "member_removed", "project", uid, userId, map[string]interface{}{
+		"member": memberUUID,
+		"role":   string(member.Role),
+	})
+
+	return nil
+}
+
+func (ps *ProjectService) GetWorkflow(ctx context.Context, userId int, uid string) (domain.Workflow, error) {
+	start := time.Now()
+
+	project, err := ps.findWithRole(ctx, userId, uid, domain.ProjectViewer)
+
+	if err != nil {
+		return domain.Workflow{}, err
+	}
+
+	workflow, err := ps.workflowOf(ctx, project)
+
+	ps.telemetry.RecordServiceOperation(ctx, "project", "GetWorkflow", userId, time.Since(start), err)
+
+	return workflow, err
+}
+
+// DefineWorkflow replaces the project's statuses and transitions, creating
+// the project workflow the first time
+func (ps *ProjectService) DefineWorkflow(ctx context.Context, userId int, uid string, workflow domain.Workflow) (domain.Workflow, error) {
+	start := time.Now()
+
+	project, err := ps.findWithRole(ctx, userId, uid, domain.ProjectOwner)
+
+	if err != nil {
+		return domain.Workflow{}, err
+	}
+
+	if workflow.ReviewRule == "" {
+		workflow.ReviewRule = domain.ReviewRuleAll
+	}
+
+	if err := workflow.Validate(); err != nil {
+		return domain.Workflow{}, err
+	}
+
+	current, err := ps.workflows.GetByProject(ctx, project.ID)
+
+	switch {
+	case err == nil:
+		workflow.ID = current.ID
+		workflow.UUID = current.UUID
+	case errors.Is(err, domain.ErrWorkflowNotFound):
+		workflow.ID = 0
+		workflow.UUID = uuid.New()
+	default:
+		return domain.Workflow{}, err
+	}
+
+	workflow.ProjectId = &project.ID
+
+	if err := ps.ensureStatusesKept(ctx, project, workflow); err != nil {
+		return domain.Workflow{}, err
+	}
+
+	saved, err := ps.workflows.Save(ctx, workflow)
+
+	ps.telemetry.RecordServiceOperation(ctx, "project", "DefineWorkflow", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.Workflow{}, err
+	}
+
+	ps.telemetry.RecordBusinessEvent(ctx, "workflow_defined", "project", uid, userId, map[string]interface{}{
+		"statuses":    len(saved.Statuses),
+		"transitions": len(saved.Transitions),
+	})
+
+	return saved, nil
+}
+
+// ResetWorkflow drops the project's own workflow so it follows the default
+func (ps *ProjectService) ResetWorkflow(ctx context.Context, userId int, uid string) (domain.Workflow, error) {
+	start := time.Now()
+
+	project, err := ps.findWithRole(ctx, userId, uid, domain.ProjectOwner)
+
+	if err != nil {
+		return domain.Workflow{}, err
+	}
+
+	fallback, err := ps.workflows.GetDefault(ctx)
+
+	if err != nil {
+		return domain.Workflow{}, err
+	}
+
+	current, err := ps.workflows.GetByProject(ctx, project.ID)
+
+	if errors.Is(err, domain.ErrWorkflowNotFound) {
+		return fallback, nil
+	}
+
+	if err != nil {
+		return domain.Workflow{}, err
+	}
+
+	if err := ps.ensureStatusesKept(ctx, project, fallback); err != nil {
+		return domain.Workflow{}, err
+	}
+
+	err = ps.workflows.Delete(ctx, current)
+
+	ps.telemetry.RecordServiceOperation(ctx, "project", "ResetWorkflow", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.Workflow{}, err
+	}
+
+	ps.telemetry.RecordBusinessEvent(ctx, "workflow_reset", "project", uid, userId, nil)
+
+	return fallback, nil
+}
+
+func (ps *ProjectService) workflowOf(ctx context.Context, project domain.Project) (domain.Workflow, error) {
+	workflow, err := ps.workflows.GetByProject(ctx, project.ID)
+
+	if errors.Is(err, domain.ErrWorkflowNotFound) {
+		return ps.workflows.GetDefault(ctx)
+	}
+
+	return workflow, err
+}
+
+// ensureStatusesKept refuses a workflow that leaves todos of the project in a
+// status it does not have
+func (ps *ProjectService) ensureStatusesKept(ctx context.Context, project domain.Project, workflow domain.Workflow) error {
+	inUse, err := ps.workflows.StatusesInUse(ctx, project.ID)
+
+	if err != nil {
+		return err
+	}
+
+	for _, key := range inUse {
+		if _, ok := workflow.Status(key); !ok {
+			return fmt.Errorf("%w: %s", domain.ErrStatusInUse, key)
+		}
+	}
+
+	return nil
+}
+
+// findWithRole loads a project for a member holding at least role. Users
+// outside the project get ErrProjectNotFound, members with a lesser role get
+// ErrForbidden. The returned project carries the caller's role.
+func (ps *ProjectService) findWithRole(ctx context.Context, userId int, uid string, role domain.ProjectRole) (domain.Project, error) {
+	project, err := ps.repo.GetByUUID(ctx, uid)
+
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	member, err := ps.repo.GetMember(ctx, project.ID, userId)
+
+	if errors.Is(err, domain.ErrProjectMemberNotFound) {
+		return domain.Project{}, domain.ErrProjectNotFound
+	}
+
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	if !member.Role.AtLeast(role) {
+		return domain.Project{}, domain.ErrForbidden
+	}
+
+	project.Role = member.Role
+
+	return project, nil
+}
+
+func (ps *ProjectService) findMember(ctx context.Context, project domain.Project, memberUUID string) (domain.ProjectMember, error) {
+	user, err := ps.users.GetByUUID(ctx, memberUUID)
+
+	if err != nil || user.ID == 0 {
+		return domain.ProjectMember{}, domain.ErrProjectMemberNotFound
+	}
+
+	return ps.repo.GetMember(ctx, project.ID, user.ID)
+}
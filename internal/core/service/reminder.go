@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+)
+
+type ReminderService struct {
+	repo      port.ReminderRepository
+	todos     port.TodoService
+	telemetry port.Telemetry
+}
+
+func NewReminderService(repo port.ReminderRepository, todos port.TodoService, telemetry port.Telemetry) *ReminderService {
+	return &ReminderService{
+		repo:      repo,
+		todos:     todos,
+		telemetry: telemetry,
+	}
+}
+
+// Create attaches a reminder to a todo the caller can see. Reminders belong to
+// the caller rather than the todo owner, since they notify whoever set them.
+func (rs *ReminderService) Create(ctx context.Context, userId int, todoUUID string, reminder domain.Reminder) (domain.Reminder, domain.Todo, error) {
+	start := time.Now()
+
+	todo, err := rs.todos.GetByUUID(ctx, userId, todoUUID)
+
+	if err != nil {
+		return domain.Reminder{}, domain.Todo{}, err
+	}
+
+	if err := reminder.Validate(); err != nil {
+		return domain.Reminder{}, domain.Todo{}, err
+	}
+
+	if todo.DueAt == nil {
+		return domain.Reminder{}, domain.Todo{}, domain.ErrTodoHasNoDueDate
+	}
+
+	now := time.Now()
+
+	reminder.UUID = uuid.New()
+	reminder.TodoId = todo.ID
+	reminder.UserId = userId
+	reminder.CreatedAt = now
+	reminder.UpdatedAt = now
+
+	saved, err := rs.repo.Create(ctx, reminder)
+
+	rs.telemetry.RecordServiceOperation(ctx, "reminder", "Create", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.Reminder{}, domain.Todo{}, err
+	}
+
+	rs.telemetry.RecordBusinessEvent(ctx, "created", "reminder", saved.UUID.String(), userId, map[string]interface{}{
+		"todo_uuid": todoUUID,
+		"kind":      string(saved.Kind),
+	})
+
+	return saved, todo, nil
+}
+
+func (rs *ReminderService) ListByTodo(ctx context.Context, userId int, todoUUID string) ([]domain.Reminder, domain.Todo, error) {
+	start := time.Now()
+
+	todo, err := rs.todos.GetByUUID(ctx, userId, todoUUID)
+
+	if err != nil {
+		return []domain.Reminder{}, domain.Todo{}, err
+	}
+
+	reminders, err := rs.repo.ListByTodo(ctx, todo.ID, userId)
+
+	rs.telemetry.RecordServiceOperation(ctx, "reminder", "ListByTodo", userId, time.Since(start), err)
+
+	if err != nil {
+		return []domain.Reminder{}, domain.Todo{}, err
+	}
+
+	return reminders, todo, nil
+}
+
+func (rs *ReminderService) DeleteByUUID(ctx context.Context, userId int, todoUUID string, reminderUUID string) error {
+	start := time.Now()
+
+	todo, err := rs.todos.GetByUUID(ctx, userId, todoUUID)
+
+	if err != nil {
+		return err
+	}
+
+	reminder, err := rs.repo.GetByUUID(ctx, reminderUUID)
+
+	if err != nil {
+		return err
+	}
+
+	// Someone else's reminder, or one from another todo, is simply not there
+	if reminder.TodoId != todo.ID || reminder.UserId != userId {
+		return domain.ErrReminderNotFound
+	}
+
+	err = rs.repo.DeleteByUUID(ctx, reminderUUID)
+
+	rs.telemetry.RecordServiceOperation(ctx, "reminder", "DeleteByUUID", userId, time.Since(start), err)
+
+	if err != nil {
+		return err
+	}
+
+	rs.telemetry.RecordBusinessEvent(ctx, "deleted", "reminder", reminderUUID, userId, map[string]interface{}{
+		"todo_uuid": todoUUID,
+	})
+
+	return nil
+}
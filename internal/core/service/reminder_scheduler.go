@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"todos/internal/core/port"
+)
+
+const (
+	DefaultReminderInterval  = 30 * time.Second
+	DefaultReminderBatchSize = 50
+	DefaultReminderClaimTTL  = 5 * time.Minute
+)
+
+// ReminderScheduler periodically delivers due reminders. All state lives in
+// the reminders table: a reminder is claimed before delivery and marked sent
+// after, so restarts never fire a delivered reminder twice. A crash between
+// delivery and MarkSent can repeat that one delivery once the claim goes stale.
+type ReminderScheduler struct {
+	repo      port.ReminderRepository
+	delivery  port.NotificationDelivery
+	clock     port.Clock
+	telemetry port.Telemetry
+
+	Interval  time.Duration
+	BatchSize int
+	ClaimTTL  time.Duration
+}
+
+func NewReminderScheduler(repo port.ReminderRepository, delivery port.NotificationDelivery, clock port.Clock, telemetry port.Telemetry) *ReminderScheduler {
+	return &ReminderScheduler{
+		repo:      repo,
+		delivery:  delivery,
+		clock:     clock,
+		telemetry: telemetry,
+		Interval:  DefaultReminderInterval,
+		BatchSize: DefaultReminderBatchSize,
+		ClaimTTL:  DefaultReminderClaimTTL,
+	}
+}
+
+// Run ticks until ctx is cancelled
+func (rs *ReminderScheduler) Run(ctx context.Context) {
+	slog.Info("Reminder scheduler started", "interval", rs.Interval)
+
+	ticker := time.NewTicker(rs.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := rs.Tick(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("Reminder tick failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("Reminder scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick claims every due reminder and delivers it, returning how many were sent
+func (rs *ReminderScheduler) Tick(ctx context.Context) (int, error) {
+	start := time.Now()
+	sent := 0
+
+	for {
+		now := rs.clock.Now()
+
+		notifications, err := rs.repo.ClaimDue(ctx, now, now.Add(-rs.ClaimTTL), rs.BatchSize)
+
+		if err != nil {
+			rs.telemetry.RecordServiceOperation(ctx, "reminder", "Tick", 0, time.Since(start), err)
+			return sent, err
+		}
+
+		for _, notification := range notifications {
+			if err := rs.delivery.Deliver(ctx, notification); err != nil {
+				slog.Warn("Reminder delivery failed", "reminder", notification.ReminderUUID, "attempt", notification.Attempts+1, "error", err)
+
+				if err := rs.repo.MarkFailed(ctx, notification.ReminderId, err.Error()); err != nil {
+					slog.Error("Error marking reminder as failed", "reminder", notification.ReminderUUID, "error", err)
+				}
+
+				continue
+			}
+
+			if err := rs.repo.MarkSent(ctx, notification.ReminderId, rs.clock.Now()); err != nil {
+				slog.Error("Error marking reminder as sent", "reminder", notification.ReminderUUID, "error", err)
+				continue
+			}
+
+			sent++
+
+			rs.telemetry.RecordBusinessEvent(ctx, "reminder_sent", "reminder", notification.ReminderUUID.String(), notification.UserId, map[string]interface{}{
+				"todo_uuid": notification.TodoUUID.String(),
+				"kind":      string(notification.Kind),
+			})
+		}
+
+		if len(notifications) < rs.BatchSize {
+			break
+		}
+	}
+
+	rs.telemetry.RecordServiceOperation(ctx, "reminder", "Tick", 0, time.Since(start), nil)
+
+	return sent, nil
+}
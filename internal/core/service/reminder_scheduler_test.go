@@ -0,0 +1,226 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/suite"
+
+	. "todos/pkg/test"
+
+	"todos/internal/adapter/database/sqlite/repository"
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	"todos/internal/core/service"
+	"todos/internal/core/telemetry"
+)
+
+type fakeDelivery struct {
+	mu   sync.Mutex
+	sent []domain.Notification
+	err  error
+}
+
+func (f *fakeDelivery) Deliver(ctx context.Context, notification domain.Notification) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+
+	f.sent = append(f.sent, notification)
+	return nil
+}
+
+type ReminderSchedulerTestSuite struct {
+	suite.Suite
+	UserRepo     port.UserRepository
+	TodoRepo     port.TodoRepository
+	ReminderRepo port.ReminderRepository
+	Clock        *FakeClock
+	Delivery     *fakeDelivery
+	Scheduler    *service.ReminderScheduler
+	Todo         domain.Todo
+}
+
+func (s *ReminderSchedulerTestSuite) SetupTest() {
+	db := InitTestDB()
+	probe := telemetry.NewNoOpProbe()
+
+	s.UserRepo = repository.NewUserRepository(db, probe)
+	s.TodoRepo = repository.NewTodoRepository(db, probe)
+	s.ReminderRepo = repository.NewReminderRepository(db, probe)
+	s.Clock = NewFakeClock(time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC))
+	s.Delivery = &fakeDelivery{}
+	s.Scheduler = service.NewReminderScheduler(s.ReminderRepo, s.Delivery, s.Clock, probe)
+
+	user, _ := s.UserRepo.Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Test User",
+		Email: "test@example.com",
+	})
+
+	dueAt := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	s.Todo, _ = s.TodoRepo.Create(context.Background(), domain.Todo{
+		UUID:      uuid.New(),
+		Title:     "Pay rent",
+		DueAt:     &dueAt,
+		UserId:    user.ID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+}
+
+func TestReminderSchedulerTestSuite(t *testing.T) {
+	RegisterTestingT(t)
+
+	suite.Run(t, new(ReminderSchedulerTestSuite))
+}
+
+func (s *ReminderSchedulerTestSuite) createReminder(reminder domain.Reminder) domain.Reminder {
+	reminder.UUID = uuid.New()
+	reminder.TodoId = s.Todo.ID
+	reminder.UserId = s.Todo.UserId
+	reminder.CreatedAt = time.Now()
+	reminder.UpdatedAt = time.Now()
+
+	saved, err := s.ReminderRepo.Create(context.Background(), reminder)
+	Expect(err).To(BeNil())
+
+	return saved
+}
+
+func (s *ReminderSchedulerTestSuite) TestTick_FiresOnlyOnceDue() {
+	s.createReminder(domain.Reminder{Kind: domain.ReminderBeforeDue, OffsetMinutes: 60})
+
+	sent, err := s.Scheduler.Tick(context.Background())
+	Expect(err).To(BeNil())
+	Expect(sent).To(Equal(0))
+
+	s.Clock.Advance(3 * time.Hour)
+
+	sent, err = s.Scheduler.Tick(context.Background())
+	Expect(err).To(BeNil())
+	Expect(sent).To(Equal(1))
+	Expect(s.Delivery.sent[0].TodoUUID).To(Equal(s.Todo.UUID))
+	Expect(s.Delivery.sent[0].RemindAt).To(Equal(time.Date(2025, 3, 10, 11, 0, 0, 0, time.UTC)))
+
+	sent, err = s.Scheduler.Tick(context.Background())
+	Expect(err).To(BeNil())
+	Expect(sent).To(Equal(0))
+}
+
+func (s *ReminderSchedulerTestSuite) TestTick_DayOfReminder() {
+	s.createReminder(domain.Reminder{Kind: domain.ReminderDayOf, TimeOfDay: 9 * 60})
+
+	sent, _ := s.Scheduler.Tick(context.Background())
+	Expect(sent).To(Equal(0))
+
+	s.Clock.Advance(time.Hour)
+
+	sent, _ = s.Scheduler.Tick(context.Background())
+	Expect(sent).To(Equal(1))
+}
+
+func (s *ReminderSchedulerTestSuite) TestTick_RestartDoesNotDoubleFire() {
+	s.createReminder(domain.Reminder{Kind: domain.ReminderBeforeDue})
+	s.Clock.Advance(5 * time.Hour)
+
+	sent, _ := s.Scheduler.Tick(context.Background())
+	Expect(sent).To(Equal(1))
+
+	// A fresh scheduler sees the same table, as it would after a restart
+	restarted := service.NewReminderScheduler(s.ReminderRepo, s.Delivery, s.Clock, telemetry.NewNoOpProbe())
+	s.Clock.Advance(time.Hour)
+
+	sent, _ = restarted.Tick(context.Background())
+	Expect(sent).To(Equal(0))
+	Expect(s.Delivery.sent).To(HaveLen(1))
+}
+
+func (s *ReminderSchedulerTestSuite) TestTick_AbandonedClaimIsRetried() {
+	s.createReminder(domain.Reminder{Kind: domain.ReminderBeforeDue})
+	s.Clock.Advance(5 * time.Hour)
+
+	// Simulate a scheduler that claimed the reminder and died before delivering
+	claimed, err := s.ReminderRepo.ClaimDue(context.Background(), s.Clock.Now(), s.Clock.Now().Add(-time.Minute), 10)
+	Expect(err).To(BeNil())
+	Expect(claimed).To(HaveLen(1))
+
+	sent, _ := s.Scheduler.Tick(context.Background())
+	Expect(sent).To(Equal(0))
+
+	s.Clock.Advance(s.Scheduler.ClaimTTL + time.Second)
+
+	sent, _ = s.Scheduler.Tick(context.Background())
+	Expect(sent).To(Equal(1))
+}
+
+func (s *ReminderSchedulerTestSuite) TestTick_FailedDeliveryIsRetriedLater() {
+	s.createReminder(domain.Reminder{Kind: domain.ReminderBeforeDue})
+	s.Clock.Advance(5 * time.Hour)
+	s.Delivery.err = errors.New("connection refused")
+
+	sent, err := s.Scheduler.Tick(context.Background())
+	Expect(err).To(BeNil())
+	Expect(sent).To(Equal(0))
+
+	s.Delivery.err = nil
+
+	sent, _ = s.Scheduler.Tick(context.Background())
+	Expect(sent).To(Equal(0))
+
+	s.Clock.Advance(s.Scheduler.ClaimTTL + time.Second)
+
+	sent, _ = s.Scheduler.Tick(context.Background())
+	Expect(sent).To(Equal(1))
+}
+
+func (s *ReminderSchedulerTestSuite) TestTick_MovedDueDateFiresAgain() {
+	s.createReminder(domain.Reminder{Kind: domain.ReminderBeforeDue, OffsetMinutes: 60})
+	s.Clock.Advance(3 * time.Hour)
+
+	sent, _ := s.Scheduler.Tick(context.Background())
+	Expect(sent).To(Equal(1))
+
+	// Pushing the due date to tomorrow re-arms the delivered reminder
+	dueAt := time.Date(2025, 3, 11, 12, 0, 0, 0, time.UTC)
+	_, err := s.TodoRepo.UpdateByUUID(context.Background(), domain.Todo{UUID: s.Todo.UUID, DueAt: &dueAt})
+	Expect(err).To(BeNil())
+
+	sent, _ = s.Scheduler.Tick(context.Background())
+	Expect(sent).To(Equal(0))
+
+	s.Clock.Advance(24 * time.Hour)
+
+	sent, _ = s.Scheduler.Tick(context.Background())
+	Expect(sent).To(Equal(1))
+	Expect(s.Delivery.sent[1].RemindAt).To(Equal(time.Date(2025, 3, 11, 11, 0, 0, 0, time.UTC)))
+
+	// Edits that leave the due date alone do not
+	_, err = s.TodoRepo.UpdateByUUID(context.Background(), domain.Todo{UUID: s.Todo.UUID, Title: "Pay the rent"})
+	Expect(err).To(BeNil())
+
+	sent, _ = s.Scheduler.Tick(context.Background())
+	Expect(sent).To(Equal(0))
+}
+
+func (s *ReminderSchedulerTestSuite) TestTick_SkipsCompletedTodos() {
+	s.createReminder(domain.Reminder{Kind: domain.ReminderBeforeDue})
+
+	s.Todo.MoveTo(domain.WorkflowStatus{Key: domain.TodoStatusCompleted, Terminal: true}, time.Now())
+	_, err := s.TodoRepo.UpdateByUUID(context.Background(), s.Todo)
+	Expect(err).To(BeNil())
+
+	s.Clock.Advance(5 * time.Hour)
+
+	sent, _ := s.Scheduler.Tick(context.Background())
+	Expect(sent).To(Equal(0))
+}
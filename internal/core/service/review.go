@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+	"todos/internal/core/port"
+	"todos/internal/core/util"
+)
+
+type ReviewService struct {
+	repo      port.ReviewRepository
+	todos     port.TodoService
+	users     port.UserRepository
+	telemetry port.Telemetry
+}
+
+func NewReviewService(repo port.ReviewRepository, todos port.TodoService, users port.UserRepository, telemetry port.Telemetry) *ReviewService {
+	return &ReviewService{
+		repo:      repo,
+		todos:     todos,
+		users:     users,
+		telemetry: telemetry,
+	}
+}
+
+func (rs *ReviewService) List(ctx context.Context, userId int, todoUUID string) ([]domain.Review, error) {
+	start := time.Now()
+
+	todo, err := rs.todos.Authorize(ctx, userId, domain.TodoActionView, todoUUID)
+
+	if err != nil {
+		return []domain.Review{}, err
+	}
+
+	reviews, err := rs.repo.ListByTodo(ctx, todo.ID)
+
+	rs.telemetry.RecordServiceOperation(ctx, "review", "List", userId, time.Since(start), err)
+
+	return reviews, err
+}
+
+// Request is open to whoever may update the todo. Asking a reviewer again
+// puts their review back to pending, for a new round after changes.
+func (rs *ReviewService) Request(ctx context.Context, userId int, todoUUID string, emails []string) ([]domain.Review, error) {
+	start := time.Now()
+
+	todo, err := rs.todos.Authorize(ctx, userId, domain.TodoActionUpdate, todoUUID)
+
+	if err != nil {
+		return []domain.Review{}, err
+	}
+
+	now := time.Now()
+	seen := make(map[int]bool, len(emails))
+	requested := make([]domain.Review, 0, len(emails))
+	reviewers := make([]domain.User, 0, len(emails))
+
+	for _, email := range emails {
+		// An unknown email gets the same answer as a user who cannot see the
+		// todo, so requesting reviews does not reveal who is registered
+		reviewer, err := rs.users.GetByEmail(ctx, email)
+
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return []domain.Review{}, fmt.Errorf("%w: %s", domain.ErrReviewerNotAllowed, email)
+		}
+
+		if err != nil {
+			return []domain.Review{}, err
+		}
+
+		if reviewer.ID == userId {
+			return []domain.Review{}, domain.ErrSelfReview
+		}
+
+		if seen[reviewer.ID] {
+			continue
+		}
+
+		seen[reviewer.ID] = true
+
+		if _, err := rs.todos.Authorize(ctx, reviewer.ID, domain.TodoActionView, todoUUID); err != nil {
+			if errors.Is(err, domain.ErrTodoNotFound) || errors.Is(err, domain.ErrForbidden) {
+				return []domain.Review{}, fmt.Errorf("%w: %s", domain.ErrReviewerNotAllowed, email)
+			}
+
+			return []domain.Review{}, err
+		}
+
+		requested = append(requested, domain.Review{
+			UUID:        uuid.New(),
+			TodoId:      todo.ID,
+			ReviewerId:  reviewer.ID,
+			RequestedBy: userId,
+			Decision:    domain.ReviewPending,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+		reviewers = append(reviewers, reviewer)
+	}
+
+	reviews, err := rs.repo.Request(ctx, requested)
+
+	rs.telemetry.RecordServiceOperation(ctx, "review", "Request", userId, time.Since(start), err)
+
+	if err != nil {
+		return []domain.Review{}, err
+	}
+
+	for _, reviewer := range reviewers {
+		rs.telemetry.RecordBusinessEvent(ctx, "review_requested", "todo", todoUUID, userId, map[string]interface{}{
+			"reviewer": reviewer.UUID.String(),
+		})
+	}
+
+	return reviews, nil
+}
+
+// Decide records the caller's decision on a todo they were asked to review,
+// a later decision replaces the earlier one. Decisions are only taken while
+// the todo is in_review.
+func (rs *ReviewService) Decide(ctx context.Context, userId int, todoUUID string, decision domain.ReviewDecision, comment string) (domain.Review, error) {
+	start := time.Now()
+
+	todo, err := rs.todos.Authorize(ctx, userId, domain.TodoActionView, todoUUID)
+
+	if err != nil {
+		return domain.Review{}, err
+	}
+
+	review, err := rs.repo.GetByReviewer(ctx, todo.ID, userId)
+
+	if err != nil {
+		return domain.Review{}, err
+	}
+
+	if todo.Status != domain.TodoStatusInReview {
+		return domain.Review{}, domain.ErrTodoNotInReview
+	}
+
+	previous := review.Decision
+
+	if err := review.Decide(decision, comment, time.Now()); err != nil {
+		return domain.Review{}, err
+	}
+
+	review, err = rs.repo.Decide(ctx, review)
+
+	rs.telemetry.RecordServiceOperation(ctx, "review", "Decide", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.Review{}, err
+	}
+
+	rs.telemetry.RecordBusinessEvent(ctx, "review_"+string(review.Decision), "todo", todoUUID, userId, map[string]interface{}{
+		"review":      review.UUID.String(),
+		"previous":    string(previous),
+		"has_comment": review.Comment != nil,
+	})
+
+	return review, nil
+}
+
+// Waiting pages through the reviews the user still has to decide
+func (rs *ReviewService) Waiting(ctx context.Context, userId int, limit int, cursor string) (*response.CursorResponse, error) {
+	start := time.Now()
+
+	reviews, hasNext, err := rs.repo.ListWaiting(ctx, userId, limit, cursor)
+
+	rs.telemetry.RecordServiceOperation(ctx, "review", "Waiting", userId, time.Since(start), err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]response.ReviewResponse, 0, len(reviews))
+
+	for _, review := range reviews {
+		data = append(data, response.NewReviewResponse(review))
+	}
+
+	var nextCursor string
+
+	if hasNext && len(reviews) > 0 {
+		nextCursor = util.EncodeSortCursor(domain.ReviewCursorSort, "", reviews[len(reviews)-1].ID)
+	}
+
+	dataBytes, _ := util.Serialize(data)
+
+	responsable := response.CursorResponse{
+		Size: len(data),
+		Data: dataBytes,
+	}
+
+	responsable.Pagination.HasNext = hasNext
+	responsable.Pagination.NextCursor = nextCursor
+
+	return &responsable, nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+)
+
+type TagService struct {
+	repo      port.TagRepository
+	telemetry port.Telemetry
+}
+
+func NewTagService(repo port.TagRepository, telemetry port.Telemetry) *TagService {
+	return &TagService{
+		repo:      repo,
+		telemetry: telemetry,
+	}
+}
+
+func (ts *TagService) List(ctx context.Context, userId int) ([]domain.Tag, error) {
+	start := time.Now()
+
+	tags, err := ts.repo.ListByUser(ctx, userId)
+
+	ts.telemetry.RecordServiceOperation(ctx, "tag", "List", userId, time.Since(start), err)
+
+	return tags, err
+}
+
+func (ts *TagService) Create(ctx context.Context, userId int, name string) (domain.Tag, error) {
+	start := time.Now()
+
+	name, err := domain.NormalizeTagName(name)
+
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	now := time.Now()
+
+	tag, err := ts.repo.Create(ctx, domain.Tag{
+		UUID:      uuid.New(),
+		UserId:    userId,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+
+	ts.telemetry.RecordServiceOperation(ctx, "tag", "Create", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	ts.telemetry.RecordBusinessEvent(ctx, "created", "tag", tag.UUID.String(), userId, map[string]interface{}{
+		"name": tag.Name,
+	})
+
+	return tag, nil
+}
+
+func (ts *TagService) Rename(ctx context.Context, userId int, uid string, name string) (domain.Tag, error) {
+	start := time.Now()
+
+	name, err := domain.NormalizeTagName(name)
+
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	tag, err := ts.findOwned(ctx, userId, uid)
+
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	renamed, err := ts.repo.Rename(ctx, tag, name)
+
+	ts.telemetry.RecordServiceOperation(ctx, "tag", "Rename", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	ts.telemetry.RecordBusinessEvent(ctx, "renamed", "tag", uid, userId, map[string]interface{}{
+		"from": tag.Name,
+		"to":   renamed.Name,
+	})
+
+	return renamed, nil
+}
+
+func (ts *TagService) Merge(ctx context.Context, userId int, sourceUUID string, targetUUID string) (domain.Tag, error) {
+	start := time.Now()
+
+	if sourceUUID == targetUUID {
+		return domain.Tag{}, domain.ErrTagMergeSelf
+	}
+
+	source, err := ts.findOwned(ctx, userId, sourceUUID)
+
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	target, err := ts.findOwned(ctx, userId, targetUUID)
+
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	merged, err := ts.repo.Merge(ctx, source, target)
+
+	ts.telemetry.RecordServiceOperation(ctx, "tag", "Merge", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	ts.telemetry.RecordBusinessEvent(ctx, "merged", "tag", targetUUID, userId, map[string]interface{}{
+		"source":     source.Name,
+		"target":     target.Name,
+		"todo_count": merged.TodoCount,
+	})
+
+	return merged, nil
+}
+
+func (ts *TagService) Delete(ctx context.Context, userId int, uid string) error {
+	start := time.Now()
+
+	tag, err := ts.findOwned(ctx, userId, uid)
+
+	if err != nil {
+		return err
+	}
+
+	err = ts.repo.Delete(ctx, tag)
+
+	ts.telemetry.RecordServiceOperation(ctx, "tag", "Delete", userId, time.Since(start), err)
+
+	if err != nil {
+		return err
+	}
+
+	ts.telemetry.RecordBusinessEvent(ctx, "deleted", "tag", uid, userId, map[string]interface{}{
+		"name":       tag.Name,
+		"todo_count": tag.TodoCount,
+	})
+
+	return nil
+}
+
+// findOwned hides other users' tags behind ErrTagNotFound
+func (ts *TagService) findOwned(ctx context.Context, userId int, uid string) (domain.Tag, error) {
+	tag, err := ts.repo.GetByUUID(ctx, uid)
+
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	if !tag.BelongsToUser(userId) {
+		return domain.Tag{}, domain.ErrTagNotFound
+	}
+
+	return tag, nil
+}
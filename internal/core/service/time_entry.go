@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+)
+
+type TimeEntryService struct {
+	repo      port.TimeEntryRepository
+	todos     port.TodoService
+	clock     port.Clock
+	telemetry port.Telemetry
+}
+
+func NewTimeEntryService(repo port.TimeEntryRepository, todos port.TodoService, clock port.Clock, telemetry port.Telemetry) *TimeEntryService {
+	return &TimeEntryService{
+		repo:      repo,
+		todos:     todos,
+		clock:     clock,
+		telemetry: telemetry,
+	}
+}
+
+// Start runs a timer on a todo the user can see. Only one timer runs per
+// user, the one already running has to be stopped first.
+func (ts *TimeEntryService) Start(ctx context.Context, userId int, todoUUID string, note string) (domain.TimeEntry, error) {
+	start := time.Now()
+
+	entry, err := func() (domain.TimeEntry, error) {
+		todo, err := ts.todos.Authorize(ctx, userId, domain.TodoActionView, todoUUID)
+
+		if err != nil {
+			return domain.TimeEntry{}, err
+		}
+
+		if _, err := ts.repo.GetRunning(ctx, userId); err == nil {
+			return domain.TimeEntry{}, domain.ErrTimerRunning
+		} else if !errors.Is(err, domain.ErrNoTimerRunning) {
+			return domain.TimeEntry{}, err
+		}
+
+		return ts.create(ctx, userId, todo, domain.TimeEntry{Note: note, StartedAt: ts.clock.Now().UTC()})
+	}()
+
+	ts.telemetry.RecordServiceOperation(ctx, "time_entry", "Start", userId, time.Since(start), err)
+
+	if err == nil {
+		ts.telemetry.RecordBusinessEvent(ctx, "timer_started", "time_entry", entry.UUID.String(), userId, map[string]interface{}{
+			"todo_uuid": todoUUID,
+		})
+	}
+
+	return entry, err
+}
+
+// Stop ends the user's running timer, wherever it runs
+func (ts *TimeEntryService) Stop(ctx context.Context, userId int) (domain.TimeEntry, error) {
+	start := time.Now()
+
+	entry, err := func() (domain.TimeEntry, error) {
+		entry, err := ts.repo.GetRunning(ctx, userId)
+
+		if err != nil {
+			return domain.TimeEntry{}, err
+		}
+
+		if err := entry.Stop(ts.clock.Now().UTC()); err != nil {
+			return domain.TimeEntry{}, err
+		}
+
+		return ts.repo.Update(ctx, entry)
+	}()
+
+	ts.telemetry.RecordServiceOperation(ctx, "time_entry", "Stop", userId, time.Since(start), err)
+
+	if err == nil {
+		ts.telemetry.RecordBusinessEvent(ctx, "timer_stopped", "time_entry", entry.UUID.String(), userId, map[string]interface{}{
+			"todo_uuid": entry.TodoUUID.String(),
+			"seconds":   entry.Seconds,
+		})
+	}
+
+	return entry, err
+}
+
+func (ts *TimeEntryService) Running(ctx context.Context, userId int) (domain.TimeEntry, error) {
+	return ts.repo.GetRunning(ctx, userId)
+}
+
+// List returns the time everyone tracked on a todo the user can see
+func (ts *TimeEntryService) List(ctx context.Context, userId int, todoUUID string) ([]domain.TimeEntry, error) {
+	start := time.Now()
+
+	todo, err := ts.todos.Authorize(ctx, userId, domain.TodoActionView, todoUUID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ts.repo.ListByTodo(ctx, todo.ID)
+
+	ts.telemetry.RecordServiceOperation(ctx, "time_entry", "List", userId, time.Since(start), err)
+
+	return entries, err
+}
+
+// Create records time spent on a todo after the fact
+func (ts *TimeEntryService) Create(ctx context.Context, userId int, todoUUID string, entry domain.TimeEntry) (domain.TimeEntry, error) {
+	start := time.Now()
+
+	saved, err := func() (domain.TimeEntry, error) {
+		todo, err := ts.todos.Authorize(ctx, userId, domain.TodoActionView, todoUUID)
+
+		if err != nil {
+			return domain.TimeEntry{}, err
+		}
+
+		if entry.EndedAt == nil {
+			return domain.TimeEntry{}, fmt.Errorf("%w: ended_at is required, start a timer instead", domain.ErrInvalidTimeEntry)
+		}
+
+		entry.StartedAt = entry.StartedAt.UTC()
+		ended := entry.EndedAt.UTC()
+		entry.EndedAt = &ended
+
+		return ts.create(ctx, userId, todo, entry)
+	}()
+
+	ts.telemetry.RecordServiceOperation(ctx, "time_entry", "Create", userId, time.Since(start), err)
+
+	return saved, err
+}
+
+func (ts *TimeEntryService) create(ctx context.Context, userId int, todo domain.Todo, entry domain.TimeEntry) (domain.TimeEntry, error) {
+	now := ts.clock.Now().UTC()
+
+	if err := entry.Validate(now); err != nil {
+		return domain.TimeEntry{}, err
+	}
+
+	entry.UUID = uuid.New()
+	entry.TodoId = todo.ID
+	entry.UserId = userId
+	entry.CreatedAt = now
+	entry.UpdatedAt = now
+
+	return ts.repo.Create(ctx, entry)
+}
+
+// Update edits one of the user's own entries
+func (ts *TimeEntryService) Update(ctx context.Context, userId int, uid string, change domain.TimeEntryChange) (domain.TimeEntry, error) {
+	start := time.Now()
+
+	entry, err := func() (domain.TimeEntry, error) {
+		entry, err := ts.find(ctx, userId, uid)
+
+		if err != nil {
+			return domain.TimeEntry{}, err
+		}
+
+		if err := change.Apply(&entry, ts.clock.Now().UTC()); err != nil {
+			return domain.TimeEntry{}, err
+		}
+
+		return ts.repo.Update(ctx, entry)
+	}()
+
+	ts.telemetry.RecordServiceOperation(ctx, "time_entry", "Update", userId, time.Since(start), err)
+
+	return entry, err
+}
+
+func (ts *TimeEntryService) Delete(ctx context.Context, userId int, uid string) error {
+	start := time.Now()
+
+	entry, err := ts.find(ctx, userId, uid)
+
+	if err == nil {
+		err = ts.repo.Delete(ctx, entry)
+	}
+
+	ts.telemetry.RecordServiceOperation(ctx, "time_entry", "Delete", userId, time.Since(start), err)
+
+	return err
+}
+
+// Report sums the time the user tracked within the filter's range
+func (ts *TimeEntryService) Report(ctx context.Context, userId int, filter domain.TimeReportFilter) (domain.TimeReport, error) {
+	start := time.Now()
+
+	report, err := func() (domain.TimeReport, error) {
+		if err := filter.Validate(); err != nil {
+			return domain.TimeReport{}, err
+		}
+
+		entries, err := ts.repo.ListForReport(ctx, userId, filter.From, filter.To)
+
+		if err != nil {
+			return domain.TimeReport{}, err
+		}
+
+		return domain.BuildTimeReport(entries, filter, ts.clock.Now()), nil
+	}()
+
+	ts.telemetry.RecordServiceOperation(ctx, "time_entry", "Report", userId, time.Since(start), err)
+
+	return report, err
+}
+
+// find loads an entry of the user, the entries of others are not found
+func (ts *TimeEntryService) find(ctx context.Context, userId int, uid string) (domain.TimeEntry, error) {
+	entry, err := ts.repo.GetByUUID(ctx, uid)
+
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+
+	if entry.UserId != userId {
+		return domain.TimeEntry{}, domain.ErrTimeEntryNotFound
+	}
+
+	return entry, nil
+}
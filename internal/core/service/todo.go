@@ -2,34 +2,66 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 
 	"todos/internal/core/domain"
 	"todos/internal/core/model/response"
+	"todos/internal/core/policy"
 	"todos/internal/core/port"
+	"todos/internal/core/telemetry"
 	"todos/internal/core/util"
 )
 
 type TodoService struct {
 	repo      port.TodoRepository
+	projects  port.ProjectRepository
+	workflows port.WorkflowRepository
+	reviews   port.ReviewRepository
+	tx        port.Transactor
 	telemetry port.Telemetry
+	policy    port.TodoPolicy
 }
 
-func NewTodoService(repo port.TodoRepository, telemetry port.Telemetry) *TodoService {
+func NewTodoService(repo port.TodoRepository, projects port.ProjectRepository, workflows port.WorkflowRepository, reviews port.ReviewRepository, tx port.Transactor, telemetry port.Telemetry, todoPolicy port.TodoPolicy) *TodoService {
+	if todoPolicy == nil {
+		todoPolicy = policy.NewOwnerPolicy()
+	}
+
 	return &TodoService{
 		repo:      repo,
+		projects:  projects,
+		workflows: workflows,
+		reviews:   reviews,
+		tx:        tx,
 		telemetry: telemetry,
+		policy:    todoPolicy,
 	}
 }
 
-func (ts *TodoService) GetTodosWithPagination(ctx context.Context, userId int, limit int, cursor string) (*response.CursorResponse, error) {
+func (ts *TodoService) GetTodosWithPagination(ctx context.Context, userId int, limit int, cursor string, filter domain.TodoFilter) (*response.CursorResponse, error) {
 	// Record service operation start
 	start := time.Now()
 
-	rows, hasNext, err := ts.repo.GetAllWithCursor(ctx, userId, limit, cursor)
+	// Listing is scoped to the caller, so authorize against a todo owned by them
+	if err := ts.authorize(ctx, userId, domain.TodoActionList, domain.Todo{UserId: userId}); err != nil {
+		return nil, err
+	}
+
+	if filter.Sort.IsZero() {
+		filter.Sort = domain.DefaultTodoSort()
+	}
+
+	if filter.Now.IsZero() {
+		filter.Now = time.Now()
+	}
+
+	rows, hasNext, err := ts.repo.GetAllWithCursor(ctx, userId, limit, cursor, filter)
 
 	// Record service operation end
 	duration := time.Since(start)
@@ -56,24 +88,14 @@ func (ts *TodoService) GetTodosWithPagination(ctx context.Context, userId int, l
 	}
 
 	for _, todo := range rows {
-		item := response.TodoResponse{
-			UUID:        todo.UUID,
-			Title:       todo.Title,
-			Description: todo.Description,
-			Status:      todo.StatusOrFallback(),
-			Completed:   todo.Completed,
-			CreatedAt:   todo.CreatedAt,
-			UpdatedAt:   todo.UpdatedAt,
-		}
-
-		data = append(data, item)
+		data = append(data, response.NewTodoResponse(todo))
 	}
 
 	var nextCursor string
 
 	if hasNext && len(rows) > 0 {
 		lastTodo := rows[len(rows)-1]
-		nextCursor = util.EncodeCursor(lastTodo.CreatedAt.Format(time.RFC3339), lastTodo.ID)
+		nextCursor = util.EncodeSortCursor(filter.Sort.String(), lastTodo.SortValue(filter.Sort.Field), lastTodo.ID)
 	}
 
 	dataBytes, _ := util.Serialize(data)
@@ -93,21 +115,124 @@ func (ts *TodoService) GetTodosWithPagination(ctx context.Context, userId int, l
 	return &responsable, nil
 }
 
+func (ts *TodoService) Search(ctx context.Context, userId int, query string, limit int, cursor string) (*response.CursorResponse, error) {
+	start := time.Now()
+
+	if err := ts.authorize(ctx, userId, domain.TodoActionList, domain.Todo{UserId: userId}); err != nil {
+		return nil, err
+	}
+
+	results, hasNext, err := ts.repo.Search(ctx, userId, query, limit, cursor)
+
+	ts.telemetry.RecordServiceOperation(ctx, "todo", "Search", userId, time.Since(start), err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]response.TodoSearchResponse, 0, len(results))
+
+	for _, result := range results {
+		data = append(data, response.NewTodoSearchResponse(result))
+	}
+
+	var nextCursor string
+
+	// Ranks shift as todos are written, so this cursor is only a best-effort
+	// position and a later page can skip or repeat a hit
+	if hasNext && len(results) > 0 {
+		last := results[len(results)-1]
+		nextCursor = util.EncodeSortCursor(domain.TodoSearchSort, strconv.FormatFloat(last.Rank, 'g', -1, 64), last.Todo.ID)
+	}
+
+	dataBytes, _ := util.Serialize(data)
+
+	responsable := response.CursorResponse{
+		Size: len(data),
+		Data: dataBytes,
+	}
+
+	responsable.Pagination.HasNext = hasNext
+	responsable.Pagination.NextCursor = nextCursor
+
+	return &responsable, nil
+}
+
+func (ts *TodoService) GetByUUID(ctx context.Context, userId int, uid string) (domain.Todo, error) {
+	start := time.Now()
+
+	todo, err := ts.findAuthorized(ctx, userId, domain.TodoActionView, uid)
+
+	ts.telemetry.RecordServiceOperation(ctx, "todo", "GetByUUID", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	return todo, nil
+}
+
 func (ts *TodoService) Create(ctx context.Context, todo domain.Todo) (domain.Todo, error) {
+	if err := ts.authorize(ctx, todo.UserId, domain.TodoActionCreate, todo); err != nil {
+		return domain.Todo{}, err
+	}
+
 	now := time.Now()
 
 	newTodo := domain.Todo{
 		UUID:        uuid.New(),
 		Title:       todo.Title,
 		Description: todo.Description,
-		Status:      todo.Status,
-		Completed:   todo.Completed,
+		DueAt:       todo.DueAt,
+		AllDay:      todo.AllDay,
+		ProjectUUID: todo.ProjectUUID,
 		UserId:      todo.UserId,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
 
-	todo, err := ts.repo.Create(ctx, newTodo)
+	newTodo.NormalizeDue()
+
+	if todo.RRule != nil && *todo.RRule != "" {
+		newTodo.RRule = todo.RRule
+
+		if err := setRecurrence(&newTodo, newTodo.DueAt); err != nil {
+			return domain.Todo{}, err
+		}
+	}
+
+	if err := ts.resolveProject(ctx, todo.UserId, domain.Todo{}, &newTodo); err != nil {
+		return domain.Todo{}, err
+	}
+
+	// New todos may start in any status of their workflow
+	workflow, err := ts.workflowFor(ctx, newTodo.ProjectId)
+
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	status := workflow.Initial()
+
+	if todo.StatusChange != nil {
+		if status, err = todo.StatusChange.Resolve(workflow, status.Key); err != nil {
+			return domain.Todo{}, err
+		}
+	}
+
+	newTodo.MoveTo(status, now)
+
+	if todo.Tags != nil {
+		tags, err := domain.NormalizeTagNames(todo.Tags)
+
+		if err != nil {
+			return domain.Todo{}, err
+		}
+
+		newTodo.Tags = tags
+	}
+
+	todo, err = ts.repo.Create(ctx, newTodo)
 
 	if err != nil {
 		slog.Error(" Repository create failed", "error", err, "title", newTodo.Title)
@@ -117,8 +242,116 @@ func (ts *TodoService) Create(ctx context.Context, todo domain.Todo) (domain.Tod
 	return todo, nil
 }
 
-func (u *TodoService) UpdateByUUID(ctx context.Context, todo domain.Todo) (domain.Todo, error) {
-	todo, err := u.repo.UpdateByUUID(ctx, todo)
+func (ts *TodoService) UpdateByUUID(ctx context.Context, userId int, todo domain.Todo) (domain.Todo, error) {
+	current, err := ts.findAuthorized(ctx, userId, domain.TodoActionUpdate, todo.UUID.String())
+
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	return ts.update(ctx, userId, current, todo)
+}
+
+// Patch applies a partial update, telling fields left out from those cleared
+func (ts *TodoService) Patch(ctx context.Context, userId int, uid string, patch domain.TodoPatch) (domain.Todo, error) {
+	start := time.Now()
+
+	current, err := ts.findAuthorized(ctx, userId, domain.TodoActionUpdate, uid)
+
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	todo, err := ts.update(ctx, userId, current, patch.Update(current))
+
+	ts.telemetry.RecordServiceOperation(ctx, "todo", "Patch", userId, time.Since(start), err)
+
+	return todo, err
+}
+
+func (ts *TodoService) update(ctx context.Context, userId int, current domain.Todo, todo domain.Todo) (domain.Todo, error) {
+	var err error
+
+	if todo.Version != 0 && todo.Version != current.Version {
+		return domain.Todo{}, domain.ErrVersionConflict
+	}
+
+	// The checks below run against current, so without a version of its own
+	// the write still only goes through while the todo is as it was read
+	pinned := todo.Version == 0
+
+	if pinned {
+		todo.Version = current.Version
+	}
+
+	// The owner never changes through an update
+	todo.UserId = current.UserId
+	todo.EditorId = userId
+
+	if todo.ClearDue {
+		todo.DueAt = nil
+	}
+
+	if todo.DueAt != nil {
+		todo.NormalizeDue()
+	}
+
+	if todo.RRule != nil {
+		due := todo.DueAt
+
+		if due == nil && !todo.ClearDue {
+			due = current.DueAt
+		}
+
+		if err := setRecurrence(&todo, due); err != nil {
+			return domain.Todo{}, err
+		}
+
+		// Resending the same rule keeps the series and its skipped dates
+		if current.RRule != nil && *todo.RRule == *current.RRule {
+			todo.RRule, todo.RRuleStart = nil, nil
+		}
+	} else if todo.ClearDue && current.IsRecurring() {
+		return domain.Todo{}, domain.ErrRecurrenceNeedsDue
+	}
+
+	if err := ts.resolveProject(ctx, userId, current, &todo); err != nil {
+		return domain.Todo{}, err
+	}
+
+	if err := ts.settleStatus(ctx, current, &todo); err != nil {
+		return domain.Todo{}, err
+	}
+
+	if todo.Tags != nil {
+		if todo.Tags, err = domain.NormalizeTagNames(todo.Tags); err != nil {
+			return domain.Todo{}, err
+		}
+	}
+
+	// Completing a recurring todo and creating its next occurrence go
+	// together, otherwise a failed spawn would end the series for good
+	err = ts.tx.WithinTx(ctx, func(ctx context.Context) error {
+		updated, err := ts.repo.UpdateByUUID(ctx, todo)
+
+		if err != nil {
+			return err
+		}
+
+		if updated.IsDone() && !current.IsDone() && updated.IsRecurring() && updated.NextOccurrenceId == nil {
+			if err := ts.spawnNextOccurrence(ctx, userId, updated); err != nil {
+				return err
+			}
+		}
+
+		todo = updated
+
+		return nil
+	})
+
+	if pinned && errors.Is(err, domain.ErrVersionConflict) {
+		return domain.Todo{}, fmt.Errorf("%w: %v", domain.ErrTodoChanged, err)
+	}
 
 	if err != nil {
 		return domain.Todo{}, err
@@ -127,12 +360,200 @@ func (u *TodoService) UpdateByUUID(ctx context.Context, todo domain.Todo) (domai
 	return todo, nil
 }
 
-func (u *TodoService) DeleteByUUID(ctx context.Context, uid string) error {
-	err := u.repo.DeleteByUUID(ctx, uid)
+// DeleteByUUID moves the todo to the trash. A non-zero version must still be
+// the todo's current one.
+func (ts *TodoService) DeleteByUUID(ctx context.Context, userId int, uid string, version int) error {
+	if _, err := ts.findAuthorized(ctx, userId, domain.TodoActionDelete, uid); err != nil {
+		return err
+	}
+
+	err := ts.repo.DeleteByUUID(ctx, uid, version)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ts *TodoService) Authorize(ctx context.Context, userId int, action domain.TodoAction, uid string) (domain.Todo, error) {
+	return ts.findAuthorized(ctx, userId, action, uid)
+}
+
+// resolveProject turns the project uuid sent by the client into the project
+// id the repository stores. uuid.Nil moves the todo out of its project, and
+// todos can only be put in live, unarchived projects the user edits. Taking a
+// todo out of the project it is in, to another one or to none, also needs
+// the user to edit that project. current is empty for a new todo.
+func (ts *TodoService) resolveProject(ctx context.Context, userId int, current domain.Todo, todo *domain.Todo) error {
+	if todo.ProjectUUID == nil {
+		return nil
+	}
+
+	if *todo.ProjectUUID == uuid.Nil {
+		if current.ProjectId != nil {
+			if err := ts.requireProjectEditor(ctx, userId, *current.ProjectId); err != nil {
+				return err
+			}
+		}
+
+		todo.ProjectId = new(int)
+		todo.ProjectUUID = nil
+
+		return nil
+	}
+
+	project, err := ts.projects.GetByUUID(ctx, todo.ProjectUUID.String())
 
 	if err != nil {
 		return err
 	}
 
+	if err := ts.requireProjectEditor(ctx, userId, project.ID); err != nil {
+		return err
+	}
+
+	if project.IsArchived() {
+		return domain.ErrProjectArchived
+	}
+
+	if current.ProjectId != nil && *current.ProjectId != project.ID {
+		if err := ts.requireProjectEditor(ctx, userId, *current.ProjectId); err != nil {
+			return err
+		}
+	}
+
+	todo.ProjectId = &project.ID
+
 	return nil
 }
+
+// requireProjectEditor fails unless the user edits or owns the project,
+// hiding it from those who are not members
+func (ts *TodoService) requireProjectEditor(ctx context.Context, userId int, projectId int) error {
+	member, err := ts.projects.GetMember(ctx, projectId, userId)
+
+	if errors.Is(err, domain.ErrProjectMemberNotFound) {
+		return domain.ErrProjectNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !member.Role.AtLeast(domain.ProjectEditor) {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
+
+// settleStatus works out the lifecycle an update leads to. Within a workflow
+// its transitions apply. A todo moving to a project with another workflow
+// first lands on the closest status there, see Workflow.Settle.
+func (ts *TodoService) settleStatus(ctx context.Context, current domain.Todo, todo *domain.Todo) error {
+	projectId := current.ProjectId
+
+	if todo.ProjectId != nil {
+		projectId = todo.ProjectId
+	}
+
+	workflow, err := ts.workflowFor(ctx, projectId)
+
+	if err != nil {
+		return err
+	}
+
+	next := current
+	now := time.Now()
+
+	if _, ok := workflow.Status(current.Status); !ok {
+		next.MoveTo(workflow.Settle(current.Status, current.Completed), now)
+
+		if todo.StatusChange != nil {
+			status, err := todo.StatusChange.Resolve(workflow, next.Status)
+
+			if err != nil {
+				return err
+			}
+
+			next.MoveTo(status, now)
+		}
+	} else if todo.StatusChange != nil {
+		if err := next.Transition(workflow, *todo.StatusChange, now); err != nil {
+			return err
+		}
+	}
+
+	// Reaching a terminal status waits on the workflow's review rule, from
+	// in_review or any status the todo was moved to after review was asked
+	if next.Completed && !current.Completed {
+		reviews, err := ts.reviews.ListByTodo(ctx, current.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if !workflow.ReviewRule.Satisfied(reviews) {
+			return domain.ErrReviewsPending
+		}
+	}
+
+	// Completing waits on the todos blocking this one unless forced
+	if current.Blocked && next.Completed && !current.Completed && (todo.StatusChange == nil || !todo.StatusChange.Force) {
+		return domain.ErrTodoBlocked
+	}
+
+	todo.Status = ""
+
+	if next.Status != current.Status {
+		todo.Status = next.Status
+		todo.Completed = next.Completed
+		todo.CompletedAt = next.CompletedAt
+	}
+
+	return nil
+}
+
+// workflowFor returns the workflow of a project, falling back on the default
+// one for todos outside projects and projects without a workflow of their own
+func (ts *TodoService) workflowFor(ctx context.Context, projectId *int) (domain.Workflow, error) {
+	if projectId != nil && *projectId != 0 {
+		workflow, err := ts.workflows.GetByProject(ctx, *projectId)
+
+		if !errors.Is(err, domain.ErrWorkflowNotFound) {
+			return workflow, err
+		}
+	}
+
+	return ts.workflows.GetDefault(ctx)
+}
+
+// findAuthorized loads a todo and runs it through the policy before returning it
+func (ts *TodoService) findAuthorized(ctx context.Context, userId int, action domain.TodoAction, uid string) (domain.Todo, error) {
+	todo, err := ts.repo.GetByUUID(ctx, uid)
+
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	if err := ts.authorize(ctx, userId, action, todo); err != nil {
+		return domain.Todo{}, err
+	}
+
+	return todo, nil
+}
+
+func (ts *TodoService) authorize(ctx context.Context, userId int, action domain.TodoAction, todo domain.Todo) error {
+	err := ts.policy.Authorize(ctx, userId, action, todo)
+
+	if err != nil {
+		// The attempt was made whether or not the work around it is kept
+		ts.telemetry.RecordBusinessEvent(telemetry.RecordingBusinessEvents(ctx), "access_denied", "todo", todo.UUID.String(), userId, map[string]interface{}{
+			"action": action.String(),
+			"reason": err.Error(),
+		})
+	}
+
+	return err
+}
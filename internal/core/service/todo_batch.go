@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	"todos/internal/core/telemetry"
+)
+
+// errBatchAborted stops an atomic batch at its first failure, rolling back
+// what the operations before it did
+var errBatchAborted = errors.New("batch aborted")
+
+// TodoBatchService runs each operation through TodoService, so a batch is
+// authorized and validated exactly like the same requests sent one by one
+type TodoBatchService struct {
+	todos     port.TodoService
+	tx        port.Transactor
+	telemetry port.Telemetry
+}
+
+func NewTodoBatchService(todos port.TodoService, tx port.Transactor, telemetry port.Telemetry) *TodoBatchService {
+	return &TodoBatchService{
+		todos:     todos,
+		tx:        tx,
+		telemetry: telemetry,
+	}
+}
+
+// Apply runs the operations in order in one transaction. Each one gets a
+// savepoint of its own, so a failed operation leaves nothing half done. In
+// best effort mode the others are committed regardless, an atomic batch is
+// rolled back as a whole and the operations it did not keep are reported
+// with ErrBatchAborted. The business events of an operation are recorded
+// only once it is committed.
+func (bs *TodoBatchService) Apply(ctx context.Context, userId int, mode domain.TodoBatchMode, operations []domain.TodoOperation) (domain.TodoBatchResult, error) {
+	start := time.Now()
+
+	switch {
+	case len(operations) == 0:
+		return domain.TodoBatchResult{}, domain.ErrBatchEmpty
+	case len(operations) > domain.MaxBatchOperations:
+		return domain.TodoBatchResult{}, domain.ErrBatchTooLarge
+	}
+
+	batch := domain.TodoBatchResult{
+		Mode:    mode,
+		Results: make([]domain.TodoOperationResult, 0, len(operations)),
+	}
+
+	var kept []*telemetry.HeldEvents
+
+	err := bs.tx.WithinTx(ctx, func(ctx context.Context) error {
+		for i, operation := range operations {
+			result := domain.TodoOperationResult{Index: i, Op: operation.Op}
+			opCtx, events := telemetry.HoldBusinessEvents(ctx)
+
+			result.Err = bs.tx.WithinTx(opCtx, func(ctx context.Context) error {
+				todo, err := bs.apply(ctx, userId, operation)
+				result.Todo = todo
+
+				return err
+			})
+
+			if result.Err != nil {
+				result.Todo = nil
+			} else {
+				kept = append(kept, events)
+			}
+
+			batch.Results = append(batch.Results, result)
+
+			if result.Err != nil && mode == domain.TodoBatchAtomic {
+				return errBatchAborted
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errBatchAborted) {
+		bs.telemetry.RecordServiceOperation(ctx, "todo_batch", "Apply", userId, time.Since(start), err)
+		return domain.TodoBatchResult{}, err
+	}
+
+	batch.Committed = err == nil
+
+	if batch.Committed {
+		for _, events := range kept {
+			events.Release(ctx, bs.telemetry)
+		}
+	}
+
+	if !batch.Committed {
+		for i := range batch.Results {
+			if batch.Results[i].Err == nil {
+				batch.Results[i].Todo = nil
+				batch.Results[i].Err = domain.ErrBatchAborted
+			}
+		}
+
+		for i := len(batch.Results); i < len(operations); i++ {
+			batch.Results = append(batch.Results, domain.TodoOperationResult{Index: i, Op: operations[i].Op, Err: domain.ErrBatchAborted})
+		}
+	}
+
+	bs.telemetry.RecordServiceOperation(ctx, "todo_batch", "Apply", userId, time.Since(start), nil)
+
+	bs.telemetry.RecordBusinessEvent(ctx, "applied", "todo_batch", "", userId, map[string]interface{}{
+		"mode":       string(mode),
+		"operations": len(operations),
+		"failed":     batch.Failed(),
+		"committed":  batch.Committed,
+	})
+
+	return batch, nil
+}
+
+func (bs *TodoBatchService) apply(ctx context.Context, userId int, operation domain.TodoOperation) (*domain.Todo, error) {
+	todo := operation.Todo
+
+	switch operation.Op {
+	case domain.TodoBatchCreate:
+		todo.UserId = userId
+		created, err := bs.todos.Create(ctx, todo)
+
+		return &created, err
+	case domain.TodoBatchUpdate:
+		if todo.StatusChange != nil {
+			todo.StatusChange.Force = operation.Force
+		}
+
+		updated, err := bs.todos.UpdateByUUID(ctx, userId, todo)
+
+		return &updated, err
+	case domain.TodoBatchComplete:
+		completed := true
+		updated, err := bs.todos.UpdateByUUID(ctx, userId, domain.Todo{
+			UUID:         todo.UUID,
+			Version:      todo.Version,
+			StatusChange: &domain.TodoStatusChange{Completed: &completed, Force: operation.Force},
+		})
+
+		return &updated, err
+	case domain.TodoBatchDelete:
+		return nil, bs.todos.DeleteByUUID(ctx, userId, todo.UUID.String(), todo.Version)
+	}
+
+	return nil, domain.ErrInvalidBatchOp
+}
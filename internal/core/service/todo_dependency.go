@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"todos/internal/core/domain"
+)
+
+func (ts *TodoService) Blockers(ctx context.Context, userId int, uid string) ([]domain.Todo, error) {
+	todo, err := ts.findAuthorized(ctx, userId, domain.TodoActionView, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// A blocker may be a todo of its own owner that the user cannot see
+	return ts.repo.ListBlockers(ctx, userId, todo.ID)
+}
+
+// AddBlocker keeps the todo from being completed before blocker is. Whoever
+// edits the todo may make it wait on any todo they can see. The todo is
+// returned as it is now.
+func (ts *TodoService) AddBlocker(ctx context.Context, userId int, uid string, blockerUid string) (domain.Todo, error) {
+	start := time.Now()
+
+	todo, err := ts.changeBlockers(ctx, userId, uid, blockerUid, ts.repo.AddBlocker)
+
+	ts.telemetry.RecordServiceOperation(ctx, "todo", "AddBlocker", userId, time.Since(start), err)
+
+	if err == nil {
+		ts.telemetry.RecordBusinessEvent(ctx, "blocker_added", "todo", uid, userId, map[string]interface{}{
+			"blocker_uuid": blockerUid,
+		})
+	}
+
+	return todo, err
+}
+
+func (ts *TodoService) RemoveBlocker(ctx context.Context, userId int, uid string, blockerUid string) (domain.Todo, error) {
+	start := time.Now()
+
+	todo, err := ts.changeBlockers(ctx, userId, uid, blockerUid, ts.repo.RemoveBlocker)
+
+	ts.telemetry.RecordServiceOperation(ctx, "todo", "RemoveBlocker", userId, time.Since(start), err)
+
+	if err == nil {
+		ts.telemetry.RecordBusinessEvent(ctx, "blocker_removed", "todo", uid, userId, map[string]interface{}{
+			"blocker_uuid": blockerUid,
+		})
+	}
+
+	return todo, err
+}
+
+func (ts *TodoService) changeBlockers(ctx context.Context, userId int, uid string, blockerUid string, change func(ctx context.Context, todoId int, blockerId int) error) (domain.Todo, error) {
+	todo, err := ts.findAuthorized(ctx, userId, domain.TodoActionUpdate, uid)
+
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	blocker, err := ts.findAuthorized(ctx, userId, domain.TodoActionView, blockerUid)
+
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	if err := change(ctx, todo.ID, blocker.ID); err != nil {
+		return domain.Todo{}, err
+	}
+
+	return ts.repo.GetByUUID(ctx, uid)
+}
+
+// DependencyGraph is open to every member of the project
+func (ts *TodoService) DependencyGraph(ctx context.Context, userId int, projectUid string) (domain.DependencyGraph, error) {
+	start := time.Now()
+
+	graph, err := func() (domain.DependencyGraph, error) {
+		project, err := ts.projects.GetByUUID(ctx, projectUid)
+
+		if err != nil {
+			return domain.DependencyGraph{}, err
+		}
+
+		if _, err := ts.projects.GetMember(ctx, project.ID, userId); err != nil {
+			if errors.Is(err, domain.ErrProjectMemberNotFound) {
+				return domain.DependencyGraph{}, domain.ErrProjectNotFound
+			}
+
+			return domain.DependencyGraph{}, err
+		}
+
+		return ts.repo.GetDependencyGraph(ctx, userId, project.ID)
+	}()
+
+	ts.telemetry.RecordServiceOperation(ctx, "todo", "DependencyGraph", userId, time.Since(start), err)
+
+	return graph, err
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	"todos/internal/core/telemetry"
+)
+
+type TodoItemService struct {
+	repo      port.TodoItemRepository
+	todos     port.TodoService
+	tx        port.Transactor
+	telemetry port.Telemetry
+}
+
+func NewTodoItemService(repo port.TodoItemRepository, todos port.TodoService, tx port.Transactor, telemetry port.Telemetry) *TodoItemService {
+	return &TodoItemService{
+		repo:      repo,
+		todos:     todos,
+		tx:        tx,
+		telemetry: telemetry,
+	}
+}
+
+func (is *TodoItemService) List(ctx context.Context, userId int, todoUUID string) ([]domain.TodoItem, error) {
+	start := time.Now()
+
+	todo, err := is.todos.Authorize(ctx, userId, domain.TodoActionView, todoUUID)
+
+	if err != nil {
+		return []domain.TodoItem{}, err
+	}
+
+	items, err := is.repo.ListByTodo(ctx, todo.ID)
+
+	is.telemetry.RecordServiceOperation(ctx, "todo_item", "List", userId, time.Since(start), err)
+
+	return items, err
+}
+
+func (is *TodoItemService) GetByUUID(ctx context.Context, userId int, todoUUID string, itemUUID string) (domain.TodoItem, error) {
+	todo, err := is.todos.Authorize(ctx, userId, domain.TodoActionView, todoUUID)
+
+	if err != nil {
+		return domain.TodoItem{}, err
+	}
+
+	return is.repo.GetByUUID(ctx, todo.ID, itemUUID)
+}
+
+func (is *TodoItemService) Create(ctx context.Context, userId int, todoUUID string, item domain.TodoItem) (domain.TodoItem, error) {
+	start := time.Now()
+
+	todo, err := is.todos.Authorize(ctx, userId, domain.TodoActionUpdate, todoUUID)
+
+	if err != nil {
+		return domain.TodoItem{}, err
+	}
+
+	now := time.Now()
+
+	item.UUID = uuid.New()
+	item.TodoId = todo.ID
+	item.CreatedAt = now
+	item.UpdatedAt = now
+
+	saved, err := is.repo.Create(ctx, item)
+
+	is.telemetry.RecordServiceOperation(ctx, "todo_item", "Create", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.TodoItem{}, err
+	}
+
+	is.telemetry.RecordBusinessEvent(ctx, "created", "todo_item", saved.UUID.String(), userId, map[string]interface{}{
+		"todo_uuid": todoUUID,
+	})
+
+	return saved, nil
+}
+
+func (is *TodoItemService) UpdateByUUID(ctx context.Context, userId int, todoUUID string, itemUUID string, changes domain.TodoItemChanges) (domain.TodoItem, error) {
+	start := time.Now()
+
+	todo, err := is.todos.Authorize(ctx, userId, domain.TodoActionUpdate, todoUUID)
+
+	if err != nil {
+		return domain.TodoItem{}, err
+	}
+
+	item, err := is.repo.GetByUUID(ctx, todo.ID, itemUUID)
+
+	if err != nil {
+		return domain.TodoItem{}, err
+	}
+
+	if changes.Title != nil {
+		item.Title = *changes.Title
+	}
+
+	if changes.Done != nil {
+		item.Done = *changes.Done
+	}
+
+	if changes.Position != nil {
+		item.Position = *changes.Position
+	}
+
+	item.UpdatedAt = time.Now()
+
+	// The item and the todo it completes change together or not at all
+	txCtx, events := telemetry.HoldBusinessEvents(ctx)
+
+	err = is.tx.WithinTx(txCtx, func(ctx context.Context) error {
+		item, err = is.repo.UpdateByUUID(ctx, item)
+
+		if err != nil || !changes.CompleteParent {
+			return err
+		}
+
+		return is.completeParent(ctx, userId, todoUUID)
+	})
+
+	is.telemetry.RecordServiceOperation(ctx, "todo_item", "UpdateByUUID", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.TodoItem{}, err
+	}
+
+	events.Release(ctx, is.telemetry)
+
+	return item, nil
+}
+
+func (is *TodoItemService) DeleteByUUID(ctx context.Context, userId int, todoUUID string, itemUUID string, completeParent bool) error {
+	start := time.Now()
+
+	todo, err := is.todos.Authorize(ctx, userId, domain.TodoActionUpdate, todoUUID)
+
+	if err != nil {
+		return err
+	}
+
+	txCtx, events := telemetry.HoldBusinessEvents(ctx)
+
+	err = is.tx.WithinTx(txCtx, func(ctx context.Context) error {
+		if err := is.repo.DeleteByUUID(ctx, todo.ID, itemUUID); err != nil || !completeParent {
+			return err
+		}
+
+		return is.completeParent(ctx, userId, todoUUID)
+	})
+
+	is.telemetry.RecordServiceOperation(ctx, "todo_item", "DeleteByUUID", userId, time.Since(start), err)
+
+	if err != nil {
+		return err
+	}
+
+	is.telemetry.RecordBusinessEvent(ctx, "deleted", "todo_item", itemUUID, userId, map[string]interface{}{
+		"todo_uuid": todoUUID,
+	})
+
+	events.Release(ctx, is.telemetry)
+
+	return nil
+}
+
+// completeParent marks the todo completed once its whole checklist is done.
+// Workflows that do not allow the move, or reviews or blockers still holding
+// the todo back, leave it where it is.
+func (is *TodoItemService) completeParent(ctx context.Context, userId int, todoUUID string) error {
+	// Reload the todo so the checklist counts include the change just made
+	todo, err := is.todos.Authorize(ctx, userId, domain.TodoActionView, todoUUID)
+
+	if err != nil {
+		return err
+	}
+
+	if todo.Completed || !todo.IsChecklistDone() {
+		return nil
+	}
+
+	completed := true
+
+	_, err = is.todos.UpdateByUUID(ctx, userId, domain.Todo{
+		UUID:         todo.UUID,
+		StatusChange: &domain.TodoStatusChange{Completed: &completed},
+	})
+
+	if errors.Is(err, domain.ErrIllegalTransition) || errors.Is(err, domain.ErrReviewsPending) || errors.Is(err, domain.ErrTodoBlocked) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	is.telemetry.RecordBusinessEvent(ctx, "auto_completed", "todo", todoUUID, userId, map[string]interface{}{
+		"items_total": todo.ItemsTotal,
+	})
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"todos/internal/core/domain"
+)
+
+func (ts *TodoService) Occurrences(ctx context.Context, userId int, uid string, n int) ([]time.Time, error) {
+	start := time.Now()
+
+	todo, err := ts.findAuthorized(ctx, userId, domain.TodoActionView, uid)
+
+	if err != nil {
+		return []time.Time{}, err
+	}
+
+	recurrence, err := todo.Recurrence()
+
+	if err != nil {
+		return []time.Time{}, err
+	}
+
+	occurrences := recurrence.Upcoming(currentOccurrence(todo), n)
+
+	ts.telemetry.RecordServiceOperation(ctx, "todo", "Occurrences", userId, time.Since(start), nil)
+
+	return occurrences, nil
+}
+
+// SkipOccurrence records an exception for one occurrence. Skipping the
+// current one moves the todo on to the next date without completing it.
+// Later occurrences are skipped ahead of time and the todo stays as is.
+func (ts *TodoService) SkipOccurrence(ctx context.Context, userId int, uid string, at *time.Time) (domain.Todo, error) {
+	start := time.Now()
+
+	todo, err := ts.findAuthorized(ctx, userId, domain.TodoActionUpdate, uid)
+
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	recurrence, err := todo.Recurrence()
+
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	current := currentOccurrence(todo)
+	target := current
+
+	if at != nil {
+		target = at.UTC()
+	}
+
+	changes := domain.Todo{
+		UUID:     todo.UUID,
+		UserId:   todo.UserId,
+		EditorId: userId,
+	}
+
+	switch {
+	case target.Equal(current):
+		next, ok := recurrence.Next(current)
+
+		if !ok {
+			return domain.Todo{}, domain.ErrNoNextOccurrence
+		}
+
+		// The due date may have been moved off the rule by hand
+		if recurrence.Includes(current) {
+			recurrence.Exceptions = append(recurrence.Exceptions, current)
+		}
+
+		changes.DueAt = &next
+		changes.AllDay = todo.AllDay
+	case target.After(current) && recurrence.Includes(target):
+		recurrence.Exceptions = append(recurrence.Exceptions, target)
+	default:
+		return domain.Todo{}, domain.ErrInvalidOccurrence
+	}
+
+	exdates := domain.FormatExdates(recurrence.Exceptions)
+	changes.RRuleExdates = &exdates
+
+	updated, err := ts.repo.UpdateByUUID(ctx, changes)
+
+	ts.telemetry.RecordServiceOperation(ctx, "todo", "SkipOccurrence", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	ts.telemetry.RecordBusinessEvent(ctx, "occurrence_skipped", "todo", uid, userId, map[string]interface{}{
+		"occurrence": target.Format(time.RFC3339),
+	})
+
+	return updated, nil
+}
+
+// setRecurrence validates the rule sent for todo and anchors the series at
+// due. An empty rule is left for the repository to clear.
+func setRecurrence(todo *domain.Todo, due *time.Time) error {
+	if *todo.RRule == "" {
+		return nil
+	}
+
+	rule, err := domain.ParseRRule(*todo.RRule)
+
+	if err != nil {
+		return err
+	}
+
+	if due == nil {
+		return domain.ErrRecurrenceNeedsDue
+	}
+
+	canonical := rule.String()
+	anchor := due.UTC()
+
+	todo.RRule = &canonical
+	todo.RRuleStart = &anchor
+
+	return nil
+}
+
+// spawnNextOccurrence creates the todo for the occurrence after a completed
+// one, carrying over its content, project and tags. Nothing is created once
+// the rule runs out.
+func (ts *TodoService) spawnNextOccurrence(ctx context.Context, userId int, completed domain.Todo) error {
+	recurrence, err := completed.Recurrence()
+
+	if err != nil {
+		return err
+	}
+
+	next, ok := recurrence.Next(currentOccurrence(completed))
+
+	if !ok {
+		return nil
+	}
+
+	workflow, err := ts.workflowFor(ctx, completed.ProjectId)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	spawned, err := ts.repo.CreateNextOccurrence(ctx, completed, domain.Todo{
+		UUID:         uuid.New(),
+		Title:        completed.Title,
+		Description:  completed.Description,
+		Status:       workflow.Initial().Key,
+		DueAt:        &next,
+		AllDay:       completed.AllDay,
+		UserId:       completed.UserId,
+		ProjectId:    completed.ProjectId,
+		Tags:         completed.Tags,
+		RRule:        completed.RRule,
+		RRuleStart:   completed.RRuleStart,
+		RRuleExdates: completed.RRuleExdates,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+
+	if errors.Is(err, domain.ErrOccurrenceExists) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	ts.telemetry.RecordBusinessEvent(ctx, "occurrence_created", "todo", spawned.UUID.String(), userId, map[string]interface{}{
+		"previous_uuid": completed.UUID.String(),
+		"due_at":        next.Format(time.RFC3339),
+	})
+
+	return nil
+}
+
+// currentOccurrence is the date the todo stands for in its series
+func currentOccurrence(todo domain.Todo) time.Time {
+	if todo.DueAt != nil {
+		return todo.DueAt.UTC()
+	}
+
+	return todo.RRuleStart.UTC()
+}
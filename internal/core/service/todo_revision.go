@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+	"todos/internal/core/util"
+)
+
+func (ts *TodoService) History(ctx context.Context, userId int, uid string, limit int, cursor string) (*response.CursorResponse, error) {
+	start := time.Now()
+
+	todo, err := ts.findAuthorized(ctx, userId, domain.TodoActionView, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, hasNext, err := ts.repo.ListRevisions(ctx, todo.ID, limit, cursor)
+
+	ts.telemetry.RecordServiceOperation(ctx, "todo", "History", userId, time.Since(start), err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]response.TodoRevisionResponse, 0, len(revisions))
+
+	for _, revision := range revisions {
+		data = append(data, response.NewTodoRevisionResponse(revision))
+	}
+
+	var nextCursor string
+
+	if hasNext && len(revisions) > 0 {
+		nextCursor = util.EncodeSortCursor(domain.RevisionCursorSort, "", revisions[len(revisions)-1].Number)
+	}
+
+	dataBytes, _ := util.Serialize(data)
+
+	responsable := response.CursorResponse{
+		Size: len(data),
+		Data: dataBytes,
+	}
+
+	responsable.Pagination.HasNext = hasNext
+	responsable.Pagination.NextCursor = nextCursor
+
+	return &responsable, nil
+}
+
+// Revert puts the todo back the way it was before a revision, undoing that
+// revision and every later one. It goes through UpdateByUUID, so the status
+// must be reachable in the workflow and the project still open to the
+// caller. A recurring todo whose rule changed restarts its series on the
+// restored due date.
+func (ts *TodoService) Revert(ctx context.Context, userId int, uid string, number int) (domain.Todo, error) {
+	start := time.Now()
+
+	todo, err := ts.findAuthorized(ctx, userId, domain.TodoActionUpdate, uid)
+
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	revision, err := ts.repo.GetRevision(ctx, todo.ID, number)
+
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	snapshot := revision.Snapshot
+
+	restored := domain.Todo{
+		UUID:         todo.UUID,
+		Title:        snapshot.Title,
+		Description:  snapshot.Description,
+		DueAt:        snapshot.DueAt,
+		AllDay:       snapshot.AllDay,
+		Tags:         append([]string{}, snapshot.Tags...),
+		RRule:        snapshot.RRule,
+		RRuleExdates: snapshot.RRuleExdates,
+		ProjectUUID:  snapshot.ProjectUUID,
+		StatusChange: &domain.TodoStatusChange{Status: &snapshot.Status},
+
+		ClearDescription: snapshot.Description == "",
+		ClearDue:         snapshot.DueAt == nil,
+	}
+
+	if restored.RRule == nil {
+		restored.RRule = new(string)
+	}
+
+	if restored.ProjectUUID == nil {
+		restored.ProjectUUID = &uuid.Nil
+	}
+
+	updated, err := ts.UpdateByUUID(ctx, userId, restored)
+
+	ts.telemetry.RecordServiceOperation(ctx, "todo", "Revert", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	ts.telemetry.RecordBusinessEvent(ctx, "reverted", "todo", uid, userId, map[string]interface{}{
+		"revision": number,
+	})
+
+	return updated, nil
+}
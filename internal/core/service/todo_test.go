@@ -2,6 +2,7 @@ package service_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 
 	"todos/internal/adapter/database/sqlite/repository"
 	"todos/internal/core/domain"
+	"todos/internal/core/policy"
 	"todos/internal/core/port"
 	"todos/internal/core/service"
 	"todos/internal/core/telemetry"
@@ -22,9 +24,10 @@ import (
 
 type TodoUseCaseTestSuite struct {
 	suite.Suite
-	UseCase  service.TodoService
-	UserRepo port.UserRepository
-	TodoRepo port.TodoRepository
+	UseCase     service.TodoService
+	UserRepo    port.UserRepository
+	TodoRepo    port.TodoRepository
+	ProjectRepo port.ProjectRepository
 }
 
 func (s *TodoUseCaseTestSuite) SetupTest() {
@@ -33,9 +36,11 @@ func (s *TodoUseCaseTestSuite) SetupTest() {
 
 	todoRepo := repository.NewTodoRepository(db, probe)
 	userRepo := repository.NewUserRepository(db, probe)
+	projectRepo := repository.NewProjectRepository(db, probe)
 
-	s.UseCase = *service.NewTodoService(todoRepo, probe)
+	s.UseCase = *service.NewTodoService(todoRepo, projectRepo, repository.NewWorkflowRepository(db, probe), repository.NewReviewRepository(db, probe), db, probe, policy.NewOwnerPolicy())
 	s.UserRepo = userRepo
+	s.ProjectRepo = projectRepo
 
 	s.TodoRepo = todoRepo
 }
@@ -51,7 +56,7 @@ func TestTodoUseCaseTestSuite(t *testing.T) {
 }
 
 func (s *TodoUseCaseTestSuite) TestUseCase_GetTodosWithPagination_Empty() {
-	todos, err := s.UseCase.GetTodosWithPagination(context.Background(), 0, 1, "")
+	todos, err := s.UseCase.GetTodosWithPagination(context.Background(), 0, 1, "", domain.TodoFilter{})
 
 	// assert.NoError(s.T(), err)
 	// assert.Empty(s.T(), todos)
@@ -88,7 +93,7 @@ func (s *TodoUseCaseTestSuite) TestUseCase_GetTodosWithPagination_WithData() {
 
 	s.TodoRepo.Create(context.Background(), item2)
 
-	todos, err := s.UseCase.GetTodosWithPagination(context.Background(), user.ID, 100, "")
+	todos, err := s.UseCase.GetTodosWithPagination(context.Background(), user.ID, 100, "", domain.TodoFilter{})
 
 	Expect(err).To(BeNil())
 	Expect(todos.Size).To(Equal(2))
@@ -135,6 +140,290 @@ func (s *TodoUseCaseTestSuite) TestUseCase_DeleteByUUID_Success() {
 }
 
 func (s *TodoUseCaseTestSuite) TestUseCase_DeleteByUUID_NotFound() {
-	err := s.TodoRepo.DeleteByUUID(context.Background(), "non-existent-uuid")
+	err := s.TodoRepo.DeleteByUUID(context.Background(), "non-existent-uuid", 0)
 	assert.Error(s.T(), err)
 }
+
+func (s *TodoUseCaseTestSuite) TestUseCase_DeleteByUUID_OtherUserIsDenied() {
+	owner, _ := s.UserRepo.Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Owner",
+		Email: "owner@example.com",
+	})
+
+	other, _ := s.UserRepo.Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Other",
+		Email: "other@example.com",
+	})
+
+	data, _ := s.TodoRepo.Create(context.Background(), domain.Todo{
+		UUID:      uuid.New(),
+		Title:     "Private Todo",
+		UserId:    owner.ID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+
+	err := s.UseCase.DeleteByUUID(context.Background(), other.ID, data.UUID.String(), 0)
+	Expect(err).To(MatchError(domain.ErrTodoNotFound))
+
+	err = s.UseCase.DeleteByUUID(context.Background(), owner.ID, data.UUID.String(), 0)
+	Expect(err).To(BeNil())
+}
+
+func (s *TodoUseCaseTestSuite) TestUseCase_UpdateByUUID_OtherUserIsDenied() {
+	owner, _ := s.UserRepo.Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Owner",
+		Email: "owner@example.com",
+	})
+
+	other, _ := s.UserRepo.Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Other",
+		Email: "other@example.com",
+	})
+
+	data, _ := s.TodoRepo.Create(context.Background(), domain.Todo{
+		UUID:      uuid.New(),
+		Title:     "Private Todo",
+		UserId:    owner.ID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+
+	_, err := s.UseCase.UpdateByUUID(context.Background(), other.ID, domain.Todo{
+		UUID:  data.UUID,
+		Title: "Hijacked",
+	})
+	Expect(err).To(MatchError(domain.ErrTodoNotFound))
+
+	updated, err := s.UseCase.UpdateByUUID(context.Background(), owner.ID, domain.Todo{
+		UUID:  data.UUID,
+		Title: "Renamed",
+	})
+	Expect(err).To(BeNil())
+	Expect(updated.Title).To(Equal("Renamed"))
+	Expect(updated.UserId).To(Equal(owner.ID))
+}
+
+func (s *TodoUseCaseTestSuite) TestUseCase_UpdateByUUID_MovingNeedsEditorOnBothProjects() {
+	ctx := context.Background()
+
+	lead, _ := s.UserRepo.Create(ctx, domain.User{UUID: uuid.New(), Name: "Lead", Email: "lead@example.com"})
+	member, _ := s.UserRepo.Create(ctx, domain.User{UUID: uuid.New(), Name: "Member", Email: "member@example.com"})
+
+	shared, _ := s.ProjectRepo.Create(ctx, domain.Project{UUID: uuid.New(), UserId: lead.ID, Name: "Shared"})
+	own, _ := s.ProjectRepo.Create(ctx, domain.Project{UUID: uuid.New(), UserId: member.ID, Name: "Own"})
+
+	viewer, err := s.ProjectRepo.AddMember(ctx, domain.ProjectMember{ProjectId: shared.ID, UserId: member.ID, Role: domain.ProjectViewer})
+	Expect(err).To(BeNil())
+
+	// The owner policy lets the member update the todo they created
+	todo, _ := s.TodoRepo.Create(ctx, domain.Todo{
+		UUID:      uuid.New(),
+		Title:     "Shared Todo",
+		Status:    domain.TodoStatusPending,
+		UserId:    member.ID,
+		ProjectId: &shared.ID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+
+	_, err = s.UseCase.UpdateByUUID(ctx, member.ID, domain.Todo{UUID: todo.UUID, Title: "Taken out", ProjectUUID: &uuid.Nil})
+	Expect(err).To(MatchError(domain.ErrForbidden))
+
+	_, err = s.UseCase.UpdateByUUID(ctx, member.ID, domain.Todo{UUID: todo.UUID, Title: "Moved", ProjectUUID: &own.UUID})
+	Expect(err).To(MatchError(domain.ErrForbidden))
+
+	_, err = s.ProjectRepo.UpdateMemberRole(ctx, viewer, domain.ProjectEditor)
+	Expect(err).To(BeNil())
+
+	moved, err := s.UseCase.UpdateByUUID(ctx, member.ID, domain.Todo{UUID: todo.UUID, Title: "Moved", ProjectUUID: &own.UUID})
+	Expect(err).To(BeNil())
+	Expect(*moved.ProjectId).To(Equal(own.ID))
+}
+
+func (s *TodoUseCaseTestSuite) TestUseCase_GetByUUID() {
+	owner, _ := s.UserRepo.Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Owner",
+		Email: "owner@example.com",
+	})
+
+	data, _ := s.TodoRepo.Create(context.Background(), domain.Todo{
+		UUID:      uuid.New(),
+		Title:     "Read Me",
+		UserId:    owner.ID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+
+	todo, err := s.UseCase.GetByUUID(context.Background(), owner.ID, data.UUID.String())
+	Expect(err).To(BeNil())
+	Expect(todo.Title).To(Equal("Read Me"))
+
+	_, err = s.UseCase.GetByUUID(context.Background(), owner.ID, uuid.NewString())
+	Expect(err).To(MatchError(domain.ErrTodoNotFound))
+
+	_, err = s.UseCase.GetByUUID(context.Background(), owner.ID+1, data.UUID.String())
+	Expect(err).To(MatchError(domain.ErrTodoNotFound))
+}
+
+// failingOccurrenceRepository cannot create the next occurrence of a series
+type failingOccurrenceRepository struct {
+	port.TodoRepository
+}
+
+func (r failingOccurrenceRepository) CreateNextOccurrence(ctx context.Context, previous domain.Todo, next domain.Todo) (domain.Todo, error) {
+	return domain.Todo{}, errors.New("disk full")
+}
+
+func (s *TodoUseCaseTestSuite) TestUseCase_UpdateByUUID_FailedSpawnKeepsTodoOpen() {
+	db := InitTestDB()
+	probe := telemetry.NewNoOpProbe()
+
+	todoRepo := repository.NewTodoRepository(db, probe)
+	useCase := service.NewTodoService(failingOccurrenceRepository{todoRepo}, repository.NewProjectRepository(db, probe), repository.NewWorkflowRepository(db, probe), repository.NewReviewRepository(db, probe), db, probe, policy.NewOwnerPolicy())
+
+	owner, _ := repository.NewUserRepository(db, probe).Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Owner",
+		Email: "owner@example.com",
+	})
+
+	due := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+	rule := "FREQ=WEEKLY"
+
+	created, err := useCase.Create(context.Background(), domain.Todo{
+		UUID:   uuid.New(),
+		Title:  "Water plants",
+		UserId: owner.ID,
+		DueAt:  &due,
+		RRule:  &rule,
+	})
+	Expect(err).To(BeNil())
+
+	// The completion is rolled back with the spawn, so a retry can finish both
+	completed := true
+
+	_, err = useCase.UpdateByUUID(context.Background(), owner.ID, domain.Todo{
+		UUID:         created.UUID,
+		Title:        created.Title,
+		StatusChange: &domain.TodoStatusChange{Completed: &completed},
+	})
+	Expect(err).To(MatchError("disk full"))
+
+	todo, err := todoRepo.GetByUUID(context.Background(), created.UUID.String())
+	Expect(err).To(BeNil())
+	Expect(todo.IsDone()).To(BeFalse())
+}
+
+// racingTodoRepository lets another write land right after the first read
+type racingTodoRepository struct {
+	port.TodoRepository
+	race *func(ctx context.Context, uid string)
+}
+
+func (r racingTodoRepository) GetByUUID(ctx context.Context, uid string) (domain.Todo, error) {
+	todo, err := r.TodoRepository.GetByUUID(ctx, uid)
+
+	if race := *r.race; race != nil {
+		*r.race = nil
+		race(ctx, uid)
+	}
+
+	return todo, err
+}
+
+func (s *TodoUseCaseTestSuite) TestUseCase_UpdateByUUID_WithoutVersionLosesRace() {
+	db := InitTestDB()
+	probe := telemetry.NewNoOpProbe()
+
+	todoRepo := repository.NewTodoRepository(db, probe)
+	race := func(ctx context.Context, uid string) {
+		todoRepo.UpdateByUUID(ctx, domain.Todo{UUID: uuid.MustParse(uid), Title: "Renamed meanwhile"})
+	}
+	racing := racingTodoRepository{TodoRepository: todoRepo, race: &race}
+	useCase := service.NewTodoService(racing, repository.NewProjectRepository(db, probe), repository.NewWorkflowRepository(db, probe), repository.NewReviewRepository(db, probe), db, probe, policy.NewOwnerPolicy())
+
+	owner, _ := repository.NewUserRepository(db, probe).Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Owner",
+		Email: "owner@example.com",
+	})
+
+	created, _ := todoRepo.Create(context.Background(), domain.Todo{
+		UUID:      uuid.New(),
+		Title:     "Write guide",
+		Status:    domain.TodoStatusPending,
+		UserId:    owner.ID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+
+	// The update was checked against the todo before the other write
+	_, err := useCase.UpdateByUUID(context.Background(), owner.ID, domain.Todo{UUID: created.UUID, Title: "Write the guide"})
+	Expect(err).To(MatchError(domain.ErrTodoChanged))
+
+	current, _ := todoRepo.GetByUUID(context.Background(), created.UUID.String())
+	Expect(current.Title).To(Equal("Renamed meanwhile"))
+}
+
+// failingTodoService cannot complete todos
+type failingTodoService struct {
+	port.TodoService
+}
+
+func (f failingTodoService) UpdateByUUID(ctx context.Context, userId int, todo domain.Todo) (domain.Todo, error) {
+	return domain.Todo{}, errors.New("disk full")
+}
+
+func (s *TodoUseCaseTestSuite) TestUseCase_CompletingParentFailsWithItem() {
+	db := InitTestDB()
+	probe := telemetry.NewNoOpProbe()
+
+	todoRepo := repository.NewTodoRepository(db, probe)
+	itemRepo := repository.NewTodoItemRepository(db, probe)
+	todos := service.NewTodoService(todoRepo, repository.NewProjectRepository(db, probe), repository.NewWorkflowRepository(db, probe), repository.NewReviewRepository(db, probe), db, probe, policy.NewOwnerPolicy())
+	items := service.NewTodoItemService(itemRepo, failingTodoService{todos}, db, probe)
+
+	owner, _ := repository.NewUserRepository(db, probe).Create(context.Background(), domain.User{
+		UUID:  uuid.New(),
+		Name:  "Owner",
+		Email: "owner@example.com",
+	})
+
+	created, _ := todoRepo.Create(context.Background(), domain.Todo{
+		UUID:      uuid.New(),
+		Title:     "Pack",
+		Status:    domain.TodoStatusPending,
+		UserId:    owner.ID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+
+	item, err := items.Create(context.Background(), owner.ID, created.UUID.String(), domain.TodoItem{Title: "Passport"})
+	Expect(err).To(BeNil())
+
+	tickets, err := items.Create(context.Background(), owner.ID, created.UUID.String(), domain.TodoItem{Title: "Tickets"})
+	Expect(err).To(BeNil())
+
+	done := true
+	_, err = items.UpdateByUUID(context.Background(), owner.ID, created.UUID.String(), tickets.UUID.String(), domain.TodoItemChanges{Done: &done})
+	Expect(err).To(BeNil())
+
+	_, err = items.UpdateByUUID(context.Background(), owner.ID, created.UUID.String(), item.UUID.String(), domain.TodoItemChanges{Done: &done, CompleteParent: true})
+	Expect(err).To(MatchError("disk full"))
+
+	// The item is not left done on a todo that was not completed
+	item, _ = items.GetByUUID(context.Background(), owner.ID, created.UUID.String(), item.UUID.String())
+	Expect(item.Done).To(BeFalse())
+
+	err = items.DeleteByUUID(context.Background(), owner.ID, created.UUID.String(), item.UUID.String(), true)
+	Expect(err).To(MatchError("disk full"))
+
+	_, err = items.GetByUUID(context.Background(), owner.ID, created.UUID.String(), item.UUID.String())
+	Expect(err).To(BeNil())
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/port"
+	"todos/internal/core/util"
+
+	"github.com/google/uuid"
+)
+
+// exportPageSize is how many todos Export loads at a time
+const exportPageSize = 100
+
+type TodoTransferService struct {
+	repo      port.TodoRepository
+	workflows port.WorkflowRepository
+	clock     port.Clock
+	telemetry port.Telemetry
+}
+
+func NewTodoTransferService(repo port.TodoRepository, workflows port.WorkflowRepository, clock port.Clock, telemetry port.Telemetry) *TodoTransferService {
+	return &TodoTransferService{
+		repo:      repo,
+		workflows: workflows,
+		clock:     clock,
+		telemetry: telemetry,
+	}
+}
+
+// Export hands each todo the user created to each, todos other members
+// created in the user's projects are theirs to export
+func (ts *TodoTransferService) Export(ctx context.Context, userId int, each func(domain.Todo) error) error {
+	start := time.Now()
+
+	filter := domain.TodoFilter{
+		Owner:           userId,
+		Sort:            domain.TodoSort{Field: domain.TodoSortCreatedAt},
+		IncludeArchived: true,
+		Now:             ts.clock.Now(),
+	}
+
+	exported := 0
+	cursor := ""
+
+	err := func() error {
+		for {
+			todos, hasNext, err := ts.repo.GetAllWithCursor(ctx, userId, exportPageSize, cursor, filter)
+
+			if err != nil {
+				return err
+			}
+
+			for _, todo := range todos {
+				if err := each(todo); err != nil {
+					return err
+				}
+			}
+
+			exported += len(todos)
+
+			if !hasNext || len(todos) == 0 {
+				return nil
+			}
+
+			last := todos[len(todos)-1]
+			cursor = util.EncodeSortCursor(filter.Sort.String(), last.SortValue(filter.Sort.Field), last.ID)
+		}
+	}()
+
+	ts.telemetry.RecordServiceOperation(ctx, "todo_transfer", "Export", userId, time.Since(start), err)
+
+	if err == nil {
+		ts.telemetry.RecordBusinessEvent(ctx, "exported", "todo", "", userId, map[string]interface{}{
+			"count": exported,
+		})
+	}
+
+	return err
+}
+
+// Import checks every todo against the default workflow, imported todos are
+// not put in projects. Nothing is created when one fails unless the options
+// skip invalid todos, and everything that is created is created at once.
+func (ts *TodoTransferService) Import(ctx context.Context, userId int, todos []domain.ImportedTodo, options domain.TodoImportOptions) (domain.TodoImport, error) {
+	start := time.Now()
+
+	switch {
+	case len(todos) == 0:
+		return domain.TodoImport{}, domain.ErrImportEmpty
+	case len(todos) > domain.MaxImportRows:
+		return domain.TodoImport{}, domain.ErrImportTooLarge
+	}
+
+	result, err := ts.importTodos(ctx, userId, todos, options)
+
+	ts.telemetry.RecordServiceOperation(ctx, "todo_transfer", "Import", userId, time.Since(start), err)
+
+	if err == nil && result.Imported {
+		ts.telemetry.RecordBusinessEvent(ctx, "imported", "todo", "", userId, map[string]interface{}{
+			"count":   len(result.Rows) - result.Failed(),
+			"skipped": result.Failed(),
+		})
+	}
+
+	return result, err
+}
+
+func (ts *TodoTransferService) importTodos(ctx context.Context, userId int, todos []domain.ImportedTodo, options domain.TodoImportOptions) (domain.TodoImport, error) {
+	result := domain.TodoImport{
+		DryRun: options.DryRun,
+		Rows:   make([]domain.TodoImportRow, 0, len(todos)),
+	}
+
+	workflow, err := ts.workflows.GetDefault(ctx)
+
+	if err != nil {
+		return domain.TodoImport{}, err
+	}
+
+	now := ts.clock.Now().UTC()
+	created := make([]domain.Todo, 0, len(todos))
+
+	for _, imported := range todos {
+		todo, err := newImportedTodo(imported, userId, workflow, now)
+
+		result.Rows = append(result.Rows, domain.TodoImportRow{Line: imported.Line, Todo: todo, Err: err})
+
+		if err == nil {
+			created = append(created, todo)
+		}
+	}
+
+	if options.DryRun || len(created) == 0 || (result.Failed() > 0 && !options.SkipInvalid) {
+		return result, nil
+	}
+
+	if err := ts.repo.CreateMany(ctx, created); err != nil {
+		return domain.TodoImport{}, err
+	}
+
+	result.Imported = true
+
+	return result, nil
+}
+
+// newImportedTodo builds the todo an import creates, the way Create would.
+// Dates the file knows, such as when the todo was created or completed, are
+// kept.
+func newImportedTodo(imported domain.ImportedTodo, userId int, workflow domain.Workflow, now time.Time) (domain.Todo, error) {
+	if err := imported.Validate(); err != nil {
+		return domain.Todo{}, err
+	}
+
+	todo := domain.Todo{
+		UUID:        uuid.New(),
+		Title:       strings.TrimSpace(imported.Title),
+		Description: imported.Description,
+		DueAt:       imported.DueAt,
+		AllDay:      imported.AllDay,
+		UserId:      userId,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if imported.CreatedAt != nil {
+		todo.CreatedAt = imported.CreatedAt.UTC()
+	}
+
+	todo.NormalizeDue()
+
+	if imported.RRule != "" {
+		rule := imported.RRule
+		todo.RRule = &rule
+
+		if err := setRecurrence(&todo, todo.DueAt); err != nil {
+			return domain.Todo{}, err
+		}
+	}
+
+	key := domain.ParseImportStatus(imported.Status)
+	status := workflow.Initial()
+
+	switch {
+	case key != "":
+		var ok bool
+
+		if status, ok = workflow.Status(key); !ok {
+			return domain.Todo{}, fmt.Errorf("%w: %s", domain.ErrUnknownStatus, key)
+		}
+	case imported.Completed:
+		status = workflow.Done()
+	}
+
+	if status.Terminal && imported.CompletedAt != nil {
+		completedAt := imported.CompletedAt.UTC()
+		todo.CompletedAt = &completedAt
+	}
+
+	todo.MoveTo(status, now)
+
+	if len(imported.Tags) > 0 {
+		var err error
+
+		if todo.Tags, err = domain.NormalizeTagNames(imported.Tags); err != nil {
+			return domain.Todo{}, err
+		}
+	}
+
+	return todo, nil
+}
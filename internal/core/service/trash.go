@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"todos/internal/core/domain"
+	"todos/internal/core/model/response"
+	"todos/internal/core/port"
+	"todos/internal/core/util"
+)
+
+const (
+	DefaultTrashRetention      = 30 * 24 * time.Hour
+	DefaultTrashPurgeInterval  = time.Hour
+	DefaultTrashPurgeBatchSize = 100
+)
+
+// TrashService lists deleted todos and brings them back or removes them for
+// good. Todos stay in the trash for Retention, PurgeExpired then removes them.
+type TrashService struct {
+	repo        port.TodoRepository
+	attachments port.AttachmentRepository
+	blobs       port.BlobStore
+	policy      port.TodoPolicy
+	clock       port.Clock
+	telemetry   port.Telemetry
+
+	Retention time.Duration
+	BatchSize int
+}
+
+func NewTrashService(repo port.TodoRepository, attachments port.AttachmentRepository, blobs port.BlobStore, policy port.TodoPolicy, clock port.Clock, telemetry port.Telemetry) *TrashService {
+	return &TrashService{
+		repo:        repo,
+		attachments: attachments,
+		blobs:       blobs,
+		policy:      policy,
+		clock:       clock,
+		telemetry:   telemetry,
+		Retention:   DefaultTrashRetention,
+		BatchSize:   DefaultTrashPurgeBatchSize,
+	}
+}
+
+func (ts *TrashService) List(ctx context.Context, userId int, limit int, cursor string) (*response.CursorResponse, error) {
+	start := time.Now()
+
+	todos, hasNext, err := ts.repo.ListTrash(ctx, userId, limit, cursor)
+
+	ts.telemetry.RecordServiceOperation(ctx, "trash", "List", userId, time.Since(start), err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]response.TrashedTodoResponse, 0, len(todos))
+
+	for _, todo := range todos {
+		data = append(data, response.NewTrashedTodoResponse(todo, ts.Retention))
+	}
+
+	var nextCursor string
+
+	if hasNext && len(todos) > 0 {
+		last := todos[len(todos)-1]
+		nextCursor = util.EncodeSortCursor(domain.TrashCursorSort, last.DeletedAt.Format(time.RFC3339Nano), last.ID)
+	}
+
+	dataBytes, _ := util.Serialize(data)
+
+	responsable := response.CursorResponse{
+		Size: len(data),
+		Data: dataBytes,
+	}
+
+	responsable.Pagination.HasNext = hasNext
+	responsable.Pagination.NextCursor = nextCursor
+
+	return &responsable, nil
+}
+
+// Restore brings a todo back with its comments, items and attachments
+func (ts *TrashService) Restore(ctx context.Context, userId int, uid string) (domain.Todo, error) {
+	start := time.Now()
+
+	todo, err := ts.findAuthorized(ctx, userId, uid)
+
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	restored, err := ts.repo.Restore(ctx, todo)
+
+	ts.telemetry.RecordServiceOperation(ctx, "trash", "Restore", userId, time.Since(start), err)
+
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	ts.telemetry.RecordBusinessEvent(ctx, "restored", "todo", uid, userId, map[string]interface{}{
+		"deleted_for": ts.clock.Now().Sub(*todo.DeletedAt).String(),
+	})
+
+	return restored, nil
+}
+
+// Delete removes a todo from the trash for good without waiting for the purge
+func (ts *TrashService) Delete(ctx context.Context, userId int, uid string) error {
+	start := time.Now()
+
+	todo, err := ts.findAuthorized(ctx, userId, uid)
+
+	if err != nil {
+		return err
+	}
+
+	err = ts.purge(ctx, todo)
+
+	ts.telemetry.RecordServiceOperation(ctx, "trash", "Delete", userId, time.Since(start), err)
+
+	if err != nil {
+		return err
+	}
+
+	ts.telemetry.RecordBusinessEvent(ctx, "purged", "todo", uid, userId, map[string]interface{}{
+		"reason": "manual",
+	})
+
+	return nil
+}
+
+// PurgeExpired removes every todo deleted more than Retention ago, returning
+// how many were removed
+func (ts *TrashService) PurgeExpired(ctx context.Context) (int, error) {
+	start := time.Now()
+	purged := 0
+
+	for {
+		todos, err := ts.repo.ListPurgeable(ctx, ts.clock.Now().Add(-ts.Retention), ts.BatchSize)
+
+		if err != nil {
+			ts.telemetry.RecordServiceOperation(ctx, "trash", "PurgeExpired", 0, time.Since(start), err)
+			return purged, err
+		}
+
+		progress := 0
+
+		for _, todo := range todos {
+			if err := ts.purge(ctx, todo); err != nil {
+				slog.Error("Error purging todo", "todo", todo.UUID, "error", err)
+				continue
+			}
+
+			ts.telemetry.RecordBusinessEvent(ctx, "purged", "todo", todo.UUID.String(), 0, map[string]interface{}{
+				"reason": "retention",
+			})
+
+			progress++
+		}
+
+		purged += progress
+
+		// A batch that failed entirely would come back unchanged next time
+		if len(todos) < ts.BatchSize || progress == 0 {
+			break
+		}
+	}
+
+	ts.telemetry.RecordServiceOperation(ctx, "trash", "PurgeExpired", 0, time.Since(start), nil)
+
+	return purged, nil
+}
+
+// purge deletes the attachment blobs first, like AttachmentPurger, so a
+// failure leaves the todo in the trash to be retried
+func (ts *TrashService) purge(ctx context.Context, todo domain.Todo) error {
+	attachments, err := ts.attachments.ListStored(ctx, todo.ID)
+
+	if err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		if err := ts.blobs.Delete(ctx, attachment.StorageKey); err != nil {
+			return err
+		}
+	}
+
+	return ts.repo.Purge(ctx, todo)
+}
+
+func (ts *TrashService) findAuthorized(ctx context.Context, userId int, uid string) (domain.Todo, error) {
+	todo, err := ts.repo.GetTrashedByUUID(ctx, uid)
+
+	if err != nil {
+		return domain.Todo{}, err
+	}
+
+	if err := ts.policy.Authorize(ctx, userId, domain.TodoActionDelete, todo); err != nil {
+		return domain.Todo{}, err
+	}
+
+	return todo, nil
+}
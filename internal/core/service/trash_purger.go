@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// TrashPurger empties the trash of todos deleted more than the trash's
+// Retention ago, see TrashService.PurgeExpired
+type TrashPurger struct {
+	trash *TrashService
+
+	Interval time.Duration
+}
+
+func NewTrashPurger(trash *TrashService) *TrashPurger {
+	return &TrashPurger{
+		trash:    trash,
+		Interval: DefaultTrashPurgeInterval,
+	}
+}
+
+// Run purges until ctx is cancelled
+func (tp *TrashPurger) Run(ctx context.Context) {
+	slog.Info("Trash purger started", "interval", tp.Interval, "retention", tp.trash.Retention)
+
+	ticker := time.NewTicker(tp.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := tp.trash.PurgeExpired(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("Trash purge failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("Trash purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
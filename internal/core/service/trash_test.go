@@ -0,0 +1,109 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	. "todos/pkg/test"
+
+	"todos/internal/adapter/database/sqlite/repository"
+	"todos/internal/adapter/storage"
+	"todos/internal/core/domain"
+	"todos/internal/core/policy"
+	"todos/internal/core/service"
+	"todos/internal/core/telemetry"
+)
+
+func TestTrashService_PurgeExpiredTakesEverythingWithTheTodo(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := context.Background()
+	db := InitTestDB()
+	probe := telemetry.NewNoOpProbe()
+
+	userRepo := repository.NewUserRepository(db, probe)
+	todoRepo := repository.NewTodoRepository(db, probe)
+	attachmentRepo := repository.NewAttachmentRepository(db, probe)
+	blobs := storage.NewFilesystemStore(t.TempDir())
+	clock := NewFakeClock(time.Now())
+
+	trash := service.NewTrashService(todoRepo, attachmentRepo, blobs, policy.NewOwnerPolicy(), clock, probe)
+
+	user, _ := userRepo.Create(ctx, domain.User{UUID: uuid.New(), Name: "Test User", Email: "test@example.com"})
+
+	createTodo := func(title string) domain.Todo {
+		todo, err := todoRepo.Create(ctx, domain.Todo{UUID: uuid.New(), Title: title, UserId: user.ID, Tags: []string{"home"}, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+		Expect(err).To(BeNil())
+
+		return todo
+	}
+
+	count := func(table string, todo domain.Todo) int {
+		var n int
+		Expect(db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table+" WHERE todo_id = ?", todo.ID).Scan(&n)).To(Succeed())
+
+		return n
+	}
+
+	kept := createTodo("Kept")
+	trashed := createTodo("Trashed")
+
+	_, err := db.ExecContext(ctx, "INSERT INTO comments (uuid, todo_id, user_id, body) VALUES (?, ?, ?, 'First'), (?, ?, ?, 'Second')",
+		uuid.NewString(), trashed.ID, user.ID, uuid.NewString(), trashed.ID, user.ID)
+	Expect(err).To(BeNil())
+
+	_, err = db.ExecContext(ctx, "INSERT INTO comment_revisions (comment_id, body) SELECT id, 'Draft' FROM comments WHERE todo_id = ?", trashed.ID)
+	Expect(err).To(BeNil())
+
+	_, err = db.ExecContext(ctx, "INSERT INTO todo_items (uuid, todo_id, title) VALUES (?, ?, 'Step')", uuid.NewString(), trashed.ID)
+	Expect(err).To(BeNil())
+
+	attachment, err := attachmentRepo.Create(ctx, domain.Attachment{
+		UUID:        uuid.New(),
+		TodoId:      trashed.ID,
+		UserId:      user.ID,
+		Filename:    "notes.txt",
+		ContentType: "text/plain",
+		Size:        5,
+		StorageKey:  "todos/" + trashed.UUID.String() + "/notes",
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	})
+	Expect(err).To(BeNil())
+
+	_, err = blobs.Put(ctx, attachment.StorageKey, strings.NewReader("notes"))
+	Expect(err).To(BeNil())
+
+	Expect(todoRepo.DeleteByUUID(ctx, trashed.UUID.String(), 0)).To(Succeed())
+
+	purged, err := trash.PurgeExpired(ctx)
+	Expect(err).To(BeNil())
+	Expect(purged).To(Equal(0))
+
+	clock.Advance(service.DefaultTrashRetention + time.Hour)
+
+	purged, err = trash.PurgeExpired(ctx)
+	Expect(err).To(BeNil())
+	Expect(purged).To(Equal(1))
+
+	_, err = todoRepo.GetTrashedByUUID(ctx, trashed.UUID.String())
+	Expect(err).To(MatchError(domain.ErrTodoNotFound))
+
+	for _, table := range []string{"comments", "todo_items", "attachments", "todo_tags"} {
+		Expect(count(table, trashed)).To(Equal(0), table)
+	}
+
+	_, err = blobs.Open(ctx, attachment.StorageKey)
+	Expect(err).To(MatchError(domain.ErrBlobNotFound))
+
+	// Live todos are left alone
+	Expect(count("todo_tags", kept)).To(Equal(1))
+
+	_, err = todoRepo.GetByUUID(ctx, kept.UUID.String())
+	Expect(err).To(BeNil())
+}
@@ -0,0 +1,68 @@
+package telemetry
+
+import (
+	"context"
+	"sync"
+
+	"todos/internal/core/port"
+)
+
+type heldEventsKey struct{}
+
+type businessEvent struct {
+	event    string
+	entity   string
+	entityID string
+	userID   int
+	metadata map[string]interface{}
+}
+
+// HeldEvents keeps the business events recorded with its context instead of
+// letting the probes record them, for work that may still be rolled back
+type HeldEvents struct {
+	mu     sync.Mutex
+	events []businessEvent
+}
+
+// HoldBusinessEvents returns a context in which the probes hand business
+// events to the returned HeldEvents. They are recorded only once Release is
+// called, dropping the HeldEvents discards them.
+func HoldBusinessEvents(ctx context.Context) (context.Context, *HeldEvents) {
+	held := &HeldEvents{}
+
+	return context.WithValue(ctx, heldEventsKey{}, held), held
+}
+
+// RecordingBusinessEvents returns a context in which business events are
+// recorded right away, even inside HoldBusinessEvents
+func RecordingBusinessEvents(ctx context.Context) context.Context {
+	return context.WithValue(ctx, heldEventsKey{}, (*HeldEvents)(nil))
+}
+
+// Release records the held events through telemetry, in the order they came
+func (h *HeldEvents) Release(ctx context.Context, telemetry port.Telemetry) {
+	h.mu.Lock()
+	events := h.events
+	h.events = nil
+	h.mu.Unlock()
+
+	for _, e := range events {
+		telemetry.RecordBusinessEvent(ctx, e.event, e.entity, e.entityID, e.userID, e.metadata)
+	}
+}
+
+// holdBusinessEvent keeps the event when ctx holds business events
+func holdBusinessEvent(ctx context.Context, event string, entity string, entityID string, userID int, metadata map[string]interface{}) bool {
+	held, _ := ctx.Value(heldEventsKey{}).(*HeldEvents)
+
+	if held == nil {
+		return false
+	}
+
+	held.mu.Lock()
+	defer held.mu.Unlock()
+
+	held.events = append(held.events, businessEvent{event, entity, entityID, userID, metadata})
+
+	return true
+}
@@ -0,0 +1,42 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHoldBusinessEvents(t *testing.T) {
+	var logs bytes.Buffer
+	probe := NewOTELProbe(slog.New(slog.NewTextHandler(&logs, nil)))
+
+	recorded := func() int {
+		return strings.Count(logs.String(), "Business event recorded")
+	}
+
+	ctx, held := HoldBusinessEvents(context.Background())
+	probe.RecordBusinessEvent(ctx, "created", "todo", "a", 1, nil)
+	probe.RecordBusinessEvent(ctx, "updated", "todo", "a", 1, nil)
+	assert.Equal(t, 0, recorded())
+
+	// Some events are recorded whatever happens to the work around them
+	probe.RecordBusinessEvent(RecordingBusinessEvents(ctx), "access_denied", "todo", "b", 1, nil)
+	assert.Equal(t, 1, recorded())
+
+	held.Release(context.Background(), probe)
+	assert.Equal(t, 3, recorded())
+	assert.Less(t, strings.Index(logs.String(), "event=created"), strings.Index(logs.String(), "event=updated"))
+
+	// Released events are not recorded twice
+	held.Release(context.Background(), probe)
+	assert.Equal(t, 3, recorded())
+
+	// Events that are never released are dropped
+	ctx, _ = HoldBusinessEvents(context.Background())
+	probe.RecordBusinessEvent(ctx, "deleted", "todo", "c", 1, nil)
+	assert.Equal(t, 3, recorded())
+}
@@ -240,6 +240,10 @@ func (p *OTELProbe) RecordServiceOperation(ctx context.Context, service string,
 
 // Business events
 func (p *OTELProbe) RecordBusinessEvent(ctx context.Context, event string, entity string, entityID string, userID int, metadata map[string]interface{}) {
+	if holdBusinessEvent(ctx, event, entity, entityID, userID, metadata) {
+		return
+	}
+
 	// Create a span for the business event
 	ctx, span := p.StartRepositorySpan(ctx, fmt.Sprintf("event.%s", event), entity, map[string]interface{}{
 		"event":     event,
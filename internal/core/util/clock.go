@@ -0,0 +1,10 @@
+package util
+
+import "time"
+
+// SystemClock reads the wall clock
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
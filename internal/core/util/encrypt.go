@@ -24,33 +24,55 @@ func verifySignature(encoded string, signature string) bool {
 }
 
 func EncodeCursor(date string, id int) string {
-	data := response.CursorData{Datetime: date, ID: id}
-	jsonData, _ := json.Marshal(data)
-	encoded := base64.StdEncoding.EncodeToString(jsonData)
-	signature := hmacSignature(encoded)
+	return encodeCursorData(response.CursorData{Datetime: date, ID: id})
+}
 
-	return encoded + "." + signature
+// EncodeSortCursor signs the sort key together with the last seen value,
+// so a cursor can only continue the ordering that produced it
+func EncodeSortCursor(sort string, value string, id int) string {
+	return encodeCursorData(response.CursorData{Sort: sort, Value: value, ID: id})
 }
 
 func DecodeCursor(token string) (string, int, error) {
+	cursor, err := DecodeSortCursor(token)
+
+	if err != nil {
+		return "", 0, err
+	}
+
+	return cursor.Datetime, cursor.ID, nil
+}
+
+func DecodeSortCursor(token string) (response.CursorData, error) {
+	var cursor response.CursorData
+
 	parts := strings.Split(token, ".")
 
 	if len(parts) != 2 {
-		return "", 0, errors.New("invalid cursor format")
+		return cursor, errors.New("invalid cursor format")
 	}
 
 	if !verifySignature(parts[0], parts[1]) {
-		return "", 0, errors.New("invalid cursor signature")
+		return cursor, errors.New("invalid cursor signature")
 	}
 
 	decoded, err := base64.StdEncoding.DecodeString(parts[0])
 
 	if err != nil {
-		return "", 0, err
+		return cursor, err
 	}
 
-	var cursor response.CursorData
-	json.Unmarshal(decoded, &cursor)
+	if err := json.Unmarshal(decoded, &cursor); err != nil {
+		return cursor, err
+	}
 
-	return cursor.Datetime, cursor.ID, nil
+	return cursor, nil
+}
+
+func encodeCursorData(data response.CursorData) string {
+	jsonData, _ := json.Marshal(data)
+	encoded := base64.StdEncoding.EncodeToString(jsonData)
+	signature := hmacSignature(encoded)
+
+	return encoded + "." + signature
 }
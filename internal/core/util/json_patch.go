@@ -0,0 +1,339 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidJSONPatch    = errors.New("invalid JSON patch")
+	ErrJSONPatchTestFailed = errors.New("JSON patch test failed")
+)
+
+// JSONPatchOperation is one operation of a JSON patch (RFC 6902)
+type JSONPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ApplyJSONPatch applies the operations to document in order. The document is
+// what encoding/json decodes into an interface{}, maps are changed in place.
+func ApplyJSONPatch(document any, operations []JSONPatchOperation) (any, error) {
+	var err error
+
+	for i, operation := range operations {
+		if document, err = applyJSONPatchOperation(document, operation); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	return document, nil
+}
+
+func applyJSONPatchOperation(document any, operation JSONPatchOperation) (any, error) {
+	path, err := parseJSONPointer(operation.Path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case "add":
+		value, err := decodeJSONPatchValue(operation)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonPointerAdd(document, path, value)
+	case "remove":
+		document, _, err := jsonPointerRemove(document, path)
+
+		return document, err
+	case "replace":
+		value, err := decodeJSONPatchValue(operation)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(path) == 0 {
+			return value, nil
+		}
+
+		if document, _, err = jsonPointerRemove(document, path); err != nil {
+			return nil, err
+		}
+
+		return jsonPointerAdd(document, path, value)
+	case "move", "copy":
+		from, err := parseJSONPointer(operation.From)
+
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := jsonPointerGet(document, from)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if operation.Op == "copy" {
+			return jsonPointerAdd(document, path, deepCopyJSON(value))
+		}
+
+		// Moving a value onto itself leaves the document as it is
+		if operation.From == operation.Path {
+			return document, nil
+		}
+
+		if strings.HasPrefix(operation.Path+"/", operation.From+"/") {
+			return nil, fmt.Errorf("%w: cannot move %s into itself", ErrInvalidJSONPatch, operation.From)
+		}
+
+		if document, _, err = jsonPointerRemove(document, from); err != nil {
+			return nil, err
+		}
+
+		return jsonPointerAdd(document, path, value)
+	case "test":
+		expected, err := decodeJSONPatchValue(operation)
+
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := jsonPointerGet(document, path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !reflect.DeepEqual(value, expected) {
+			return nil, fmt.Errorf("%w at %s", ErrJSONPatchTestFailed, operation.Path)
+		}
+
+		return document, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidJSONPatch, operation.Op)
+}
+
+func decodeJSONPatchValue(operation JSONPatchOperation) (any, error) {
+	if operation.Value == nil {
+		return nil, fmt.Errorf("%w: %s needs a value", ErrInvalidJSONPatch, operation.Op)
+	}
+
+	var value any
+
+	if err := json.Unmarshal(operation.Value, &value); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidJSONPatch, err)
+	}
+
+	return value, nil
+}
+
+// parseJSONPointer splits a JSON pointer (RFC 6901) into its reference tokens
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: invalid path %q", ErrInvalidJSONPatch, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+func jsonPointerGet(document any, path []string) (any, error) {
+	for _, token := range path {
+		switch node := document.(type) {
+		case map[string]any:
+			value, ok := node[token]
+
+			if !ok {
+				return nil, fmt.Errorf("%w: %s not found", ErrInvalidJSONPatch, token)
+			}
+
+			document = value
+		case []any:
+			i, err := jsonArrayIndex(token, len(node), false)
+
+			if err != nil {
+				return nil, err
+			}
+
+			document = node[i]
+		default:
+			return nil, fmt.Errorf("%w: %s not found", ErrInvalidJSONPatch, token)
+		}
+	}
+
+	return document, nil
+}
+
+// jsonPointerSet replaces an existing value, putting back arrays that grew
+// or shrank into their parent
+func jsonPointerSet(document any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := jsonPointerGet(document, path[:len(path)-1])
+
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+	case []any:
+		i, err := jsonArrayIndex(last, len(node), false)
+
+		if err != nil {
+			return nil, err
+		}
+
+		node[i] = value
+	default:
+		return nil, fmt.Errorf("%w: %s not found", ErrInvalidJSONPatch, last)
+	}
+
+	return document, nil
+}
+
+func jsonPointerAdd(document any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := jsonPointerGet(document, path[:len(path)-1])
+
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+
+		return document, nil
+	case []any:
+		i, err := jsonArrayIndex(last, len(node), true)
+
+		if err != nil {
+			return nil, err
+		}
+
+		grown := make([]any, 0, len(node)+1)
+		grown = append(grown, node[:i]...)
+		grown = append(grown, value)
+		grown = append(grown, node[i:]...)
+
+		return jsonPointerSet(document, path[:len(path)-1], grown)
+	}
+
+	return nil, fmt.Errorf("%w: %s not found", ErrInvalidJSONPatch, last)
+}
+
+func jsonPointerRemove(document any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: cannot remove the whole document", ErrInvalidJSONPatch)
+	}
+
+	parent, err := jsonPointerGet(document, path[:len(path)-1])
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	last := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]any:
+		value, ok := node[last]
+
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s not found", ErrInvalidJSONPatch, last)
+		}
+
+		delete(node, last)
+
+		return document, value, nil
+	case []any:
+		i, err := jsonArrayIndex(last, len(node), false)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		value := node[i]
+		shrunk := append(append([]any{}, node[:i]...), node[i+1:]...)
+
+		document, err = jsonPointerSet(document, path[:len(path)-1], shrunk)
+
+		return document, value, err
+	}
+
+	return nil, nil, fmt.Errorf("%w: %s not found", ErrInvalidJSONPatch, last)
+}
+
+// jsonArrayIndex reads an array index, "-" past the end is only valid to add.
+// Indexes are plain digits without sign or leading zeros.
+func jsonArrayIndex(token string, length int, adding bool) (int, error) {
+	if token == "-" && adding {
+		return length, nil
+	}
+
+	invalid := fmt.Errorf("%w: invalid array index %q", ErrInvalidJSONPatch, token)
+
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, invalid
+	}
+
+	for _, r := range token {
+		if r < '0' || r > '9' {
+			return 0, invalid
+		}
+	}
+
+	i, err := strconv.Atoi(token)
+
+	if err != nil {
+		return 0, invalid
+	}
+
+	if i > length || (i == length && !adding) {
+		return 0, fmt.Errorf("%w: array index %d out of range", ErrInvalidJSONPatch, i)
+	}
+
+	return i, nil
+}
+
+func deepCopyJSON(value any) any {
+	data, _ := json.Marshal(value)
+
+	var copied any
+	json.Unmarshal(data, &copied)
+
+	return copied
+}
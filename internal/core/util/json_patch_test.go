@@ -0,0 +1,273 @@
+package util
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyJSONPatch(t *testing.T) {
+	cases := []struct {
+		name     string
+		document string
+		patch    string
+		want     string
+		err      error
+	}{
+		{
+			name:     "add member",
+			document: `{"title": "Buy milk"}`,
+			patch:    `[{"op": "add", "path": "/done", "value": true}]`,
+			want:     `{"title": "Buy milk", "done": true}`,
+		},
+		{
+			name:     "add inserts into an array",
+			document: `{"tags": ["a", "c"]}`,
+			patch:    `[{"op": "add", "path": "/tags/1", "value": "b"}]`,
+			want:     `{"tags": ["a", "b", "c"]}`,
+		},
+		{
+			name:     "add at the length appends",
+			document: `{"tags": ["a"]}`,
+			patch:    `[{"op": "add", "path": "/tags/1", "value": "b"}]`,
+			want:     `{"tags": ["a", "b"]}`,
+		},
+		{
+			name:     "add with - appends",
+			document: `{"tags": ["a"]}`,
+			patch:    `[{"op": "add", "path": "/tags/-", "value": "b"}]`,
+			want:     `{"tags": ["a", "b"]}`,
+		},
+		{
+			name:     "add past the length",
+			document: `{"tags": ["a"]}`,
+			patch:    `[{"op": "add", "path": "/tags/2", "value": "b"}]`,
+			err:      ErrInvalidJSONPatch,
+		},
+		{
+			name:     "add without a parent",
+			document: `{}`,
+			patch:    `[{"op": "add", "path": "/project/name", "value": "Home"}]`,
+			err:      ErrInvalidJSONPatch,
+		},
+		{
+			name:     "add without a value",
+			document: `{}`,
+			patch:    `[{"op": "add", "path": "/title"}]`,
+			err:      ErrInvalidJSONPatch,
+		},
+		{
+			name:     "remove member",
+			document: `{"title": "Buy milk", "done": true}`,
+			patch:    `[{"op": "remove", "path": "/done"}]`,
+			want:     `{"title": "Buy milk"}`,
+		},
+		{
+			name:     "remove array element",
+			document: `{"tags": ["a", "b", "c"]}`,
+			patch:    `[{"op": "remove", "path": "/tags/1"}]`,
+			want:     `{"tags": ["a", "c"]}`,
+		},
+		{
+			name:     "remove with - is out of range",
+			document: `{"tags": ["a"]}`,
+			patch:    `[{"op": "remove", "path": "/tags/-"}]`,
+			err:      ErrInvalidJSONPatch,
+		},
+		{
+			name:     "remove at the length is out of range",
+			document: `{"tags": ["a"]}`,
+			patch:    `[{"op": "remove", "path": "/tags/1"}]`,
+			err:      ErrInvalidJSONPatch,
+		},
+		{
+			name:     "remove missing member",
+			document: `{}`,
+			patch:    `[{"op": "remove", "path": "/done"}]`,
+			err:      ErrInvalidJSONPatch,
+		},
+		{
+			name:     "replace nested value",
+			document: `{"project": {"name": "Home"}}`,
+			patch:    `[{"op": "replace", "path": "/project/name", "value": "Work"}]`,
+			want:     `{"project": {"name": "Work"}}`,
+		},
+		{
+			name:     "replace the whole document",
+			document: `{"title": "Buy milk"}`,
+			patch:    `[{"op": "replace", "path": "", "value": {"title": "Buy bread"}}]`,
+			want:     `{"title": "Buy bread"}`,
+		},
+		{
+			name:     "replace missing member",
+			document: `{}`,
+			patch:    `[{"op": "replace", "path": "/title", "value": "Buy milk"}]`,
+			err:      ErrInvalidJSONPatch,
+		},
+		{
+			name:     "move member",
+			document: `{"title": "Buy milk", "project": {}}`,
+			patch:    `[{"op": "move", "from": "/title", "path": "/project/title"}]`,
+			want:     `{"project": {"title": "Buy milk"}}`,
+		},
+		{
+			name:     "move array element",
+			document: `{"tags": ["a", "b", "c"]}`,
+			patch:    `[{"op": "move", "from": "/tags/0", "path": "/tags/-"}]`,
+			want:     `{"tags": ["b", "c", "a"]}`,
+		},
+		{
+			name:     "move onto itself is a no-op",
+			document: `{"project": {"name": "Home"}}`,
+			patch:    `[{"op": "move", "from": "/project", "path": "/project"}]`,
+			want:     `{"project": {"name": "Home"}}`,
+		},
+		{
+			name:     "move onto itself still needs the value",
+			document: `{}`,
+			patch:    `[{"op": "move", "from": "/project", "path": "/project"}]`,
+			err:      ErrInvalidJSONPatch,
+		},
+		{
+			name:     "move into its own child",
+			document: `{"project": {"name": "Home"}}`,
+			patch:    `[{"op": "move", "from": "/project", "path": "/project/inner"}]`,
+			err:      ErrInvalidJSONPatch,
+		},
+		{
+			name:     "move next to a sibling sharing its prefix",
+			document: `{"tag": "a", "tags": []}`,
+			patch:    `[{"op": "move", "from": "/tag", "path": "/tags/0"}]`,
+			want:     `{"tags": ["a"]}`,
+		},
+		{
+			name:     "copy member",
+			document: `{"title": "Buy milk", "project": {}}`,
+			patch:    `[{"op": "copy", "from": "/title", "path": "/project/title"}]`,
+			want:     `{"title": "Buy milk", "project": {"title": "Buy milk"}}`,
+		},
+		{
+			name:     "copy is independent of its source",
+			document: `{"project": {"tags": ["a"]}}`,
+			patch: `[
+				{"op": "copy", "from": "/project", "path": "/archived"},
+				{"op": "add", "path": "/archived/tags/-", "value": "b"}
+			]`,
+			want: `{"project": {"tags": ["a"]}, "archived": {"tags": ["a", "b"]}}`,
+		},
+		{
+			name:     "copy from a missing member",
+			document: `{}`,
+			patch:    `[{"op": "copy", "from": "/title", "path": "/name"}]`,
+			err:      ErrInvalidJSONPatch,
+		},
+		{
+			name:     "~1 and ~0 unescape to / and ~",
+			document: `{"a/b": 1, "m~n": 2, "~1": 3}`,
+			patch: `[
+				{"op": "test", "path": "/a~1b", "value": 1},
+				{"op": "replace", "path": "/m~0n", "value": 20},
+				{"op": "remove", "path": "/~01"}
+			]`,
+			want: `{"a/b": 1, "m~n": 20}`,
+		},
+		{
+			name:     "test nested object",
+			document: `{"project": {"name": "Home", "tags": ["a", "b"], "order": 1}}`,
+			patch:    `[{"op": "test", "path": "/project", "value": {"order": 1.0, "tags": ["a", "b"], "name": "Home"}}]`,
+			want:     `{"project": {"name": "Home", "tags": ["a", "b"], "order": 1}}`,
+		},
+		{
+			name:     "test nested array element",
+			document: `{"items": [{"done": false}, {"done": true}]}`,
+			patch:    `[{"op": "test", "path": "/items/1/done", "value": true}]`,
+			want:     `{"items": [{"done": false}, {"done": true}]}`,
+		},
+		{
+			name:     "test nested mismatch",
+			document: `{"project": {"tags": ["a", "b"]}}`,
+			patch:    `[{"op": "test", "path": "/project/tags", "value": ["b", "a"]}]`,
+			err:      ErrJSONPatchTestFailed,
+		},
+		{
+			name:     "test missing value",
+			document: `{}`,
+			patch:    `[{"op": "test", "path": "/title", "value": null}]`,
+			err:      ErrInvalidJSONPatch,
+		},
+		{
+			name:     "path without leading slash",
+			document: `{}`,
+			patch:    `[{"op": "add", "path": "title", "value": "Buy milk"}]`,
+			err:      ErrInvalidJSONPatch,
+		},
+		{
+			name:     "unknown op",
+			document: `{}`,
+			patch:    `[{"op": "merge", "path": "/title", "value": "Buy milk"}]`,
+			err:      ErrInvalidJSONPatch,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var document any
+			require.NoError(t, json.Unmarshal([]byte(tc.document), &document))
+
+			var operations []JSONPatchOperation
+			require.NoError(t, json.Unmarshal([]byte(tc.patch), &operations))
+
+			patched, err := ApplyJSONPatch(document, operations)
+
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			data, _ := json.Marshal(patched)
+			assert.JSONEq(t, tc.want, string(data))
+		})
+	}
+}
+
+func TestJSONArrayIndex(t *testing.T) {
+	cases := []struct {
+		token  string
+		adding bool
+		want   int
+		valid  bool
+	}{
+		{token: "0", want: 0, valid: true},
+		{token: "2", want: 2, valid: true},
+		{token: "3", adding: true, want: 3, valid: true},
+		{token: "-", adding: true, want: 3, valid: true},
+		{token: "3"},
+		{token: "4", adding: true},
+		{token: "-"},
+		{token: ""},
+		{token: "01"},
+		{token: "+1"},
+		{token: "-1"},
+		{token: "-0"},
+		{token: " 1"},
+		{token: "1e0"},
+		{token: "99999999999999999999"},
+	}
+
+	for _, tc := range cases {
+		i, err := jsonArrayIndex(tc.token, 3, tc.adding)
+
+		if !tc.valid {
+			assert.ErrorIs(t, err, ErrInvalidJSONPatch, "token %q", tc.token)
+			continue
+		}
+
+		if assert.NoError(t, err, "token %q", tc.token) {
+			assert.Equal(t, tc.want, i, "token %q", tc.token)
+		}
+	}
+}
@@ -1,13 +1,18 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"todos/internal/core/domain"
 	"todos/internal/core/telemetry"
 	. "todos/pkg"
 
@@ -20,6 +25,9 @@ type RateLimitEndpointConfig struct {
 	Requests int
 	Window   time.Duration
 	KeyFunc  func(*gin.Context) string
+
+	// Cost weighs a request against Requests, nil counts every request as one
+	Cost func(*gin.Context) int
 }
 
 type RateLimiter struct {
@@ -54,21 +62,252 @@ func NewRateLimiter(logger *zap.Logger, metrics *telemetry.AppMetrics) *RateLimi
 			Window:   time.Minute,
 			KeyFunc:  getUserID,
 		},
+		"GET /todos/search": {
+			Requests: 60,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"GET /todos/:uuid": {
+			Requests: 100,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
 		"POST /todos": {
 			Requests: 20,
 			Window:   time.Minute,
 			KeyFunc:  getUserID,
 		},
+		"POST /todos/batch": {
+			Requests: 500,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+			Cost:     batchOperationCount,
+		},
+		"GET /todos/export": {
+			Requests: 5,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /todos/import": {
+			Requests: 5,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
 		"PUT /todo/:uuid": {
 			Requests: 10,
 			Window:   time.Minute,
 			KeyFunc:  getUserID,
 		},
+		"PATCH /todos/:uuid": {
+			Requests: 10,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
 		"DELETE /todos/:uuid": {
 			Requests: 5,
 			Window:   time.Minute,
 			KeyFunc:  getUserID,
 		},
+		"GET /todos/:uuid/reminders": {
+			Requests: 100,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /todos/:uuid/reminders": {
+			Requests: 20,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"DELETE /todos/:uuid/reminders/:reminder_uuid": {
+			Requests: 10,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /todos/:uuid/items": {
+			Requests: 60,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"PUT /todos/:uuid/items/:item_uuid": {
+			Requests: 60,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"DELETE /todos/:uuid/items/:item_uuid": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /todos/:uuid/occurrences/skip": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /todos/:uuid/revert/:revision": {
+			Requests: 10,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /todos/:uuid/blockers": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"DELETE /todos/:uuid/blockers/:blocker_uuid": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /todos/:uuid/time-entries": {
+			Requests: 60,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /todos/:uuid/timer/start": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /timer/stop": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"GET /time-entries/report": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /trash/:uuid/restore": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"DELETE /trash/:uuid": {
+			Requests: 10,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /todos/:uuid/comments": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"PUT /todos/:uuid/comments/:comment_uuid": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"DELETE /todos/:uuid/comments/:comment_uuid": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /todos/:uuid/reviews": {
+			Requests: 20,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /todos/:uuid/reviews/approve": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /todos/:uuid/reviews/reject": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /todos/:uuid/attachments": {
+			Requests: 20,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"DELETE /todos/:uuid/attachments/:attachment_uuid": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"GET /attachments/:uuid/download": {
+			Requests: 60,
+			Window:   time.Minute,
+			KeyFunc:  GetClientIP,
+		},
+		"POST /tags": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"PUT /tags/:uuid": {
+			Requests: 10,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /tags/:uuid/merge": {
+			Requests: 10,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"DELETE /tags/:uuid": {
+			Requests: 10,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /projects": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"PUT /projects/:uuid": {
+			Requests: 30,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"DELETE /projects/:uuid": {
+			Requests: 10,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /projects/:uuid/archive": {
+			Requests: 10,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /projects/:uuid/unarchive": {
+			Requests: 10,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"PUT /projects/:uuid/order": {
+			Requests: 60,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"POST /projects/:uuid/members": {
+			Requests: 20,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"PUT /projects/:uuid/members/:user_uuid": {
+			Requests: 20,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"DELETE /projects/:uuid/members/:user_uuid": {
+			Requests: 20,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"PUT /projects/:uuid/workflow": {
+			Requests: 20,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
+		"DELETE /projects/:uuid/workflow": {
+			Requests: 20,
+			Window:   time.Minute,
+			KeyFunc:  getUserID,
+		},
 		"/todos": {
 			Requests: 100,
 			Window:   time.Minute,
@@ -119,7 +358,18 @@ func (rl *RateLimiter) RateLimitMiddleware() gin.HandlerFunc {
 			zap.Int("limit", config.Requests),
 			zap.Duration("window", config.Window))
 
-		allowed, remaining, resetTime, err := rl.checkRateLimit(key, config)
+		cost := 1
+
+		if config.Cost != nil {
+			cost = max(config.Cost(c), 1)
+		}
+
+		// Weighing may turn the request away, say when its body is too large
+		if c.IsAborted() {
+			return
+		}
+
+		allowed, remaining, resetTime, err := rl.checkRateLimit(key, config, cost)
 		if err != nil {
 			rl.logger.Error("Rate limit check failed",
 				zap.String("key", key),
@@ -166,7 +416,7 @@ func (rl *RateLimiter) RateLimitMiddleware() gin.HandlerFunc {
 	}
 }
 
-func (rl *RateLimiter) checkRateLimit(key string, config RateLimitEndpointConfig) (bool, int, time.Time, error) {
+func (rl *RateLimiter) checkRateLimit(key string, config RateLimitEndpointConfig, cost int) (bool, int, time.Time, error) {
 	now := time.Now()
 
 	rl.mutex.Lock()
@@ -175,35 +425,80 @@ func (rl *RateLimiter) checkRateLimit(key string, config RateLimitEndpointConfig
 	if entry, found := rl.cache.Get(key); found {
 		rateLimitEntry := entry.(RateLimitEntry)
 
-		if now.After(rateLimitEntry.ResetTime) {
-
-			resetTime := now.Add(config.Window)
-			newEntry := RateLimitEntry{
-				Count:     1,
-				ResetTime: resetTime,
+		if !now.After(rateLimitEntry.ResetTime) {
+			if rateLimitEntry.Count+cost > config.Requests {
+				return false, config.Requests - rateLimitEntry.Count, rateLimitEntry.ResetTime, nil
 			}
-			rl.cache.Set(key, newEntry, config.Window)
-			return true, config.Requests - 1, resetTime, nil
-		}
 
-		if rateLimitEntry.Count >= config.Requests {
-			return false, 0, rateLimitEntry.ResetTime, nil
+			rateLimitEntry.Count += cost
+			rl.cache.Set(key, rateLimitEntry, cache.DefaultExpiration)
+
+			return true, config.Requests - rateLimitEntry.Count, rateLimitEntry.ResetTime, nil
 		}
+	}
 
-		rateLimitEntry.Count++
-		rl.cache.Set(key, rateLimitEntry, cache.DefaultExpiration)
+	resetTime := now.Add(config.Window)
 
-		return true, config.Requests - rateLimitEntry.Count, rateLimitEntry.ResetTime, nil
+	if cost > config.Requests {
+		return false, config.Requests, resetTime, nil
 	}
 
-	resetTime := now.Add(config.Window)
 	newEntry := RateLimitEntry{
-		Count:     1,
+		Count:     cost,
 		ResetTime: resetTime,
 	}
 	rl.cache.Set(key, newEntry, config.Window)
 
-	return true, config.Requests - 1, resetTime, nil
+	return true, config.Requests - cost, resetTime, nil
+}
+
+// maxBatchBodySize bounds the batch body read to weigh it, room for every
+// operation of the largest batch
+const maxBatchBodySize = domain.MaxBatchOperations * 16 << 10
+
+// batchOperationCount weighs a batch by its number of operations. The body is
+// put back for the handler to read, a body over maxBatchBodySize is answered
+// with 413 right away.
+func batchOperationCount(c *gin.Context) int {
+	if c.Request.Body == nil {
+		return 1
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBatchBodySize))
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var tooLarge *http.MaxBytesError
+
+	if errors.As(err, &tooLarge) {
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":   "Request body too large",
+			"message": fmt.Sprintf("A batch body must be at most %d KB", maxBatchBodySize>>10),
+		})
+
+		return 1
+	}
+
+	if err != nil {
+		return 1
+	}
+
+	var batch struct {
+		Operations []json.RawMessage `json:"operations"`
+	}
+
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return 1
+	}
+
+	return len(batch.Operations)
+}
+
+// staticTodoSegments are routes under /todos/ that must not be mistaken for a uuid
+var staticTodoSegments = map[string]bool{
+	"search": true,
+	"batch":  true,
+	"export": true,
+	"import": true,
 }
 
 func (rl *RateLimiter) normalizePath(path string) string {
@@ -219,7 +514,7 @@ func (rl *RateLimiter) normalizePath(path string) string {
 	if strings.HasPrefix(path, "/todos/") {
 		// /todos/123 -> /todos/:uuid
 		parts := strings.Split(path, "/")
-		if len(parts) >= 3 {
+		if len(parts) >= 3 && !staticTodoSegments[parts[2]] {
 			parts[2] = ":uuid"
 			return strings.Join(parts, "/")
 		}
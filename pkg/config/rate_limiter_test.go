@@ -1,6 +1,7 @@
 package config
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"sort"
@@ -319,3 +320,102 @@ func TestRateLimitMiddleware_NoDoubleCounting(t *testing.T) {
 	Expect(results).To(Equal(expectedRemaining),
 		"Concurrent requests should have correct remaining counts without double counting: %v", results)
 }
+
+func TestRateLimitMiddleware_BatchWeighedByOperations(t *testing.T) {
+	RegisterTestingT(t)
+	logger := zap.NewNop()
+	metrics := telemetry.NewAppMetrics(prometheus.NewRegistry())
+	rl := NewRateLimiter(logger, metrics)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("x-user-id", "123")
+		c.Next()
+	})
+	router.Use(rl.RateLimitMiddleware())
+
+	var received []string
+	router.POST("/todos/batch", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		received = append(received, string(body))
+		c.JSON(200, gin.H{"status": "ok"})
+	})
+
+	batch := func(n int) string {
+		operations := make([]string, n)
+
+		for i := range operations {
+			operations[i] = `{"op":"delete","uuid":"` + strconv.Itoa(i) + `"}`
+		}
+
+		return `{"operations":[` + strings.Join(operations, ",") + `]}`
+	}
+
+	send := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/todos/batch", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		return w
+	}
+
+	// Each operation counts against the limit of 500 per minute
+	w := send(batch(300))
+	Expect(w.Code).To(Equal(200))
+	Expect(w.Header().Get("X-RateLimit-Remaining")).To(Equal("200"))
+
+	// The handler still sees the whole body
+	Expect(received).To(Equal([]string{batch(300)}))
+
+	w = send(batch(250))
+	Expect(w.Code).To(Equal(429))
+	Expect(w.Header().Get("X-RateLimit-Remaining")).To(Equal("200"))
+
+	w = send(batch(200))
+	Expect(w.Code).To(Equal(200))
+	Expect(w.Header().Get("X-RateLimit-Remaining")).To(Equal("0"))
+
+	// A body without operations still counts once
+	w = send(`not json`)
+	Expect(w.Code).To(Equal(429))
+}
+
+func TestRateLimitMiddleware_BatchBodyTooLarge(t *testing.T) {
+	RegisterTestingT(t)
+	rl := NewRateLimiter(zap.NewNop(), nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(rl.RateLimitMiddleware())
+
+	called := false
+	router.POST("/todos/batch", func(c *gin.Context) {
+		called = true
+		c.JSON(200, gin.H{"status": "ok"})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/todos/batch", strings.NewReader(strings.Repeat("x", maxBatchBodySize+1)))
+	router.ServeHTTP(w, req)
+
+	Expect(w.Code).To(Equal(http.StatusRequestEntityTooLarge))
+	Expect(called).To(BeFalse())
+}
+
+func TestRateLimiter_BatchIsNotATodoUUID(t *testing.T) {
+	RegisterTestingT(t)
+	rl := NewRateLimiter(zap.NewNop(), nil)
+
+	Expect(rl.normalizePath("/todos/batch")).To(Equal("/todos/batch"))
+	Expect(rl.normalizePath("/todos/123")).To(Equal("/todos/:uuid"))
+}
+
+func TestRateLimiter_TransferIsNotATodoUUID(t *testing.T) {
+	RegisterTestingT(t)
+	rl := NewRateLimiter(zap.NewNop(), nil)
+
+	Expect(rl.normalizePath("/todos/export")).To(Equal("/todos/export"))
+	Expect(rl.normalizePath("/todos/import")).To(Equal("/todos/import"))
+}
@@ -1,11 +1,33 @@
 package factory
 
 import (
+	"time"
+
 	fab "github.com/Goldziher/fabricator"
+	"github.com/google/uuid"
+
+	"todos/internal/core/domain"
 )
 
+// todoDefaults keeps foreign keys out of the random data so built todos can
+// be persisted without first creating the rows they would point at. Random
+// recurrence rules would not parse, so built todos do not repeat, and a
+// random status would not be part of any workflow.
+var todoDefaults = map[string]any{
+	"ProjectId":        (*int)(nil),
+	"ProjectUUID":      (*uuid.UUID)(nil),
+	"NextOccurrenceId": (*int)(nil),
+	"RRule":            (*string)(nil),
+	"RRuleStart":       (*time.Time)(nil),
+	"RRuleExdates":     (*string)(nil),
+	"Status":           domain.TodoStatusPending,
+	"Completed":        false,
+	"CompletedAt":      (*time.Time)(nil),
+	"StatusChange":     (*domain.TodoStatusChange)(nil),
+}
+
 func NewTodo[T any](customData ...map[string]any) T {
-	instance := fab.New(*new(T))
+	instance := fab.New(*new(T), fab.Options[T]{Defaults: todoDefaults})
 
 	if len(customData) > 0 {
 		return instance.Build(customData...)